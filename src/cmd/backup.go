@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/usecase"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var backupIncludeMedia bool
+
+// backupCmd takes an online snapshot of the databases into a tarball
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Create a backup of the WhatsApp session, chat storage and optionally media",
+	Long:  `Take consistent online snapshots of the SQLite databases (device keys, optional keys database and chat storage) and write them with a manifest into a tar.gz archive.`,
+	Run:   backupRun,
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	backupCmd.Flags().BoolVarP(
+		&backupIncludeMedia,
+		"include-media", "",
+		false,
+		`include downloaded media files --include-media <true/false> | example: --include-media=true`,
+	)
+	backupCmd.Flags().StringVarP(
+		&config.PathBackups,
+		"output-dir", "o",
+		config.PathBackups,
+		`directory to write the backup archive into --output-dir <string> | example: --output-dir="/var/backups/whatsapp"`,
+	)
+}
+
+func backupRun(_ *cobra.Command, _ []string) {
+	service, closeService := newBackupService(context.Background())
+	defer closeService()
+
+	response, err := service.Backup(context.Background(), domainApp.BackupRequest{IncludeMedia: backupIncludeMedia})
+	if err != nil {
+		logrus.Fatalf("backup failed: %v", err)
+	}
+
+	fmt.Printf("Backup written to %s (%d bytes, %d files)\n", response.Path, response.Size, len(response.Manifest.Files))
+}
+
+// newBackupService opens the WhatsApp database, the only store the backup and restore commands
+// read, to find the paired device. Chat storage, the WhatsApp clients and the webhook outbox are
+// left alone so a restore does not race the databases it overwrites.
+func newBackupService(ctx context.Context) (domainApp.IAppUsecase, func()) {
+	if config.AppDebug {
+		config.WhatsappLogLevel = "DEBUG"
+		logrus.SetLevel(logrus.DebugLevel)
+	}
+	if err := utils.CreateFolder(config.PathStorages); err != nil {
+		logrus.Errorln(err)
+	}
+
+	deviceStore := whatsapp.InitWaDB(ctx, config.DBURI)
+	return usecase.NewBackupService(deviceStore), func() {
+		if err := deviceStore.Close(); err != nil {
+			logrus.Errorf("Failed to close the WhatsApp database: %v", err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/lifecycle"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/usecase"
+)
+
+// newLifecycle creates the lifecycle manager of a server command, stopServer stops accepting
+// requests and waits for the ones in flight. Every command shuts down in the same order:
+//
+//  1. readiness turns false
+//  2. login event streams and websocket connections are closed, they would hold the server open
+//  3. the server stops accepting requests and finishes the ones in flight
+//  4. background jobs such as bulk number checks and participant imports are cancelled and
+//     store their progress, pending greeting batches are dropped
+//  5. messages queued for moderation are moderated while the clients are still connected
+//  6. the WhatsApp clients disconnect, so no new events arrive
+//  7. the LID chat merge in progress and webhook deliveries in flight finish
+//  8. the WhatsApp and chat storage databases are closed, which checkpoints the SQLite WAL
+func newLifecycle(stopServer func(ctx context.Context) error) *lifecycle.Manager {
+	manager := lifecycle.New(config.AppShutdownTimeout)
+	manager.OnShutdown("login event streams", func(context.Context) error {
+		whatsapp.CloseLoginEvents()
+		return nil
+	})
+	manager.OnShutdown("websocket hub", websocket.StopHub)
+	manager.OnShutdown("server", stopServer)
+	manager.OnShutdown("background jobs", usecase.StopBackgroundJobs)
+	manager.OnShutdown("message moderation", whatsapp.StopModeration)
+	manager.OnShutdown("whatsapp clients", whatsapp.DisconnectSessions)
+	manager.OnShutdown("LID chat merges", whatsapp.StopLIDChatMerges)
+	manager.OnShutdown("webhook deliveries", whatsapp.DrainWebhooks)
+	manager.OnShutdown("whatsapp database", func(context.Context) error {
+		return whatsapp.CloseStores()
+	})
+	manager.OnShutdown("chat storage database", func(context.Context) error {
+		return chatStorageDB.Close()
+	})
+	return manager
+}
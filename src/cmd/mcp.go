@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 
 	"github.com/sirupsen/logrus"
 
@@ -17,7 +20,10 @@ var mcpCmd = &cobra.Command{
 	Use:   "mcp",
 	Short: "Start WhatsApp MCP server using SSE",
 	Long:  `Start a WhatsApp MCP (Model Context Protocol) server using Server-Sent Events (SSE) transport. This allows AI agents to interact with WhatsApp through a standardized protocol.`,
-	Run:   mcpServer,
+	PreRun: func(*cobra.Command, []string) {
+		initApp()
+	},
+	Run: mcpServer,
 }
 
 func init() {
@@ -29,8 +35,6 @@ func init() {
 func mcpServer(_ *cobra.Command, _ []string) {
 	// Set auto reconnect to whatsapp server after booting
 	go helpers.SetAutoConnectAfterBooting(appUsecase)
-	// Set auto reconnect checking
-	go helpers.SetAutoReconnectChecking(whatsappCli)
 
 	// Create MCP server with capabilities
 	mcpServer := server.NewMCPServer(
@@ -53,20 +57,54 @@ func mcpServer(_ *cobra.Command, _ []string) {
 	groupHandler := mcp.InitMcpGroup(groupUsecase)
 	groupHandler.AddGroupTools(mcpServer)
 
-	// Create SSE server
+	analyticsHandler := mcp.InitMcpAnalytics(analyticsUsecase)
+	analyticsHandler.AddAnalyticsTools(mcpServer)
+
+	userHandler := mcp.InitMcpUser(userUsecase)
+	userHandler.AddUserTools(mcpServer)
+
+	contactHandler := mcp.InitMcpContact(contactUsecase)
+	contactHandler.AddContactTools(mcpServer)
+
+	sessionHandler := mcp.InitMcpSession(sessionUsecase)
+	sessionHandler.AddSessionTools(mcpServer)
+
+	// Create SSE server, served for the default session and under /sessions/{session_id}
+	mux := http.NewServeMux()
+	httpServer := &http.Server{Handler: mux}
 	sseServer := server.NewSSEServer(
 		mcpServer,
 		server.WithBaseURL(fmt.Sprintf("http://%s:%s", config.McpHost, config.McpPort)),
 		server.WithKeepAlive(true),
+		server.WithDynamicBasePath(mcp.SessionBasePath),
+		server.WithHTTPServer(httpServer),
 	)
+	manager := newLifecycle(sseServer.Shutdown)
+	mux.Handle("/healthz", mcp.HealthHandler())
+	mux.Handle("/readyz", mcp.ReadinessHandler(manager.Ready))
+	mux.Handle("/sse", mcp.SessionMiddleware(sseServer.SSEHandler()))
+	mux.Handle("/message", mcp.SessionMiddleware(sseServer.MessageHandler()))
+	mux.Handle("/sessions/{session_id}/sse", mcp.SessionMiddleware(sseServer.SSEHandler()))
+	mux.Handle("/sessions/{session_id}/message", mcp.SessionMiddleware(sseServer.MessageHandler()))
 
 	// Start the SSE server
 	addr := fmt.Sprintf("%s:%s", config.McpHost, config.McpPort)
 	logrus.Printf("Starting WhatsApp MCP SSE server on %s", addr)
 	logrus.Printf("SSE endpoint: http://%s:%s/sse", config.McpHost, config.McpPort)
 	logrus.Printf("Message endpoint: http://%s:%s/message", config.McpHost, config.McpPort)
+	logrus.Printf("Session endpoints: http://%s:%s/sessions/{session_id}/sse", config.McpHost, config.McpPort)
 
-	if err := sseServer.Start(addr); err != nil {
-		logrus.Fatalf("Failed to start SSE server: %v", err)
+	if err := manager.Run(func() error {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+		manager.SetReady(true)
+		if err = httpServer.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}); err != nil {
+		logrus.Fatalf("SSE server stopped with error: %v", err)
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
@@ -26,7 +27,10 @@ var restCmd = &cobra.Command{
 	Use:   "rest",
 	Short: "Send whatsapp API over http",
 	Long:  `This application is from clone https://github.com/aldinokemal/go-whatsapp-web-multidevice`,
-	Run:   restServer,
+	PreRun: func(*cobra.Command, []string) {
+		initApp()
+	},
+	Run: restServer,
 }
 
 func init() {
@@ -57,13 +61,28 @@ func restServer(_ *cobra.Command, _ []string) {
 	}))
 
 	app.Use(middleware.Recovery())
+
+	// Create base path group or use app directly
+	var apiGroup fiber.Router = app
+	if config.AppBasePath != "" {
+		apiGroup = app.Group(config.AppBasePath)
+	}
+
+	// Probes are registered before authentication
+	manager := newLifecycle(app.ShutdownWithContext)
+	rest.InitRestHealth(apiGroup, manager.Ready)
+	app.Hooks().OnListen(func(fiber.ListenData) error {
+		manager.SetReady(true)
+		return nil
+	})
+
 	app.Use(middleware.BasicAuth())
 	if config.AppDebug {
 		app.Use(logger.New())
 	}
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept",
+		AllowHeaders: "Origin, Content-Type, Accept, " + domainSession.HeaderSessionID,
 	}))
 
 	if len(config.AppBasicAuthCredential) > 0 {
@@ -81,11 +100,8 @@ func restServer(_ *cobra.Command, _ []string) {
 		}))
 	}
 
-	// Create base path group or use app directly
-	var apiGroup fiber.Router = app
-	if config.AppBasePath != "" {
-		apiGroup = app.Group(config.AppBasePath)
-	}
+	// Select the WhatsApp session of every request
+	app.Use(middleware.Session(config.AppBasePath))
 
 	// Rest
 	rest.InitRestApp(apiGroup, appUsecase)
@@ -95,6 +111,12 @@ func restServer(_ *cobra.Command, _ []string) {
 	rest.InitRestMessage(apiGroup, messageUsecase)
 	rest.InitRestGroup(apiGroup, groupUsecase)
 	rest.InitRestNewsletter(apiGroup, newsletterUsecase)
+	rest.InitRestAnalytics(apiGroup, analyticsUsecase)
+	rest.InitRestModeration(apiGroup, moderationUsecase)
+	rest.InitRestGreeting(apiGroup, greetingUsecase)
+	rest.InitRestWebhook(apiGroup, webhookUsecase)
+	rest.InitRestContact(apiGroup, contactUsecase)
+	rest.InitRestSession(apiGroup, sessionUsecase)
 
 	apiGroup.Get("/", func(c *fiber.Ctx) error {
 		return c.Render("views/index", fiber.Map{
@@ -112,10 +134,10 @@ func restServer(_ *cobra.Command, _ []string) {
 
 	// Set auto reconnect to whatsapp server after booting
 	go helpers.SetAutoConnectAfterBooting(appUsecase)
-	// Set auto reconnect checking
-	go helpers.SetAutoReconnectChecking(whatsappCli)
 
-	if err := app.Listen(":" + config.AppPort); err != nil {
-		logrus.Fatalln("Failed to start: ", err.Error())
+	if err := manager.Run(func() error {
+		return app.Listen(":" + config.AppPort)
+	}); err != nil {
+		logrus.Fatalln("Server stopped with error: ", err.Error())
 	}
 }
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	restoreIncludeMedia bool
+	restoreForce        bool
+)
+
+// restoreCmd restores a backup archive created by the backup command or POST /app/backup
+var restoreCmd = &cobra.Command{
+	Use:   "restore <archive>",
+	Short: "Restore a backup archive so a paired session can move to a new host",
+	Long:  `Validate the manifest and checksums of a backup archive, then restore the databases (and optionally media) into the configured locations. The paired session is reused without scanning the QR code again.`,
+	Args:  cobra.ExactArgs(1),
+	Run:   restoreRun,
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+	restoreCmd.Flags().BoolVarP(
+		&restoreIncludeMedia,
+		"include-media", "",
+		false,
+		`restore media files contained in the archive --include-media <true/false> | example: --include-media=true`,
+	)
+	restoreCmd.Flags().BoolVarP(
+		&restoreForce,
+		"force", "",
+		false,
+		`overwrite an already paired device on this host --force <true/false> | example: --force=true`,
+	)
+}
+
+func restoreRun(_ *cobra.Command, args []string) {
+	service, closeService := newBackupService(context.Background())
+	defer closeService()
+
+	manifest, err := service.Restore(context.Background(), domainApp.RestoreRequest{
+		ArchivePath:  args[0],
+		IncludeMedia: restoreIncludeMedia,
+		Force:        restoreForce,
+	})
+	if err != nil {
+		logrus.Fatalf("restore failed: %v", err)
+	}
+
+	fmt.Printf("Restored backup created at %s", manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+	if manifest.DeviceID != "" {
+		fmt.Printf(" for device %s", manifest.DeviceID)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,82 @@
+package analytics
+
+// Request and Response structures for conversation analytics
+
+const (
+	GranularityDay  = "day"
+	GranularityHour = "hour"
+
+	PeriodToday      = "today"
+	PeriodYesterday  = "yesterday"
+	PeriodLast7Days  = "last_7_days"
+	PeriodLastWeek   = "last_week"
+	PeriodThisWeek   = "this_week"
+	PeriodLast30Days = "last_30_days"
+	PeriodThisMonth  = "this_month"
+	PeriodLastMonth  = "last_month"
+)
+
+type GetAnalyticsRequest struct {
+	ChatJID     string  `json:"chat_jid" query:"chat_jid"`
+	Period      string  `json:"period" query:"period"`
+	StartTime   *string `json:"start_time" query:"start_time"`
+	EndTime     *string `json:"end_time" query:"end_time"`
+	Granularity string  `json:"granularity" query:"granularity"`
+	Limit       int     `json:"limit" query:"limit"`
+}
+
+type GetAnalyticsResponse struct {
+	Range        AnalyticsRange   `json:"range"`
+	Totals       MessageTotals    `json:"totals"`
+	Volume       []VolumeBucket   `json:"volume"`
+	TopChats     []ChatActivity   `json:"top_chats"`
+	TopSenders   []SenderActivity `json:"top_senders"`
+	ResponseTime ResponseTime     `json:"response_time"`
+	Media        []MediaVolume    `json:"media"`
+}
+
+type AnalyticsRange struct {
+	ChatJID     string `json:"chat_jid,omitempty"`
+	Period      string `json:"period,omitempty"`
+	StartTime   string `json:"start_time,omitempty"`
+	EndTime     string `json:"end_time,omitempty"`
+	Granularity string `json:"granularity"`
+}
+
+type MessageTotals struct {
+	Total    int64 `json:"total"`
+	Inbound  int64 `json:"inbound"`
+	Outbound int64 `json:"outbound"`
+}
+
+type VolumeBucket struct {
+	Bucket   string `json:"bucket"`
+	Total    int64  `json:"total"`
+	Inbound  int64  `json:"inbound"`
+	Outbound int64  `json:"outbound"`
+}
+
+type ChatActivity struct {
+	ChatJID  string `json:"chat_jid"`
+	Name     string `json:"name"`
+	Total    int64  `json:"total"`
+	Inbound  int64  `json:"inbound"`
+	Outbound int64  `json:"outbound"`
+}
+
+type SenderActivity struct {
+	Sender       string `json:"sender"`
+	MessageCount int64  `json:"message_count"`
+}
+
+type ResponseTime struct {
+	Samples        int     `json:"samples"`
+	MedianSeconds  float64 `json:"median_seconds"`
+	AverageSeconds float64 `json:"average_seconds"`
+}
+
+type MediaVolume struct {
+	MediaType  string `json:"media_type"`
+	Count      int64  `json:"count"`
+	TotalBytes int64  `json:"total_bytes"`
+}
@@ -0,0 +1,10 @@
+package analytics
+
+import (
+	"context"
+)
+
+// IAnalyticsUsecase defines the interface for conversation analytics
+type IAnalyticsUsecase interface {
+	GetAnalytics(ctx context.Context, request GetAnalyticsRequest) (response GetAnalyticsResponse, err error)
+}
@@ -12,6 +12,8 @@ type IAppUsecase interface {
 	Reconnect(ctx context.Context) (err error)
 	FirstDevice(ctx context.Context) (response DevicesResponse, err error)
 	FetchDevices(ctx context.Context) (response []DevicesResponse, err error)
+	Backup(ctx context.Context, request BackupRequest) (response BackupResponse, err error)
+	Restore(ctx context.Context, request RestoreRequest) (response BackupManifest, err error)
 }
 
 type DevicesResponse struct {
@@ -24,3 +26,76 @@ type LoginResponse struct {
 	Duration  time.Duration `json:"duration"`
 	Code      string        `json:"code"`
 }
+
+// Steps of a login streamed by LoginEvent
+const (
+	LoginEventCode    = "code"    // a new QR code to scan, replacing the previous one
+	LoginEventTimeout = "timeout" // no code was scanned in time, a new login must be started
+	LoginEventSuccess = "success" // the device was paired
+	LoginEventError   = "error"   // pairing failed
+)
+
+// LoginEvent is a step of a login, pushed over the websocket and the login events stream
+type LoginEvent struct {
+	Event     string    `json:"event"`
+	SessionID string    `json:"session_id"`
+	Code      string    `json:"code,omitempty"`      // raw QR code content
+	Image     string    `json:"image,omitempty"`     // QR code as a PNG data URI
+	Timeout   int       `json:"timeout,omitempty"`   // seconds until the next code replaces this one
+	DeviceID  string    `json:"device_id,omitempty"` // device paired on success
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// States of the WhatsApp connection recorded by ConnectionState
+const (
+	ConnectionStateConnected    = "connected"
+	ConnectionStateUnstable     = "unstable"      // keepalive pings fail, the connection may be dead
+	ConnectionStateDisconnected = "disconnected"  // dropped, a reconnect is scheduled unless it cannot help
+	ConnectionStateReconnecting = "reconnecting"  // waiting for the backoff before the next attempt
+	ConnectionStateTemporaryBan = "temporary_ban" // reconnecting once the ban expires
+	ConnectionStateReplaced     = "stream_replaced"
+	ConnectionStateLoggedOut    = "logged_out"
+)
+
+// ConnectionState is a transition of the connection of a session
+type ConnectionState struct {
+	State     string     `json:"state"`
+	Reason    string     `json:"reason,omitempty"`
+	Attempt   int        `json:"attempt,omitempty"`  // reconnect attempt since the last successful connection
+	RetryAt   *time.Time `json:"retry_at,omitempty"` // when the next reconnect is attempted
+	Timestamp time.Time  `json:"timestamp"`
+}
+
+type BackupRequest struct {
+	IncludeMedia bool `json:"include_media" form:"include_media" query:"include_media"`
+}
+
+type BackupResponse struct {
+	Filename string         `json:"filename"`
+	Path     string         `json:"path"`
+	Size     int64          `json:"size"`
+	Manifest BackupManifest `json:"manifest"`
+}
+
+type RestoreRequest struct {
+	ArchivePath  string `json:"archive_path"`
+	IncludeMedia bool   `json:"include_media"`
+	Force        bool   `json:"force"`
+}
+
+// BackupManifest describes the content of a backup archive
+type BackupManifest struct {
+	FormatVersion int                  `json:"format_version"`
+	AppVersion    string               `json:"app_version"`
+	CreatedAt     time.Time            `json:"created_at"`
+	DeviceID      string               `json:"device_id,omitempty"`
+	Files         []BackupManifestFile `json:"files"`
+}
+
+type BackupManifestFile struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
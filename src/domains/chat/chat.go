@@ -7,6 +7,8 @@ type ListChatsRequest struct {
 	Offset   int    `json:"offset" query:"offset"`
 	Search   string `json:"search" query:"search"`
 	HasMedia bool   `json:"has_media" query:"has_media"`
+	Before   string `json:"before" query:"before"`
+	After    string `json:"after" query:"after"`
 }
 
 type ListChatsResponse struct {
@@ -23,6 +25,8 @@ type GetChatMessagesRequest struct {
 	MediaOnly bool    `json:"media_only" query:"media_only"`
 	IsFromMe  *bool   `json:"is_from_me" query:"is_from_me"`
 	Search    string  `json:"search" query:"search"`
+	Before    string  `json:"before" query:"before"`
+	After     string  `json:"after" query:"after"`
 }
 
 type GetChatMessagesResponse struct {
@@ -45,12 +49,29 @@ type PinChatResponse struct {
 }
 
 type ChatInfo struct {
-	JID                 string `json:"jid"`
-	Name                string `json:"name"`
-	LastMessageTime     string `json:"last_message_time"`
-	EphemeralExpiration uint32 `json:"ephemeral_expiration"`
-	CreatedAt           string `json:"created_at"`
-	UpdatedAt           string `json:"updated_at"`
+	JID                 string           `json:"jid"`
+	Name                string           `json:"name"`
+	LastMessageTime     string           `json:"last_message_time"`
+	EphemeralExpiration uint32           `json:"ephemeral_expiration"`
+	CreatedAt           string           `json:"created_at"`
+	UpdatedAt           string           `json:"updated_at"`
+	UnreadCount         int              `json:"unread_count"`
+	Archived            bool             `json:"archived"`
+	Pinned              bool             `json:"pinned"`
+	Muted               bool             `json:"muted"`
+	MutedUntil          string           `json:"muted_until,omitempty"`
+	AvatarURL           string           `json:"avatar_url,omitempty"`
+	LID                 string           `json:"lid,omitempty"`
+	LastMessage         *LastMessageInfo `json:"last_message,omitempty"`
+}
+
+type LastMessageInfo struct {
+	ID        string `json:"id"`
+	SenderJID string `json:"sender_jid"`
+	IsFromMe  bool   `json:"is_from_me"`
+	Type      string `json:"type"`
+	Preview   string `json:"preview"`
+	Timestamp string `json:"timestamp"`
 }
 
 type MessageInfo struct {
@@ -69,7 +90,9 @@ type MessageInfo struct {
 }
 
 type PaginationResponse struct {
-	Limit  int `json:"limit"`
-	Offset int `json:"offset"`
-	Total  int `json:"total"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"` // pass as `before` to fetch older items
+	PrevCursor string `json:"prev_cursor,omitempty"` // pass as `after` to fetch newer items
 }
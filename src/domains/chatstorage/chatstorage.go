@@ -4,12 +4,23 @@ import "time"
 
 // Chat represents a WhatsApp chat/conversation
 type Chat struct {
-	JID                 string    `db:"jid"`
-	Name                string    `db:"name"`
-	LastMessageTime     time.Time `db:"last_message_time"`
-	EphemeralExpiration uint32    `db:"ephemeral_expiration"`
-	CreatedAt           time.Time `db:"created_at"`
-	UpdatedAt           time.Time `db:"updated_at"`
+	JID                 string     `db:"jid"`
+	Name                string     `db:"name"`
+	LastMessageTime     time.Time  `db:"last_message_time"`
+	EphemeralExpiration uint32     `db:"ephemeral_expiration"`
+	CreatedAt           time.Time  `db:"created_at"`
+	UpdatedAt           time.Time  `db:"updated_at"`
+	UnreadCount         int        `db:"unread_count"`
+	Archived            bool       `db:"archived"`
+	Pinned              bool       `db:"pinned"`
+	Muted               bool       `db:"muted"`
+	MutedUntil          *time.Time `db:"muted_until"`
+	AvatarURL           string     `db:"avatar_url"`
+	LID                 string     `db:"lid"`        // LID of a user chat stored under its phone number JID
+	SessionID           string     `db:"session_id"` // session owning this copy of the chat, the default session when empty
+
+	// LastMessage is only populated by GetChats
+	LastMessage *Message `db:"-"`
 }
 
 // Message represents a WhatsApp message
@@ -29,6 +40,7 @@ type Message struct {
 	FileLength    uint64    `db:"file_length"`
 	CreatedAt     time.Time `db:"created_at"`
 	UpdatedAt     time.Time `db:"updated_at"`
+	SessionID     string    `db:"session_id"` // session that received or sent the message, the default session when empty
 }
 
 // MediaInfo represents downloadable media information
@@ -53,6 +65,9 @@ type MessageFilter struct {
 	EndTime   *time.Time
 	MediaOnly bool
 	IsFromMe  *bool
+	Before    *PageCursor
+	After     *PageCursor
+	SessionID string // only messages of this session, all sessions when empty
 }
 
 // ChatFilter represents query filters for chats
@@ -61,4 +76,386 @@ type ChatFilter struct {
 	Offset     int
 	SearchName string
 	HasMedia   bool
+	Before     *PageCursor
+	After      *PageCursor
+	SessionID  string // only chats of this session, all sessions when empty
+}
+
+// PageCursor marks a position in a list ordered by timestamp, with the ID breaking ties.
+// Before selects older rows than the cursor and After selects newer ones.
+type PageCursor struct {
+	Timestamp time.Time
+	ID        string
+}
+
+// AnalyticsFilter represents query filters for message analytics
+type AnalyticsFilter struct {
+	ChatJID   string
+	StartTime *time.Time
+	EndTime   *time.Time
+	Limit     int
+	SessionID string // only messages of this session, all sessions when empty
+}
+
+// MessageVolume represents message counts within a time bucket
+type MessageVolume struct {
+	Bucket   string `db:"bucket"`
+	Total    int64  `db:"total"`
+	Inbound  int64  `db:"inbound"`
+	Outbound int64  `db:"outbound"`
+}
+
+// ChatActivity represents message counts for a single chat
+type ChatActivity struct {
+	ChatJID  string `db:"chat_jid"`
+	Name     string `db:"name"`
+	Total    int64  `db:"total"`
+	Inbound  int64  `db:"inbound"`
+	Outbound int64  `db:"outbound"`
+}
+
+// SenderActivity represents inbound message counts for a single sender
+type SenderActivity struct {
+	Sender       string `db:"sender"`
+	MessageCount int64  `db:"message_count"`
+}
+
+// MediaVolume represents media counts and sizes for a media type
+type MediaVolume struct {
+	MediaType  string `db:"media_type"`
+	Count      int64  `db:"count"`
+	TotalBytes int64  `db:"total_bytes"`
+}
+
+// ResponseTimeStats summarises the first-response delays of inbound message runs
+type ResponseTimeStats struct {
+	Samples        int     `db:"samples"`
+	MedianSeconds  float64 `db:"median_seconds"`
+	AverageSeconds float64 `db:"average_seconds"`
+}
+
+// Group event types recorded in the group event log
+const (
+	GroupEventJoin               = "join"   // participant joined on their own (e.g. invite link)
+	GroupEventAdd                = "add"    // participant was added by an admin
+	GroupEventLeave              = "leave"  // participant left on their own
+	GroupEventRemove             = "remove" // participant was removed by an admin
+	GroupEventPromote            = "promote"
+	GroupEventDemote             = "demote"
+	GroupEventName               = "name"
+	GroupEventTopic              = "topic"
+	GroupEventLocked             = "locked"
+	GroupEventAnnounce           = "announce"
+	GroupEventEphemeral          = "ephemeral"
+	GroupEventMembershipApproval = "membership_approval"
+	GroupEventInviteLink         = "invite_link"
+	GroupEventLink               = "link"
+	GroupEventUnlink             = "unlink"
+	GroupEventDelete             = "delete"
+)
+
+// GroupEvent represents a single membership or setting change of a group
+type GroupEvent struct {
+	ID          int64     `db:"id"`
+	GroupJID    string    `db:"group_jid"`
+	EventType   string    `db:"event_type"`
+	Participant string    `db:"participant_jid"` // affected member, empty for setting changes
+	Actor       string    `db:"actor_jid"`       // who made the change, empty when unknown
+	Value       string    `db:"value"`           // new setting value, empty for membership changes
+	Timestamp   time.Time `db:"timestamp"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// GroupEventFilter represents query filters for the group event log
+type GroupEventFilter struct {
+	GroupJID    string
+	Participant string
+	Actor       string
+	EventTypes  []string
+	StartTime   *time.Time
+	EndTime     *time.Time
+	Limit       int
+	Offset      int
+}
+
+// GroupMembershipDay represents membership changes of a group within one day
+type GroupMembershipDay struct {
+	Day    string `db:"day"`
+	Joined int64  `db:"joined"`
+	Left   int64  `db:"left"`
+}
+
+// Group participant import statuses
+const (
+	GroupImportRunning   = "running"
+	GroupImportCompleted = "completed"
+	GroupImportFailed    = "failed"
+
+	GroupImportEntryPending       = "pending"
+	GroupImportEntryNotOnWhatsApp = "not_on_whatsapp"
+	GroupImportEntryAdded         = "added"
+	GroupImportEntryAlreadyMember = "already_member"
+	GroupImportEntryInvited       = "invited" // could not be added directly, invite sent by DM
+	GroupImportEntryJoined        = "joined"  // accepted the invite after it was sent
+	GroupImportEntryFailed        = "failed"
+)
+
+// GroupImport represents a bulk participant import into a group
+type GroupImport struct {
+	ID         string     `db:"id"`
+	GroupJID   string     `db:"group_jid"`
+	Status     string     `db:"status"`
+	Error      string     `db:"error"`
+	Total      int        `db:"total"`
+	CreatedAt  time.Time  `db:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at"`
+	FinishedAt *time.Time `db:"finished_at"`
+}
+
+// GroupImportEntry represents the outcome for a single phone number of an import
+type GroupImportEntry struct {
+	ImportID  string     `db:"import_id"`
+	Phone     string     `db:"phone"`
+	JID       string     `db:"jid"`
+	Status    string     `db:"status"`
+	Message   string     `db:"message"`
+	InvitedAt *time.Time `db:"invited_at"`
+	JoinedAt  *time.Time `db:"joined_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+}
+
+// Group moderation rule types and actions
+const (
+	ModerationRuleLinks          = "links"
+	ModerationRuleBannedWords    = "banned_words"
+	ModerationRuleRateLimit      = "rate_limit"
+	ModerationRuleForwardedMedia = "forwarded_media"
+
+	ModerationActionWarn   = "warn"
+	ModerationActionDelete = "delete" // revoke the message for everyone
+	ModerationActionRemove = "remove" // remove the sender from the group
+
+	ModerationAuditSuccess = "success"
+	ModerationAuditFailed  = "failed"
+)
+
+// ModerationRule represents a moderation rule evaluated against incoming group messages
+type ModerationRule struct {
+	ID          int64     `db:"id"`
+	GroupJID    string    `db:"group_jid"`
+	Type        string    `db:"type"`
+	Words       []string  `db:"words"`        // banned words, only for banned_words rules
+	Limit       int       `db:"rate_limit"`   // messages per member per minute, only for rate_limit rules
+	Actions     []string  `db:"actions"`      // actions taken on every violation
+	RemoveAfter int       `db:"remove_after"` // remove the sender after this many violations, 0 disables
+	Enabled     bool      `db:"enabled"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// ModerationAudit represents one action taken by the moderation engine
+type ModerationAudit struct {
+	ID        int64     `db:"id"`
+	GroupJID  string    `db:"group_jid"`
+	RuleID    int64     `db:"rule_id"`
+	RuleType  string    `db:"rule_type"`
+	SenderJID string    `db:"sender_jid"`
+	MessageID string    `db:"message_id"`
+	Action    string    `db:"action"`
+	Status    string    `db:"status"`
+	Error     string    `db:"error"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// ModerationAuditFilter represents query filters for the moderation audit trail
+type ModerationAuditFilter struct {
+	GroupJID  string
+	SenderJID string
+	RuleID    int64
+	Limit     int
+	Offset    int
+}
+
+// GroupGreeting holds the welcome, goodbye and onboarding messages of a group
+type GroupGreeting struct {
+	GroupJID          string    `db:"group_jid"`
+	WelcomeEnabled    bool      `db:"welcome_enabled"`
+	WelcomeTemplate   string    `db:"welcome_template"`
+	GoodbyeEnabled    bool      `db:"goodbye_enabled"`
+	GoodbyeTemplate   string    `db:"goodbye_template"`
+	DMEnabled         bool      `db:"dm_enabled"`
+	DMTemplate        string    `db:"dm_template"`
+	BatchSeconds      int       `db:"batch_seconds"`       // how long joins and leaves are collected before greeting them at once
+	RulesDocumentName string    `db:"rules_document_name"` // empty when no rules document is attached
+	RulesDocument     []byte    `db:"rules_document"`
+	CreatedAt         time.Time `db:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at"`
+}
+
+// BlockedContact is a contact on the account's block list, mirrored from WhatsApp
+type BlockedContact struct {
+	JID       string    `db:"jid"`
+	BlockedAt time.Time `db:"blocked_at"` // when the block was first seen, not necessarily when it was made
+}
+
+// NumberCheck is the cached outcome of checking whether a phone number is on WhatsApp
+type NumberCheck struct {
+	Phone        string    `db:"phone"` // digits only, with country code
+	IsOnWhatsApp bool      `db:"is_on_whatsapp"`
+	JID          string    `db:"jid"`
+	LID          string    `db:"lid"`
+	IsBusiness   bool      `db:"is_business"`
+	BusinessName string    `db:"business_name"`
+	CheckedAt    time.Time `db:"checked_at"`
+}
+
+// PresenceSubscription is a contact whose presence updates are requested again after every reconnect
+type PresenceSubscription struct {
+	JID          string    `db:"jid"`
+	SubscribedAt time.Time `db:"subscribed_at"`
+}
+
+// ContactPresence is the last known presence of a contact
+type ContactPresence struct {
+	JID       string     `db:"jid"`
+	Online    bool       `db:"online"`
+	LastSeen  *time.Time `db:"last_seen"` // nil when unknown or hidden by the contact's privacy settings
+	UpdatedAt time.Time  `db:"updated_at"`
+}
+
+// PresenceEvent is a single online or offline change of a contact
+type PresenceEvent struct {
+	ID        int64      `db:"id"`
+	JID       string     `db:"jid"`
+	Online    bool       `db:"online"`
+	LastSeen  *time.Time `db:"last_seen"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// AddressBookContact is the team's own record of a contact or group: a custom name that takes
+// precedence over push names, tags usable as send recipients, notes and free-form fields
+type AddressBookContact struct {
+	JID          string            `db:"jid"`
+	CustomName   string            `db:"custom_name"`
+	Notes        string            `db:"notes"`
+	Tags         []string          `db:"-"` // lower case, stored in address_book_tags
+	CustomFields map[string]string `db:"custom_fields"`
+	CreatedAt    time.Time         `db:"created_at"`
+	UpdatedAt    time.Time         `db:"updated_at"`
+}
+
+// AddressBookFilter selects address book contacts. Search matches the name, JID, notes and tags.
+type AddressBookFilter struct {
+	Search string
+	Tag    string
+	Limit  int
+	Offset int
+}
+
+// Bulk number check job and entry statuses
+const (
+	NumberCheckJobRunning   = "running"
+	NumberCheckJobCompleted = "completed"
+	NumberCheckJobFailed    = "failed"
+
+	NumberCheckEntryPending = "pending"
+	NumberCheckEntryChecked = "checked"
+	NumberCheckEntryFailed  = "failed"
+)
+
+// NumberCheckJob represents a bulk check of phone numbers running in the background
+type NumberCheckJob struct {
+	ID         string     `db:"id"`
+	Status     string     `db:"status"`
+	Error      string     `db:"error"`
+	Total      int        `db:"total"`
+	Checked    int        `db:"checked"` // entries that are no longer pending
+	CreatedAt  time.Time  `db:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at"`
+	FinishedAt *time.Time `db:"finished_at"`
+}
+
+// NumberCheckJobEntry is the outcome for a single phone number of a bulk check
+type NumberCheckJobEntry struct {
+	NumberCheck
+	JobID    string `db:"job_id"`
+	Position int    `db:"position"`
+	Status   string `db:"status"`
+	Message  string `db:"message"`
+	Cached   bool   `db:"cached"` // answered from the cache instead of asking WhatsApp
+}
+
+// Session maps a session of the session manager to the WhatsApp device it is paired with
+type Session struct {
+	ID        string    `db:"id"`
+	DeviceJID string    `db:"device_jid"` // empty until the session is paired
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"   // waiting for its next attempt
+	WebhookDeliveryDelivered = "delivered" // accepted by the receiver with a 2xx response
+	WebhookDeliveryDead      = "dead"      // gave up after the maximum attempts, kept until replayed
+)
+
+// WebhookDelivery is one event queued in the webhook outbox for one webhook URL
+type WebhookDelivery struct {
+	ID             int64      `db:"id"`
+	SessionID      string     `db:"session_id"`
+	Event          string     `db:"event"`
+	URL            string     `db:"url"`
+	Payload        string     `db:"payload"` // JSON body as sent, signed on every attempt
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	MaxAttempts    int        `db:"max_attempts"` // raised by a replay to allow new attempts
+	NextAttemptAt  time.Time  `db:"next_attempt_at"`
+	LastStatusCode int        `db:"last_status_code"` // 0 when no response was received
+	LastError      string     `db:"last_error"`
+	CreatedAt      time.Time  `db:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+}
+
+// WebhookDeliveryAttempt is the outcome of one attempt to deliver a webhook
+type WebhookDeliveryAttempt struct {
+	ID          int64     `db:"id"`
+	DeliveryID  int64     `db:"delivery_id"`
+	Attempt     int       `db:"attempt"`
+	StatusCode  int       `db:"status_code"` // 0 when no response was received
+	Error       string    `db:"error"`
+	DurationMs  int64     `db:"duration_ms"`
+	AttemptedAt time.Time `db:"attempted_at"`
+}
+
+// WebhookDeliveryFilter selects webhook deliveries, the newest first
+type WebhookDeliveryFilter struct {
+	SessionID string
+	Status    string
+	Event     string
+	Limit     int
+	Offset    int
+}
+
+// Chat types a webhook definition can be limited to
+const (
+	WebhookChatAll     = "all"
+	WebhookChatPrivate = "private"
+	WebhookChatGroup   = "group"
+)
+
+// WebhookDefinition is a webhook URL with the events and chats it subscribes to
+type WebhookDefinition struct {
+	ID            int64     `db:"id"`
+	Name          string    `db:"name"`
+	URL           string    `db:"url"`
+	Events        []string  `db:"events"`        // event types delivered, every event when empty
+	IncludeChats  []string  `db:"include_chats"` // chat or sender JIDs delivered, every chat when empty
+	ExcludeChats  []string  `db:"exclude_chats"` // chat or sender JIDs never delivered
+	IncludeFromMe bool      `db:"include_from_me"`
+	ChatType      string    `db:"chat_type"`
+	Enabled       bool      `db:"enabled"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
 }
@@ -12,26 +12,132 @@ type IChatStorageRepository interface {
 	// Chat operations
 	CreateMessage(ctx context.Context, evt *events.Message) error
 	StoreChat(chat *Chat) error
-	GetChat(jid string) (*Chat, error)
+	// Chats and messages are stored per session, operations taking a context act on the session it selects
+	GetChat(ctx context.Context, jid string) (*Chat, error)
 	GetChats(filter *ChatFilter) ([]*Chat, error)
-	DeleteChat(jid string) error
+	DeleteChat(ctx context.Context, jid string) error
+	SetChatUnreadCount(ctx context.Context, jid string, count int) error
+	MarkChatAsRead(ctx context.Context, jid string, readAt time.Time) error
+	SetChatArchived(ctx context.Context, jid string, archived bool) error
+	SetChatPinned(ctx context.Context, jid string, pinned bool) error
+	SetChatMuted(ctx context.Context, jid string, muted bool, mutedUntil *time.Time) error
+	SetChatAvatarURL(ctx context.Context, jid string, avatarURL string) error
 
 	// Message operations
 	StoreMessage(message *Message) error
 	StoreMessagesBatch(messages []*Message) error
-	GetMessageByID(id string) (*Message, error) // New method for efficient ID-only search
+	GetMessageByID(ctx context.Context, id string) (*Message, error) // New method for efficient ID-only search
 	GetMessages(filter *MessageFilter) ([]*Message, error)
-	SearchMessages(chatJID, searchText string, limit int) ([]*Message, error) // Database-level search
-	DeleteMessage(id, chatJID string) error
+	SearchMessages(ctx context.Context, chatJID, searchText string, limit int) ([]*Message, error) // Database-level search
+	DeleteMessage(ctx context.Context, id, chatJID string) error
 	StoreSentMessageWithContext(ctx context.Context, messageID string, senderJID string, recipientJID string, content string, timestamp time.Time) error
 
 	// Statistics
-	GetChatMessageCount(chatJID string) (int64, error)
+	GetChatMessageCount(ctx context.Context, chatJID string) (int64, error)
 	GetTotalMessageCount() (int64, error)
 	GetTotalChatCount() (int64, error)
-	GetChatNameWithPushName(jid types.JID, chatJID string, senderUser string, pushName string) string
+	GetChatNameWithPushName(ctx context.Context, jid types.JID, chatJID string, senderUser string, pushName string) string
 	GetStorageStatistics() (chatCount int64, messageCount int64, err error)
 
+	// Analytics
+	GetMessageVolume(filter *AnalyticsFilter, bucketFormat string) ([]*MessageVolume, error)
+	GetTopChats(filter *AnalyticsFilter) ([]*ChatActivity, error)
+	GetTopSenders(filter *AnalyticsFilter) ([]*SenderActivity, error)
+	GetMediaVolume(filter *AnalyticsFilter) ([]*MediaVolume, error)
+	GetFirstResponseTimes(filter *AnalyticsFilter) (*ResponseTimeStats, error)
+
+	// Group event log of the session selected by the context
+	StoreGroupEvents(ctx context.Context, events []*GroupEvent) error
+	GetGroupEvents(ctx context.Context, filter *GroupEventFilter) ([]*GroupEvent, error)
+	CountGroupEvents(ctx context.Context, filter *GroupEventFilter) (int64, error)
+	GetGroupMembershipDaily(ctx context.Context, groupJID string, startTime, endTime *time.Time) ([]*GroupMembershipDay, error)
+
+	// Group participant imports, created and looked up in the session selected by the context
+	CreateGroupImport(ctx context.Context, groupImport *GroupImport, entries []*GroupImportEntry) error
+	UpdateGroupImportEntry(entry *GroupImportEntry) error
+	FinishGroupImport(id, status, errMessage string) error
+	FailRunningGroupImports(errMessage string) (int64, error)
+	GetGroupImport(ctx context.Context, id string) (*GroupImport, error)
+	GetGroupImportEntries(importID string) ([]*GroupImportEntry, error)
+	MarkGroupImportJoined(ctx context.Context, groupJID string, participantJIDs []string, joinedAt time.Time) (int64, error)
+
+	// Group moderation of the session selected by the context
+	StoreModerationRule(ctx context.Context, rule *ModerationRule) error
+	GetModerationRule(ctx context.Context, id int64) (*ModerationRule, error)
+	GetModerationRules(ctx context.Context, groupJID string) ([]*ModerationRule, error)
+	DeleteModerationRule(ctx context.Context, id int64) error
+	StoreModerationAudit(ctx context.Context, entry *ModerationAudit) error
+	GetModerationAudit(ctx context.Context, filter *ModerationAuditFilter) ([]*ModerationAudit, error)
+	CountModerationAudit(ctx context.Context, filter *ModerationAuditFilter) (int64, error)
+	CountModerationViolations(ctx context.Context, ruleID int64, senderJID string) (int64, error)
+
+	// Group greetings of the session selected by the context
+	StoreGroupGreeting(ctx context.Context, greeting *GroupGreeting) error
+	GetGroupGreeting(ctx context.Context, groupJID string) (*GroupGreeting, error)
+	GetGroupGreetings(ctx context.Context) ([]*GroupGreeting, error)
+	DeleteGroupGreeting(ctx context.Context, groupJID string) error
+
+	// Block list mirror of the session selected by the context
+	StoreBlockedContact(ctx context.Context, jid string, blockedAt time.Time) error
+	DeleteBlockedContact(ctx context.Context, jid string) error
+	ReplaceBlockedContacts(ctx context.Context, jids []string, blockedAt time.Time) error
+	GetBlockedContacts(ctx context.Context) ([]*BlockedContact, error)
+	IsContactBlocked(ctx context.Context, jids ...string) (bool, error)
+
+	// Number check cache and bulk check jobs
+	StoreNumberChecks(checks []*NumberCheck) error
+	GetNumberChecks(phones []string, checkedSince time.Time) (map[string]*NumberCheck, error)
+	DeleteNumberChecksBefore(checkedBefore time.Time) (int64, error)
+	CreateNumberCheckJob(job *NumberCheckJob, phones []string) error
+	UpdateNumberCheckJobEntries(jobID string, entries []*NumberCheckJobEntry) error
+	FinishNumberCheckJob(id, status, errMessage string) error
+	FailRunningNumberCheckJobs(errMessage string) (int64, error)
+	GetNumberCheckJob(id string) (*NumberCheckJob, error)
+	GetNumberCheckJobEntries(jobID string) ([]*NumberCheckJobEntry, error)
+
+	// Presence subscriptions of the session selected by the context, and presence history
+	StorePresenceSubscriptions(ctx context.Context, jids []string, subscribedAt time.Time) error
+	DeletePresenceSubscriptions(ctx context.Context, jids []string) error
+	GetPresenceSubscriptions(ctx context.Context) ([]*PresenceSubscription, error)
+	StorePresence(presence *ContactPresence) error
+	GetContactPresences(jids []string) (map[string]*ContactPresence, error)
+	GetPresenceHistory(jid string, limit int) ([]*PresenceEvent, error)
+	DeletePresenceHistoryBefore(before time.Time) (int64, error)
+
+	// Address book of the session selected by the context
+	StoreAddressBookContact(ctx context.Context, contact *AddressBookContact) error
+	GetAddressBookContact(ctx context.Context, jid string) (*AddressBookContact, error)
+	GetAddressBookContacts(ctx context.Context, filter *AddressBookFilter) ([]*AddressBookContact, error)
+	CountAddressBookContacts(ctx context.Context, filter *AddressBookFilter) (int64, error)
+	DeleteAddressBookContact(ctx context.Context, jid string) error
+	GetAddressBookJIDsByTag(ctx context.Context, tag string) ([]string, error)
+	GetCustomName(ctx context.Context, jid string) string
+
+	// Sessions of the session manager
+	StoreSession(session *Session) error
+	GetSessions() ([]*Session, error)
+	DeleteSession(id string) error
+
+	// Webhook outbox
+	StoreWebhookDeliveries(deliveries []*WebhookDelivery) error
+	GetDueWebhookDeliveries(now time.Time, limit int) ([]*WebhookDelivery, error)
+	RecordWebhookDeliveryAttempt(delivery *WebhookDelivery, attempt *WebhookDeliveryAttempt) error
+	GetWebhookDelivery(id int64) (*WebhookDelivery, error)
+	GetWebhookDeliveries(filter *WebhookDeliveryFilter) ([]*WebhookDelivery, error)
+	CountWebhookDeliveries(filter *WebhookDeliveryFilter) (int64, error)
+	GetWebhookDeliveryAttempts(deliveryIDs []int64) (map[int64][]*WebhookDeliveryAttempt, error)
+	ReplayWebhookDelivery(id int64, extraAttempts int, now time.Time) (*WebhookDelivery, error)
+	DeleteWebhookDeliveriesBefore(before time.Time) (int64, error)
+
+	// Webhook definitions
+	StoreWebhookDefinition(definition *WebhookDefinition) error
+	GetWebhookDefinition(id int64) (*WebhookDefinition, error)
+	GetWebhookDefinitions() ([]*WebhookDefinition, error)
+	DeleteWebhookDefinition(id int64) error
+
+	// Identity operations
+	MergeLIDChats() (int, error)
+
 	// Cleanup operations
 	TruncateAllChats() error
 	TruncateAllDataWithLogging(logPrefix string) error
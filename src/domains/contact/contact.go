@@ -0,0 +1,95 @@
+package contact
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+// Export formats
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatVCard = "vcard"
+)
+
+type Contact struct {
+	JID          string            `json:"jid"`
+	Phone        string            `json:"phone,omitempty"` // empty for groups
+	Name         string            `json:"name"`
+	PushName     string            `json:"push_name,omitempty"` // the name the contact gave itself on WhatsApp
+	Tags         []string          `json:"tags"`
+	Notes        string            `json:"notes"`
+	CustomFields map[string]string `json:"custom_fields"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// ContactRequest creates an address book entry for a user or group, given by phone number or JID.
+// Tags are stored in lower case and can be used as send recipients with "tag:<name>".
+type ContactRequest struct {
+	Phone        string            `json:"phone" form:"phone"`
+	Name         string            `json:"name" form:"name"`
+	Tags         []string          `json:"tags" form:"tags"`
+	Notes        string            `json:"notes" form:"notes"`
+	CustomFields map[string]string `json:"custom_fields" form:"custom_fields"`
+}
+
+// UpdateContactRequest changes the fields that are given and keeps the others. Custom fields are
+// merged into the existing ones; a field set to an empty value is removed.
+type UpdateContactRequest struct {
+	Phone        string            `json:"phone" uri:"phone"`
+	Name         *string           `json:"name" form:"name"`
+	Tags         *[]string         `json:"tags" form:"tags"`
+	Notes        *string           `json:"notes" form:"notes"`
+	CustomFields map[string]string `json:"custom_fields" form:"custom_fields"`
+}
+
+type GetContactRequest struct {
+	Phone string `json:"phone" uri:"phone"`
+}
+
+type ListContactsRequest struct {
+	Search string `json:"search" query:"search"`
+	Tag    string `json:"tag" query:"tag"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+type ContactPagination struct {
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+	Total  int64 `json:"total"`
+}
+
+type ListContactsResponse struct {
+	Data       []Contact         `json:"data"`
+	Pagination ContactPagination `json:"pagination"`
+}
+
+// ImportContactsRequest imports a CSV or vCard file. Existing entries are updated: imported names,
+// notes and custom fields replace the stored ones and tags are added. Tags are applied to every
+// imported contact.
+type ImportContactsRequest struct {
+	File *multipart.FileHeader `json:"file" form:"file"`
+	Tags []string              `json:"tags" form:"tags"`
+}
+
+type ImportContactsResponse struct {
+	Created int      `json:"created"`
+	Updated int      `json:"updated"`
+	Skipped int      `json:"skipped"`
+	Errors  []string `json:"errors,omitempty"` // one per skipped record
+}
+
+// ExportContactsRequest exports the address book, or the part matching the tag or search
+type ExportContactsRequest struct {
+	Format string `json:"format" query:"format"` // csv (default) or vcard
+	Tag    string `json:"tag" query:"tag"`
+	Search string `json:"search" query:"search"`
+}
+
+type ExportContactsResponse struct {
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"content"`
+	Total       int    `json:"total"`
+}
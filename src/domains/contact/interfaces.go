@@ -0,0 +1,26 @@
+package contact
+
+import (
+	"context"
+)
+
+// IContactBook manages the address book entries
+type IContactBook interface {
+	ListContacts(ctx context.Context, request ListContactsRequest) (response ListContactsResponse, err error)
+	GetContact(ctx context.Context, request GetContactRequest) (response Contact, err error)
+	CreateContact(ctx context.Context, request ContactRequest) (response Contact, err error)
+	UpdateContact(ctx context.Context, request UpdateContactRequest) (response Contact, err error)
+	DeleteContact(ctx context.Context, request GetContactRequest) (err error)
+}
+
+// IContactTransfer moves the address book in and out as CSV or vCard files
+type IContactTransfer interface {
+	ImportContacts(ctx context.Context, request ImportContactsRequest) (response ImportContactsResponse, err error)
+	ExportContacts(ctx context.Context, request ExportContactsRequest) (response ExportContactsResponse, err error)
+}
+
+// IContactUsecase combines all address book interfaces
+type IContactUsecase interface {
+	IContactBook
+	IContactTransfer
+}
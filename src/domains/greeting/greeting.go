@@ -0,0 +1,50 @@
+package greeting
+
+import "mime/multipart"
+
+type Greeting struct {
+	GroupID           string `json:"group_id"`
+	WelcomeEnabled    bool   `json:"welcome_enabled"`
+	WelcomeTemplate   string `json:"welcome_template"`
+	GoodbyeEnabled    bool   `json:"goodbye_enabled"`
+	GoodbyeTemplate   string `json:"goodbye_template"`
+	DMEnabled         bool   `json:"dm_enabled"`
+	DMTemplate        string `json:"dm_template"`
+	BatchSeconds      int    `json:"batch_seconds"`
+	RulesDocumentName string `json:"rules_document_name,omitempty"`
+	CreatedAt         string `json:"created_at"`
+	UpdatedAt         string `json:"updated_at"`
+}
+
+// SetGreetingRequest creates or replaces the greeting configuration of a group.
+//
+// Templates support the placeholders {user} (a mention of every member greeted at once),
+// {group} (the group name) and {count} (how many members joined or left). DMTemplate is
+// sent privately to each new member. Joins and leaves are collected for BatchSeconds before
+// a single message greets them all. An uploaded RulesDocument is sent after the welcome
+// message and the DM; the stored one is kept unless a new one is uploaded or
+// RemoveRulesDocument is set.
+type SetGreetingRequest struct {
+	GroupID             string                `json:"group_id" form:"group_id"`
+	WelcomeEnabled      bool                  `json:"welcome_enabled" form:"welcome_enabled"`
+	WelcomeTemplate     string                `json:"welcome_template" form:"welcome_template"`
+	GoodbyeEnabled      bool                  `json:"goodbye_enabled" form:"goodbye_enabled"`
+	GoodbyeTemplate     string                `json:"goodbye_template" form:"goodbye_template"`
+	DMEnabled           bool                  `json:"dm_enabled" form:"dm_enabled"`
+	DMTemplate          string                `json:"dm_template" form:"dm_template"`
+	BatchSeconds        *int                  `json:"batch_seconds" form:"batch_seconds"` // defaults to 30
+	RulesDocument       *multipart.FileHeader `json:"rules_document" form:"rules_document"`
+	RemoveRulesDocument bool                  `json:"remove_rules_document" form:"remove_rules_document"`
+}
+
+type GetGreetingRequest struct {
+	GroupID string `json:"group_id" query:"group_id"`
+}
+
+type DeleteGreetingRequest struct {
+	GroupID string `json:"group_id" form:"group_id"`
+}
+
+type ListGreetingsResponse struct {
+	Greetings []Greeting `json:"greetings"`
+}
@@ -0,0 +1,26 @@
+package greeting
+
+import (
+	"context"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+// IGreetingSettings manages the welcome and goodbye configuration of groups
+type IGreetingSettings interface {
+	ListGreetings(ctx context.Context) (response ListGreetingsResponse, err error)
+	GetGreeting(ctx context.Context, request GetGreetingRequest) (response Greeting, err error)
+	SetGreeting(ctx context.Context, request SetGreetingRequest) (response Greeting, err error)
+	DeleteGreeting(ctx context.Context, request DeleteGreetingRequest) (err error)
+}
+
+// IMembershipGreeter greets members joining or leaving a group
+type IMembershipGreeter interface {
+	GreetMembers(ctx context.Context, groupJID types.JID, joined, left []types.JID)
+}
+
+// IGreetingUsecase combines all greeting interfaces
+type IGreetingUsecase interface {
+	IGreetingSettings
+	IMembershipGreeter
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 )
 
 // NOTE: IGroupUsecase is now defined in interfaces.go with proper segregation
@@ -20,6 +21,7 @@ type LeaveGroupRequest struct {
 type CreateGroupRequest struct {
 	Title        string   `json:"title" form:"title"`
 	Participants []string `json:"participants" form:"participants"`
+	CommunityID  string   `json:"community_id,omitempty" form:"community_id"`
 }
 
 type ParticipantRequest struct {
@@ -68,9 +70,11 @@ type GroupRequestParticipantsRequest struct {
 	Action       whatsmeow.ParticipantRequestChange `json:"action" form:"action"`
 }
 
+// SetGroupPhotoRequest sets the group photo from an upload or an image URL; with neither the photo is removed
 type SetGroupPhotoRequest struct {
-	GroupID string                `json:"group_id" form:"group_id"`
-	Photo   *multipart.FileHeader `json:"photo" form:"photo"`
+	GroupID  string                `json:"group_id" form:"group_id"`
+	Photo    *multipart.FileHeader `json:"photo" form:"photo"`
+	PhotoURL string                `json:"photo_url" form:"photo_url"`
 }
 
 type SetGroupPhotoResponse struct {
@@ -98,6 +102,38 @@ type SetGroupTopicRequest struct {
 	Topic   string `json:"topic" form:"topic"`
 }
 
+type SetGroupMemberAddModeRequest struct {
+	GroupID string                   `json:"group_id" form:"group_id"`
+	Mode    types.GroupMemberAddMode `json:"mode" form:"mode"`
+}
+
+type SetGroupJoinApprovalRequest struct {
+	GroupID string `json:"group_id" form:"group_id"`
+	Enabled bool   `json:"enabled" form:"enabled"`
+}
+
+// SetGroupDisappearingRequest sets the default disappearing-messages timer of a group.
+// WhatsApp only accepts off (0), 24 hours, 7 days and 90 days, expressed in seconds.
+type SetGroupDisappearingRequest struct {
+	GroupID string `json:"group_id" form:"group_id"`
+	Timer   int    `json:"timer" form:"timer"`
+}
+
+// SetGroupDescriptionRequest updates the group description. PreviousID is the description ID
+// the caller last saw (topic_id in group info); when set, the update is rejected if someone
+// changed the description in the meantime.
+type SetGroupDescriptionRequest struct {
+	GroupID     string `json:"group_id" form:"group_id"`
+	Description string `json:"description" form:"description"`
+	PreviousID  string `json:"previous_id" form:"previous_id"`
+}
+
+type SetGroupDescriptionResponse struct {
+	GroupID       string `json:"group_id"`
+	DescriptionID string `json:"description_id"`
+	PreviousID    string `json:"previous_id"`
+}
+
 type GetGroupInfoFromLinkRequest struct {
 	Link string `json:"link" form:"link"`
 }
@@ -126,8 +162,209 @@ type GetGroupInviteLinkRequest struct {
 type GetGroupInviteLinkResponse struct {
 	InviteLink string `json:"invite_link"`
 	GroupID    string `json:"group_id"`
+	Reset      bool   `json:"reset"`
 }
 
 type GroupInfoResponse struct {
 	Data any `json:"data"`
 }
+
+// GroupInfo is the group metadata returned by WhatsApp extended with the
+// community relationship of the group.
+type GroupInfo struct {
+	types.GroupInfo
+	Community *CommunityInfo `json:"community,omitempty"`
+}
+
+// CommunityInfo describes how a group relates to a community. It is only
+// present when the group is a community itself or is linked to one.
+type CommunityInfo struct {
+	IsCommunity         bool       `json:"is_community"`
+	ParentID            string     `json:"parent_id,omitempty"`
+	IsAnnouncementGroup bool       `json:"is_announcement_group"`
+	AnnouncementGroupID string     `json:"announcement_group_id,omitempty"`
+	LinkedGroups        []SubGroup `json:"linked_groups,omitempty"`
+}
+
+type CreateCommunityRequest struct {
+	Name        string `json:"name" form:"name"`
+	Description string `json:"description" form:"description"`
+}
+
+type CreateCommunityResponse struct {
+	CommunityID         string `json:"community_id"`
+	AnnouncementGroupID string `json:"announcement_group_id,omitempty"`
+}
+
+type LinkGroupRequest struct {
+	CommunityID string `json:"community_id" form:"community_id"`
+	GroupID     string `json:"group_id" form:"group_id"`
+}
+
+type GetSubGroupsRequest struct {
+	CommunityID string `json:"community_id" query:"community_id"`
+}
+
+type SubGroup struct {
+	GroupID             string `json:"group_id"`
+	Name                string `json:"name"`
+	IsAnnouncementGroup bool   `json:"is_announcement_group"`
+}
+
+type GetSubGroupsResponse struct {
+	CommunityID         string     `json:"community_id"`
+	AnnouncementGroupID string     `json:"announcement_group_id,omitempty"`
+	SubGroups           []SubGroup `json:"sub_groups"`
+}
+
+type SendCommunityAnnouncementRequest struct {
+	CommunityID string `json:"community_id" form:"community_id"`
+	Message     string `json:"message" form:"message"`
+}
+
+type GetGroupHistoryRequest struct {
+	GroupID     string  `json:"group_id" query:"group_id"`
+	Participant string  `json:"participant" query:"participant"`
+	Actor       string  `json:"actor" query:"actor"`
+	EventType   string  `json:"event_type" query:"event_type"` // comma separated list of event types
+	StartTime   *string `json:"start_time" query:"start_time"`
+	EndTime     *string `json:"end_time" query:"end_time"`
+	Limit       int     `json:"limit" query:"limit"`
+	Offset      int     `json:"offset" query:"offset"`
+}
+
+type GroupHistoryEvent struct {
+	ID          int64  `json:"id"`
+	EventType   string `json:"event_type"`
+	Participant string `json:"participant,omitempty"`
+	Actor       string `json:"actor,omitempty"`
+	Value       string `json:"value,omitempty"`
+	Timestamp   string `json:"timestamp"`
+}
+
+type GroupHistoryPagination struct {
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+	Total  int64 `json:"total"`
+}
+
+type GetGroupHistoryResponse struct {
+	GroupID    string                 `json:"group_id"`
+	Events     []GroupHistoryEvent    `json:"events"`
+	Pagination GroupHistoryPagination `json:"pagination"`
+}
+
+type GetGroupMembershipRequest struct {
+	GroupID   string  `json:"group_id" query:"group_id"`
+	StartTime *string `json:"start_time" query:"start_time"`
+	EndTime   *string `json:"end_time" query:"end_time"`
+}
+
+// GroupMembershipPoint is the membership of a group on a single day. Members is only
+// reported when the current participant count could be fetched from WhatsApp.
+type GroupMembershipPoint struct {
+	Date    string `json:"date"`
+	Joined  int64  `json:"joined"`
+	Left    int64  `json:"left"`
+	Net     int64  `json:"net"`
+	Members *int64 `json:"members,omitempty"`
+}
+
+type GetGroupMembershipResponse struct {
+	GroupID        string                 `json:"group_id"`
+	CurrentMembers *int64                 `json:"current_members,omitempty"`
+	Series         []GroupMembershipPoint `json:"series"`
+}
+
+type ImportParticipantsRequest struct {
+	GroupID       string                `json:"group_id" form:"group_id"`
+	File          *multipart.FileHeader `json:"file" form:"file"`
+	DelaySeconds  int                   `json:"delay_seconds" form:"delay_seconds"`   // pause between additions, defaults to 5
+	InviteCaption string                `json:"invite_caption" form:"invite_caption"` // text shown with the DM invite
+}
+
+type GetImportReportRequest struct {
+	ImportID string `json:"import_id" query:"import_id"`
+}
+
+type ImportEntry struct {
+	Phone     string     `json:"phone"`
+	JID       string     `json:"jid,omitempty"`
+	Status    string     `json:"status"`
+	Message   string     `json:"message,omitempty"`
+	InvitedAt *time.Time `json:"invited_at,omitempty"`
+	JoinedAt  *time.Time `json:"joined_at,omitempty"`
+}
+
+// ImportReport describes a bulk participant import. Summary counts entries per status.
+type ImportReport struct {
+	ImportID   string         `json:"import_id"`
+	GroupID    string         `json:"group_id"`
+	Status     string         `json:"status"`
+	Error      string         `json:"error,omitempty"`
+	Total      int            `json:"total"`
+	Summary    map[string]int `json:"summary"`
+	CreatedAt  time.Time      `json:"created_at"`
+	FinishedAt *time.Time     `json:"finished_at,omitempty"`
+	Entries    []ImportEntry  `json:"entries"`
+}
+
+// GroupSnapshot is a portable copy of a group's configuration and membership, used to
+// clone a group or to recreate it later from an exported JSON file.
+type GroupSnapshot struct {
+	Version       int                        `json:"version"`
+	SourceGroupID string                     `json:"source_group_id,omitempty"`
+	Name          string                     `json:"name"`
+	Topic         string                     `json:"topic,omitempty"`
+	Photo         string                     `json:"photo,omitempty"` // base64 encoded JPEG
+	Settings      GroupSnapshotSettings      `json:"settings"`
+	Participants  []GroupSnapshotParticipant `json:"participants"`
+	ExportedAt    time.Time                  `json:"exported_at"`
+}
+
+type GroupSnapshotSettings struct {
+	Locked            bool                     `json:"locked"`
+	Announce          bool                     `json:"announce"`
+	JoinApproval      bool                     `json:"join_approval"`
+	MemberAddMode     types.GroupMemberAddMode `json:"member_add_mode,omitempty"`
+	DisappearingTimer int                      `json:"disappearing_timer"` // seconds, 0 when off
+}
+
+type GroupSnapshotParticipant struct {
+	JID  string `json:"jid"`
+	Role string `json:"role"` // member, admin or super_admin
+}
+
+type ExportGroupSnapshotRequest struct {
+	GroupID   string `json:"group_id" query:"group_id"`
+	SkipPhoto bool   `json:"skip_photo" query:"skip_photo"`
+}
+
+// CloneGroupRequest creates a new group from an existing one. Name may use the placeholders
+// {name} (the source group name) and {date} (today as YYYY-MM-DD) and defaults to {name}.
+type CloneGroupRequest struct {
+	GroupID          string `json:"group_id" form:"group_id"`
+	Name             string `json:"name" form:"name"`
+	SkipParticipants bool   `json:"skip_participants" form:"skip_participants"`
+	SkipPhoto        bool   `json:"skip_photo" form:"skip_photo"`
+}
+
+// ImportGroupSnapshotRequest creates a new group from a snapshot, given either as JSON in
+// Snapshot or as an uploaded file. Name works like in CloneGroupRequest.
+type ImportGroupSnapshotRequest struct {
+	Snapshot         *GroupSnapshot        `json:"snapshot" form:"-"`
+	File             *multipart.FileHeader `json:"file" form:"file"`
+	Name             string                `json:"name" form:"name"`
+	SkipParticipants bool                  `json:"skip_participants" form:"skip_participants"`
+	SkipPhoto        bool                  `json:"skip_photo" form:"skip_photo"`
+}
+
+// CloneGroupResponse describes the group created from a snapshot. Steps that failed after
+// the group was created, such as setting the photo, are listed in Warnings.
+type CloneGroupResponse struct {
+	GroupID       string              `json:"group_id"`
+	Name          string              `json:"name"`
+	SourceGroupID string              `json:"source_group_id,omitempty"`
+	Participants  []ParticipantStatus `json:"participants"`
+	Warnings      []string            `json:"warnings,omitempty"`
+}
@@ -2,6 +2,8 @@ package group
 
 import (
 	"context"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 )
 
 // IGroupManagement handles basic group management operations
@@ -20,6 +22,8 @@ type IGroupParticipants interface {
 	GetGroupParticipants(ctx context.Context, request GetGroupParticipantsRequest) (response GetGroupParticipantsResponse, err error)
 	GetGroupRequestParticipants(ctx context.Context, request GetGroupRequestParticipantsRequest) (result []GetGroupRequestParticipantsResponse, err error)
 	ManageGroupRequestParticipants(ctx context.Context, request GroupRequestParticipantsRequest) (result []ParticipantStatus, err error)
+	ImportParticipants(ctx context.Context, request ImportParticipantsRequest) (response ImportReport, err error)
+	GetImportReport(ctx context.Context, request GetImportReportRequest) (response ImportReport, err error)
 }
 
 // IGroupSettings handles group settings operations
@@ -29,6 +33,33 @@ type IGroupSettings interface {
 	SetGroupLocked(ctx context.Context, request SetGroupLockedRequest) (err error)
 	SetGroupAnnounce(ctx context.Context, request SetGroupAnnounceRequest) (err error)
 	SetGroupTopic(ctx context.Context, request SetGroupTopicRequest) (err error)
+	SetGroupMemberAddMode(ctx context.Context, request SetGroupMemberAddModeRequest) (err error)
+	SetGroupJoinApproval(ctx context.Context, request SetGroupJoinApprovalRequest) (err error)
+	SetGroupDisappearing(ctx context.Context, request SetGroupDisappearingRequest) (err error)
+	SetGroupDescription(ctx context.Context, request SetGroupDescriptionRequest) (response SetGroupDescriptionResponse, err error)
+}
+
+// IGroupCommunity handles community operations: creating a community, linking
+// groups to it and broadcasting through its announcement group
+type IGroupCommunity interface {
+	CreateCommunity(ctx context.Context, request CreateCommunityRequest) (response CreateCommunityResponse, err error)
+	LinkGroup(ctx context.Context, request LinkGroupRequest) (err error)
+	UnlinkGroup(ctx context.Context, request LinkGroupRequest) (err error)
+	GetSubGroups(ctx context.Context, request GetSubGroupsRequest) (response GetSubGroupsResponse, err error)
+	SendCommunityAnnouncement(ctx context.Context, request SendCommunityAnnouncementRequest) (response domainSend.GenericResponse, err error)
+}
+
+// IGroupHistory exposes the stored log of group membership and setting changes
+type IGroupHistory interface {
+	GetGroupHistory(ctx context.Context, request GetGroupHistoryRequest) (response GetGroupHistoryResponse, err error)
+	GetGroupMembership(ctx context.Context, request GetGroupMembershipRequest) (response GetGroupMembershipResponse, err error)
+}
+
+// IGroupSnapshot copies groups, either directly or through an exported snapshot
+type IGroupSnapshot interface {
+	ExportGroupSnapshot(ctx context.Context, request ExportGroupSnapshotRequest) (response GroupSnapshot, err error)
+	ImportGroupSnapshot(ctx context.Context, request ImportGroupSnapshotRequest) (response CloneGroupResponse, err error)
+	CloneGroup(ctx context.Context, request CloneGroupRequest) (response CloneGroupResponse, err error)
 }
 
 // IGroupUsecase combines all group interfaces for backward compatibility
@@ -36,4 +67,7 @@ type IGroupUsecase interface {
 	IGroupManagement
 	IGroupParticipants
 	IGroupSettings
+	IGroupCommunity
+	IGroupHistory
+	IGroupSnapshot
 }
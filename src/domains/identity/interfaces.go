@@ -0,0 +1,19 @@
+package identity
+
+import (
+	"context"
+
+	"go.mau.fi/whatsmeow/types"
+)
+
+// IIdentityResolver maps WhatsApp users between their phone number JID (@s.whatsapp.net)
+// and their LID (@lid), so one person is always stored and reported under the same JID.
+type IIdentityResolver interface {
+	// Canonical returns the phone number form of a user JID without its device part.
+	// LIDs without a known mapping and non-user JIDs such as groups are returned as they are.
+	Canonical(ctx context.Context, jid types.JID) types.JID
+	// CanonicalString is Canonical for JID strings, unparseable input is returned unchanged
+	CanonicalString(ctx context.Context, jid string) string
+	// Alternate returns the other form of a user JID, or an empty JID when it is not known
+	Alternate(ctx context.Context, jid types.JID) types.JID
+}
@@ -8,6 +8,8 @@ type GenericResponse struct {
 type RevokeRequest struct {
 	MessageID string `json:"message_id" uri:"message_id"`
 	Phone     string `json:"phone" form:"phone"`
+	// Sender of the message when a group admin revokes someone else's message, empty for own messages
+	Sender string `json:"sender,omitempty" form:"sender"`
 }
 
 type DeleteRequest struct {
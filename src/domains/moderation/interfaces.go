@@ -0,0 +1,32 @@
+package moderation
+
+import (
+	"context"
+
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// IModerationRules manages the per-group moderation rules
+type IModerationRules interface {
+	ListRules(ctx context.Context, request ListRulesRequest) (response ListRulesResponse, err error)
+	CreateRule(ctx context.Context, request RuleRequest) (response Rule, err error)
+	UpdateRule(ctx context.Context, request RuleRequest) (response Rule, err error)
+	DeleteRule(ctx context.Context, request DeleteRuleRequest) (err error)
+}
+
+// IModerationAudit exposes the trail of actions taken by the moderation engine
+type IModerationAudit interface {
+	GetAudit(ctx context.Context, request GetAuditRequest) (response GetAuditResponse, err error)
+}
+
+// IMessageModerator evaluates incoming messages against the moderation rules
+type IMessageModerator interface {
+	ModerateMessage(ctx context.Context, evt *events.Message)
+}
+
+// IModerationUsecase combines all moderation interfaces
+type IModerationUsecase interface {
+	IModerationRules
+	IModerationAudit
+	IMessageModerator
+}
@@ -0,0 +1,75 @@
+package moderation
+
+type Rule struct {
+	ID          int64    `json:"id"`
+	GroupID     string   `json:"group_id"`
+	Type        string   `json:"type"`
+	Words       []string `json:"words,omitempty"`
+	Limit       int      `json:"limit,omitempty"`
+	Actions     []string `json:"actions"`
+	RemoveAfter int      `json:"remove_after"`
+	Enabled     bool     `json:"enabled"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+// RuleRequest creates or updates a moderation rule.
+//
+// Type is one of links, banned_words, rate_limit or forwarded_media. Words is required for
+// banned_words and Limit (messages per member per minute) for rate_limit. Actions are taken
+// on every violation: warn, delete (for everyone) and remove (the sender from the group).
+// RemoveAfter removes the sender once they reach that many violations of the rule.
+type RuleRequest struct {
+	RuleID      int64    `json:"rule_id" uri:"rule_id"`
+	GroupID     string   `json:"group_id" form:"group_id"`
+	Type        string   `json:"type" form:"type"`
+	Words       []string `json:"words" form:"words"`
+	Limit       int      `json:"limit" form:"limit"`
+	Actions     []string `json:"actions" form:"actions"`
+	RemoveAfter int      `json:"remove_after" form:"remove_after"`
+	Enabled     *bool    `json:"enabled" form:"enabled"` // defaults to true
+}
+
+type ListRulesRequest struct {
+	GroupID string `json:"group_id" query:"group_id"`
+}
+
+type ListRulesResponse struct {
+	Rules []Rule `json:"rules"`
+}
+
+type DeleteRuleRequest struct {
+	RuleID int64 `json:"rule_id" uri:"rule_id"`
+}
+
+type GetAuditRequest struct {
+	GroupID string `json:"group_id" query:"group_id"`
+	Sender  string `json:"sender" query:"sender"`
+	RuleID  int64  `json:"rule_id" query:"rule_id"`
+	Limit   int    `json:"limit" query:"limit"`
+	Offset  int    `json:"offset" query:"offset"`
+}
+
+type AuditEntry struct {
+	ID        int64  `json:"id"`
+	GroupID   string `json:"group_id"`
+	RuleID    int64  `json:"rule_id"`
+	RuleType  string `json:"rule_type"`
+	Sender    string `json:"sender"`
+	MessageID string `json:"message_id,omitempty"`
+	Action    string `json:"action"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+type AuditPagination struct {
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+	Total  int64 `json:"total"`
+}
+
+type GetAuditResponse struct {
+	Entries    []AuditEntry    `json:"entries"`
+	Pagination AuditPagination `json:"pagination"`
+}
@@ -1,6 +1,15 @@
 package send
 
 type GenericResponse struct {
-	MessageID string `json:"message_id"`
-	Status    string `json:"status"`
+	MessageID  string            `json:"message_id"`
+	Status     string            `json:"status"`
+	Recipients []RecipientResult `json:"recipients,omitempty"` // set when sending to an address book tag
+}
+
+// RecipientResult is the outcome of a send to one contact selected by a tag
+type RecipientResult struct {
+	Phone     string `json:"phone"`
+	MessageID string `json:"message_id,omitempty"`
+	Status    string `json:"status,omitempty"`
+	Error     string `json:"error,omitempty"`
 }
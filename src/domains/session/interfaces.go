@@ -0,0 +1,18 @@
+package session
+
+import (
+	"context"
+)
+
+// ISessionManagement adds, lists and removes the accounts served by this process
+type ISessionManagement interface {
+	ListSessions(ctx context.Context) (response []Session, err error)
+	GetSession(ctx context.Context, request SessionRequest) (response Session, err error)
+	AddSession(ctx context.Context, request AddSessionRequest) (response Session, err error)
+	RemoveSession(ctx context.Context, request SessionRequest) (err error)
+}
+
+// ISessionUsecase combines all session interfaces
+type ISessionUsecase interface {
+	ISessionManagement
+}
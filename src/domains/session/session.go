@@ -0,0 +1,48 @@
+package session
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultSessionID names the session used when a request does not pick one. It holds the
+// device that was paired before the process served more than one account.
+const DefaultSessionID = "default"
+
+// HeaderSessionID is the request header that selects the session of an API call
+const HeaderSessionID = "X-Session-ID"
+
+// Session is one WhatsApp account served by this process
+type Session struct {
+	ID          string    `json:"id"`
+	DeviceID    string    `json:"device_id"`
+	PushName    string    `json:"push_name"`
+	IsConnected bool      `json:"is_connected"`
+	IsLoggedIn  bool      `json:"is_logged_in"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type AddSessionRequest struct {
+	SessionID string `json:"session_id" form:"session_id"`
+}
+
+type SessionRequest struct {
+	SessionID string `json:"session_id"`
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that selects the given session
+func NewContext(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, sessionID)
+}
+
+// FromContext returns the session selected by ctx, or DefaultSessionID when none is
+func FromContext(ctx context.Context) string {
+	if ctx != nil {
+		if sessionID, ok := ctx.Value(contextKey{}).(string); ok && sessionID != "" {
+			return sessionID
+		}
+	}
+	return DefaultSessionID
+}
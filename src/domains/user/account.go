@@ -2,6 +2,7 @@ package user
 
 import (
 	"mime/multipart"
+	"time"
 
 	"go.mau.fi/whatsmeow/types"
 )
@@ -47,6 +48,69 @@ type MyPrivacySettingResponse struct {
 	Status       string `json:"status"`
 	Profile      string `json:"profile"`
 	ReadReceipts string `json:"read_receipts"`
+	Online       string `json:"online"`
+	CallAdd      string `json:"call_add"`
+}
+
+// SetPrivacySettingRequest changes one or more privacy settings; empty fields are left as
+// they are. Status controls who can see the about text. DisappearingTimer is the default
+// timer, in seconds, for new chats; 0 turns it off.
+type SetPrivacySettingRequest struct {
+	LastSeen          string `json:"last_seen" form:"last_seen"`         // all, contacts, contact_blacklist or none
+	Online            string `json:"online" form:"online"`               // all or match_last_seen
+	Profile           string `json:"profile" form:"profile"`             // all, contacts, contact_blacklist or none
+	Status            string `json:"status" form:"status"`               // all, contacts, contact_blacklist or none
+	GroupAdd          string `json:"group_add" form:"group_add"`         // all, contacts, contact_blacklist or none
+	ReadReceipts      string `json:"read_receipts" form:"read_receipts"` // all or none
+	CallAdd           string `json:"call_add" form:"call_add"`           // all or known
+	DisappearingTimer *int   `json:"disappearing_timer" form:"disappearing_timer"`
+}
+
+// BlockContactRequest blocks or unblocks a contact, given by phone number or JID
+type BlockContactRequest struct {
+	Phone string `json:"phone" form:"phone"`
+}
+
+type BlocklistResponse struct {
+	Data []BlocklistResponseData `json:"data"`
+}
+
+type BlocklistResponseData struct {
+	JID       string     `json:"jid"`
+	Name      string     `json:"name,omitempty"`
+	BlockedAt *time.Time `json:"blocked_at,omitempty"` // when this server first saw the block
+}
+
+// PresenceSubscriptionRequest subscribes to or unsubscribes from the presence of contacts, given by phone number or JID
+type PresenceSubscriptionRequest struct {
+	Phones []string `json:"phones" form:"phones"`
+}
+
+// PresenceRequest selects whose presence is returned. Without a phone every subscribed or seen
+// contact is listed; with a phone its online/offline history is included as well.
+type PresenceRequest struct {
+	Phone        string `json:"phone" query:"phone"`
+	HistoryLimit int    `json:"history_limit" query:"history_limit"` // defaults to 50
+}
+
+type PresenceResponse struct {
+	Data []PresenceResponseData `json:"data"`
+}
+
+type PresenceResponseData struct {
+	JID          string                `json:"jid"`
+	Subscribed   bool                  `json:"subscribed"`
+	SubscribedAt *time.Time            `json:"subscribed_at,omitempty"`
+	Status       string                `json:"status"`              // online, offline or unknown when no update was received yet
+	LastSeen     *time.Time            `json:"last_seen,omitempty"` // missing when hidden by the contact's privacy settings
+	UpdatedAt    *time.Time            `json:"updated_at,omitempty"`
+	History      []PresenceHistoryItem `json:"history,omitempty"`
+}
+
+type PresenceHistoryItem struct {
+	Status   string     `json:"status"`
+	LastSeen *time.Time `json:"last_seen,omitempty"`
+	At       time.Time  `json:"at"`
 }
 
 type MyListGroupsResponse struct {
@@ -57,8 +121,18 @@ type MyListNewsletterResponse struct {
 	Data []types.NewsletterMetadata `json:"data"`
 }
 
+// ChangeAvatarRequest sets the profile photo from either an upload or an image URL
 type ChangeAvatarRequest struct {
-	Avatar *multipart.FileHeader `json:"avatar" form:"avatar"`
+	Avatar    *multipart.FileHeader `json:"avatar" form:"avatar"`
+	AvatarURL string                `json:"avatar_url" form:"avatar_url"`
+}
+
+type MyAboutResponse struct {
+	About string `json:"about"`
+}
+
+type ChangeAboutRequest struct {
+	About string `json:"about" form:"about"`
 }
 
 type MyListContactsResponse struct {
@@ -82,6 +156,46 @@ type CheckResponse struct {
 	IsOnWhatsApp bool `json:"is_on_whatsapp"`
 }
 
+// BulkCheckRequest checks many phone numbers in the background, given as a list or as a CSV file
+type BulkCheckRequest struct {
+	Phones       []string              `json:"phones" form:"phones"`
+	File         *multipart.FileHeader `json:"file" form:"file"`
+	DelaySeconds int                   `json:"delay_seconds" form:"delay_seconds"` // pause between queries sent to WhatsApp, defaults to 3
+}
+
+type GetBulkCheckRequest struct {
+	JobID string `json:"job_id" query:"job_id"`
+}
+
+type BulkCheckResult struct {
+	Phone        string     `json:"phone"`
+	Status       string     `json:"status"`
+	Message      string     `json:"message,omitempty"`
+	IsOnWhatsApp bool       `json:"is_on_whatsapp"`
+	JID          string     `json:"jid,omitempty"`
+	LID          string     `json:"lid,omitempty"`
+	IsBusiness   bool       `json:"is_business"`
+	BusinessName string     `json:"business_name,omitempty"`
+	Cached       bool       `json:"cached"` // answered from the cache instead of asking WhatsApp
+	CheckedAt    *time.Time `json:"checked_at,omitempty"`
+}
+
+// BulkCheckReport describes a bulk number check. Checked counts the numbers done so far,
+// Registered and NotRegistered split them by outcome.
+type BulkCheckReport struct {
+	JobID         string            `json:"job_id"`
+	Status        string            `json:"status"`
+	Error         string            `json:"error,omitempty"`
+	Total         int               `json:"total"`
+	Checked       int               `json:"checked"`
+	Registered    int               `json:"registered"`
+	NotRegistered int               `json:"not_registered"`
+	Failed        int               `json:"failed"`
+	CreatedAt     time.Time         `json:"created_at"`
+	FinishedAt    *time.Time        `json:"finished_at,omitempty"`
+	Results       []BulkCheckResult `json:"results"`
+}
+
 type BusinessProfileRequest struct {
 	Phone string `json:"phone" query:"phone"`
 }
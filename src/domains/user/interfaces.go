@@ -11,10 +11,19 @@ type IUserInfo interface {
 	BusinessProfile(ctx context.Context, request BusinessProfileRequest) (response BusinessProfileResponse, err error)
 }
 
+// IUserNumberCheck handles checking many phone numbers at once
+type IUserNumberCheck interface {
+	BulkCheck(ctx context.Context, request BulkCheckRequest) (response BulkCheckReport, err error)
+	GetBulkCheck(ctx context.Context, request GetBulkCheckRequest) (response BulkCheckReport, err error)
+}
+
 // IUserProfile handles user profile operations
 type IUserProfile interface {
 	Avatar(ctx context.Context, request AvatarRequest) (response AvatarResponse, err error)
 	ChangeAvatar(ctx context.Context, request ChangeAvatarRequest) (err error)
+	RemoveAvatar(ctx context.Context) (err error)
+	MyAbout(ctx context.Context) (response MyAboutResponse, err error)
+	ChangeAbout(ctx context.Context, request ChangeAboutRequest) (err error)
 	ChangePushName(ctx context.Context, request ChangePushNameRequest) (err error)
 }
 
@@ -28,12 +37,30 @@ type IUserListing interface {
 // IUserPrivacy handles user privacy operations
 type IUserPrivacy interface {
 	MyPrivacySetting(ctx context.Context) (response MyPrivacySettingResponse, err error)
+	SetPrivacySetting(ctx context.Context, request SetPrivacySettingRequest) (response MyPrivacySettingResponse, err error)
+}
+
+// IUserBlocklist handles the account block list
+type IUserBlocklist interface {
+	MyBlocklist(ctx context.Context) (response BlocklistResponse, err error)
+	BlockContact(ctx context.Context, request BlockContactRequest) (response BlocklistResponse, err error)
+	UnblockContact(ctx context.Context, request BlockContactRequest) (response BlocklistResponse, err error)
+}
+
+// IUserPresence handles presence subscriptions and the recorded presence of contacts
+type IUserPresence interface {
+	SubscribePresence(ctx context.Context, request PresenceSubscriptionRequest) (response PresenceResponse, err error)
+	UnsubscribePresence(ctx context.Context, request PresenceSubscriptionRequest) (err error)
+	Presence(ctx context.Context, request PresenceRequest) (response PresenceResponse, err error)
 }
 
 // IUserUsecase combines all user interfaces for backward compatibility
 type IUserUsecase interface {
 	IUserInfo
+	IUserNumberCheck
 	IUserProfile
 	IUserListing
 	IUserPrivacy
+	IUserBlocklist
+	IUserPresence
 }
@@ -0,0 +1,30 @@
+package webhook
+
+import "context"
+
+// IWebhookDeliveries inspects and replays the deliveries of the webhook outbox
+type IWebhookDeliveries interface {
+	ListDeliveries(ctx context.Context, request ListDeliveriesRequest) (response ListDeliveriesResponse, err error)
+	ReplayDelivery(ctx context.Context, request ReplayDeliveryRequest) (response Delivery, err error)
+}
+
+// IWebhookDefinitions manages the webhook definitions and their subscriptions
+type IWebhookDefinitions interface {
+	ListDefinitions(ctx context.Context) (response ListDefinitionsResponse, err error)
+	CreateDefinition(ctx context.Context, request DefinitionRequest) (response Definition, err error)
+	UpdateDefinition(ctx context.Context, request DefinitionRequest) (response Definition, err error)
+	DeleteDefinition(ctx context.Context, request DeleteDefinitionRequest) (err error)
+}
+
+// IWebhookRouter picks the webhook URLs an event is delivered to
+type IWebhookRouter interface {
+	WebhookTargets(event Event) []string
+	WebhookSubscribed(eventType string) bool
+}
+
+// IWebhookUsecase combines all webhook interfaces
+type IWebhookUsecase interface {
+	IWebhookDeliveries
+	IWebhookDefinitions
+	IWebhookRouter
+}
@@ -0,0 +1,124 @@
+package webhook
+
+// Event types a webhook definition can subscribe to
+const (
+	EventMessage      = "message"       // messages, including revokes and edits
+	EventReceipt      = "receipt"       // delivered and read receipts (message.ack)
+	EventGroup        = "group"         // group participant changes (group.participants)
+	EventDelete       = "delete"        // messages deleted for me
+	EventPresence     = "presence"      // contacts going online or offline
+	EventChatPresence = "chat_presence" // typing and recording indicators
+	EventBlocklist    = "blocklist"     // block list changes
+	EventConnection   = "connection"    // connection state changes
+)
+
+// EventTypes lists every event type in the order they are documented
+var EventTypes = []string{
+	EventMessage, EventReceipt, EventGroup, EventDelete,
+	EventPresence, EventChatPresence, EventBlocklist, EventConnection,
+}
+
+// Where a webhook definition comes from
+const (
+	SourceAPI    = "api"    // managed with the REST API
+	SourceConfig = "config" // read from the webhook config file, read-only
+)
+
+// Event describes a webhook event for routing it to the definitions that subscribe to it.
+// Chat and Sender are empty for events that do not belong to a chat, such as connection changes.
+type Event struct {
+	Type   string
+	Chat   string // JID of the chat
+	Sender string // JID of the sender, when not the chat itself
+	FromMe bool
+}
+
+type Definition struct {
+	ID            int64    `json:"id,omitempty"`
+	Name          string   `json:"name"`
+	URL           string   `json:"url"`
+	Events        []string `json:"events"`
+	IncludeChats  []string `json:"include_chats"`
+	ExcludeChats  []string `json:"exclude_chats"`
+	IncludeFromMe bool     `json:"include_from_me"`
+	ChatType      string   `json:"chat_type"`
+	Enabled       bool     `json:"enabled"`
+	Source        string   `json:"source"`
+	CreatedAt     string   `json:"created_at,omitempty"`
+	UpdatedAt     string   `json:"updated_at,omitempty"`
+}
+
+// DefinitionRequest creates or updates a webhook definition, also used for the entries of the webhook config file.
+//
+// Events lists the event types delivered, every event when empty. IncludeChats delivers only events of those chats
+// or senders and ExcludeChats never delivers them; both take phone numbers, group IDs or JIDs. IncludeFromMe
+// (default true) delivers events of our own messages. ChatType limits events to private chats or groups.
+type DefinitionRequest struct {
+	DefinitionID  int64    `json:"definition_id" uri:"definition_id"`
+	Name          string   `json:"name" form:"name"`
+	URL           string   `json:"url" form:"url"`
+	Events        []string `json:"events" form:"events"`
+	IncludeChats  []string `json:"include_chats" form:"include_chats"`
+	ExcludeChats  []string `json:"exclude_chats" form:"exclude_chats"`
+	IncludeFromMe *bool    `json:"include_from_me" form:"include_from_me"` // defaults to true
+	ChatType      string   `json:"chat_type" form:"chat_type"`             // all (default), private or group
+	Enabled       *bool    `json:"enabled" form:"enabled"`                 // defaults to true
+}
+
+type ListDefinitionsResponse struct {
+	Definitions []Definition `json:"definitions"`
+}
+
+type DeleteDefinitionRequest struct {
+	DefinitionID int64 `json:"definition_id" uri:"definition_id"`
+}
+
+// ConfigFile is the webhook config file, see --webhook-config
+type ConfigFile struct {
+	Webhooks []DefinitionRequest `json:"webhooks"`
+}
+
+type ListDeliveriesRequest struct {
+	Status string `json:"status" query:"status"`
+	Event  string `json:"event" query:"event"`
+	Limit  int    `json:"limit" query:"limit"`
+	Offset int    `json:"offset" query:"offset"`
+}
+
+type ReplayDeliveryRequest struct {
+	DeliveryID int64 `json:"delivery_id" uri:"delivery_id"`
+}
+
+type DeliveryAttempt struct {
+	Attempt     int    `json:"attempt"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	Error       string `json:"error,omitempty"`
+	DurationMs  int64  `json:"duration_ms"`
+	AttemptedAt string `json:"attempted_at"`
+}
+
+type Delivery struct {
+	ID             int64             `json:"id"`
+	Event          string            `json:"event"`
+	URL            string            `json:"url"`
+	Status         string            `json:"status"`
+	Attempts       int               `json:"attempts"`
+	MaxAttempts    int               `json:"max_attempts"`
+	NextAttemptAt  string            `json:"next_attempt_at,omitempty"`
+	LastStatusCode int               `json:"last_status_code,omitempty"`
+	LastError      string            `json:"last_error,omitempty"`
+	CreatedAt      string            `json:"created_at"`
+	DeliveredAt    string            `json:"delivered_at,omitempty"`
+	History        []DeliveryAttempt `json:"history"`
+}
+
+type DeliveriesPagination struct {
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+	Total  int64 `json:"total"`
+}
+
+type ListDeliveriesResponse struct {
+	Deliveries []Delivery           `json:"deliveries"`
+	Pagination DeliveriesPagination `json:"pagination"`
+}
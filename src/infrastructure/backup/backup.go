@@ -0,0 +1,574 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	"github.com/mattn/go-sqlite3"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// FormatVersion is bumped whenever the archive layout changes incompatibly
+	FormatVersion = 1
+
+	KindWhatsappDB  = "whatsapp_db"
+	KindKeysDB      = "keys_db"
+	KindChatStorage = "chat_storage"
+	KindMedia       = "media"
+
+	manifestName = "manifest.json"
+	databasesDir = "databases"
+	mediaDir     = "media"
+)
+
+// ErrInvalidArchive is wrapped by the restore errors caused by the archive itself, such as a
+// corrupt file, a checksum mismatch or an unsupported manifest
+var ErrInvalidArchive = errors.New("invalid backup archive")
+
+// databaseFilenames maps database kinds to their file name inside the archive
+var databaseFilenames = map[string]string{
+	KindWhatsappDB:  "whatsapp.db",
+	KindKeysDB:      "keys.db",
+	KindChatStorage: "chatstorage.db",
+}
+
+// Source is a SQLite database to be included in a backup
+type Source struct {
+	Kind string
+	URI  string
+}
+
+// Options configures Create
+type Options struct {
+	Sources    []Source
+	MediaDir   string // media directory to include, empty to skip media
+	OutputDir  string
+	AppVersion string
+	DeviceID   string
+}
+
+// RestoreOptions configures Restore
+type RestoreOptions struct {
+	ArchivePath  string
+	Targets      map[string]string // database kind to destination URI
+	MediaDir     string            // destination of media files, empty to skip media
+	IncludeMedia bool
+}
+
+// SQLitePath extracts the file path from a SQLite connection URI.
+// It returns false for non-SQLite or in-memory databases.
+func SQLitePath(uri string) (string, bool) {
+	if uri == "" || strings.HasPrefix(uri, "postgres:") || strings.HasPrefix(uri, "postgresql:") {
+		return "", false
+	}
+
+	filePath, query, _ := strings.Cut(strings.TrimPrefix(uri, "file:"), "?")
+	if filePath == "" || filePath == ":memory:" || strings.Contains(query, "mode=memory") {
+		return "", false
+	}
+
+	return filePath, true
+}
+
+// Create writes a gzipped tarball with consistent snapshots of the given databases,
+// optional media files and a manifest describing every file.
+func Create(ctx context.Context, opts Options) (archivePath string, manifest domainApp.BackupManifest, err error) {
+	tempDir, err := os.MkdirTemp("", "whatsapp-backup-*")
+	if err != nil {
+		return "", manifest, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	manifest = domainApp.BackupManifest{
+		FormatVersion: FormatVersion,
+		AppVersion:    opts.AppVersion,
+		CreatedAt:     time.Now().UTC(),
+		DeviceID:      opts.DeviceID,
+	}
+
+	// localPaths maps archive names to files on disk
+	localPaths := map[string]string{}
+
+	for _, source := range opts.Sources {
+		filename, ok := databaseFilenames[source.Kind]
+		if !ok {
+			return "", manifest, fmt.Errorf("unknown database kind %q", source.Kind)
+		}
+		if _, ok := SQLitePath(source.URI); !ok {
+			return "", manifest, fmt.Errorf("%s: only SQLite databases can be backed up", source.Kind)
+		}
+
+		snapshotPath := filepath.Join(tempDir, filename)
+		if err := snapshotDatabase(ctx, source.URI, snapshotPath); err != nil {
+			return "", manifest, fmt.Errorf("%s: %w", source.Kind, err)
+		}
+
+		name := path.Join(databasesDir, filename)
+		file, err := describeFile(snapshotPath, name, source.Kind)
+		if err != nil {
+			return "", manifest, err
+		}
+		manifest.Files = append(manifest.Files, file)
+		localPaths[name] = snapshotPath
+	}
+
+	if opts.MediaDir != "" {
+		err := filepath.WalkDir(opts.MediaDir, func(filePath string, entry os.DirEntry, walkErr error) error {
+			if walkErr != nil || entry.IsDir() || !entry.Type().IsRegular() {
+				return walkErr
+			}
+
+			rel, err := filepath.Rel(opts.MediaDir, filePath)
+			if err != nil {
+				return err
+			}
+
+			name := path.Join(mediaDir, filepath.ToSlash(rel))
+			file, err := describeFile(filePath, name, KindMedia)
+			if err != nil {
+				return err
+			}
+			manifest.Files = append(manifest.Files, file)
+			localPaths[name] = filePath
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return "", manifest, fmt.Errorf("failed to collect media files: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+		return "", manifest, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	out, archivePath, err := createArchiveFile(opts.OutputDir, manifest.CreatedAt)
+	if err != nil {
+		return "", manifest, err
+	}
+	if err := writeArchive(out, manifest, localPaths); err != nil {
+		os.Remove(archivePath)
+		return "", manifest, err
+	}
+
+	logrus.Infof("[BACKUP] Created %s with %d files", archivePath, len(manifest.Files))
+	return archivePath, manifest, nil
+}
+
+// Restore validates the archive manifest and checksums, then copies each database
+// into its configured destination using the SQLite backup API.
+func Restore(ctx context.Context, opts RestoreOptions) (manifest domainApp.BackupManifest, err error) {
+	tempDir, err := os.MkdirTemp("", "whatsapp-restore-*")
+	if err != nil {
+		return manifest, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	manifest, err = extractArchive(opts.ArchivePath, tempDir)
+	if err != nil {
+		return manifest, err
+	}
+
+	for _, file := range manifest.Files {
+		if file.Kind == KindMedia {
+			continue
+		}
+
+		targetURI, ok := opts.Targets[file.Kind]
+		if !ok || targetURI == "" {
+			logrus.Warnf("[RESTORE] Skipping %s: no destination configured", file.Name)
+			continue
+		}
+
+		targetPath, ok := SQLitePath(targetURI)
+		if !ok {
+			return manifest, fmt.Errorf("%s: only SQLite destinations can be restored", file.Kind)
+		}
+		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+			return manifest, fmt.Errorf("failed to create directory for %s: %w", file.Kind, err)
+		}
+
+		if err := copyDatabase(ctx, "file:"+filepath.Join(tempDir, filepath.FromSlash(file.Name)), targetURI); err != nil {
+			return manifest, fmt.Errorf("%s: %w", file.Kind, err)
+		}
+		logrus.Infof("[RESTORE] Restored %s into %s", file.Name, targetPath)
+	}
+
+	if opts.IncludeMedia && opts.MediaDir != "" {
+		for _, file := range manifest.Files {
+			if file.Kind != KindMedia {
+				continue
+			}
+
+			rel := strings.TrimPrefix(file.Name, mediaDir+"/")
+			dest := filepath.Join(opts.MediaDir, filepath.FromSlash(rel))
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				return manifest, fmt.Errorf("failed to create media directory: %w", err)
+			}
+			if err := copyFile(filepath.Join(tempDir, filepath.FromSlash(file.Name)), dest); err != nil {
+				return manifest, fmt.Errorf("failed to restore %s: %w", file.Name, err)
+			}
+		}
+	}
+
+	return manifest, nil
+}
+
+// ValidateManifest checks that a manifest can be restored by this version
+func ValidateManifest(manifest domainApp.BackupManifest) error {
+	if manifest.FormatVersion != FormatVersion {
+		return fmt.Errorf("unsupported backup format version %d (expected %d)", manifest.FormatVersion, FormatVersion)
+	}
+
+	hasWhatsappDB := false
+	seen := map[string]bool{}
+	for _, file := range manifest.Files {
+		if !isSafeName(file.Name) {
+			return fmt.Errorf("invalid file name %q in manifest", file.Name)
+		}
+		if seen[file.Name] {
+			return fmt.Errorf("duplicate file %q in manifest", file.Name)
+		}
+		seen[file.Name] = true
+
+		switch file.Kind {
+		case KindWhatsappDB, KindKeysDB, KindChatStorage:
+			if file.Name != path.Join(databasesDir, databaseFilenames[file.Kind]) {
+				return fmt.Errorf("unexpected file %q for %s", file.Name, file.Kind)
+			}
+			if file.Kind == KindWhatsappDB {
+				hasWhatsappDB = true
+			}
+		case KindMedia:
+			if !strings.HasPrefix(file.Name, mediaDir+"/") {
+				return fmt.Errorf("unexpected media file %q", file.Name)
+			}
+		default:
+			return fmt.Errorf("unknown file kind %q in manifest", file.Kind)
+		}
+
+		if len(file.SHA256) != sha256.Size*2 {
+			return fmt.Errorf("missing checksum for %q", file.Name)
+		}
+	}
+
+	if !hasWhatsappDB {
+		return fmt.Errorf("manifest does not contain the %s", KindWhatsappDB)
+	}
+
+	return nil
+}
+
+// isSafeName rejects absolute paths and path traversal inside the archive
+func isSafeName(name string) bool {
+	if name == "" || strings.HasPrefix(name, "/") || strings.Contains(name, "\\") {
+		return false
+	}
+	return path.Clean(name) == name && !strings.HasPrefix(name, "../") && name != ".."
+}
+
+// snapshotDatabase takes a consistent online copy of the source database into destPath
+func snapshotDatabase(ctx context.Context, sourceURI, destPath string) error {
+	return copyDatabase(ctx, sourceURI, "file:"+destPath)
+}
+
+// copyDatabase copies the whole "main" database from sourceURI into destURI
+// using the SQLite online backup API.
+func copyDatabase(ctx context.Context, sourceURI, destURI string) error {
+	sourceDB, err := sql.Open("sqlite3", sourceURI)
+	if err != nil {
+		return fmt.Errorf("failed to open source database: %w", err)
+	}
+	defer sourceDB.Close()
+
+	destDB, err := sql.Open("sqlite3", destURI)
+	if err != nil {
+		return fmt.Errorf("failed to open destination database: %w", err)
+	}
+	defer destDB.Close()
+
+	sourceConn, err := sourceDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to source database: %w", err)
+	}
+	defer sourceConn.Close()
+
+	destConn, err := destDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to destination database: %w", err)
+	}
+	defer destConn.Close()
+
+	return destConn.Raw(func(destDriverConn any) error {
+		return sourceConn.Raw(func(sourceDriverConn any) error {
+			dest, ok := destDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("destination is not a SQLite connection")
+			}
+			source, ok := sourceDriverConn.(*sqlite3.SQLiteConn)
+			if !ok {
+				return fmt.Errorf("source is not a SQLite connection")
+			}
+
+			backup, err := dest.Backup("main", source, "main")
+			if err != nil {
+				return fmt.Errorf("failed to start backup: %w", err)
+			}
+
+			// Step returns false without error while the source is busy or locked,
+			// so keep retrying until done or the context is cancelled.
+			for {
+				done, err := backup.Step(-1)
+				if err != nil {
+					backup.Close()
+					return fmt.Errorf("backup step failed: %w", err)
+				}
+				if done {
+					break
+				}
+
+				select {
+				case <-ctx.Done():
+					backup.Close()
+					return ctx.Err()
+				case <-time.After(100 * time.Millisecond):
+				}
+			}
+
+			return backup.Finish()
+		})
+	})
+}
+
+// describeFile builds the manifest entry for a file on disk
+func describeFile(filePath, name, kind string) (domainApp.BackupManifestFile, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return domainApp.BackupManifestFile{}, fmt.Errorf("failed to open %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return domainApp.BackupManifestFile{}, fmt.Errorf("failed to hash %s: %w", filePath, err)
+	}
+
+	return domainApp.BackupManifestFile{
+		Name:   name,
+		Kind:   kind,
+		Size:   size,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// createArchiveFile creates a new archive named after its creation time. Backups created within the
+// same second get a numbered suffix instead of overwriting each other.
+func createArchiveFile(dir string, createdAt time.Time) (*os.File, string, error) {
+	base := "backup-" + createdAt.Format("20060102-150405")
+	for attempt := 1; ; attempt++ {
+		name := base + ".tar.gz"
+		if attempt > 1 {
+			name = fmt.Sprintf("%s-%d.tar.gz", base, attempt)
+		}
+
+		archivePath := filepath.Join(dir, name)
+		out, err := os.OpenFile(archivePath, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0600)
+		if err == nil {
+			return out, archivePath, nil
+		}
+		if !os.IsExist(err) {
+			return nil, "", fmt.Errorf("failed to create archive: %w", err)
+		}
+	}
+}
+
+// writeArchive writes the manifest followed by every file into a gzipped tarball and closes out
+func writeArchive(out *os.File, manifest domainApp.BackupManifest, localPaths map[string]string) error {
+	defer out.Close()
+
+	gzipWriter := gzip.NewWriter(out)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := tarWriter.WriteHeader(&tar.Header{
+		Name:    manifestName,
+		Mode:    0600,
+		Size:    int64(len(manifestBytes)),
+		ModTime: manifest.CreatedAt,
+	}); err != nil {
+		return fmt.Errorf("failed to write manifest header: %w", err)
+	}
+	if _, err := tarWriter.Write(manifestBytes); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	for _, file := range manifest.Files {
+		if err := writeArchiveFile(tarWriter, file, localPaths[file.Name], manifest.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	return out.Close()
+}
+
+func writeArchiveFile(tarWriter *tar.Writer, file domainApp.BackupManifestFile, localPath string, modTime time.Time) error {
+	in, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", localPath, err)
+	}
+	defer in.Close()
+
+	if err := tarWriter.WriteHeader(&tar.Header{
+		Name:    file.Name,
+		Mode:    0600,
+		Size:    file.Size,
+		ModTime: modTime,
+	}); err != nil {
+		return fmt.Errorf("failed to write header for %s: %w", file.Name, err)
+	}
+
+	// Media may change while the archive is written, so copy exactly the hashed size
+	if _, err := io.CopyN(tarWriter, in, file.Size); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file.Name, err)
+	}
+	return nil
+}
+
+// extractArchive unpacks the archive into dir, validating the manifest and every checksum
+func extractArchive(archivePath, dir string) (manifest domainApp.BackupManifest, err error) {
+	in, err := os.Open(archivePath)
+	if err != nil {
+		return manifest, invalidArchive(fmt.Errorf("failed to open archive: %w", err))
+	}
+	defer in.Close()
+
+	gzipReader, err := gzip.NewReader(in)
+	if err != nil {
+		return manifest, invalidArchive(fmt.Errorf("failed to read archive: %w", err))
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+
+	header, err := tarReader.Next()
+	if err != nil {
+		return manifest, invalidArchive(fmt.Errorf("failed to read archive: %w", err))
+	}
+	if header.Name != manifestName {
+		return manifest, invalidArchive(fmt.Errorf("archive does not start with %s", manifestName))
+	}
+	if err := json.NewDecoder(io.LimitReader(tarReader, 64<<20)).Decode(&manifest); err != nil {
+		return manifest, invalidArchive(fmt.Errorf("failed to decode manifest: %w", err))
+	}
+	if err := ValidateManifest(manifest); err != nil {
+		return manifest, invalidArchive(err)
+	}
+
+	expected := make(map[string]domainApp.BackupManifestFile, len(manifest.Files))
+	for _, file := range manifest.Files {
+		expected[file.Name] = file
+	}
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return manifest, invalidArchive(fmt.Errorf("failed to read archive: %w", err))
+		}
+
+		file, ok := expected[header.Name]
+		if !ok {
+			return manifest, invalidArchive(fmt.Errorf("archive contains unexpected file %q", header.Name))
+		}
+		delete(expected, header.Name)
+
+		if err := extractFile(tarReader, file, filepath.Join(dir, filepath.FromSlash(file.Name))); err != nil {
+			return manifest, err
+		}
+	}
+
+	if len(expected) > 0 {
+		missing := make([]string, 0, len(expected))
+		for name := range expected {
+			missing = append(missing, name)
+		}
+		sort.Strings(missing)
+		return manifest, invalidArchive(fmt.Errorf("archive is missing %s", strings.Join(missing, ", ")))
+	}
+
+	return manifest, nil
+}
+
+func extractFile(reader io.Reader, file domainApp.BackupManifestFile, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", file.Name, err)
+	}
+
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", file.Name, err)
+	}
+	defer out.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(out, hash), reader)
+	if err != nil {
+		return invalidArchive(fmt.Errorf("failed to extract %s: %w", file.Name, err))
+	}
+
+	if size != file.Size || hex.EncodeToString(hash.Sum(nil)) != file.SHA256 {
+		return invalidArchive(fmt.Errorf("checksum mismatch for %s", file.Name))
+	}
+	return out.Close()
+}
+
+// invalidArchive marks err as caused by the archive rather than by this host
+func invalidArchive(err error) error {
+	return fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+}
+
+func copyFile(source, dest string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,257 @@
+package chatstorage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// StoreAddressBookContact creates or replaces an address book entry of the context session including
+// its tags. Chats that were named after the previous custom name are renamed, so removing a custom
+// name lets the push name take over again on the next message.
+func (r *SQLiteRepository) StoreAddressBookContact(ctx context.Context, contact *domainChatStorage.AddressBookContact) error {
+	sessionID := domainSession.FromContext(ctx)
+	contact.JID = r.canonicalJID(ctx, contact.JID)
+	if contact.CustomFields == nil {
+		contact.CustomFields = map[string]string{}
+	}
+	fields, err := json.Marshal(contact.CustomFields)
+	if err != nil {
+		return fmt.Errorf("failed to encode custom fields: %w", err)
+	}
+
+	previous, err := r.GetAddressBookContact(ctx, contact.JID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err = tx.Exec(`
+		INSERT INTO address_book (session_id, jid, custom_name, notes, custom_fields, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id, jid) DO UPDATE SET
+			custom_name = excluded.custom_name,
+			notes = excluded.notes,
+			custom_fields = excluded.custom_fields,
+			updated_at = excluded.updated_at
+	`, sessionID, contact.JID, contact.CustomName, contact.Notes, string(fields), now, now); err != nil {
+		return fmt.Errorf("failed to store address book contact: %w", err)
+	}
+
+	if _, err = tx.Exec("DELETE FROM address_book_tags WHERE session_id = ? AND jid = ?", sessionID, contact.JID); err != nil {
+		return fmt.Errorf("failed to clear address book tags: %w", err)
+	}
+	for _, tag := range contact.Tags {
+		if _, err = tx.Exec("INSERT OR IGNORE INTO address_book_tags (session_id, jid, tag) VALUES (?, ?, ?)", sessionID, contact.JID, tag); err != nil {
+			return fmt.Errorf("failed to store address book tag: %w", err)
+		}
+	}
+
+	if previous != nil && previous.CustomName != "" && previous.CustomName != contact.CustomName {
+		if err = renameChatFromCustomName(tx, sessionID, contact.JID, previous.CustomName, contact.CustomName); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	contact.UpdatedAt = now
+	if previous != nil {
+		contact.CreatedAt = previous.CreatedAt
+	} else {
+		contact.CreatedAt = now
+	}
+	return nil
+}
+
+// GetAddressBookContact returns the address book entry of a JID in the context session, or nil when
+// there is none
+func (r *SQLiteRepository) GetAddressBookContact(ctx context.Context, jid string) (*domainChatStorage.AddressBookContact, error) {
+	contacts, err := r.queryAddressBook("WHERE ab.session_id = ? AND ab.jid = ?", []any{domainSession.FromContext(ctx), r.canonicalJID(ctx, jid)}, "")
+	if err != nil || len(contacts) == 0 {
+		return nil, err
+	}
+	return contacts[0], nil
+}
+
+// GetAddressBookContacts returns the address book entries of the context session matching the
+// filter, ordered by name
+func (r *SQLiteRepository) GetAddressBookContacts(ctx context.Context, filter *domainChatStorage.AddressBookFilter) ([]*domainChatStorage.AddressBookContact, error) {
+	where, args := addressBookConditions(ctx, filter)
+
+	suffix := ""
+	if filter.Limit > 0 {
+		suffix = " LIMIT ? OFFSET ?"
+		args = append(args, filter.Limit, filter.Offset)
+	}
+
+	return r.queryAddressBook(where, args, suffix)
+}
+
+// CountAddressBookContacts counts the address book entries of the context session matching the filter
+func (r *SQLiteRepository) CountAddressBookContacts(ctx context.Context, filter *domainChatStorage.AddressBookFilter) (int64, error) {
+	where, args := addressBookConditions(ctx, filter)
+
+	var count int64
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM address_book ab "+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count address book contacts: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteAddressBookContact removes an address book entry of the context session and its tags
+func (r *SQLiteRepository) DeleteAddressBookContact(ctx context.Context, jid string) error {
+	sessionID := domainSession.FromContext(ctx)
+	jid = r.canonicalJID(ctx, jid)
+
+	previous, err := r.GetAddressBookContact(ctx, jid)
+	if err != nil || previous == nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec("DELETE FROM address_book_tags WHERE session_id = ? AND jid = ?", sessionID, jid); err != nil {
+		return fmt.Errorf("failed to delete address book tags: %w", err)
+	}
+	if _, err = tx.Exec("DELETE FROM address_book WHERE session_id = ? AND jid = ?", sessionID, jid); err != nil {
+		return fmt.Errorf("failed to delete address book contact: %w", err)
+	}
+	if previous.CustomName != "" {
+		if err = renameChatFromCustomName(tx, sessionID, jid, previous.CustomName, ""); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetAddressBookJIDsByTag returns the JIDs of every entry of the context session carrying the tag
+func (r *SQLiteRepository) GetAddressBookJIDsByTag(ctx context.Context, tag string) ([]string, error) {
+	rows, err := r.db.Query(
+		"SELECT jid FROM address_book_tags WHERE session_id = ? AND tag = ? ORDER BY jid",
+		domainSession.FromContext(ctx), strings.ToLower(tag),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query address book tag: %w", err)
+	}
+	defer rows.Close()
+
+	var jids []string
+	for rows.Next() {
+		var jid string
+		if err := rows.Scan(&jid); err != nil {
+			return nil, fmt.Errorf("failed to scan address book tag: %w", err)
+		}
+		jids = append(jids, jid)
+	}
+
+	return jids, rows.Err()
+}
+
+// GetCustomName returns the custom name given to a JID in the address book of the context session,
+// or an empty string
+func (r *SQLiteRepository) GetCustomName(ctx context.Context, jid string) string {
+	var name string
+	err := r.db.QueryRow(
+		"SELECT custom_name FROM address_book WHERE session_id = ? AND jid = ?",
+		domainSession.FromContext(ctx), r.canonicalJID(ctx, jid),
+	).Scan(&name)
+	if err != nil && err != sql.ErrNoRows {
+		logrus.WithError(err).WithField("jid", jid).Debug("Failed to look up custom name")
+	}
+	return name
+}
+
+// queryAddressBook loads address book entries with their tags
+func (r *SQLiteRepository) queryAddressBook(where string, args []any, suffix string) ([]*domainChatStorage.AddressBookContact, error) {
+	rows, err := r.db.Query(`
+		SELECT ab.jid, ab.custom_name, ab.notes, ab.custom_fields, ab.created_at, ab.updated_at,
+			COALESCE((SELECT GROUP_CONCAT(tag, ',') FROM (
+				SELECT tag FROM address_book_tags t WHERE t.session_id = ab.session_id AND t.jid = ab.jid ORDER BY tag
+			)), '')
+		FROM address_book ab
+		`+where+`
+		ORDER BY CASE WHEN ab.custom_name = '' THEN 1 ELSE 0 END, ab.custom_name COLLATE NOCASE, ab.jid
+	`+suffix, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query address book: %w", err)
+	}
+	defer rows.Close()
+
+	var contacts []*domainChatStorage.AddressBookContact
+	for rows.Next() {
+		contact := &domainChatStorage.AddressBookContact{}
+		var fields, tags string
+		if err := rows.Scan(&contact.JID, &contact.CustomName, &contact.Notes, &fields, &contact.CreatedAt, &contact.UpdatedAt, &tags); err != nil {
+			return nil, fmt.Errorf("failed to scan address book contact: %w", err)
+		}
+		if err := json.Unmarshal([]byte(fields), &contact.CustomFields); err != nil {
+			return nil, fmt.Errorf("failed to decode custom fields of %s: %w", contact.JID, err)
+		}
+		contact.Tags = []string{}
+		if tags != "" {
+			contact.Tags = strings.Split(tags, ",")
+		}
+		contacts = append(contacts, contact)
+	}
+
+	return contacts, rows.Err()
+}
+
+// addressBookConditions builds the WHERE clause of an address book filter in the context session
+func addressBookConditions(ctx context.Context, filter *domainChatStorage.AddressBookFilter) (string, []any) {
+	conditions := []string{"ab.session_id = ?"}
+	args := []any{domainSession.FromContext(ctx)}
+
+	if filter.Tag != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM address_book_tags t WHERE t.session_id = ab.session_id AND t.jid = ab.jid AND t.tag = ?)")
+		args = append(args, strings.ToLower(filter.Tag))
+	}
+
+	if filter.Search != "" {
+		pattern := "%" + filter.Search + "%"
+		conditions = append(conditions, `(ab.custom_name LIKE ? OR ab.jid LIKE ? OR ab.notes LIKE ? OR ab.custom_fields LIKE ?
+			OR EXISTS (SELECT 1 FROM address_book_tags t WHERE t.session_id = ab.session_id AND t.jid = ab.jid AND t.tag LIKE ?))`)
+		args = append(args, pattern, pattern, pattern, pattern, pattern)
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// renameChatFromCustomName renames a session's chat that still carries a previous custom name.
+// Without a new name the chat falls back to the phone number, which the next push name replaces.
+func renameChatFromCustomName(tx *sql.Tx, sessionID, jid, previousName, name string) error {
+	if name == "" {
+		if parsed, err := types.ParseJID(jid); err == nil && parsed.Server != types.GroupServer {
+			name = parsed.User
+		} else {
+			return nil
+		}
+	}
+
+	if _, err := tx.Exec("UPDATE chats SET name = ? WHERE session_id = ? AND jid = ? AND name = ?", name, sessionID, jid, previousName); err != nil {
+		return fmt.Errorf("failed to rename chat: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package chatstorage
+
+import (
+	"fmt"
+	"strings"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+// analyticsConditions builds the shared WHERE clause for analytics queries.
+// The alias is used to prefix column names when the messages table is joined.
+func (r *SQLiteRepository) analyticsConditions(filter *domainChatStorage.AnalyticsFilter, alias string) (string, []any) {
+	prefix := ""
+	if alias != "" {
+		prefix = alias + "."
+	}
+
+	conditions := []string{"1 = 1"}
+	var args []any
+
+	if filter.SessionID != "" {
+		conditions = append(conditions, prefix+"session_id = ?")
+		args = append(args, filter.SessionID)
+	}
+
+	if filter.ChatJID != "" {
+		conditions = append(conditions, prefix+"chat_jid = ?")
+		args = append(args, r.canonicalJID(sessionContext(filter.SessionID), filter.ChatJID))
+	}
+
+	if filter.StartTime != nil {
+		conditions = append(conditions, prefix+"timestamp >= ?")
+		args = append(args, storedTime(*filter.StartTime))
+	}
+
+	if filter.EndTime != nil {
+		conditions = append(conditions, prefix+"timestamp <= ?")
+		args = append(args, storedTime(*filter.EndTime))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// analyticsLimit caps the number of ranked rows returned by analytics queries
+func analyticsLimit(limit int) int {
+	if limit <= 0 {
+		return 10
+	}
+	if limit > 100 {
+		return 100
+	}
+	return limit
+}
+
+// GetMessageVolume returns message counts grouped by the given strftime bucket format
+func (r *SQLiteRepository) GetMessageVolume(filter *domainChatStorage.AnalyticsFilter, bucketFormat string) ([]*domainChatStorage.MessageVolume, error) {
+	where, args := r.analyticsConditions(filter, "")
+
+	query := `
+		SELECT strftime(?, timestamp) AS bucket,
+			COUNT(*) AS total,
+			SUM(CASE WHEN is_from_me THEN 0 ELSE 1 END) AS inbound,
+			SUM(CASE WHEN is_from_me THEN 1 ELSE 0 END) AS outbound
+		FROM messages
+		WHERE ` + where + `
+		GROUP BY bucket
+		HAVING bucket IS NOT NULL
+		ORDER BY bucket ASC
+	`
+
+	rows, err := r.db.Query(query, append([]any{bucketFormat}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query message volume: %w", err)
+	}
+	defer rows.Close()
+
+	var volumes []*domainChatStorage.MessageVolume
+	for rows.Next() {
+		volume := &domainChatStorage.MessageVolume{}
+		if err := rows.Scan(&volume.Bucket, &volume.Total, &volume.Inbound, &volume.Outbound); err != nil {
+			return nil, fmt.Errorf("failed to scan message volume: %w", err)
+		}
+		volumes = append(volumes, volume)
+	}
+
+	return volumes, rows.Err()
+}
+
+// GetTopChats returns the most active chats ordered by message count
+func (r *SQLiteRepository) GetTopChats(filter *domainChatStorage.AnalyticsFilter) ([]*domainChatStorage.ChatActivity, error) {
+	where, args := r.analyticsConditions(filter, "m")
+
+	query := `
+		SELECT m.chat_jid, COALESCE(c.name, '') AS name,
+			COUNT(*) AS total,
+			SUM(CASE WHEN m.is_from_me THEN 0 ELSE 1 END) AS inbound,
+			SUM(CASE WHEN m.is_from_me THEN 1 ELSE 0 END) AS outbound
+		FROM messages m
+		LEFT JOIN chats c ON c.session_id = m.session_id AND c.jid = m.chat_jid
+		WHERE ` + where + `
+		GROUP BY m.chat_jid
+		ORDER BY total DESC, m.chat_jid ASC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, append(args, analyticsLimit(filter.Limit))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top chats: %w", err)
+	}
+	defer rows.Close()
+
+	var chats []*domainChatStorage.ChatActivity
+	for rows.Next() {
+		chat := &domainChatStorage.ChatActivity{}
+		if err := rows.Scan(&chat.ChatJID, &chat.Name, &chat.Total, &chat.Inbound, &chat.Outbound); err != nil {
+			return nil, fmt.Errorf("failed to scan top chat: %w", err)
+		}
+		chats = append(chats, chat)
+	}
+
+	return chats, rows.Err()
+}
+
+// GetTopSenders returns the senders with the most inbound messages
+func (r *SQLiteRepository) GetTopSenders(filter *domainChatStorage.AnalyticsFilter) ([]*domainChatStorage.SenderActivity, error) {
+	where, args := r.analyticsConditions(filter, "")
+
+	query := `
+		SELECT sender, COUNT(*) AS message_count
+		FROM messages
+		WHERE ` + where + ` AND is_from_me = FALSE AND sender != ''
+		GROUP BY sender
+		ORDER BY message_count DESC, sender ASC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, append(args, analyticsLimit(filter.Limit))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top senders: %w", err)
+	}
+	defer rows.Close()
+
+	var senders []*domainChatStorage.SenderActivity
+	for rows.Next() {
+		sender := &domainChatStorage.SenderActivity{}
+		if err := rows.Scan(&sender.Sender, &sender.MessageCount); err != nil {
+			return nil, fmt.Errorf("failed to scan top sender: %w", err)
+		}
+		senders = append(senders, sender)
+	}
+
+	return senders, rows.Err()
+}
+
+// GetMediaVolume returns media message counts and total sizes grouped by media type
+func (r *SQLiteRepository) GetMediaVolume(filter *domainChatStorage.AnalyticsFilter) ([]*domainChatStorage.MediaVolume, error) {
+	where, args := r.analyticsConditions(filter, "")
+
+	query := `
+		SELECT media_type, COUNT(*) AS count, COALESCE(SUM(file_length), 0) AS total_bytes
+		FROM messages
+		WHERE ` + where + ` AND media_type IS NOT NULL AND media_type != ''
+		GROUP BY media_type
+		ORDER BY count DESC, media_type ASC
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query media volume: %w", err)
+	}
+	defer rows.Close()
+
+	var volumes []*domainChatStorage.MediaVolume
+	for rows.Next() {
+		volume := &domainChatStorage.MediaVolume{}
+		if err := rows.Scan(&volume.MediaType, &volume.Count, &volume.TotalBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan media volume: %w", err)
+		}
+		volumes = append(volumes, volume)
+	}
+
+	return volumes, rows.Err()
+}
+
+// GetFirstResponseTimes summarises, over every inbound message run that received a reply, the
+// delay between the first inbound message of the run and the first outbound reply. The runs are
+// found with window functions, so only the aggregates leave the database.
+func (r *SQLiteRepository) GetFirstResponseTimes(filter *domainChatStorage.AnalyticsFilter) (*domainChatStorage.ResponseTimeStats, error) {
+	where, args := r.analyticsConditions(filter, "")
+
+	// A run starts at an inbound message following an outbound one (or none) and is answered by
+	// the next outbound message, so the marks of a chat alternate between start and reply
+	query := `
+		WITH ordered AS (
+			SELECT session_id, chat_jid, timestamp, is_from_me,
+				LAG(is_from_me) OVER (PARTITION BY session_id, chat_jid ORDER BY timestamp) AS previous_from_me
+			FROM messages
+			WHERE ` + where + `
+		),
+		marks AS (
+			SELECT session_id, chat_jid, timestamp, is_from_me
+			FROM ordered
+			WHERE (is_from_me = FALSE AND (previous_from_me IS NULL OR previous_from_me = TRUE))
+				OR (is_from_me = TRUE AND previous_from_me = FALSE)
+		),
+		responses AS (
+			SELECT is_from_me,
+				ROUND((julianday(timestamp) - julianday(LAG(timestamp) OVER (PARTITION BY session_id, chat_jid ORDER BY timestamp))) * 86400, 3) AS seconds
+			FROM marks
+		),
+		ranked AS (
+			SELECT seconds,
+				ROW_NUMBER() OVER (ORDER BY seconds) AS position,
+				COUNT(*) OVER () AS samples
+			FROM responses
+			WHERE is_from_me = TRUE AND seconds IS NOT NULL
+		)
+		SELECT COUNT(*),
+			COALESCE(AVG(CASE WHEN position IN ((samples + 1) / 2, (samples + 2) / 2) THEN seconds END), 0),
+			COALESCE(AVG(seconds), 0)
+		FROM ranked
+	`
+
+	stats := &domainChatStorage.ResponseTimeStats{}
+	if err := r.db.QueryRow(query, args...).Scan(&stats.Samples, &stats.MedianSeconds, &stats.AverageSeconds); err != nil {
+		return nil, fmt.Errorf("failed to query response times: %w", err)
+	}
+
+	return stats, nil
+}
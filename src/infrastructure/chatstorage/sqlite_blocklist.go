@@ -0,0 +1,107 @@
+package chatstorage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+)
+
+// StoreBlockedContact records a contact blocked by the context session, keeping the original time
+// if it was already blocked
+func (r *SQLiteRepository) StoreBlockedContact(ctx context.Context, jid string, blockedAt time.Time) error {
+	if _, err := r.db.ExecContext(ctx, "INSERT OR IGNORE INTO blocked_contacts (session_id, jid, blocked_at) VALUES (?, ?, ?)",
+		domainSession.FromContext(ctx), jid, blockedAt); err != nil {
+		return fmt.Errorf("failed to store blocked contact: %w", err)
+	}
+	return nil
+}
+
+// DeleteBlockedContact removes a contact from the block list mirror of the context session
+func (r *SQLiteRepository) DeleteBlockedContact(ctx context.Context, jid string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM blocked_contacts WHERE session_id = ? AND jid = ?", domainSession.FromContext(ctx), jid); err != nil {
+		return fmt.Errorf("failed to delete blocked contact: %w", err)
+	}
+	return nil
+}
+
+// ReplaceBlockedContacts makes the mirror of the context session match a full block list fetched
+// from WhatsApp. Contacts that stay blocked keep their original time; new ones get blockedAt.
+func (r *SQLiteRepository) ReplaceBlockedContacts(ctx context.Context, jids []string, blockedAt time.Time) error {
+	sessionID := domainSession.FromContext(ctx)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if len(jids) == 0 {
+		if _, err = tx.Exec("DELETE FROM blocked_contacts WHERE session_id = ?", sessionID); err != nil {
+			return fmt.Errorf("failed to clear blocked contacts: %w", err)
+		}
+		return tx.Commit()
+	}
+
+	args := []any{sessionID}
+	for _, jid := range jids {
+		args = append(args, jid)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(jids)), ",")
+	if _, err = tx.Exec("DELETE FROM blocked_contacts WHERE session_id = ? AND jid NOT IN ("+placeholders+")", args...); err != nil {
+		return fmt.Errorf("failed to prune blocked contacts: %w", err)
+	}
+
+	for _, jid := range jids {
+		if _, err = tx.Exec("INSERT OR IGNORE INTO blocked_contacts (session_id, jid, blocked_at) VALUES (?, ?, ?)", sessionID, jid, blockedAt); err != nil {
+			return fmt.Errorf("failed to store blocked contact: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetBlockedContacts returns the mirrored block list of the context session, most recently blocked first
+func (r *SQLiteRepository) GetBlockedContacts(ctx context.Context) ([]*domainChatStorage.BlockedContact, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT jid, blocked_at FROM blocked_contacts WHERE session_id = ? ORDER BY blocked_at DESC, jid ASC",
+		domainSession.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blocked contacts: %w", err)
+	}
+	defer rows.Close()
+
+	var contacts []*domainChatStorage.BlockedContact
+	for rows.Next() {
+		var contact domainChatStorage.BlockedContact
+		if err := rows.Scan(&contact.JID, &contact.BlockedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan blocked contact: %w", err)
+		}
+		contacts = append(contacts, &contact)
+	}
+
+	return contacts, rows.Err()
+}
+
+// IsContactBlocked reports whether any of the given JIDs is on the block list of the context session.
+// A contact can be blocked under either its phone number or its LID, so callers pass every identity they know.
+func (r *SQLiteRepository) IsContactBlocked(ctx context.Context, jids ...string) (bool, error) {
+	if len(jids) == 0 {
+		return false, nil
+	}
+
+	args := []any{domainSession.FromContext(ctx)}
+	for _, jid := range jids {
+		args = append(args, jid)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(jids)), ",")
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM blocked_contacts WHERE session_id = ? AND jid IN ("+placeholders+")", args...).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check blocked contact: %w", err)
+	}
+
+	return count > 0, nil
+}
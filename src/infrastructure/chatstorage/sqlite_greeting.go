@@ -0,0 +1,115 @@
+package chatstorage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+)
+
+const groupGreetingColumns = "group_jid, welcome_enabled, welcome_template, goodbye_enabled, goodbye_template, dm_enabled, dm_template, batch_seconds, rules_document_name, rules_document, created_at, updated_at"
+
+// StoreGroupGreeting creates or replaces the greeting configuration of a group for the context session
+func (r *SQLiteRepository) StoreGroupGreeting(ctx context.Context, greeting *domainChatStorage.GroupGreeting) error {
+	now := time.Now()
+	if greeting.CreatedAt.IsZero() {
+		greeting.CreatedAt = now
+	}
+	greeting.UpdatedAt = now
+
+	_, err := r.db.Exec(`
+		INSERT INTO group_greetings (session_id, `+groupGreetingColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id, group_jid) DO UPDATE SET
+			welcome_enabled = excluded.welcome_enabled,
+			welcome_template = excluded.welcome_template,
+			goodbye_enabled = excluded.goodbye_enabled,
+			goodbye_template = excluded.goodbye_template,
+			dm_enabled = excluded.dm_enabled,
+			dm_template = excluded.dm_template,
+			batch_seconds = excluded.batch_seconds,
+			rules_document_name = excluded.rules_document_name,
+			rules_document = excluded.rules_document,
+			updated_at = excluded.updated_at
+	`, domainSession.FromContext(ctx), greeting.GroupJID, greeting.WelcomeEnabled, greeting.WelcomeTemplate, greeting.GoodbyeEnabled, greeting.GoodbyeTemplate,
+		greeting.DMEnabled, greeting.DMTemplate, greeting.BatchSeconds, greeting.RulesDocumentName, greeting.RulesDocument,
+		greeting.CreatedAt, greeting.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store group greeting: %w", err)
+	}
+
+	return nil
+}
+
+// GetGroupGreeting returns the greeting configuration of a group for the context session, or nil
+// when it has none
+func (r *SQLiteRepository) GetGroupGreeting(ctx context.Context, groupJID string) (*domainChatStorage.GroupGreeting, error) {
+	greeting, err := scanGroupGreeting(r.db.QueryRow(
+		"SELECT "+groupGreetingColumns+" FROM group_greetings WHERE session_id = ? AND group_jid = ?",
+		domainSession.FromContext(ctx), groupJID,
+	))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group greeting: %w", err)
+	}
+
+	return greeting, nil
+}
+
+// GetGroupGreetings returns the greeting configuration of every group of the context session
+func (r *SQLiteRepository) GetGroupGreetings(ctx context.Context) ([]*domainChatStorage.GroupGreeting, error) {
+	rows, err := r.db.Query(
+		"SELECT "+groupGreetingColumns+" FROM group_greetings WHERE session_id = ? ORDER BY group_jid ASC",
+		domainSession.FromContext(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query group greetings: %w", err)
+	}
+	defer rows.Close()
+
+	var greetings []*domainChatStorage.GroupGreeting
+	for rows.Next() {
+		greeting, err := scanGroupGreeting(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan group greeting: %w", err)
+		}
+		greetings = append(greetings, greeting)
+	}
+
+	return greetings, rows.Err()
+}
+
+// DeleteGroupGreeting removes the greeting configuration of a group for the context session
+func (r *SQLiteRepository) DeleteGroupGreeting(ctx context.Context, groupJID string) error {
+	if _, err := r.db.Exec("DELETE FROM group_greetings WHERE session_id = ? AND group_jid = ?", domainSession.FromContext(ctx), groupJID); err != nil {
+		return fmt.Errorf("failed to delete group greeting: %w", err)
+	}
+	return nil
+}
+
+func scanGroupGreeting(row interface{ Scan(dest ...any) error }) (*domainChatStorage.GroupGreeting, error) {
+	var greeting domainChatStorage.GroupGreeting
+	if err := row.Scan(
+		&greeting.GroupJID,
+		&greeting.WelcomeEnabled,
+		&greeting.WelcomeTemplate,
+		&greeting.GoodbyeEnabled,
+		&greeting.GoodbyeTemplate,
+		&greeting.DMEnabled,
+		&greeting.DMTemplate,
+		&greeting.BatchSeconds,
+		&greeting.RulesDocumentName,
+		&greeting.RulesDocument,
+		&greeting.CreatedAt,
+		&greeting.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return &greeting, nil
+}
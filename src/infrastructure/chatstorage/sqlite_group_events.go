@@ -0,0 +1,199 @@
+package chatstorage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+)
+
+// StoreGroupEvents records group changes seen by the context session. Events already stored for
+// the same group, type, participant and time are ignored so redelivered notifications do not
+// duplicate the log.
+func (r *SQLiteRepository) StoreGroupEvents(ctx context.Context, events []*domainChatStorage.GroupEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT OR IGNORE INTO group_events (session_id, group_jid, event_type, participant_jid, actor_jid, value, timestamp)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare group event insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, event := range events {
+		if event == nil || event.GroupJID == "" || event.EventType == "" {
+			continue
+		}
+
+		timestamp := event.Timestamp
+		if timestamp.IsZero() {
+			timestamp = time.Now()
+		}
+
+		if _, err := stmt.Exec(
+			domainSession.FromContext(ctx),
+			event.GroupJID,
+			event.EventType,
+			r.canonicalJID(ctx, event.Participant),
+			r.canonicalJID(ctx, event.Actor),
+			event.Value,
+			storedTime(timestamp),
+		); err != nil {
+			return fmt.Errorf("failed to store group event: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// groupEventConditions builds the WHERE clause shared by group event queries of the context session
+func (r *SQLiteRepository) groupEventConditions(ctx context.Context, filter *domainChatStorage.GroupEventFilter) (string, []any) {
+	conditions := []string{"session_id = ?", "group_jid = ?"}
+	args := []any{domainSession.FromContext(ctx), filter.GroupJID}
+
+	if filter.Participant != "" {
+		conditions = append(conditions, "participant_jid = ?")
+		args = append(args, r.canonicalJID(ctx, filter.Participant))
+	}
+
+	if filter.Actor != "" {
+		conditions = append(conditions, "actor_jid = ?")
+		args = append(args, r.canonicalJID(ctx, filter.Actor))
+	}
+
+	if len(filter.EventTypes) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(filter.EventTypes)), ",")
+		conditions = append(conditions, "event_type IN ("+placeholders+")")
+		for _, eventType := range filter.EventTypes {
+			args = append(args, eventType)
+		}
+	}
+
+	if filter.StartTime != nil {
+		conditions = append(conditions, "timestamp >= ?")
+		args = append(args, storedTime(*filter.StartTime))
+	}
+
+	if filter.EndTime != nil {
+		conditions = append(conditions, "timestamp <= ?")
+		args = append(args, storedTime(*filter.EndTime))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// GetGroupEvents returns the event log of a group seen by the context session, newest first
+func (r *SQLiteRepository) GetGroupEvents(ctx context.Context, filter *domainChatStorage.GroupEventFilter) ([]*domainChatStorage.GroupEvent, error) {
+	where, args := r.groupEventConditions(ctx, filter)
+
+	query := `
+		SELECT id, group_jid, event_type, participant_jid, actor_jid, value, timestamp, created_at
+		FROM group_events
+		WHERE ` + where + `
+		ORDER BY timestamp DESC, id DESC
+	`
+
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query group events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domainChatStorage.GroupEvent
+	for rows.Next() {
+		event := &domainChatStorage.GroupEvent{}
+		if err := rows.Scan(
+			&event.ID,
+			&event.GroupJID,
+			&event.EventType,
+			&event.Participant,
+			&event.Actor,
+			&event.Value,
+			&event.Timestamp,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan group event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// CountGroupEvents returns the number of events matching the filter, ignoring limit and offset
+func (r *SQLiteRepository) CountGroupEvents(ctx context.Context, filter *domainChatStorage.GroupEventFilter) (int64, error) {
+	where, args := r.groupEventConditions(ctx, filter)
+
+	var count int64
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM group_events WHERE "+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count group events: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetGroupMembershipDaily returns the number of members that joined and left a group per day
+func (r *SQLiteRepository) GetGroupMembershipDaily(ctx context.Context, groupJID string, startTime, endTime *time.Time) ([]*domainChatStorage.GroupMembershipDay, error) {
+	where, args := r.groupEventConditions(ctx, &domainChatStorage.GroupEventFilter{
+		GroupJID: groupJID,
+		EventTypes: []string{
+			domainChatStorage.GroupEventJoin,
+			domainChatStorage.GroupEventAdd,
+			domainChatStorage.GroupEventLeave,
+			domainChatStorage.GroupEventRemove,
+		},
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+
+	query := `
+		SELECT strftime('%Y-%m-%d', timestamp) AS day,
+			SUM(CASE WHEN event_type IN ('join', 'add') THEN 1 ELSE 0 END) AS joined,
+			SUM(CASE WHEN event_type IN ('leave', 'remove') THEN 1 ELSE 0 END) AS left
+		FROM group_events
+		WHERE ` + where + `
+		GROUP BY day
+		HAVING day IS NOT NULL
+		ORDER BY day ASC
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query group membership: %w", err)
+	}
+	defer rows.Close()
+
+	var days []*domainChatStorage.GroupMembershipDay
+	for rows.Next() {
+		day := &domainChatStorage.GroupMembershipDay{}
+		if err := rows.Scan(&day.Day, &day.Joined, &day.Left); err != nil {
+			return nil, fmt.Errorf("failed to scan group membership: %w", err)
+		}
+		days = append(days, day)
+	}
+
+	return days, rows.Err()
+}
@@ -0,0 +1,201 @@
+package chatstorage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+)
+
+// CreateGroupImport stores a new import of the context session together with its pending entries
+func (r *SQLiteRepository) CreateGroupImport(ctx context.Context, groupImport *domainChatStorage.GroupImport, entries []*domainChatStorage.GroupImportEntry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err := tx.Exec(`
+		INSERT INTO group_imports (id, session_id, group_jid, status, error, total, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, groupImport.ID, domainSession.FromContext(ctx), groupImport.GroupJID, groupImport.Status, groupImport.Error, groupImport.Total, now, now); err != nil {
+		return fmt.Errorf("failed to store group import: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO group_import_entries (import_id, phone, jid, status, message, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare group import entry insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		if _, err := stmt.Exec(groupImport.ID, entry.Phone, entry.JID, entry.Status, entry.Message, now); err != nil {
+			return fmt.Errorf("failed to store group import entry %s: %w", entry.Phone, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	groupImport.CreatedAt = now
+	groupImport.UpdatedAt = now
+	return nil
+}
+
+// UpdateGroupImportEntry stores the outcome of a single number and touches its import
+func (r *SQLiteRepository) UpdateGroupImportEntry(entry *domainChatStorage.GroupImportEntry) error {
+	now := time.Now()
+	_, err := r.db.Exec(`
+		UPDATE group_import_entries
+		SET jid = ?, status = ?, message = ?, invited_at = ?, joined_at = ?, updated_at = ?
+		WHERE import_id = ? AND phone = ?
+	`, entry.JID, entry.Status, entry.Message, entry.InvitedAt, entry.JoinedAt, now, entry.ImportID, entry.Phone)
+	if err != nil {
+		return fmt.Errorf("failed to update group import entry: %w", err)
+	}
+
+	if _, err := r.db.Exec("UPDATE group_imports SET updated_at = ? WHERE id = ?", now, entry.ImportID); err != nil {
+		return fmt.Errorf("failed to touch group import: %w", err)
+	}
+
+	entry.UpdatedAt = now
+	return nil
+}
+
+// FinishGroupImport marks an import as completed or failed
+func (r *SQLiteRepository) FinishGroupImport(id, status, errMessage string) error {
+	now := time.Now()
+	_, err := r.db.Exec(`
+		UPDATE group_imports SET status = ?, error = ?, updated_at = ?, finished_at = ?
+		WHERE id = ?
+	`, status, errMessage, now, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to finish group import: %w", err)
+	}
+	return nil
+}
+
+// FailRunningGroupImports marks every import still running as failed with errMessage and
+// returns how many there were
+func (r *SQLiteRepository) FailRunningGroupImports(errMessage string) (int64, error) {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		UPDATE group_imports SET status = ?, error = ?, updated_at = ?, finished_at = ?
+		WHERE status = ?
+	`, domainChatStorage.GroupImportFailed, errMessage, now, now, domainChatStorage.GroupImportRunning)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail running group imports: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// GetGroupImport returns an import of the context session by ID, or nil when it does not exist
+func (r *SQLiteRepository) GetGroupImport(ctx context.Context, id string) (*domainChatStorage.GroupImport, error) {
+	groupImport := &domainChatStorage.GroupImport{}
+	var finishedAt sql.NullTime
+
+	err := r.db.QueryRow(`
+		SELECT id, group_jid, status, error, total, created_at, updated_at, finished_at
+		FROM group_imports WHERE id = ? AND session_id = ?
+	`, id, domainSession.FromContext(ctx)).Scan(
+		&groupImport.ID,
+		&groupImport.GroupJID,
+		&groupImport.Status,
+		&groupImport.Error,
+		&groupImport.Total,
+		&groupImport.CreatedAt,
+		&groupImport.UpdatedAt,
+		&finishedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group import: %w", err)
+	}
+
+	if finishedAt.Valid {
+		groupImport.FinishedAt = &finishedAt.Time
+	}
+
+	return groupImport, nil
+}
+
+// GetGroupImportEntries returns the entries of an import in the order they were imported
+func (r *SQLiteRepository) GetGroupImportEntries(importID string) ([]*domainChatStorage.GroupImportEntry, error) {
+	rows, err := r.db.Query(`
+		SELECT import_id, phone, jid, status, message, invited_at, joined_at, updated_at
+		FROM group_import_entries
+		WHERE import_id = ?
+		ORDER BY rowid ASC
+	`, importID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query group import entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*domainChatStorage.GroupImportEntry
+	for rows.Next() {
+		entry := &domainChatStorage.GroupImportEntry{}
+		var invitedAt, joinedAt sql.NullTime
+		if err := rows.Scan(
+			&entry.ImportID,
+			&entry.Phone,
+			&entry.JID,
+			&entry.Status,
+			&entry.Message,
+			&invitedAt,
+			&joinedAt,
+			&entry.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan group import entry: %w", err)
+		}
+		if invitedAt.Valid {
+			entry.InvitedAt = &invitedAt.Time
+		}
+		if joinedAt.Valid {
+			entry.JoinedAt = &joinedAt.Time
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// MarkGroupImportJoined flags invited numbers of a group as joined once they show up in
+// the group. Only imports of the context session are updated. It returns the number of
+// import entries that changed.
+func (r *SQLiteRepository) MarkGroupImportJoined(ctx context.Context, groupJID string, participantJIDs []string, joinedAt time.Time) (int64, error) {
+	if len(participantJIDs) == 0 {
+		return 0, nil
+	}
+
+	args := []any{domainChatStorage.GroupImportEntryJoined, joinedAt, time.Now()}
+	for _, jid := range participantJIDs {
+		args = append(args, r.canonicalJID(ctx, jid))
+	}
+	args = append(args, domainChatStorage.GroupImportEntryInvited, groupJID, domainSession.FromContext(ctx))
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(participantJIDs)), ",")
+	result, err := r.db.Exec(`
+		UPDATE group_import_entries
+		SET status = ?, joined_at = ?, updated_at = ?
+		WHERE jid IN (`+placeholders+`)
+			AND status = ?
+			AND import_id IN (SELECT id FROM group_imports WHERE group_jid = ? AND session_id = ?)
+	`, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark group import entries as joined: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,180 @@
+package chatstorage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// canonicalJID maps a user JID to its phone number form so every person has a single chat,
+// whichever form the JID arrived in. Group and other JIDs are returned unchanged. The LID
+// mappings are looked up through the session selected by ctx.
+func (r *SQLiteRepository) canonicalJID(ctx context.Context, jid string) string {
+	if r.identity == nil || jid == "" {
+		return jid
+	}
+	return r.identity.CanonicalString(ctx, jid)
+}
+
+// chatIdentity returns the canonical JID of a chat together with its LID, when known
+func (r *SQLiteRepository) chatIdentity(ctx context.Context, jid string) (canonical string, lid string) {
+	canonical = r.canonicalJID(ctx, jid)
+	if r.identity == nil {
+		return canonical, ""
+	}
+
+	parsed, err := types.ParseJID(jid)
+	if err != nil {
+		return canonical, ""
+	}
+
+	switch parsed.Server {
+	case types.HiddenUserServer:
+		return canonical, parsed.ToNonAD().String()
+	case types.DefaultUserServer:
+		if alternate := r.identity.Alternate(ctx, parsed); alternate.Server == types.HiddenUserServer {
+			return canonical, alternate.String()
+		}
+	}
+
+	return canonical, ""
+}
+
+// MergeLIDChats folds chats and senders stored under a LID into their phone number JID
+// once the mapping is known. It is safe to run repeatedly.
+func (r *SQLiteRepository) MergeLIDChats() (int, error) {
+	if r.identity == nil {
+		return 0, nil
+	}
+
+	lidChats, err := r.sessionLIDs("chats", "jid")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list LID chats: %w", err)
+	}
+
+	merged := 0
+	for _, chat := range lidChats {
+		pn := r.canonicalJID(sessionContext(chat.SessionID), chat.JID)
+		if pn == chat.JID {
+			continue
+		}
+
+		if err := r.mergeChat(chat.SessionID, chat.JID, pn); err != nil {
+			return merged, fmt.Errorf("failed to merge chat %s into %s: %w", chat.JID, pn, err)
+		}
+		merged++
+	}
+
+	// Senders and group members are mapped with the LIDs of the session that stored them
+	lidSenders, err := r.sessionLIDs("messages", "sender")
+	if err != nil {
+		return merged, fmt.Errorf("failed to list LID senders: %w", err)
+	}
+
+	for _, sender := range lidSenders {
+		if pn := r.canonicalJID(sessionContext(sender.SessionID), sender.JID); pn != sender.JID {
+			if _, err := r.db.Exec("UPDATE messages SET sender = ? WHERE sender = ? AND session_id = ?", pn, sender.JID, sender.SessionID); err != nil {
+				return merged, fmt.Errorf("failed to update sender %s: %w", sender.JID, err)
+			}
+		}
+	}
+
+	for _, column := range []string{"participant_jid", "actor_jid"} {
+		lidMembers, err := r.sessionLIDs("group_events", column)
+		if err != nil {
+			return merged, fmt.Errorf("failed to list LID group event members: %w", err)
+		}
+
+		for _, member := range lidMembers {
+			if pn := r.canonicalJID(sessionContext(member.SessionID), member.JID); pn != member.JID {
+				if _, err := r.db.Exec("UPDATE OR IGNORE group_events SET "+column+" = ? WHERE "+column+" = ? AND session_id = ?", pn, member.JID, member.SessionID); err != nil {
+					return merged, fmt.Errorf("failed to update group event member %s: %w", member.JID, err)
+				}
+			}
+		}
+	}
+
+	if merged > 0 {
+		logrus.Infof("Merged %d LID chats into their phone number chats", merged)
+	}
+
+	return merged, nil
+}
+
+// sessionLIDs returns every distinct LID still stored in a column of a table, together with the
+// session that stored it. Only the session and JID of the returned chats are set.
+func (r *SQLiteRepository) sessionLIDs(table, column string) ([]*domainChatStorage.Chat, error) {
+	rows, err := r.db.Query("SELECT DISTINCT session_id, "+column+" FROM "+table+" WHERE "+column+" LIKE ?", "%@"+types.HiddenUserServer)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chats []*domainChatStorage.Chat
+	for rows.Next() {
+		chat := &domainChatStorage.Chat{}
+		if err := rows.Scan(&chat.SessionID, &chat.JID); err != nil {
+			return nil, err
+		}
+		chats = append(chats, chat)
+	}
+
+	return chats, rows.Err()
+}
+
+// mergeChat moves a LID chat of a session and its messages onto the phone number chat of the
+// session, keeping the most recent activity, the combined unread counter and the LID for later lookups
+func (r *SQLiteRepository) mergeChat(sessionID, lid, pn string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The phone number chat must exist before messages can reference it
+	if _, err := tx.Exec(`
+		INSERT INTO chats (jid, name, last_message_time, ephemeral_expiration, created_at, updated_at,
+			unread_count, archived, pinned, muted, muted_until, avatar_url, lid, session_id)
+		SELECT ?, name, last_message_time, ephemeral_expiration, created_at, updated_at,
+			0, archived, pinned, muted, muted_until, avatar_url, ?, session_id
+		FROM chats WHERE session_id = ? AND jid = ?
+		ON CONFLICT(session_id, jid) DO NOTHING
+	`, pn, lid, sessionID, lid); err != nil {
+		return err
+	}
+
+	var (
+		lastMessageTime sql.NullTime
+		unreadCount     int
+	)
+	if err := tx.QueryRow("SELECT last_message_time, unread_count FROM chats WHERE session_id = ? AND jid = ?", sessionID, lid).Scan(&lastMessageTime, &unreadCount); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE chats SET
+			last_message_time = MAX(last_message_time, COALESCE(?, last_message_time)),
+			unread_count = unread_count + ?,
+			lid = ?
+		WHERE session_id = ? AND jid = ?
+	`, lastMessageTime, unreadCount, lid, sessionID, pn); err != nil {
+		return err
+	}
+
+	// Messages already stored under the phone number chat win over their LID copies
+	if _, err := tx.Exec("UPDATE OR IGNORE messages SET chat_jid = ? WHERE session_id = ? AND chat_jid = ?", pn, sessionID, lid); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM messages WHERE session_id = ? AND chat_jid = ?", sessionID, lid); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM chats WHERE session_id = ? AND jid = ?", sessionID, lid); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
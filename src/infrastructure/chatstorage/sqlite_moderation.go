@@ -0,0 +1,260 @@
+package chatstorage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+)
+
+const moderationRuleColumns = "id, group_jid, type, words, rate_limit, actions, remove_after, enabled, created_at, updated_at"
+
+// StoreModerationRule inserts a new rule for the context session, or updates the existing one
+// when the rule has an ID
+func (r *SQLiteRepository) StoreModerationRule(ctx context.Context, rule *domainChatStorage.ModerationRule) error {
+	words, err := json.Marshal(rule.Words)
+	if err != nil {
+		return fmt.Errorf("failed to encode banned words: %w", err)
+	}
+	actions := strings.Join(rule.Actions, ",")
+	sessionID := domainSession.FromContext(ctx)
+	now := time.Now()
+
+	if rule.ID == 0 {
+		result, err := r.db.Exec(`
+			INSERT INTO moderation_rules (session_id, group_jid, type, words, rate_limit, actions, remove_after, enabled, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, sessionID, rule.GroupJID, rule.Type, string(words), rule.Limit, actions, rule.RemoveAfter, rule.Enabled, now, now)
+		if err != nil {
+			return fmt.Errorf("failed to store moderation rule: %w", err)
+		}
+
+		if rule.ID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("failed to read moderation rule id: %w", err)
+		}
+		rule.CreatedAt = now
+		rule.UpdatedAt = now
+		return nil
+	}
+
+	if _, err := r.db.Exec(`
+		UPDATE moderation_rules
+		SET group_jid = ?, type = ?, words = ?, rate_limit = ?, actions = ?, remove_after = ?, enabled = ?, updated_at = ?
+		WHERE id = ? AND session_id = ?
+	`, rule.GroupJID, rule.Type, string(words), rule.Limit, actions, rule.RemoveAfter, rule.Enabled, now, rule.ID, sessionID); err != nil {
+		return fmt.Errorf("failed to update moderation rule: %w", err)
+	}
+
+	rule.UpdatedAt = now
+	return nil
+}
+
+// GetModerationRule returns a rule of the context session by ID, or nil when it does not exist
+func (r *SQLiteRepository) GetModerationRule(ctx context.Context, id int64) (*domainChatStorage.ModerationRule, error) {
+	rule, err := scanModerationRule(r.db.QueryRow(
+		"SELECT "+moderationRuleColumns+" FROM moderation_rules WHERE id = ? AND session_id = ?",
+		id, domainSession.FromContext(ctx),
+	))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moderation rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// GetModerationRules returns the rules of the context session for a group, or for every group
+// when groupJID is empty
+func (r *SQLiteRepository) GetModerationRules(ctx context.Context, groupJID string) ([]*domainChatStorage.ModerationRule, error) {
+	query := "SELECT " + moderationRuleColumns + " FROM moderation_rules WHERE session_id = ?"
+	args := []any{domainSession.FromContext(ctx)}
+	if groupJID != "" {
+		query += " AND group_jid = ?"
+		args = append(args, groupJID)
+	}
+	query += " ORDER BY id ASC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query moderation rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*domainChatStorage.ModerationRule
+	for rows.Next() {
+		rule, err := scanModerationRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan moderation rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// DeleteModerationRule removes a rule of the context session. Its audit entries are kept.
+func (r *SQLiteRepository) DeleteModerationRule(ctx context.Context, id int64) error {
+	if _, err := r.db.Exec("DELETE FROM moderation_rules WHERE id = ? AND session_id = ?", id, domainSession.FromContext(ctx)); err != nil {
+		return fmt.Errorf("failed to delete moderation rule: %w", err)
+	}
+	return nil
+}
+
+func scanModerationRule(row interface{ Scan(dest ...any) error }) (*domainChatStorage.ModerationRule, error) {
+	var (
+		rule    domainChatStorage.ModerationRule
+		words   string
+		actions string
+	)
+
+	if err := row.Scan(
+		&rule.ID,
+		&rule.GroupJID,
+		&rule.Type,
+		&words,
+		&rule.Limit,
+		&actions,
+		&rule.RemoveAfter,
+		&rule.Enabled,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(words), &rule.Words); err != nil {
+		return nil, fmt.Errorf("invalid banned words of rule %d: %w", rule.ID, err)
+	}
+	if actions != "" {
+		rule.Actions = strings.Split(actions, ",")
+	}
+
+	return &rule, nil
+}
+
+// StoreModerationAudit records an action taken by the moderation engine of the context session
+func (r *SQLiteRepository) StoreModerationAudit(ctx context.Context, entry *domainChatStorage.ModerationAudit) error {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	entry.SenderJID = r.canonicalJID(ctx, entry.SenderJID)
+
+	result, err := r.db.Exec(`
+		INSERT INTO moderation_audit (session_id, group_jid, rule_id, rule_type, sender_jid, message_id, action, status, error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, domainSession.FromContext(ctx), entry.GroupJID, entry.RuleID, entry.RuleType, entry.SenderJID, entry.MessageID, entry.Action, entry.Status, entry.Error, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store moderation audit: %w", err)
+	}
+
+	if entry.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to read moderation audit id: %w", err)
+	}
+	return nil
+}
+
+// moderationAuditConditions builds the WHERE clause shared by moderation audit queries of the context session
+func (r *SQLiteRepository) moderationAuditConditions(ctx context.Context, filter *domainChatStorage.ModerationAuditFilter) (string, []any) {
+	conditions := []string{"session_id = ?"}
+	args := []any{domainSession.FromContext(ctx)}
+
+	if filter.GroupJID != "" {
+		conditions = append(conditions, "group_jid = ?")
+		args = append(args, filter.GroupJID)
+	}
+
+	if filter.SenderJID != "" {
+		conditions = append(conditions, "sender_jid = ?")
+		args = append(args, r.canonicalJID(ctx, filter.SenderJID))
+	}
+
+	if filter.RuleID > 0 {
+		conditions = append(conditions, "rule_id = ?")
+		args = append(args, filter.RuleID)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// GetModerationAudit returns the moderation audit trail, newest first
+func (r *SQLiteRepository) GetModerationAudit(ctx context.Context, filter *domainChatStorage.ModerationAuditFilter) ([]*domainChatStorage.ModerationAudit, error) {
+	where, args := r.moderationAuditConditions(ctx, filter)
+
+	query := `
+		SELECT id, group_jid, rule_id, rule_type, sender_jid, message_id, action, status, error, created_at
+		FROM moderation_audit
+		WHERE ` + where + `
+		ORDER BY created_at DESC, id DESC
+	`
+
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query moderation audit: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*domainChatStorage.ModerationAudit
+	for rows.Next() {
+		entry := &domainChatStorage.ModerationAudit{}
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.GroupJID,
+			&entry.RuleID,
+			&entry.RuleType,
+			&entry.SenderJID,
+			&entry.MessageID,
+			&entry.Action,
+			&entry.Status,
+			&entry.Error,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan moderation audit: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// CountModerationAudit returns the number of audit entries matching the filter, ignoring limit and offset
+func (r *SQLiteRepository) CountModerationAudit(ctx context.Context, filter *domainChatStorage.ModerationAuditFilter) (int64, error) {
+	where, args := r.moderationAuditConditions(ctx, filter)
+
+	var count int64
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM moderation_audit WHERE "+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count moderation audit: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountModerationViolations returns how many distinct messages of a sender broke a rule.
+// A violation can produce several audit entries, one per action taken.
+func (r *SQLiteRepository) CountModerationViolations(ctx context.Context, ruleID int64, senderJID string) (int64, error) {
+	var count int64
+	err := r.db.QueryRow(`
+		SELECT COUNT(DISTINCT message_id) FROM moderation_audit WHERE session_id = ? AND rule_id = ? AND sender_jid = ?
+	`, domainSession.FromContext(ctx), ruleID, r.canonicalJID(ctx, senderJID)).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count moderation violations: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,283 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+// numberCheckLookupChunk keeps IN (...) lists well below SQLite's bound parameter limit
+const numberCheckLookupChunk = 500
+
+// StoreNumberChecks creates or refreshes cached number checks
+func (r *SQLiteRepository) StoreNumberChecks(checks []*domainChatStorage.NumberCheck) error {
+	if len(checks) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO number_checks (phone, is_on_whatsapp, jid, lid, is_business, business_name, checked_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(phone) DO UPDATE SET
+			is_on_whatsapp = excluded.is_on_whatsapp,
+			jid = excluded.jid,
+			lid = excluded.lid,
+			is_business = excluded.is_business,
+			business_name = excluded.business_name,
+			checked_at = excluded.checked_at
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare number check insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, check := range checks {
+		if check.CheckedAt.IsZero() {
+			check.CheckedAt = time.Now()
+		}
+		if _, err := stmt.Exec(check.Phone, check.IsOnWhatsApp, check.JID, check.LID, check.IsBusiness, check.BusinessName, check.CheckedAt); err != nil {
+			return fmt.Errorf("failed to store number check %s: %w", check.Phone, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetNumberChecks returns the cached checks of the given phones made at or after checkedSince, keyed by phone
+func (r *SQLiteRepository) GetNumberChecks(phones []string, checkedSince time.Time) (map[string]*domainChatStorage.NumberCheck, error) {
+	checks := make(map[string]*domainChatStorage.NumberCheck, len(phones))
+
+	for start := 0; start < len(phones); start += numberCheckLookupChunk {
+		chunk := phones[start:min(start+numberCheckLookupChunk, len(phones))]
+
+		args := make([]any, 0, len(chunk)+1)
+		for _, phone := range chunk {
+			args = append(args, phone)
+		}
+		args = append(args, checkedSince)
+
+		rows, err := r.db.Query(`
+			SELECT phone, is_on_whatsapp, jid, lid, is_business, business_name, checked_at
+			FROM number_checks
+			WHERE phone IN (`+strings.TrimSuffix(strings.Repeat("?,", len(chunk)), ",")+`) AND checked_at >= ?
+		`, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query number checks: %w", err)
+		}
+
+		for rows.Next() {
+			check := &domainChatStorage.NumberCheck{}
+			if err := rows.Scan(&check.Phone, &check.IsOnWhatsApp, &check.JID, &check.LID, &check.IsBusiness, &check.BusinessName, &check.CheckedAt); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan number check: %w", err)
+			}
+			checks[check.Phone] = check
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return checks, nil
+}
+
+// DeleteNumberChecksBefore removes cached checks older than checkedBefore
+func (r *SQLiteRepository) DeleteNumberChecksBefore(checkedBefore time.Time) (int64, error) {
+	result, err := r.db.Exec("DELETE FROM number_checks WHERE checked_at < ?", checkedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired number checks: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// CreateNumberCheckJob stores a new bulk check together with a pending entry per phone
+func (r *SQLiteRepository) CreateNumberCheckJob(job *domainChatStorage.NumberCheckJob, phones []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err := tx.Exec(`
+		INSERT INTO number_check_jobs (id, status, error, total, checked, created_at, updated_at)
+		VALUES (?, ?, ?, ?, 0, ?, ?)
+	`, job.ID, job.Status, job.Error, job.Total, now, now); err != nil {
+		return fmt.Errorf("failed to store number check job: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO number_check_job_entries (job_id, position, phone, status)
+		VALUES (?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare number check entry insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, phone := range phones {
+		if _, err := stmt.Exec(job.ID, i, phone, domainChatStorage.NumberCheckEntryPending); err != nil {
+			return fmt.Errorf("failed to store number check entry %s: %w", phone, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	job.CreatedAt = now
+	job.UpdatedAt = now
+	return nil
+}
+
+// UpdateNumberCheckJobEntries stores the outcome of a batch of entries and refreshes the job progress
+func (r *SQLiteRepository) UpdateNumberCheckJobEntries(jobID string, entries []*domainChatStorage.NumberCheckJobEntry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		UPDATE number_check_job_entries
+		SET status = ?, message = ?, is_on_whatsapp = ?, jid = ?, lid = ?, is_business = ?, business_name = ?, cached = ?, checked_at = ?
+		WHERE job_id = ? AND phone = ?
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare number check entry update: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		var checkedAt any
+		if !entry.CheckedAt.IsZero() {
+			checkedAt = entry.CheckedAt
+		}
+		if _, err := stmt.Exec(entry.Status, entry.Message, entry.IsOnWhatsApp, entry.JID, entry.LID, entry.IsBusiness,
+			entry.BusinessName, entry.Cached, checkedAt, jobID, entry.Phone); err != nil {
+			return fmt.Errorf("failed to update number check entry %s: %w", entry.Phone, err)
+		}
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE number_check_jobs
+		SET checked = (SELECT COUNT(*) FROM number_check_job_entries WHERE job_id = ? AND status != ?), updated_at = ?
+		WHERE id = ?
+	`, jobID, domainChatStorage.NumberCheckEntryPending, time.Now(), jobID); err != nil {
+		return fmt.Errorf("failed to update number check job progress: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// FinishNumberCheckJob marks a bulk check as completed or failed
+func (r *SQLiteRepository) FinishNumberCheckJob(id, status, errMessage string) error {
+	now := time.Now()
+	_, err := r.db.Exec(`
+		UPDATE number_check_jobs SET status = ?, error = ?, updated_at = ?, finished_at = ?
+		WHERE id = ?
+	`, status, errMessage, now, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to finish number check job: %w", err)
+	}
+	return nil
+}
+
+// FailRunningNumberCheckJobs marks every bulk check still running as failed with errMessage and
+// returns how many there were
+func (r *SQLiteRepository) FailRunningNumberCheckJobs(errMessage string) (int64, error) {
+	now := time.Now()
+	result, err := r.db.Exec(`
+		UPDATE number_check_jobs SET status = ?, error = ?, updated_at = ?, finished_at = ?
+		WHERE status = ?
+	`, domainChatStorage.NumberCheckJobFailed, errMessage, now, now, domainChatStorage.NumberCheckJobRunning)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail running number check jobs: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// GetNumberCheckJob returns a bulk check by ID, or nil when it does not exist
+func (r *SQLiteRepository) GetNumberCheckJob(id string) (*domainChatStorage.NumberCheckJob, error) {
+	job := &domainChatStorage.NumberCheckJob{}
+	var finishedAt sql.NullTime
+
+	err := r.db.QueryRow(`
+		SELECT id, status, error, total, checked, created_at, updated_at, finished_at
+		FROM number_check_jobs WHERE id = ?
+	`, id).Scan(
+		&job.ID,
+		&job.Status,
+		&job.Error,
+		&job.Total,
+		&job.Checked,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&finishedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get number check job: %w", err)
+	}
+
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+
+	return job, nil
+}
+
+// GetNumberCheckJobEntries returns the entries of a bulk check in the order the numbers were given
+func (r *SQLiteRepository) GetNumberCheckJobEntries(jobID string) ([]*domainChatStorage.NumberCheckJobEntry, error) {
+	rows, err := r.db.Query(`
+		SELECT job_id, position, phone, status, message, is_on_whatsapp, jid, lid, is_business, business_name, cached, checked_at
+		FROM number_check_job_entries
+		WHERE job_id = ?
+		ORDER BY position ASC
+	`, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query number check entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*domainChatStorage.NumberCheckJobEntry
+	for rows.Next() {
+		entry := &domainChatStorage.NumberCheckJobEntry{}
+		var checkedAt sql.NullTime
+		if err := rows.Scan(
+			&entry.JobID,
+			&entry.Position,
+			&entry.Phone,
+			&entry.Status,
+			&entry.Message,
+			&entry.IsOnWhatsApp,
+			&entry.JID,
+			&entry.LID,
+			&entry.IsBusiness,
+			&entry.BusinessName,
+			&entry.Cached,
+			&checkedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan number check entry: %w", err)
+		}
+		if checkedAt.Valid {
+			entry.CheckedAt = checkedAt.Time
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
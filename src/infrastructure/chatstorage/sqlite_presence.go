@@ -0,0 +1,180 @@
+package chatstorage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+)
+
+// StorePresenceSubscriptions remembers contacts the context session subscribes to, keeping the
+// original time of existing ones
+func (r *SQLiteRepository) StorePresenceSubscriptions(ctx context.Context, jids []string, subscribedAt time.Time) error {
+	sessionID := domainSession.FromContext(ctx)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, jid := range jids {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO presence_subscriptions (session_id, jid, subscribed_at) VALUES (?, ?, ?)", sessionID, jid, subscribedAt); err != nil {
+			return fmt.Errorf("failed to store presence subscription: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DeletePresenceSubscriptions stops re-subscribing the context session to the given contacts.
+// Their history is kept.
+func (r *SQLiteRepository) DeletePresenceSubscriptions(ctx context.Context, jids []string) error {
+	if len(jids) == 0 {
+		return nil
+	}
+
+	args := []any{domainSession.FromContext(ctx)}
+	for _, jid := range jids {
+		args = append(args, jid)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(jids)), ",")
+
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM presence_subscriptions WHERE session_id = ? AND jid IN ("+placeholders+")", args...); err != nil {
+		return fmt.Errorf("failed to delete presence subscriptions: %w", err)
+	}
+	return nil
+}
+
+// GetPresenceSubscriptions returns every contact the context session subscribes to, oldest subscription first
+func (r *SQLiteRepository) GetPresenceSubscriptions(ctx context.Context) ([]*domainChatStorage.PresenceSubscription, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT jid, subscribed_at FROM presence_subscriptions WHERE session_id = ? ORDER BY subscribed_at ASC, jid ASC",
+		domainSession.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query presence subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []*domainChatStorage.PresenceSubscription
+	for rows.Next() {
+		var subscription domainChatStorage.PresenceSubscription
+		if err := rows.Scan(&subscription.JID, &subscription.SubscribedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan presence subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, &subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// StorePresence records a presence change in the history and as the contact's current presence.
+// A missing last seen keeps the previously known one, as WhatsApp only sends it when going offline.
+func (r *SQLiteRepository) StorePresence(presence *domainChatStorage.ContactPresence) error {
+	if presence.UpdatedAt.IsZero() {
+		presence.UpdatedAt = time.Now()
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO contact_presence (jid, online, last_seen, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(jid) DO UPDATE SET
+			online = excluded.online,
+			last_seen = COALESCE(excluded.last_seen, contact_presence.last_seen),
+			updated_at = excluded.updated_at
+	`, presence.JID, presence.Online, presence.LastSeen, presence.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to store presence: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO presence_history (jid, online, last_seen, created_at)
+		VALUES (?, ?, ?, ?)
+	`, presence.JID, presence.Online, presence.LastSeen, presence.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to store presence history: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetContactPresences returns the last known presence of the given contacts keyed by JID,
+// or of every known contact when no JIDs are given
+func (r *SQLiteRepository) GetContactPresences(jids []string) (map[string]*domainChatStorage.ContactPresence, error) {
+	query := "SELECT jid, online, last_seen, updated_at FROM contact_presence"
+	var args []any
+	if len(jids) > 0 {
+		args = make([]any, len(jids))
+		for i, jid := range jids {
+			args[i] = jid
+		}
+		query += " WHERE jid IN (" + strings.TrimSuffix(strings.Repeat("?,", len(jids)), ",") + ")"
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contact presence: %w", err)
+	}
+	defer rows.Close()
+
+	presences := make(map[string]*domainChatStorage.ContactPresence)
+	for rows.Next() {
+		presence := &domainChatStorage.ContactPresence{}
+		var lastSeen sql.NullTime
+		if err := rows.Scan(&presence.JID, &presence.Online, &lastSeen, &presence.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan contact presence: %w", err)
+		}
+		if lastSeen.Valid {
+			presence.LastSeen = &lastSeen.Time
+		}
+		presences[presence.JID] = presence
+	}
+
+	return presences, rows.Err()
+}
+
+// GetPresenceHistory returns the most recent presence changes of a contact, newest first
+func (r *SQLiteRepository) GetPresenceHistory(jid string, limit int) ([]*domainChatStorage.PresenceEvent, error) {
+	rows, err := r.db.Query(`
+		SELECT id, jid, online, last_seen, created_at
+		FROM presence_history
+		WHERE jid = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`, jid, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query presence history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []*domainChatStorage.PresenceEvent
+	for rows.Next() {
+		event := &domainChatStorage.PresenceEvent{}
+		var lastSeen sql.NullTime
+		if err := rows.Scan(&event.ID, &event.JID, &event.Online, &lastSeen, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan presence history: %w", err)
+		}
+		if lastSeen.Valid {
+			event.LastSeen = &lastSeen.Time
+		}
+		history = append(history, event)
+	}
+
+	return history, rows.Err()
+}
+
+// DeletePresenceHistoryBefore removes presence changes recorded before the given time
+func (r *SQLiteRepository) DeletePresenceHistoryBefore(before time.Time) (int64, error) {
+	result, err := r.db.Exec("DELETE FROM presence_history WHERE created_at < ?", before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete presence history: %w", err)
+	}
+	return result.RowsAffected()
+}
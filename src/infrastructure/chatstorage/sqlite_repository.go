@@ -8,50 +8,71 @@ import (
 	"time"
 
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainIdentity "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/identity"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
+// chatColumns lists the chat columns in the order expected by scanChat
+const chatColumns = `jid, name, last_message_time, ephemeral_expiration, created_at, updated_at,
+			unread_count, archived, pinned, muted, muted_until, avatar_url, lid, session_id`
+
+// messageColumns lists the message columns in the order expected by scanMessage
+const messageColumns = `id, chat_jid, sender, content, timestamp, is_from_me,
+			media_type, filename, url, media_key, file_sha256,
+			file_enc_sha256, file_length, created_at, updated_at, session_id`
+
 // SQLiteRepository implements Repository using SQLite
 type SQLiteRepository struct {
-	db *sql.DB
+	db       *sql.DB
+	identity domainIdentity.IIdentityResolver
 }
 
-// NewSQLiteRepository creates a new SQLite repository
-func NewStorageRepository(db *sql.DB) domainChatStorage.IChatStorageRepository {
-	return &SQLiteRepository{db: db}
+// NewSQLiteRepository creates a new SQLite repository. The identity resolver is used to store
+// every user under their phone number JID, it may be nil to store JIDs as they are.
+func NewStorageRepository(db *sql.DB, identity domainIdentity.IIdentityResolver) domainChatStorage.IChatStorageRepository {
+	return &SQLiteRepository{db: db, identity: identity}
 }
 
-// StoreChat creates or updates a chat
+// StoreChat creates or updates the chat of a session, a chat without session belongs to the default session
 func (r *SQLiteRepository) StoreChat(chat *domainChatStorage.Chat) error {
 	now := time.Now()
 	chat.UpdatedAt = now
+	chat.SessionID = storedSessionID(chat.SessionID)
+
+	var lid string
+	chat.JID, lid = r.chatIdentity(sessionContext(chat.SessionID), chat.JID)
+	if lid != "" {
+		chat.LID = lid
+	}
 
 	query := `
-		INSERT INTO chats (jid, name, last_message_time, ephemeral_expiration, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
-		ON CONFLICT(jid) DO UPDATE SET
+		INSERT INTO chats (jid, name, last_message_time, ephemeral_expiration, created_at, updated_at, lid, session_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id, jid) DO UPDATE SET
 			name = excluded.name,
 			last_message_time = excluded.last_message_time,
 			ephemeral_expiration = excluded.ephemeral_expiration,
-			updated_at = excluded.updated_at
+			updated_at = excluded.updated_at,
+			lid = CASE WHEN excluded.lid != '' THEN excluded.lid ELSE chats.lid END
 	`
 
-	_, err := r.db.Exec(query, chat.JID, chat.Name, chat.LastMessageTime, chat.EphemeralExpiration, now, chat.UpdatedAt)
+	_, err := r.db.Exec(query, chat.JID, chat.Name, storedTime(chat.LastMessageTime), chat.EphemeralExpiration, now, chat.UpdatedAt, chat.LID, chat.SessionID)
 	return err
 }
 
-// GetChat retrieves a chat by JID
-func (r *SQLiteRepository) GetChat(jid string) (*domainChatStorage.Chat, error) {
+// GetChat retrieves a chat of the context session by JID
+func (r *SQLiteRepository) GetChat(ctx context.Context, jid string) (*domainChatStorage.Chat, error) {
 	query := `
-		SELECT jid, name, last_message_time, ephemeral_expiration, created_at, updated_at
+		SELECT ` + chatColumns + `
 		FROM chats
-		WHERE jid = ?
+		WHERE session_id = ? AND jid = ?
 	`
 
-	chat, err := r.scanChat(r.db.QueryRow(query, jid))
+	chat, err := r.scanChat(r.db.QueryRowContext(ctx, query, domainSession.FromContext(ctx), r.canonicalJID(ctx, jid)))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -59,19 +80,17 @@ func (r *SQLiteRepository) GetChat(jid string) (*domainChatStorage.Chat, error)
 	return chat, err
 }
 
-// GetMessageByID retrieves a message by its ID from any chat
+// GetMessageByID retrieves a message of the context session by its ID from any chat
 // This is more efficient than searching through all chats
-func (r *SQLiteRepository) GetMessageByID(id string) (*domainChatStorage.Message, error) {
+func (r *SQLiteRepository) GetMessageByID(ctx context.Context, id string) (*domainChatStorage.Message, error) {
 	query := `
-		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
-			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+		SELECT ` + messageColumns + `
 		FROM messages
-		WHERE id = ?
+		WHERE session_id = ? AND id = ?
 		LIMIT 1
 	`
 
-	message, err := r.scanMessage(r.db.QueryRow(query, id))
+	message, err := r.scanMessage(r.db.QueryRowContext(ctx, query, domainSession.FromContext(ctx), id))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -79,31 +98,41 @@ func (r *SQLiteRepository) GetMessageByID(id string) (*domainChatStorage.Message
 	return message, err
 }
 
-// GetChats retrieves chats with filtering
+// GetChats retrieves chats with filtering, including each chat's latest message
 func (r *SQLiteRepository) GetChats(filter *domainChatStorage.ChatFilter) ([]*domainChatStorage.Chat, error) {
 	var conditions []string
 	var args []any
 
-	query := `
-		SELECT c.jid, c.name, c.last_message_time, c.ephemeral_expiration, c.created_at, c.updated_at
-		FROM chats c
+	inner := `
+		SELECT ` + chatColumns + `
+		FROM chats
 	`
 
 	if filter.SearchName != "" {
-		conditions = append(conditions, "c.name LIKE ?")
-		args = append(args, "%"+filter.SearchName+"%")
+		conditions = append(conditions, "(name LIKE ? OR jid IN (SELECT jid FROM address_book ab WHERE ab.session_id = chats.session_id AND ab.custom_name LIKE ?))")
+		args = append(args, "%"+filter.SearchName+"%", "%"+filter.SearchName+"%")
 	}
 
 	if filter.HasMedia {
-		query += " INNER JOIN messages m ON c.jid = m.chat_jid"
-		conditions = append(conditions, "m.media_type != ''")
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM messages m WHERE m.session_id = chats.session_id AND m.chat_jid = chats.jid AND m.media_type != '')")
+	}
+
+	if filter.SessionID != "" {
+		conditions = append(conditions, "session_id = ?")
+		args = append(args, filter.SessionID)
+	}
+
+	cursorCondition, cursorArgs, innerOrder := cursorClause(filter.Before, filter.After, "last_message_time", "jid")
+	if cursorCondition != "" {
+		conditions = append(conditions, cursorCondition)
+		args = append(args, cursorArgs...)
 	}
 
 	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+		inner += " WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	query += " ORDER BY c.last_message_time DESC"
+	inner += " ORDER BY " + innerOrder
 
 	// Safely add LIMIT and OFFSET using parameterized values
 	if filter.Limit > 0 {
@@ -111,15 +140,33 @@ func (r *SQLiteRepository) GetChats(filter *domainChatStorage.ChatFilter) ([]*do
 		if filter.Limit > 1000 {
 			filter.Limit = 1000
 		}
-		query += " LIMIT ?"
+		inner += " LIMIT ?"
 		args = append(args, filter.Limit)
 
 		if filter.Offset > 0 {
-			query += " OFFSET ?"
+			inner += " OFFSET ?"
 			args = append(args, filter.Offset)
 		}
 	}
 
+	// The page of chats is selected first so the latest-message lookup, which uses
+	// idx_messages_chat_timestamp, only runs for the chats actually returned.
+	// Custom names from the address book take precedence over the stored name.
+	query := `
+		SELECT c.jid, COALESCE(NULLIF(ab.custom_name, ''), c.name), c.last_message_time, c.ephemeral_expiration, c.created_at, c.updated_at,
+			c.unread_count, c.archived, c.pinned, c.muted, c.muted_until, c.avatar_url, c.lid, c.session_id,
+			m.id, m.sender, m.content, m.timestamp, m.is_from_me, m.media_type
+		FROM (` + inner + `) c
+		LEFT JOIN address_book ab ON ab.session_id = c.session_id AND ab.jid = c.jid
+		LEFT JOIN messages m ON m.rowid = (
+			SELECT rowid FROM messages
+			WHERE session_id = c.session_id AND chat_jid = c.jid
+			ORDER BY timestamp DESC
+			LIMIT 1
+		)
+		ORDER BY c.last_message_time DESC, c.jid DESC
+	`
+
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
@@ -128,18 +175,51 @@ func (r *SQLiteRepository) GetChats(filter *domainChatStorage.ChatFilter) ([]*do
 
 	var chats []*domainChatStorage.Chat
 	for rows.Next() {
-		chat, err := r.scanChat(rows)
+		var (
+			chat          = &domainChatStorage.Chat{}
+			mutedUntil    sql.NullTime
+			lastID        sql.NullString
+			lastSender    sql.NullString
+			lastContent   sql.NullString
+			lastTimestamp sql.NullTime
+			lastIsFromMe  sql.NullBool
+			lastMediaType sql.NullString
+		)
+		err := rows.Scan(
+			&chat.JID, &chat.Name, &chat.LastMessageTime, &chat.EphemeralExpiration,
+			&chat.CreatedAt, &chat.UpdatedAt,
+			&chat.UnreadCount, &chat.Archived, &chat.Pinned, &chat.Muted, &mutedUntil, &chat.AvatarURL, &chat.LID, &chat.SessionID,
+			&lastID, &lastSender, &lastContent, &lastTimestamp, &lastIsFromMe, &lastMediaType,
+		)
 		if err != nil {
 			return nil, err
 		}
+
+		if mutedUntil.Valid {
+			chat.MutedUntil = &mutedUntil.Time
+		}
+		if lastID.Valid {
+			chat.LastMessage = &domainChatStorage.Message{
+				ID:        lastID.String,
+				ChatJID:   chat.JID,
+				Sender:    lastSender.String,
+				Content:   lastContent.String,
+				Timestamp: lastTimestamp.Time,
+				IsFromMe:  lastIsFromMe.Bool,
+				MediaType: lastMediaType.String,
+			}
+		}
 		chats = append(chats, chat)
 	}
 
 	return chats, rows.Err()
 }
 
-// DeleteChat deletes a chat and all its messages
-func (r *SQLiteRepository) DeleteChat(jid string) error {
+// DeleteChat deletes a chat of the context session and all its messages
+func (r *SQLiteRepository) DeleteChat(ctx context.Context, jid string) error {
+	jid = r.canonicalJID(ctx, jid)
+	sessionID := domainSession.FromContext(ctx)
+
 	tx, err := r.db.Begin()
 	if err != nil {
 		return err
@@ -147,13 +227,13 @@ func (r *SQLiteRepository) DeleteChat(jid string) error {
 	defer tx.Rollback()
 
 	// Delete messages first (foreign key constraint)
-	_, err = tx.Exec("DELETE FROM messages WHERE chat_jid = ?", jid)
+	_, err = tx.Exec("DELETE FROM messages WHERE session_id = ? AND chat_jid = ?", sessionID, jid)
 	if err != nil {
 		return err
 	}
 
 	// Delete chat
-	_, err = tx.Exec("DELETE FROM chats WHERE jid = ?", jid)
+	_, err = tx.Exec("DELETE FROM chats WHERE session_id = ? AND jid = ?", sessionID, jid)
 	if err != nil {
 		return err
 	}
@@ -173,13 +253,17 @@ func (r *SQLiteRepository) StoreMessage(message *domainChatStorage.Message) erro
 		return nil
 	}
 
+	message.SessionID = storedSessionID(message.SessionID)
+	message.ChatJID = r.canonicalJID(sessionContext(message.SessionID), message.ChatJID)
+	message.Sender = r.canonicalJID(sessionContext(message.SessionID), message.Sender)
+
 	query := `
 		INSERT INTO messages (
 			id, chat_jid, sender, content, timestamp, is_from_me, 
 			media_type, filename, url, media_key, file_sha256, 
-			file_enc_sha256, file_length, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(id, chat_jid) DO UPDATE SET
+			file_enc_sha256, file_length, created_at, updated_at, session_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id, id, chat_jid) DO UPDATE SET
 			sender = excluded.sender,
 			content = excluded.content,
 			timestamp = excluded.timestamp,
@@ -196,9 +280,9 @@ func (r *SQLiteRepository) StoreMessage(message *domainChatStorage.Message) erro
 
 	_, err := r.db.Exec(query,
 		message.ID, message.ChatJID, message.Sender, message.Content,
-		message.Timestamp, message.IsFromMe, message.MediaType, message.Filename,
+		storedTime(message.Timestamp), message.IsFromMe, message.MediaType, message.Filename,
 		message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
-		message.FileLength, message.CreatedAt, message.UpdatedAt,
+		message.FileLength, message.CreatedAt, message.UpdatedAt, message.SessionID,
 	)
 
 	return err
@@ -221,9 +305,9 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 		INSERT INTO messages (
 			id, chat_jid, sender, content, timestamp, is_from_me, 
 			media_type, filename, url, media_key, file_sha256, 
-			file_enc_sha256, file_length, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(id, chat_jid) DO UPDATE SET
+			file_enc_sha256, file_length, created_at, updated_at, session_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id, id, chat_jid) DO UPDATE SET
 			sender = excluded.sender,
 			content = excluded.content,
 			timestamp = excluded.timestamp,
@@ -251,12 +335,15 @@ func (r *SQLiteRepository) StoreMessagesBatch(messages []*domainChatStorage.Mess
 
 		message.CreatedAt = now
 		message.UpdatedAt = now
+		message.SessionID = storedSessionID(message.SessionID)
+		message.ChatJID = r.canonicalJID(sessionContext(message.SessionID), message.ChatJID)
+		message.Sender = r.canonicalJID(sessionContext(message.SessionID), message.Sender)
 
 		_, err = stmt.Exec(
 			message.ID, message.ChatJID, message.Sender, message.Content,
-			message.Timestamp, message.IsFromMe, message.MediaType, message.Filename,
+			storedTime(message.Timestamp), message.IsFromMe, message.MediaType, message.Filename,
 			message.URL, message.MediaKey, message.FileSHA256, message.FileEncSHA256,
-			message.FileLength, message.CreatedAt, message.UpdatedAt,
+			message.FileLength, message.CreatedAt, message.UpdatedAt, message.SessionID,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to store message %s: %w", message.ID, err)
@@ -272,16 +359,16 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 	var args []any
 
 	conditions = append(conditions, "chat_jid = ?")
-	args = append(args, filter.ChatJID)
+	args = append(args, r.canonicalJID(sessionContext(filter.SessionID), filter.ChatJID))
 
 	if filter.StartTime != nil {
 		conditions = append(conditions, "timestamp >= ?")
-		args = append(args, *filter.StartTime)
+		args = append(args, storedTime(*filter.StartTime))
 	}
 
 	if filter.EndTime != nil {
 		conditions = append(conditions, "timestamp <= ?")
-		args = append(args, *filter.EndTime)
+		args = append(args, storedTime(*filter.EndTime))
 	}
 
 	if filter.MediaOnly {
@@ -293,13 +380,22 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 		args = append(args, *filter.IsFromMe)
 	}
 
+	if filter.SessionID != "" {
+		conditions = append(conditions, "session_id = ?")
+		args = append(args, filter.SessionID)
+	}
+
+	cursorCondition, cursorArgs, order := cursorClause(filter.Before, filter.After, "timestamp", "id")
+	if cursorCondition != "" {
+		conditions = append(conditions, cursorCondition)
+		args = append(args, cursorArgs...)
+	}
+
 	query := `
-		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
-			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+		SELECT ` + messageColumns + `
 		FROM messages
 		WHERE ` + strings.Join(conditions, " AND ") + `
-		ORDER BY timestamp DESC
+		ORDER BY ` + order + `
 	`
 
 	// Safely add LIMIT and OFFSET using parameterized values
@@ -317,6 +413,12 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 		}
 	}
 
+	// Rows newer than an after cursor are read oldest first so the limit keeps the ones
+	// adjacent to the cursor, then re-sorted to the usual newest-first order.
+	if filter.After != nil {
+		query = `SELECT * FROM (` + query + `) ORDER BY timestamp DESC, id DESC`
+	}
+
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
@@ -335,8 +437,26 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 	return messages, rows.Err()
 }
 
-// SearchMessages performs database-level search for messages containing specific text
-func (r *SQLiteRepository) SearchMessages(chatJID, searchText string, limit int) ([]*domainChatStorage.Message, error) {
+// cursorClause builds the keyset condition and ordering for cursor pagination over
+// (timeColumn, idColumn). Results are newest first unless an after cursor is given,
+// in which case they are ordered oldest first so LIMIT keeps the rows nearest the cursor.
+func cursorClause(before, after *domainChatStorage.PageCursor, timeColumn, idColumn string) (string, []any, string) {
+	descending := timeColumn + " DESC, " + idColumn + " DESC"
+
+	switch {
+	case before != nil:
+		condition := fmt.Sprintf("(%[1]s < ? OR (%[1]s = ? AND %[2]s < ?))", timeColumn, idColumn)
+		return condition, []any{storedTime(before.Timestamp), storedTime(before.Timestamp), before.ID}, descending
+	case after != nil:
+		condition := fmt.Sprintf("(%[1]s > ? OR (%[1]s = ? AND %[2]s > ?))", timeColumn, idColumn)
+		return condition, []any{storedTime(after.Timestamp), storedTime(after.Timestamp), after.ID}, timeColumn + " ASC, " + idColumn + " ASC"
+	default:
+		return "", nil, descending
+	}
+}
+
+// SearchMessages performs database-level search for messages of the context session containing specific text
+func (r *SQLiteRepository) SearchMessages(ctx context.Context, chatJID, searchText string, limit int) ([]*domainChatStorage.Message, error) {
 	// Return empty results for empty search text
 	if strings.TrimSpace(searchText) == "" {
 		return []*domainChatStorage.Message{}, nil
@@ -345,18 +465,16 @@ func (r *SQLiteRepository) SearchMessages(chatJID, searchText string, limit int)
 	var conditions []string
 	var args []any
 
-	// Always filter by chat JID
-	conditions = append(conditions, "chat_jid = ?")
-	args = append(args, chatJID)
+	// Always filter by session and chat JID
+	conditions = append(conditions, "session_id = ?", "chat_jid = ?")
+	args = append(args, domainSession.FromContext(ctx), r.canonicalJID(ctx, chatJID))
 
 	// Add search condition using LIKE operator for case-insensitive search
 	conditions = append(conditions, "LOWER(content) LIKE ?")
 	args = append(args, "%"+strings.ToLower(searchText)+"%")
 
 	query := `
-		SELECT id, chat_jid, sender, content, timestamp, is_from_me,
-			media_type, filename, url, media_key, file_sha256,
-			file_enc_sha256, file_length, created_at, updated_at
+		SELECT ` + messageColumns + `
 		FROM messages
 		WHERE ` + strings.Join(conditions, " AND ") + `
 		ORDER BY timestamp DESC
@@ -372,7 +490,7 @@ func (r *SQLiteRepository) SearchMessages(chatJID, searchText string, limit int)
 		args = append(args, limit)
 	}
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search messages: %w", err)
 	}
@@ -394,12 +512,32 @@ func (r *SQLiteRepository) SearchMessages(chatJID, searchText string, limit int)
 	return messages, nil
 }
 
-// DeleteMessage deletes a specific message
-func (r *SQLiteRepository) DeleteMessage(id, chatJID string) error {
-	_, err := r.db.Exec("DELETE FROM messages WHERE id = ? AND chat_jid = ?", id, chatJID)
+// DeleteMessage deletes a specific message of the context session
+func (r *SQLiteRepository) DeleteMessage(ctx context.Context, id, chatJID string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM messages WHERE session_id = ? AND id = ? AND chat_jid = ?",
+		domainSession.FromContext(ctx), id, r.canonicalJID(ctx, chatJID))
 	return err
 }
 
+// storedSessionID returns the session rows are stored under, rows without one belong to the default session
+func storedSessionID(sessionID string) string {
+	if sessionID == "" {
+		return domainSession.DefaultSessionID
+	}
+	return sessionID
+}
+
+// storedTime returns t in UTC, the offset every timestamp is stored and compared in. SQLite
+// compares the timestamps as text, which only orders them correctly when they share an offset.
+func storedTime(t time.Time) time.Time {
+	return t.UTC()
+}
+
+// sessionContext selects the session whose LID mappings resolve the JIDs of its rows
+func sessionContext(sessionID string) context.Context {
+	return domainSession.NewContext(context.Background(), storedSessionID(sessionID))
+}
+
 // getCount is a private helper for count queries
 func (r *SQLiteRepository) getCount(query string, args ...any) (int64, error) {
 	var count int64
@@ -414,7 +552,7 @@ func (r *SQLiteRepository) scanMessage(scanner interface{ Scan(...any) error })
 		&message.ID, &message.ChatJID, &message.Sender, &message.Content,
 		&message.Timestamp, &message.IsFromMe, &message.MediaType, &message.Filename,
 		&message.URL, &message.MediaKey, &message.FileSHA256, &message.FileEncSHA256,
-		&message.FileLength, &message.CreatedAt, &message.UpdatedAt,
+		&message.FileLength, &message.CreatedAt, &message.UpdatedAt, &message.SessionID,
 	)
 	return message, err
 }
@@ -422,16 +560,78 @@ func (r *SQLiteRepository) scanMessage(scanner interface{ Scan(...any) error })
 // scanChat is a private helper for scanning chat rows
 func (r *SQLiteRepository) scanChat(scanner interface{ Scan(...any) error }) (*domainChatStorage.Chat, error) {
 	chat := &domainChatStorage.Chat{}
+	var mutedUntil sql.NullTime
 	err := scanner.Scan(
 		&chat.JID, &chat.Name, &chat.LastMessageTime, &chat.EphemeralExpiration,
 		&chat.CreatedAt, &chat.UpdatedAt,
+		&chat.UnreadCount, &chat.Archived, &chat.Pinned, &chat.Muted, &mutedUntil, &chat.AvatarURL, &chat.LID, &chat.SessionID,
 	)
+	if mutedUntil.Valid {
+		chat.MutedUntil = &mutedUntil.Time
+	}
 	return chat, err
 }
 
-// GetChatMessageCount returns the number of messages in a chat
-func (r *SQLiteRepository) GetChatMessageCount(chatJID string) (int64, error) {
-	return r.getCount("SELECT COUNT(*) FROM messages WHERE chat_jid = ?", chatJID)
+// SetChatUnreadCount overrides the unread counter of a chat of the context session
+func (r *SQLiteRepository) SetChatUnreadCount(ctx context.Context, jid string, count int) error {
+	return r.updateChat(ctx, jid, "unread_count = ?", count)
+}
+
+// MarkChatAsRead recalculates the unread counter of a chat of the context session so only
+// inbound messages newer than readAt remain unread
+func (r *SQLiteRepository) MarkChatAsRead(ctx context.Context, jid string, readAt time.Time) error {
+	jid = r.canonicalJID(ctx, jid)
+	sessionID := domainSession.FromContext(ctx)
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE chats SET unread_count = (
+			SELECT COUNT(*) FROM messages
+			WHERE session_id = ? AND chat_jid = ? AND is_from_me = FALSE AND timestamp > ?
+		)
+		WHERE session_id = ? AND jid = ?
+	`, sessionID, jid, storedTime(readAt), sessionID, jid)
+	return err
+}
+
+// SetChatArchived updates the archived flag of a chat of the context session
+func (r *SQLiteRepository) SetChatArchived(ctx context.Context, jid string, archived bool) error {
+	return r.updateChat(ctx, jid, "archived = ?", archived)
+}
+
+// SetChatPinned updates the pinned flag of a chat of the context session
+func (r *SQLiteRepository) SetChatPinned(ctx context.Context, jid string, pinned bool) error {
+	return r.updateChat(ctx, jid, "pinned = ?", pinned)
+}
+
+// SetChatMuted updates the mute state of a chat of the context session, a nil mutedUntil means muted indefinitely
+func (r *SQLiteRepository) SetChatMuted(ctx context.Context, jid string, muted bool, mutedUntil *time.Time) error {
+	var until any
+	if muted && mutedUntil != nil {
+		until = *mutedUntil
+	}
+	return r.updateChat(ctx, jid, "muted = ?, muted_until = ?", muted, until)
+}
+
+// SetChatAvatarURL caches the avatar URL of a chat of the context session
+func (r *SQLiteRepository) SetChatAvatarURL(ctx context.Context, jid string, avatarURL string) error {
+	return r.updateChat(ctx, jid, "avatar_url = ?", avatarURL)
+}
+
+// updateChat applies the SET clause to a chat of the context session
+func (r *SQLiteRepository) updateChat(ctx context.Context, jid string, set string, args ...any) error {
+	args = append(args, domainSession.FromContext(ctx), r.canonicalJID(ctx, jid))
+	_, err := r.db.ExecContext(ctx, "UPDATE chats SET "+set+" WHERE session_id = ? AND jid = ?", args...)
+	return err
+}
+
+// messageExists reports whether a message is already stored for the session
+func (r *SQLiteRepository) messageExists(sessionID, id, chatJID string) (bool, error) {
+	count, err := r.getCount("SELECT COUNT(*) FROM messages WHERE session_id = ? AND id = ? AND chat_jid = ?", sessionID, id, chatJID)
+	return count > 0, err
+}
+
+// GetChatMessageCount returns the number of messages in a chat of the context session
+func (r *SQLiteRepository) GetChatMessageCount(ctx context.Context, chatJID string) (int64, error) {
+	return r.getCount("SELECT COUNT(*) FROM messages WHERE session_id = ? AND chat_jid = ?", domainSession.FromContext(ctx), r.canonicalJID(ctx, chatJID))
 }
 
 // GetTotalMessageCount returns the total number of messages
@@ -453,6 +653,58 @@ func (r *SQLiteRepository) TruncateAllChats() error {
 	}
 	defer tx.Rollback()
 
+	_, err = tx.Exec("DELETE FROM group_events")
+	if err != nil {
+		return fmt.Errorf("failed to delete group events: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM group_import_entries")
+	if err != nil {
+		return fmt.Errorf("failed to delete group import entries: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM group_imports")
+	if err != nil {
+		return fmt.Errorf("failed to delete group imports: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM number_check_job_entries")
+	if err != nil {
+		return fmt.Errorf("failed to delete number check job entries: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM number_check_jobs")
+	if err != nil {
+		return fmt.Errorf("failed to delete number check jobs: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM presence_history")
+	if err != nil {
+		return fmt.Errorf("failed to delete presence history: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM contact_presence")
+	if err != nil {
+		return fmt.Errorf("failed to delete contact presence: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM presence_subscriptions")
+	if err != nil {
+		return fmt.Errorf("failed to delete presence subscriptions: %w", err)
+	}
+
+	// The block list belongs to the account that is being cleared
+	_, err = tx.Exec("DELETE FROM blocked_contacts")
+	if err != nil {
+		return fmt.Errorf("failed to delete blocked contacts: %w", err)
+	}
+
+	// Moderation rules are configuration and survive; only the audit trail is cleared
+	_, err = tx.Exec("DELETE FROM moderation_audit")
+	if err != nil {
+		return fmt.Errorf("failed to delete moderation audit: %w", err)
+	}
+
 	// Delete messages first (foreign key constraint)
 	_, err = tx.Exec("DELETE FROM messages")
 	if err != nil {
@@ -468,10 +720,19 @@ func (r *SQLiteRepository) TruncateAllChats() error {
 	return tx.Commit()
 }
 
-// GetChatNameWithPushName determines the appropriate name for a chat with pushname support
-func (r *SQLiteRepository) GetChatNameWithPushName(jid types.JID, chatJID string, senderUser string, pushName string) string {
+// GetChatNameWithPushName determines the appropriate name for a chat of the context session with
+// pushname support. A custom name from the address book always wins.
+func (r *SQLiteRepository) GetChatNameWithPushName(ctx context.Context, jid types.JID, chatJID string, senderUser string, pushName string) string {
+	if r.identity != nil {
+		jid = r.identity.Canonical(ctx, jid)
+	}
+
+	if customName := r.GetCustomName(ctx, jid.String()); customName != "" {
+		return customName
+	}
+
 	// First, check if chat already exists with a name
-	existingChat, err := r.GetChat(chatJID)
+	existingChat, err := r.GetChat(ctx, chatJID)
 	if err == nil && existingChat != nil && existingChat.Name != "" {
 		// If we have a pushname and the existing name is just a phone number/JID user, update it
 		if pushName != "" && (existingChat.Name == jid.User || existingChat.Name == senderUser) {
@@ -511,16 +772,22 @@ func (r *SQLiteRepository) CreateMessage(ctx context.Context, evt *events.Messag
 		return nil
 	}
 
-	// Extract chat and sender information
-	chatJID := evt.Info.Chat.String()
+	// Extract chat and sender information, users are stored under their phone number JID
+	chat := evt.Info.Chat
+	senderJID := evt.Info.Sender
+	if r.identity != nil {
+		chat = r.identity.Canonical(ctx, chat)
+		senderJID = r.identity.Canonical(ctx, senderJID)
+	}
+	chatJID := chat.String()
 	// Store the full sender JID (user@server) to ensure consistency between received and sent messages
-	sender := evt.Info.Sender.String()
+	sender := senderJID.String()
 
 	// Get appropriate chat name using pushname if available
-	chatName := r.GetChatNameWithPushName(evt.Info.Chat, chatJID, evt.Info.Sender.User, evt.Info.PushName)
+	chatName := r.GetChatNameWithPushName(ctx, chat, chatJID, senderJID.User, evt.Info.PushName)
 
 	// Get existing chat to preserve ephemeral_expiration if needed
-	existingChat, err := r.GetChat(chatJID)
+	existingChat, err := r.GetChat(ctx, chatJID)
 	if err != nil {
 		return fmt.Errorf("failed to get existing chat: %w", err)
 	}
@@ -528,23 +795,28 @@ func (r *SQLiteRepository) CreateMessage(ctx context.Context, evt *events.Messag
 	// Extract ephemeral expiration from incoming message
 	ephemeralExpiration := utils.ExtractEphemeralExpiration(evt.Message)
 
-	// Create or update chat
-	chat := &domainChatStorage.Chat{
+	// Create or update chat, keeping the LID form when the chat arrived as one
+	sessionID := domainSession.FromContext(ctx)
+	storedChat := &domainChatStorage.Chat{
 		JID:             chatJID,
 		Name:            chatName,
 		LastMessageTime: evt.Info.Timestamp,
+		SessionID:       sessionID,
+	}
+	if evt.Info.Chat.Server == types.HiddenUserServer {
+		storedChat.LID = evt.Info.Chat.ToNonAD().String()
 	}
 
 	// Set ephemeral expiration: use incoming message value if > 0, otherwise preserve existing
 	if ephemeralExpiration > 0 {
-		chat.EphemeralExpiration = ephemeralExpiration
+		storedChat.EphemeralExpiration = ephemeralExpiration
 	} else if existingChat != nil {
 		// Preserve existing ephemeral_expiration if incoming message doesn't have one
-		chat.EphemeralExpiration = existingChat.EphemeralExpiration
+		storedChat.EphemeralExpiration = existingChat.EphemeralExpiration
 	}
 
 	// Store or update the chat
-	if err := r.StoreChat(chat); err != nil {
+	if err := r.StoreChat(storedChat); err != nil {
 		return fmt.Errorf("failed to store chat: %w", err)
 	}
 
@@ -573,10 +845,27 @@ func (r *SQLiteRepository) CreateMessage(ctx context.Context, evt *events.Messag
 		FileSHA256:    fileSHA256,
 		FileEncSHA256: fileEncSHA256,
 		FileLength:    fileLength,
+		SessionID:     sessionID,
+	}
+
+	exists, err := r.messageExists(sessionID, message.ID, chatJID)
+	if err != nil {
+		return fmt.Errorf("failed to check existing message: %w", err)
 	}
 
 	// Store the message
-	return r.StoreMessage(message)
+	if err := r.StoreMessage(message); err != nil {
+		return err
+	}
+
+	// Replying from another device clears the unread counter, new inbound messages increment it
+	if message.IsFromMe {
+		return r.SetChatUnreadCount(ctx, chatJID, 0)
+	}
+	if !exists {
+		err = r.updateChat(ctx, chatJID, "unread_count = unread_count + 1")
+	}
+	return err
 }
 
 // GetStorageStatistics returns current storage statistics for logging purposes
@@ -641,11 +930,14 @@ func (r *SQLiteRepository) StoreSentMessageWithContext(ctx context.Context, mess
 	if err != nil {
 		return fmt.Errorf("invalid JID format: %w", err)
 	}
+	if r.identity != nil {
+		jid = r.identity.Canonical(ctx, jid)
+	}
 
 	chatJID := jid.String()
 
 	// Get chat name (no pushname available for sent messages)
-	chatName := r.GetChatNameWithPushName(jid, chatJID, jid.User, "")
+	chatName := r.GetChatNameWithPushName(ctx, jid, chatJID, jid.User, "")
 
 	// Check context again before database operations
 	select {
@@ -655,7 +947,7 @@ func (r *SQLiteRepository) StoreSentMessageWithContext(ctx context.Context, mess
 	}
 
 	// Get existing chat to preserve ephemeral_expiration
-	existingChat, err := r.GetChat(chatJID)
+	existingChat, err := r.GetChat(ctx, chatJID)
 	if err != nil {
 		return fmt.Errorf("failed to get existing chat: %w", err)
 	}
@@ -665,6 +957,7 @@ func (r *SQLiteRepository) StoreSentMessageWithContext(ctx context.Context, mess
 		JID:             chatJID,
 		Name:            chatName,
 		LastMessageTime: timestamp,
+		SessionID:       domainSession.FromContext(ctx),
 	}
 
 	// Preserve existing ephemeral_expiration if chat exists
@@ -691,9 +984,15 @@ func (r *SQLiteRepository) StoreSentMessageWithContext(ctx context.Context, mess
 		Content:   content,
 		Timestamp: timestamp,
 		IsFromMe:  true,
+		SessionID: chat.SessionID,
+	}
+
+	if err := r.StoreMessage(message); err != nil {
+		return err
 	}
 
-	return r.StoreMessage(message)
+	// Sending a message to a chat implies it has been read
+	return r.SetChatUnreadCount(ctx, chatJID, 0)
 }
 
 // _____________________________________________________________________________________________________________________
@@ -810,5 +1109,566 @@ func (r *SQLiteRepository) getMigrations() []string {
 		`
 		CREATE INDEX IF NOT EXISTS idx_messages_id ON messages(id);
 		`,
+
+		// Migration 3: Add composite index for per-chat timeline scans (analytics)
+		`
+		CREATE INDEX IF NOT EXISTS idx_messages_chat_timestamp ON messages(chat_jid, timestamp);
+		`,
+
+		// Migration 4: Add unread counter, chat state flags and cached avatar to chats
+		`
+		ALTER TABLE chats ADD COLUMN unread_count INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE chats ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE chats ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE chats ADD COLUMN muted BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE chats ADD COLUMN muted_until TIMESTAMP;
+		ALTER TABLE chats ADD COLUMN avatar_url TEXT NOT NULL DEFAULT '';
+		`,
+
+		// Migration 5: Keep the LID of chats stored under their phone number JID
+		`
+		ALTER TABLE chats ADD COLUMN lid TEXT NOT NULL DEFAULT '';
+		CREATE INDEX IF NOT EXISTS idx_chats_lid ON chats(lid);
+		`,
+
+		// Migration 6: Group event log for membership and setting changes
+		`
+		CREATE TABLE IF NOT EXISTS group_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			group_jid TEXT NOT NULL,
+			event_type TEXT NOT NULL,
+			participant_jid TEXT NOT NULL DEFAULT '',
+			actor_jid TEXT NOT NULL DEFAULT '',
+			value TEXT NOT NULL DEFAULT '',
+			timestamp TIMESTAMP NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (group_jid, event_type, participant_jid, timestamp)
+		);
+		CREATE INDEX IF NOT EXISTS idx_group_events_group_timestamp ON group_events(group_jid, timestamp);
+		CREATE INDEX IF NOT EXISTS idx_group_events_participant ON group_events(participant_jid);
+		`,
+
+		// Migration 7: Bulk participant imports and their per-number outcome
+		`
+		CREATE TABLE IF NOT EXISTS group_imports (
+			id TEXT PRIMARY KEY,
+			group_jid TEXT NOT NULL,
+			status TEXT NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			total INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			finished_at TIMESTAMP
+		);
+		CREATE INDEX IF NOT EXISTS idx_group_imports_group ON group_imports(group_jid);
+
+		CREATE TABLE IF NOT EXISTS group_import_entries (
+			import_id TEXT NOT NULL,
+			phone TEXT NOT NULL,
+			jid TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			message TEXT NOT NULL DEFAULT '',
+			invited_at TIMESTAMP,
+			joined_at TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (import_id, phone),
+			FOREIGN KEY (import_id) REFERENCES group_imports(id) ON DELETE CASCADE
+		);
+		CREATE INDEX IF NOT EXISTS idx_group_import_entries_jid ON group_import_entries(jid, status);
+		`,
+
+		// Migration 8: Group moderation rules and the audit trail of actions taken
+		`
+		CREATE TABLE IF NOT EXISTS moderation_rules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			group_jid TEXT NOT NULL,
+			type TEXT NOT NULL,
+			words TEXT NOT NULL DEFAULT '[]',
+			rate_limit INTEGER NOT NULL DEFAULT 0,
+			actions TEXT NOT NULL DEFAULT '',
+			remove_after INTEGER NOT NULL DEFAULT 0,
+			enabled BOOLEAN NOT NULL DEFAULT 1,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE INDEX IF NOT EXISTS idx_moderation_rules_group ON moderation_rules(group_jid);
+
+		CREATE TABLE IF NOT EXISTS moderation_audit (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			group_jid TEXT NOT NULL,
+			rule_id INTEGER NOT NULL,
+			rule_type TEXT NOT NULL,
+			sender_jid TEXT NOT NULL,
+			message_id TEXT NOT NULL DEFAULT '',
+			action TEXT NOT NULL,
+			status TEXT NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE INDEX IF NOT EXISTS idx_moderation_audit_group ON moderation_audit(group_jid, created_at);
+		CREATE INDEX IF NOT EXISTS idx_moderation_audit_sender ON moderation_audit(rule_id, sender_jid);
+		`,
+
+		// Migration 9: Welcome and goodbye messages per group
+		`
+		CREATE TABLE IF NOT EXISTS group_greetings (
+			group_jid TEXT PRIMARY KEY,
+			welcome_enabled BOOLEAN NOT NULL DEFAULT 0,
+			welcome_template TEXT NOT NULL DEFAULT '',
+			goodbye_enabled BOOLEAN NOT NULL DEFAULT 0,
+			goodbye_template TEXT NOT NULL DEFAULT '',
+			dm_enabled BOOLEAN NOT NULL DEFAULT 0,
+			dm_template TEXT NOT NULL DEFAULT '',
+			batch_seconds INTEGER NOT NULL DEFAULT 0,
+			rules_document_name TEXT NOT NULL DEFAULT '',
+			rules_document BLOB,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		`,
+
+		// Migration 10: Mirror of the account block list
+		`
+		CREATE TABLE IF NOT EXISTS blocked_contacts (
+			jid TEXT PRIMARY KEY,
+			blocked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		`,
+
+		// Migration 11: Number check cache and bulk number check jobs
+		`
+		CREATE TABLE IF NOT EXISTS number_checks (
+			phone TEXT PRIMARY KEY,
+			is_on_whatsapp BOOLEAN NOT NULL DEFAULT 0,
+			jid TEXT NOT NULL DEFAULT '',
+			lid TEXT NOT NULL DEFAULT '',
+			is_business BOOLEAN NOT NULL DEFAULT 0,
+			business_name TEXT NOT NULL DEFAULT '',
+			checked_at TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_number_checks_checked_at ON number_checks(checked_at);
+
+		CREATE TABLE IF NOT EXISTS number_check_jobs (
+			id TEXT PRIMARY KEY,
+			status TEXT NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			total INTEGER NOT NULL DEFAULT 0,
+			checked INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			finished_at TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS number_check_job_entries (
+			job_id TEXT NOT NULL,
+			position INTEGER NOT NULL,
+			phone TEXT NOT NULL,
+			status TEXT NOT NULL,
+			message TEXT NOT NULL DEFAULT '',
+			is_on_whatsapp BOOLEAN NOT NULL DEFAULT 0,
+			jid TEXT NOT NULL DEFAULT '',
+			lid TEXT NOT NULL DEFAULT '',
+			is_business BOOLEAN NOT NULL DEFAULT 0,
+			business_name TEXT NOT NULL DEFAULT '',
+			cached BOOLEAN NOT NULL DEFAULT 0,
+			checked_at TIMESTAMP,
+			PRIMARY KEY (job_id, phone),
+			FOREIGN KEY (job_id) REFERENCES number_check_jobs(id) ON DELETE CASCADE
+		);
+		`,
+
+		// Migration 12: Presence subscriptions, last known presence and online/offline history
+		`
+		CREATE TABLE IF NOT EXISTS presence_subscriptions (
+			jid TEXT PRIMARY KEY,
+			subscribed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS contact_presence (
+			jid TEXT PRIMARY KEY,
+			online BOOLEAN NOT NULL DEFAULT 0,
+			last_seen TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS presence_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			jid TEXT NOT NULL,
+			online BOOLEAN NOT NULL DEFAULT 0,
+			last_seen TIMESTAMP,
+			created_at TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_presence_history_jid ON presence_history(jid, created_at);
+		CREATE INDEX IF NOT EXISTS idx_presence_history_created_at ON presence_history(created_at);
+		`,
+
+		// Migration 13: Address book with custom names, notes, custom fields and tags
+		`
+		CREATE TABLE IF NOT EXISTS address_book (
+			jid TEXT PRIMARY KEY,
+			custom_name TEXT NOT NULL DEFAULT '',
+			notes TEXT NOT NULL DEFAULT '',
+			custom_fields TEXT NOT NULL DEFAULT '{}',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS address_book_tags (
+			jid TEXT NOT NULL,
+			tag TEXT NOT NULL,
+			PRIMARY KEY (jid, tag),
+			FOREIGN KEY (jid) REFERENCES address_book(jid) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_address_book_tags_tag ON address_book_tags(tag);
+		`,
+
+		// Migration 14: Sessions of the session manager and the session of stored chats and messages
+		`
+		CREATE TABLE IF NOT EXISTS sessions (
+			id TEXT PRIMARY KEY,
+			device_jid TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+
+		ALTER TABLE chats ADD COLUMN session_id TEXT NOT NULL DEFAULT '';
+		ALTER TABLE messages ADD COLUMN session_id TEXT NOT NULL DEFAULT '';
+
+		CREATE INDEX IF NOT EXISTS idx_chats_session_id ON chats(session_id);
+		CREATE INDEX IF NOT EXISTS idx_messages_session_id ON messages(session_id, chat_jid);
+		`,
+
+		// Migration 15: Webhook outbox with the attempts of every delivery
+		`
+		CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			session_id TEXT NOT NULL DEFAULT '',
+			event TEXT NOT NULL DEFAULT '',
+			url TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			status TEXT NOT NULL DEFAULT 'pending',
+			attempts INTEGER NOT NULL DEFAULT 0,
+			max_attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at TIMESTAMP NOT NULL,
+			last_status_code INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			delivered_at TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			delivery_id INTEGER NOT NULL,
+			attempt INTEGER NOT NULL,
+			status_code INTEGER NOT NULL DEFAULT 0,
+			error TEXT NOT NULL DEFAULT '',
+			duration_ms INTEGER NOT NULL DEFAULT 0,
+			attempted_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+		CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_session ON webhook_deliveries(session_id, created_at);
+		CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery ON webhook_delivery_attempts(delivery_id, attempt);
+		`,
+
+		// Migration 16: Webhook definitions with their event and chat filters
+		`
+		CREATE TABLE IF NOT EXISTS webhook_definitions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			url TEXT NOT NULL,
+			events TEXT NOT NULL DEFAULT '',
+			include_chats TEXT NOT NULL DEFAULT '[]',
+			exclude_chats TEXT NOT NULL DEFAULT '[]',
+			include_from_me BOOLEAN NOT NULL DEFAULT 1,
+			chat_type TEXT NOT NULL DEFAULT 'all',
+			enabled BOOLEAN NOT NULL DEFAULT 1,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		`,
+
+		// Migration 17: Key chats and messages by session so accounts sharing a chat keep their own copy.
+		// Rows older than sessions belong to the default session, and every session with messages in a
+		// chat gets its own copy of the chat.
+		`
+		UPDATE chats SET session_id = 'default' WHERE session_id = '';
+		UPDATE messages SET session_id = 'default' WHERE session_id = '';
+
+		CREATE TABLE chats_by_session (
+			session_id TEXT NOT NULL,
+			jid TEXT NOT NULL,
+			name TEXT NOT NULL,
+			last_message_time TIMESTAMP NOT NULL,
+			ephemeral_expiration INTEGER DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			unread_count INTEGER NOT NULL DEFAULT 0,
+			archived BOOLEAN NOT NULL DEFAULT FALSE,
+			pinned BOOLEAN NOT NULL DEFAULT FALSE,
+			muted BOOLEAN NOT NULL DEFAULT FALSE,
+			muted_until TIMESTAMP,
+			avatar_url TEXT NOT NULL DEFAULT '',
+			lid TEXT NOT NULL DEFAULT '',
+			PRIMARY KEY (session_id, jid)
+		);
+
+		INSERT INTO chats_by_session (session_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at,
+			unread_count, archived, pinned, muted, muted_until, avatar_url, lid)
+		SELECT session_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at,
+			unread_count, archived, pinned, muted, muted_until, avatar_url, lid
+		FROM chats;
+
+		INSERT OR IGNORE INTO chats_by_session (session_id, jid, name, last_message_time, ephemeral_expiration, created_at, updated_at,
+			unread_count, archived, pinned, muted, muted_until, avatar_url, lid)
+		SELECT m.session_id, c.jid, c.name, c.last_message_time, c.ephemeral_expiration, c.created_at, c.updated_at,
+			0, c.archived, c.pinned, c.muted, c.muted_until, c.avatar_url, c.lid
+		FROM chats c
+		JOIN (SELECT DISTINCT session_id, chat_jid FROM messages) m ON m.chat_jid = c.jid;
+
+		CREATE TABLE messages_by_session (
+			session_id TEXT NOT NULL,
+			id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			sender TEXT NOT NULL,
+			content TEXT,
+			timestamp TIMESTAMP NOT NULL,
+			is_from_me BOOLEAN DEFAULT FALSE,
+			media_type TEXT,
+			filename TEXT,
+			url TEXT,
+			media_key BLOB,
+			file_sha256 BLOB,
+			file_enc_sha256 BLOB,
+			file_length INTEGER DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (session_id, id, chat_jid),
+			FOREIGN KEY (session_id, chat_jid) REFERENCES chats_by_session(session_id, jid) ON DELETE CASCADE
+		);
+
+		INSERT INTO messages_by_session (session_id, id, chat_jid, sender, content, timestamp, is_from_me,
+			media_type, filename, url, media_key, file_sha256, file_enc_sha256, file_length, created_at, updated_at)
+		SELECT session_id, id, chat_jid, sender, content, timestamp, is_from_me,
+			media_type, filename, url, media_key, file_sha256, file_enc_sha256, file_length, created_at, updated_at
+		FROM messages
+		WHERE EXISTS (SELECT 1 FROM chats_by_session c WHERE c.session_id = messages.session_id AND c.jid = messages.chat_jid);
+
+		DROP TABLE messages;
+		DROP TABLE chats;
+		ALTER TABLE chats_by_session RENAME TO chats;
+		ALTER TABLE messages_by_session RENAME TO messages;
+
+		CREATE INDEX IF NOT EXISTS idx_chats_last_message ON chats(last_message_time);
+		CREATE INDEX IF NOT EXISTS idx_chats_name ON chats(name);
+		CREATE INDEX IF NOT EXISTS idx_chats_lid ON chats(lid);
+		CREATE INDEX IF NOT EXISTS idx_chats_jid ON chats(jid);
+
+		CREATE INDEX IF NOT EXISTS idx_messages_chat_jid ON messages(chat_jid);
+		CREATE INDEX IF NOT EXISTS idx_messages_timestamp ON messages(timestamp);
+		CREATE INDEX IF NOT EXISTS idx_messages_media_type ON messages(media_type);
+		CREATE INDEX IF NOT EXISTS idx_messages_sender ON messages(sender);
+		CREATE INDEX IF NOT EXISTS idx_messages_id ON messages(id);
+		CREATE INDEX IF NOT EXISTS idx_messages_chat_timestamp ON messages(chat_jid, timestamp);
+		CREATE INDEX IF NOT EXISTS idx_messages_session_chat_timestamp ON messages(session_id, chat_jid, timestamp);
+		`,
+
+		// Migration 18: Keep the block list mirror and presence subscriptions per session, existing
+		// rows belong to the default session
+		`
+		CREATE TABLE blocked_contacts_by_session (
+			session_id TEXT NOT NULL,
+			jid TEXT NOT NULL,
+			blocked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (session_id, jid)
+		);
+		INSERT INTO blocked_contacts_by_session (session_id, jid, blocked_at)
+		SELECT 'default', jid, blocked_at FROM blocked_contacts;
+		DROP TABLE blocked_contacts;
+		ALTER TABLE blocked_contacts_by_session RENAME TO blocked_contacts;
+
+		CREATE TABLE presence_subscriptions_by_session (
+			session_id TEXT NOT NULL,
+			jid TEXT NOT NULL,
+			subscribed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (session_id, jid)
+		);
+		INSERT INTO presence_subscriptions_by_session (session_id, jid, subscribed_at)
+		SELECT 'default', jid, subscribed_at FROM presence_subscriptions;
+		DROP TABLE presence_subscriptions;
+		ALTER TABLE presence_subscriptions_by_session RENAME TO presence_subscriptions;
+		`,
+
+		// Migration 19: Store the sort timestamps in UTC. Rows written on a host outside UTC kept its
+		// offset, which breaks the text comparisons of cursor pagination and time filters.
+		`
+		UPDATE messages
+		SET timestamp = strftime('%Y-%m-%d %H:%M:%S', timestamp) || substr(timestamp, 20, length(timestamp) - 25) || '+00:00'
+		WHERE length(timestamp) >= 25 AND substr(timestamp, -6, 1) IN ('+', '-') AND substr(timestamp, -6) != '+00:00';
+
+		UPDATE chats
+		SET last_message_time = strftime('%Y-%m-%d %H:%M:%S', last_message_time) || substr(last_message_time, 20, length(last_message_time) - 25) || '+00:00'
+		WHERE length(last_message_time) >= 25 AND substr(last_message_time, -6, 1) IN ('+', '-') AND substr(last_message_time, -6) != '+00:00';
+
+		UPDATE OR IGNORE group_events
+		SET timestamp = strftime('%Y-%m-%d %H:%M:%S', timestamp) || substr(timestamp, 20, length(timestamp) - 25) || '+00:00'
+		WHERE length(timestamp) >= 25 AND substr(timestamp, -6, 1) IN ('+', '-') AND substr(timestamp, -6) != '+00:00';
+		`,
+
+		// Migration 20: Keep group events, imports, moderation, greetings and the address book per
+		// session, existing rows belong to the default session
+		`
+		CREATE TABLE group_events_by_session (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			session_id TEXT NOT NULL,
+			group_jid TEXT NOT NULL,
+			event_type TEXT NOT NULL,
+			participant_jid TEXT NOT NULL DEFAULT '',
+			actor_jid TEXT NOT NULL DEFAULT '',
+			value TEXT NOT NULL DEFAULT '',
+			timestamp TIMESTAMP NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (session_id, group_jid, event_type, participant_jid, timestamp)
+		);
+		INSERT INTO group_events_by_session (id, session_id, group_jid, event_type, participant_jid, actor_jid, value, timestamp, created_at)
+		SELECT id, 'default', group_jid, event_type, participant_jid, actor_jid, value, timestamp, created_at FROM group_events;
+		DROP TABLE group_events;
+		ALTER TABLE group_events_by_session RENAME TO group_events;
+		CREATE INDEX IF NOT EXISTS idx_group_events_group_timestamp ON group_events(session_id, group_jid, timestamp);
+		CREATE INDEX IF NOT EXISTS idx_group_events_participant ON group_events(participant_jid);
+
+		CREATE TABLE group_imports_by_session (
+			id TEXT PRIMARY KEY,
+			session_id TEXT NOT NULL,
+			group_jid TEXT NOT NULL,
+			status TEXT NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			total INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			finished_at TIMESTAMP
+		);
+		INSERT INTO group_imports_by_session (id, session_id, group_jid, status, error, total, created_at, updated_at, finished_at)
+		SELECT id, 'default', group_jid, status, error, total, created_at, updated_at, finished_at FROM group_imports;
+
+		CREATE TABLE group_import_entries_by_session (
+			import_id TEXT NOT NULL,
+			phone TEXT NOT NULL,
+			jid TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			message TEXT NOT NULL DEFAULT '',
+			invited_at TIMESTAMP,
+			joined_at TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (import_id, phone),
+			FOREIGN KEY (import_id) REFERENCES group_imports_by_session(id) ON DELETE CASCADE
+		);
+		INSERT INTO group_import_entries_by_session (import_id, phone, jid, status, message, invited_at, joined_at, updated_at)
+		SELECT import_id, phone, jid, status, message, invited_at, joined_at, updated_at FROM group_import_entries;
+
+		DROP TABLE group_import_entries;
+		DROP TABLE group_imports;
+		ALTER TABLE group_imports_by_session RENAME TO group_imports;
+		ALTER TABLE group_import_entries_by_session RENAME TO group_import_entries;
+		CREATE INDEX IF NOT EXISTS idx_group_imports_group ON group_imports(session_id, group_jid);
+		CREATE INDEX IF NOT EXISTS idx_group_import_entries_jid ON group_import_entries(jid, status);
+
+		CREATE TABLE moderation_rules_by_session (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			session_id TEXT NOT NULL,
+			group_jid TEXT NOT NULL,
+			type TEXT NOT NULL,
+			words TEXT NOT NULL DEFAULT '[]',
+			rate_limit INTEGER NOT NULL DEFAULT 0,
+			actions TEXT NOT NULL DEFAULT '',
+			remove_after INTEGER NOT NULL DEFAULT 0,
+			enabled BOOLEAN NOT NULL DEFAULT 1,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		INSERT INTO moderation_rules_by_session (id, session_id, group_jid, type, words, rate_limit, actions, remove_after, enabled, created_at, updated_at)
+		SELECT id, 'default', group_jid, type, words, rate_limit, actions, remove_after, enabled, created_at, updated_at FROM moderation_rules;
+		DROP TABLE moderation_rules;
+		ALTER TABLE moderation_rules_by_session RENAME TO moderation_rules;
+		CREATE INDEX IF NOT EXISTS idx_moderation_rules_group ON moderation_rules(session_id, group_jid);
+
+		CREATE TABLE moderation_audit_by_session (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			session_id TEXT NOT NULL,
+			group_jid TEXT NOT NULL,
+			rule_id INTEGER NOT NULL,
+			rule_type TEXT NOT NULL,
+			sender_jid TEXT NOT NULL,
+			message_id TEXT NOT NULL DEFAULT '',
+			action TEXT NOT NULL,
+			status TEXT NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		);
+		INSERT INTO moderation_audit_by_session (id, session_id, group_jid, rule_id, rule_type, sender_jid, message_id, action, status, error, created_at)
+		SELECT id, 'default', group_jid, rule_id, rule_type, sender_jid, message_id, action, status, error, created_at FROM moderation_audit;
+		DROP TABLE moderation_audit;
+		ALTER TABLE moderation_audit_by_session RENAME TO moderation_audit;
+		CREATE INDEX IF NOT EXISTS idx_moderation_audit_group ON moderation_audit(session_id, group_jid, created_at);
+		CREATE INDEX IF NOT EXISTS idx_moderation_audit_sender ON moderation_audit(rule_id, sender_jid);
+
+		CREATE TABLE group_greetings_by_session (
+			session_id TEXT NOT NULL,
+			group_jid TEXT NOT NULL,
+			welcome_enabled BOOLEAN NOT NULL DEFAULT 0,
+			welcome_template TEXT NOT NULL DEFAULT '',
+			goodbye_enabled BOOLEAN NOT NULL DEFAULT 0,
+			goodbye_template TEXT NOT NULL DEFAULT '',
+			dm_enabled BOOLEAN NOT NULL DEFAULT 0,
+			dm_template TEXT NOT NULL DEFAULT '',
+			batch_seconds INTEGER NOT NULL DEFAULT 0,
+			rules_document_name TEXT NOT NULL DEFAULT '',
+			rules_document BLOB,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (session_id, group_jid)
+		);
+		INSERT INTO group_greetings_by_session (session_id, group_jid, welcome_enabled, welcome_template, goodbye_enabled,
+			goodbye_template, dm_enabled, dm_template, batch_seconds, rules_document_name, rules_document, created_at, updated_at)
+		SELECT 'default', group_jid, welcome_enabled, welcome_template, goodbye_enabled,
+			goodbye_template, dm_enabled, dm_template, batch_seconds, rules_document_name, rules_document, created_at, updated_at
+		FROM group_greetings;
+		DROP TABLE group_greetings;
+		ALTER TABLE group_greetings_by_session RENAME TO group_greetings;
+
+		CREATE TABLE address_book_by_session (
+			session_id TEXT NOT NULL,
+			jid TEXT NOT NULL,
+			custom_name TEXT NOT NULL DEFAULT '',
+			notes TEXT NOT NULL DEFAULT '',
+			custom_fields TEXT NOT NULL DEFAULT '{}',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (session_id, jid)
+		);
+		INSERT INTO address_book_by_session (session_id, jid, custom_name, notes, custom_fields, created_at, updated_at)
+		SELECT 'default', jid, custom_name, notes, custom_fields, created_at, updated_at FROM address_book;
+
+		CREATE TABLE address_book_tags_by_session (
+			session_id TEXT NOT NULL,
+			jid TEXT NOT NULL,
+			tag TEXT NOT NULL,
+			PRIMARY KEY (session_id, jid, tag),
+			FOREIGN KEY (session_id, jid) REFERENCES address_book_by_session(session_id, jid) ON DELETE CASCADE
+		);
+		INSERT INTO address_book_tags_by_session (session_id, jid, tag)
+		SELECT 'default', jid, tag FROM address_book_tags;
+
+		DROP TABLE address_book_tags;
+		DROP TABLE address_book;
+		ALTER TABLE address_book_by_session RENAME TO address_book;
+		ALTER TABLE address_book_tags_by_session RENAME TO address_book_tags;
+		CREATE INDEX IF NOT EXISTS idx_address_book_tags_tag ON address_book_tags(session_id, tag);
+		`,
 	}
 }
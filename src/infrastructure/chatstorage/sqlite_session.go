@@ -0,0 +1,94 @@
+package chatstorage
+
+import (
+	"fmt"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+// StoreSession creates a session or updates the device it is paired with
+func (r *SQLiteRepository) StoreSession(session *domainChatStorage.Session) error {
+	now := time.Now()
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = now
+	}
+	session.UpdatedAt = now
+
+	if _, err := r.db.Exec(`
+		INSERT INTO sessions (id, device_jid, created_at, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			device_jid = excluded.device_jid,
+			updated_at = excluded.updated_at
+	`, session.ID, session.DeviceJID, session.CreatedAt, session.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to store session: %w", err)
+	}
+	return nil
+}
+
+// GetSessions returns all sessions, oldest first
+func (r *SQLiteRepository) GetSessions() ([]*domainChatStorage.Session, error) {
+	rows, err := r.db.Query("SELECT id, device_jid, created_at, updated_at FROM sessions ORDER BY created_at, id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*domainChatStorage.Session
+	for rows.Next() {
+		session := &domainChatStorage.Session{}
+		if err := rows.Scan(&session.ID, &session.DeviceJID, &session.CreatedAt, &session.UpdatedAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// DeleteSession removes a session together with its chats, messages, block list, presence subscriptions,
+// group data and address book
+func (r *SQLiteRepository) DeleteSession(id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec("DELETE FROM messages WHERE session_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete session messages: %w", err)
+	}
+
+	if _, err = tx.Exec("DELETE FROM chats WHERE session_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete session chats: %w", err)
+	}
+
+	if _, err = tx.Exec("DELETE FROM blocked_contacts WHERE session_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete session blocked contacts: %w", err)
+	}
+
+	if _, err = tx.Exec("DELETE FROM presence_subscriptions WHERE session_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete session presence subscriptions: %w", err)
+	}
+
+	for _, table := range []string{"group_events", "moderation_rules", "moderation_audit", "group_greetings", "address_book_tags", "address_book"} {
+		if _, err = tx.Exec("DELETE FROM "+table+" WHERE session_id = ?", id); err != nil {
+			return fmt.Errorf("failed to delete session rows of %s: %w", table, err)
+		}
+	}
+
+	if _, err = tx.Exec("DELETE FROM group_import_entries WHERE import_id IN (SELECT id FROM group_imports WHERE session_id = ?)", id); err != nil {
+		return fmt.Errorf("failed to delete session group import entries: %w", err)
+	}
+
+	if _, err = tx.Exec("DELETE FROM group_imports WHERE session_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete session group imports: %w", err)
+	}
+
+	if _, err = tx.Exec("DELETE FROM sessions WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,433 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+)
+
+const webhookDefinitionColumns = "id, name, url, events, include_chats, exclude_chats, include_from_me, chat_type, enabled, created_at, updated_at"
+
+const webhookDeliveryColumns = `id, session_id, event, url, payload, status, attempts, max_attempts, next_attempt_at,
+	last_status_code, last_error, created_at, updated_at, delivered_at`
+
+// StoreWebhookDeliveries adds deliveries to the outbox in one transaction and sets their IDs
+func (r *SQLiteRepository) StoreWebhookDeliveries(deliveries []*domainChatStorage.WebhookDelivery) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO webhook_deliveries (session_id, event, url, payload, status, attempts, max_attempts, next_attempt_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, 0, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare webhook delivery insert: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, delivery := range deliveries {
+		if delivery.CreatedAt.IsZero() {
+			delivery.CreatedAt = now
+		}
+		if delivery.NextAttemptAt.IsZero() {
+			delivery.NextAttemptAt = now
+		}
+		delivery.Status = domainChatStorage.WebhookDeliveryPending
+		delivery.UpdatedAt = now
+
+		result, err := stmt.Exec(
+			delivery.SessionID, delivery.Event, delivery.URL, delivery.Payload, delivery.Status,
+			delivery.MaxAttempts, delivery.NextAttemptAt, delivery.CreatedAt, delivery.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to store webhook delivery: %w", err)
+		}
+		if delivery.ID, err = result.LastInsertId(); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetDueWebhookDeliveries returns pending deliveries whose next attempt is due, the oldest first
+func (r *SQLiteRepository) GetDueWebhookDeliveries(now time.Time, limit int) ([]*domainChatStorage.WebhookDelivery, error) {
+	rows, err := r.db.Query(`
+		SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at, id
+		LIMIT ?
+	`, domainChatStorage.WebhookDeliveryPending, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWebhookDeliveries(rows)
+}
+
+// RecordWebhookDeliveryAttempt stores an attempt together with the new state of its delivery
+func (r *SQLiteRepository) RecordWebhookDeliveryAttempt(delivery *domainChatStorage.WebhookDelivery, attempt *domainChatStorage.WebhookDeliveryAttempt) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	delivery.UpdatedAt = time.Now()
+	if _, err = tx.Exec(`
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, next_attempt_at = ?, last_status_code = ?, last_error = ?, updated_at = ?, delivered_at = ?
+		WHERE id = ?
+	`, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastStatusCode, delivery.LastError,
+		delivery.UpdatedAt, delivery.DeliveredAt, delivery.ID); err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	attempt.DeliveryID = delivery.ID
+	result, err := tx.Exec(`
+		INSERT INTO webhook_delivery_attempts (delivery_id, attempt, status_code, error, duration_ms, attempted_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, attempt.DeliveryID, attempt.Attempt, attempt.StatusCode, attempt.Error, attempt.DurationMs, attempt.AttemptedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store webhook delivery attempt: %w", err)
+	}
+	if attempt.ID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetWebhookDelivery returns a delivery, nil when it does not exist
+func (r *SQLiteRepository) GetWebhookDelivery(id int64) (*domainChatStorage.WebhookDelivery, error) {
+	rows, err := r.db.Query("SELECT "+webhookDeliveryColumns+" FROM webhook_deliveries WHERE id = ?", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook delivery: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries, err := scanWebhookDeliveries(rows)
+	if err != nil || len(deliveries) == 0 {
+		return nil, err
+	}
+	return deliveries[0], nil
+}
+
+func (r *SQLiteRepository) webhookDeliveryConditions(filter *domainChatStorage.WebhookDeliveryFilter) (string, []any) {
+	conditions := []string{"1 = 1"}
+	var args []any
+
+	if filter.SessionID != "" {
+		conditions = append(conditions, "session_id = ?")
+		args = append(args, filter.SessionID)
+	}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+
+	if filter.Event != "" {
+		conditions = append(conditions, "event = ?")
+		args = append(args, filter.Event)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// GetWebhookDeliveries returns the deliveries matching the filter, newest first
+func (r *SQLiteRepository) GetWebhookDeliveries(filter *domainChatStorage.WebhookDeliveryFilter) ([]*domainChatStorage.WebhookDelivery, error) {
+	where, args := r.webhookDeliveryConditions(filter)
+
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE ` + where + `
+		ORDER BY created_at DESC, id DESC
+	`
+
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWebhookDeliveries(rows)
+}
+
+// CountWebhookDeliveries returns the number of deliveries matching the filter, ignoring limit and offset
+func (r *SQLiteRepository) CountWebhookDeliveries(filter *domainChatStorage.WebhookDeliveryFilter) (int64, error) {
+	where, args := r.webhookDeliveryConditions(filter)
+
+	var count int64
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM webhook_deliveries WHERE "+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetWebhookDeliveryAttempts returns the attempts of the given deliveries by delivery ID, oldest first
+func (r *SQLiteRepository) GetWebhookDeliveryAttempts(deliveryIDs []int64) (map[int64][]*domainChatStorage.WebhookDeliveryAttempt, error) {
+	attempts := make(map[int64][]*domainChatStorage.WebhookDeliveryAttempt, len(deliveryIDs))
+	if len(deliveryIDs) == 0 {
+		return attempts, nil
+	}
+
+	placeholders := make([]string, len(deliveryIDs))
+	args := make([]any, len(deliveryIDs))
+	for i, id := range deliveryIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, delivery_id, attempt, status_code, error, duration_ms, attempted_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id IN (`+strings.Join(placeholders, ", ")+`)
+		ORDER BY delivery_id, attempt, id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook delivery attempts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		attempt := &domainChatStorage.WebhookDeliveryAttempt{}
+		if err := rows.Scan(
+			&attempt.ID,
+			&attempt.DeliveryID,
+			&attempt.Attempt,
+			&attempt.StatusCode,
+			&attempt.Error,
+			&attempt.DurationMs,
+			&attempt.AttemptedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery attempt: %w", err)
+		}
+		attempts[attempt.DeliveryID] = append(attempts[attempt.DeliveryID], attempt)
+	}
+
+	return attempts, rows.Err()
+}
+
+// ReplayWebhookDelivery queues a delivery again for an immediate attempt, allowing extraAttempts
+// more attempts. It returns nil when the delivery does not exist.
+func (r *SQLiteRepository) ReplayWebhookDelivery(id int64, extraAttempts int, now time.Time) (*domainChatStorage.WebhookDelivery, error) {
+	result, err := r.db.Exec(`
+		UPDATE webhook_deliveries
+		SET status = ?, max_attempts = attempts + ?, next_attempt_at = ?, delivered_at = NULL, updated_at = ?
+		WHERE id = ?
+	`, domainChatStorage.WebhookDeliveryPending, extraAttempts, now, now, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay webhook delivery: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return nil, err
+	}
+
+	return r.GetWebhookDelivery(id)
+}
+
+// DeleteWebhookDeliveriesBefore removes delivered and dead deliveries, with their attempts, last
+// updated before the given time. Pending deliveries are kept however old they are.
+func (r *SQLiteRepository) DeleteWebhookDeliveriesBefore(before time.Time) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	condition := "status != ? AND updated_at < ?"
+	if _, err = tx.Exec(`
+		DELETE FROM webhook_delivery_attempts
+		WHERE delivery_id IN (SELECT id FROM webhook_deliveries WHERE `+condition+`)
+	`, domainChatStorage.WebhookDeliveryPending, before); err != nil {
+		return 0, fmt.Errorf("failed to delete webhook delivery attempts: %w", err)
+	}
+
+	result, err := tx.Exec("DELETE FROM webhook_deliveries WHERE "+condition, domainChatStorage.WebhookDeliveryPending, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return deleted, tx.Commit()
+}
+
+func scanWebhookDeliveries(rows *sql.Rows) ([]*domainChatStorage.WebhookDelivery, error) {
+	var deliveries []*domainChatStorage.WebhookDelivery
+	for rows.Next() {
+		delivery := &domainChatStorage.WebhookDelivery{}
+		var deliveredAt sql.NullTime
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.SessionID,
+			&delivery.Event,
+			&delivery.URL,
+			&delivery.Payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.MaxAttempts,
+			&delivery.NextAttemptAt,
+			&delivery.LastStatusCode,
+			&delivery.LastError,
+			&delivery.CreatedAt,
+			&delivery.UpdatedAt,
+			&deliveredAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		if deliveredAt.Valid {
+			delivery.DeliveredAt = &deliveredAt.Time
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// StoreWebhookDefinition inserts a new definition, or updates the existing one when the definition has an ID
+func (r *SQLiteRepository) StoreWebhookDefinition(definition *domainChatStorage.WebhookDefinition) error {
+	includeChats, err := json.Marshal(definition.IncludeChats)
+	if err != nil {
+		return fmt.Errorf("failed to encode included chats: %w", err)
+	}
+	excludeChats, err := json.Marshal(definition.ExcludeChats)
+	if err != nil {
+		return fmt.Errorf("failed to encode excluded chats: %w", err)
+	}
+	events := strings.Join(definition.Events, ",")
+	now := time.Now()
+
+	if definition.ID == 0 {
+		result, err := r.db.Exec(`
+			INSERT INTO webhook_definitions (name, url, events, include_chats, exclude_chats, include_from_me, chat_type, enabled, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, definition.Name, definition.URL, events, string(includeChats), string(excludeChats), definition.IncludeFromMe,
+			definition.ChatType, definition.Enabled, now, now)
+		if err != nil {
+			return fmt.Errorf("failed to store webhook definition: %w", err)
+		}
+
+		if definition.ID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("failed to read webhook definition id: %w", err)
+		}
+		definition.CreatedAt = now
+		definition.UpdatedAt = now
+		return nil
+	}
+
+	if _, err := r.db.Exec(`
+		UPDATE webhook_definitions
+		SET name = ?, url = ?, events = ?, include_chats = ?, exclude_chats = ?, include_from_me = ?, chat_type = ?, enabled = ?, updated_at = ?
+		WHERE id = ?
+	`, definition.Name, definition.URL, events, string(includeChats), string(excludeChats), definition.IncludeFromMe,
+		definition.ChatType, definition.Enabled, now, definition.ID); err != nil {
+		return fmt.Errorf("failed to update webhook definition: %w", err)
+	}
+
+	definition.UpdatedAt = now
+	return nil
+}
+
+// GetWebhookDefinition returns a definition by ID, or nil when it does not exist
+func (r *SQLiteRepository) GetWebhookDefinition(id int64) (*domainChatStorage.WebhookDefinition, error) {
+	definition, err := scanWebhookDefinition(r.db.QueryRow("SELECT "+webhookDefinitionColumns+" FROM webhook_definitions WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook definition: %w", err)
+	}
+
+	return definition, nil
+}
+
+// GetWebhookDefinitions returns every definition, the oldest first
+func (r *SQLiteRepository) GetWebhookDefinitions() ([]*domainChatStorage.WebhookDefinition, error) {
+	rows, err := r.db.Query("SELECT " + webhookDefinitionColumns + " FROM webhook_definitions ORDER BY id ASC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook definitions: %w", err)
+	}
+	defer rows.Close()
+
+	var definitions []*domainChatStorage.WebhookDefinition
+	for rows.Next() {
+		definition, err := scanWebhookDefinition(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook definition: %w", err)
+		}
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, rows.Err()
+}
+
+// DeleteWebhookDefinition removes a definition. Its queued deliveries are kept.
+func (r *SQLiteRepository) DeleteWebhookDefinition(id int64) error {
+	if _, err := r.db.Exec("DELETE FROM webhook_definitions WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete webhook definition: %w", err)
+	}
+	return nil
+}
+
+func scanWebhookDefinition(row interface{ Scan(dest ...any) error }) (*domainChatStorage.WebhookDefinition, error) {
+	var (
+		definition   domainChatStorage.WebhookDefinition
+		events       string
+		includeChats string
+		excludeChats string
+	)
+
+	if err := row.Scan(
+		&definition.ID,
+		&definition.Name,
+		&definition.URL,
+		&events,
+		&includeChats,
+		&excludeChats,
+		&definition.IncludeFromMe,
+		&definition.ChatType,
+		&definition.Enabled,
+		&definition.CreatedAt,
+		&definition.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if events != "" {
+		definition.Events = strings.Split(events, ",")
+	}
+	if err := json.Unmarshal([]byte(includeChats), &definition.IncludeChats); err != nil {
+		return nil, fmt.Errorf("invalid included chats of webhook definition %d: %w", definition.ID, err)
+	}
+	if err := json.Unmarshal([]byte(excludeChats), &definition.ExcludeChats); err != nil {
+		return nil, fmt.Errorf("invalid excluded chats of webhook definition %d: %w", definition.ID, err)
+	}
+
+	return &definition, nil
+}
@@ -0,0 +1,118 @@
+package whatsapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// handleBlocklist mirrors block list changes made on any device into chat storage and forwards
+// them to the webhooks. A "modify" notification carries no changes, so the whole list is fetched.
+func handleBlocklist(ctx context.Context, evt *events.Blocklist, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	var blocklist *types.Blocklist
+	if evt.Action == events.BlocklistActionModify {
+		cli := ClientFromContext(ctx)
+		if cli == nil {
+			return
+		}
+		var err error
+		blocklist, err = cli.GetBlocklist(ctx)
+		if err != nil {
+			log.Errorf("Failed to fetch block list after modify notification: %v", err)
+			return
+		}
+		if err = StoreBlocklist(ctx, chatStorageRepo, blocklist); err != nil {
+			log.Errorf("Failed to store block list: %v", err)
+		}
+	} else {
+		now := time.Now()
+		for _, change := range evt.Changes {
+			jid := change.JID.ToNonAD().String()
+			var err error
+			switch change.Action {
+			case events.BlocklistChangeActionBlock:
+				err = chatStorageRepo.StoreBlockedContact(ctx, jid, now)
+			case events.BlocklistChangeActionUnblock:
+				err = chatStorageRepo.DeleteBlockedContact(ctx, jid)
+			}
+			if err != nil {
+				log.Errorf("Failed to mirror %s of %s: %v", change.Action, jid, err)
+			}
+		}
+	}
+
+	if webhookSubscribed(domainWebhook.EventBlocklist) {
+		go func() {
+			event := domainWebhook.Event{Type: domainWebhook.EventBlocklist}
+			if err := forwardPayloadToConfiguredWebhooks(ctx, createBlocklistPayload(evt, blocklist), "blocklist event", event); err != nil {
+				logrus.Errorf("Failed to forward blocklist event to webhook: %v", err)
+			}
+		}()
+	}
+}
+
+// StoreBlocklist replaces the mirrored block list of the session selected by ctx with a full list fetched from WhatsApp
+func StoreBlocklist(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository, blocklist *types.Blocklist) error {
+	jids := make([]string, 0, len(blocklist.JIDs))
+	for _, jid := range blocklist.JIDs {
+		jids = append(jids, jid.ToNonAD().String())
+	}
+
+	return chatStorageRepo.ReplaceBlockedContacts(ctx, jids, time.Now())
+}
+
+// createBlocklistPayload creates a webhook payload for block list changes. When the whole list
+// was refetched it is included as "jids" instead of individual changes.
+func createBlocklistPayload(evt *events.Blocklist, blocklist *types.Blocklist) map[string]any {
+	changes := make([]map[string]string, 0, len(evt.Changes))
+	for _, change := range evt.Changes {
+		changes = append(changes, map[string]string{
+			"jid":    change.JID.ToNonAD().String(),
+			"action": string(change.Action),
+		})
+	}
+
+	payload := map[string]any{
+		"action":  string(evt.Action),
+		"changes": changes,
+	}
+	if blocklist != nil {
+		payload["jids"] = jidsToStrings(blocklist.JIDs)
+	}
+
+	return map[string]any{
+		"event":     "blocklist",
+		"payload":   payload,
+		"timestamp": time.Now().Format(time.RFC3339),
+	}
+}
+
+// isBlockedSender reports whether a message comes from a block-listed contact and should be
+// ignored by the automations. It is always false unless --ignore-blocked is enabled.
+func isBlockedSender(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) bool {
+	if !config.WhatsappIgnoreBlocked || chatStorageRepo == nil || evt.Info.IsFromMe {
+		return false
+	}
+
+	// Contacts may be blocked under their phone number or their LID
+	jids := []string{evt.Info.Sender.ToNonAD().String()}
+	if !evt.Info.SenderAlt.IsEmpty() {
+		jids = append(jids, evt.Info.SenderAlt.ToNonAD().String())
+	} else if alternate := identity.Alternate(ctx, evt.Info.Sender); !alternate.IsEmpty() {
+		jids = append(jids, alternate.String())
+	}
+
+	blocked, err := chatStorageRepo.IsContactBlocked(ctx, jids...)
+	if err != nil {
+		log.Warnf("Failed to check whether %s is blocked: %v", evt.Info.Sender, err)
+		return false
+	}
+
+	return blocked
+}
@@ -0,0 +1,76 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestCreateBlocklistPayload(t *testing.T) {
+	spammer := types.NewJID("628111111111", types.DefaultUserServer)
+	friend := types.JID{User: "628222222222", Server: types.DefaultUserServer, Device: 3}
+
+	t.Run("changes", func(t *testing.T) {
+		body := createBlocklistPayload(&events.Blocklist{
+			Changes: []events.BlocklistChange{
+				{JID: spammer, Action: events.BlocklistChangeActionBlock},
+				{JID: friend, Action: events.BlocklistChangeActionUnblock},
+			},
+		}, nil)
+
+		assert.Equal(t, "blocklist", body["event"])
+		payload := body["payload"].(map[string]any)
+		assert.Equal(t, "", payload["action"])
+		assert.Equal(t, []map[string]string{
+			{"jid": "628111111111@s.whatsapp.net", "action": "block"},
+			{"jid": "628222222222@s.whatsapp.net", "action": "unblock"},
+		}, payload["changes"])
+		assert.NotContains(t, payload, "jids")
+	})
+
+	t.Run("modify includes the refetched list", func(t *testing.T) {
+		body := createBlocklistPayload(
+			&events.Blocklist{Action: events.BlocklistActionModify},
+			&types.Blocklist{JIDs: []types.JID{spammer}},
+		)
+
+		payload := body["payload"].(map[string]any)
+		assert.Equal(t, "modify", payload["action"])
+		assert.Empty(t, payload["changes"])
+		assert.Equal(t, []string{"628111111111@s.whatsapp.net"}, payload["jids"])
+	})
+}
+
+func TestBlocklistIsKeptPerSession(t *testing.T) {
+	repo := newTestChatStorage(t)
+	sales := domainSession.NewContext(context.Background(), "sales")
+	support := domainSession.NewContext(context.Background(), "support")
+	spammer := types.NewJID("628111111111", types.DefaultUserServer)
+	friend := types.NewJID("628222222222", types.DefaultUserServer)
+
+	handleBlocklist(sales, &events.Blocklist{
+		Changes: []events.BlocklistChange{{JID: spammer, Action: events.BlocklistChangeActionBlock}},
+	}, repo)
+	require.NoError(t, StoreBlocklist(support, repo, &types.Blocklist{JIDs: []types.JID{friend}}))
+
+	blocked, err := repo.IsContactBlocked(sales, spammer.String())
+	require.NoError(t, err)
+	assert.True(t, blocked)
+
+	blocked, err = repo.IsContactBlocked(support, spammer.String())
+	require.NoError(t, err)
+	assert.False(t, blocked, "a block by one session must not apply to the other")
+
+	// Refetching the list of one session leaves the other untouched
+	require.NoError(t, StoreBlocklist(support, repo, &types.Blocklist{}))
+	contacts, err := repo.GetBlockedContacts(sales)
+	require.NoError(t, err)
+	if assert.Len(t, contacts, 1) {
+		assert.Equal(t, spammer.String(), contacts[0].JID)
+	}
+}
@@ -0,0 +1,71 @@
+package whatsapp
+
+import (
+	"context"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// handleArchive mirrors archive changes made on other devices into chat storage
+func handleArchive(ctx context.Context, evt *events.Archive, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if err := chatStorageRepo.SetChatArchived(ctx, evt.JID.String(), evt.Action.GetArchived()); err != nil {
+		log.Warnf("Failed to store archive state for %s: %v", evt.JID, err)
+	}
+}
+
+// handlePin mirrors pin changes made on other devices into chat storage
+func handlePin(ctx context.Context, evt *events.Pin, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if err := chatStorageRepo.SetChatPinned(ctx, evt.JID.String(), evt.Action.GetPinned()); err != nil {
+		log.Warnf("Failed to store pin state for %s: %v", evt.JID, err)
+	}
+}
+
+// handleMute mirrors mute changes made on other devices into chat storage
+func handleMute(ctx context.Context, evt *events.Mute, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	mutedUntil := muteEndTime(evt.Action.GetMuteEndTimestamp())
+	if err := chatStorageRepo.SetChatMuted(ctx, evt.JID.String(), evt.Action.GetMuted(), mutedUntil); err != nil {
+		log.Warnf("Failed to store mute state for %s: %v", evt.JID, err)
+	}
+}
+
+// handleMarkChatAsRead mirrors a whole chat being marked as read or unread on other devices
+func handleMarkChatAsRead(ctx context.Context, evt *events.MarkChatAsRead, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	var err error
+	if evt.Action.GetRead() {
+		err = chatStorageRepo.SetChatUnreadCount(ctx, evt.JID.String(), 0)
+	} else {
+		// Marked as unread manually, WhatsApp shows this as a single unread badge
+		var chat *domainChatStorage.Chat
+		if chat, err = chatStorageRepo.GetChat(ctx, evt.JID.String()); err == nil && chat != nil && chat.UnreadCount == 0 {
+			err = chatStorageRepo.SetChatUnreadCount(ctx, evt.JID.String(), 1)
+		}
+	}
+	if err != nil {
+		log.Warnf("Failed to store read state for %s: %v", evt.JID, err)
+	}
+}
+
+// handlePicture invalidates the cached avatar URL when a user or group picture changes
+func handlePicture(ctx context.Context, evt *events.Picture, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if err := chatStorageRepo.SetChatAvatarURL(ctx, evt.JID.String(), ""); err != nil {
+		log.Warnf("Failed to reset cached avatar for %s: %v", evt.JID, err)
+	}
+}
+
+// muteEndTime converts a WhatsApp mute end timestamp into a time.
+// Sync actions use milliseconds while history sync uses seconds, zero or negative means forever.
+func muteEndTime(timestamp int64) *time.Time {
+	if timestamp <= 0 {
+		return nil
+	}
+
+	var end time.Time
+	if timestamp > 1e12 {
+		end = time.UnixMilli(timestamp)
+	} else {
+		end = time.Unix(timestamp, 0)
+	}
+	return &end
+}
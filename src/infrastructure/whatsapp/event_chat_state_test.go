@@ -0,0 +1,89 @@
+package whatsapp
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatstorage"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/proto/waSyncAction"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestChatStorage creates a fresh chat storage
+func newTestChatStorage(t *testing.T) domainChatStorage.IChatStorageRepository {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "storage.db")+"?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	repo := chatstorage.NewStorageRepository(db, nil)
+	require.NoError(t, repo.InitializeSchema())
+	return repo
+}
+
+func TestChatStateIsKeptPerSession(t *testing.T) {
+	repo := newTestChatStorage(t)
+	group := types.NewJID("120363000000000001", types.GroupServer)
+	sales := domainSession.NewContext(context.Background(), "sales")
+	support := domainSession.NewContext(context.Background(), "support")
+
+	// Both accounts are members of the group and receive the same message
+	now := time.Now().Truncate(time.Second)
+	for _, ctx := range []context.Context{sales, support} {
+		require.NoError(t, repo.CreateMessage(ctx, &events.Message{
+			Info: types.MessageInfo{
+				MessageSource: types.MessageSource{Chat: group, Sender: types.NewJID("628111", types.DefaultUserServer)},
+				ID:            "SHARED",
+				Timestamp:     now,
+			},
+			Message: &waE2E.Message{Conversation: proto.String("hello")},
+		}))
+	}
+
+	for _, sessionID := range []string{"sales", "support"} {
+		messages, err := repo.GetMessages(&domainChatStorage.MessageFilter{ChatJID: group.String(), SessionID: sessionID})
+		require.NoError(t, err)
+		assert.Len(t, messages, 1, "session %s should see the shared message", sessionID)
+	}
+
+	handleArchive(sales, &events.Archive{JID: group, Action: &waSyncAction.ArchiveChatAction{Archived: proto.Bool(true)}}, repo)
+	handleMarkChatAsRead(sales, &events.MarkChatAsRead{JID: group, Action: &waSyncAction.MarkChatAsReadAction{Read: proto.Bool(true)}}, repo)
+
+	salesChat, err := repo.GetChat(sales, group.String())
+	require.NoError(t, err)
+	require.NotNil(t, salesChat)
+	assert.True(t, salesChat.Archived)
+	assert.Zero(t, salesChat.UnreadCount)
+
+	supportChat, err := repo.GetChat(support, group.String())
+	require.NoError(t, err)
+	require.NotNil(t, supportChat)
+	assert.False(t, supportChat.Archived, "archiving in one session must not archive the other")
+	assert.Equal(t, 1, supportChat.UnreadCount)
+
+	// Deleting the message for one account keeps the copy of the other
+	require.NoError(t, repo.DeleteMessage(sales, "SHARED", group.String()))
+	message, err := repo.GetMessageByID(support, "SHARED")
+	require.NoError(t, err)
+	assert.NotNil(t, message)
+
+	chats, err := repo.GetChats(&domainChatStorage.ChatFilter{SessionID: "support"})
+	require.NoError(t, err)
+	require.Len(t, chats, 1)
+	assert.Equal(t, "support", chats[0].SessionID)
+	if assert.NotNil(t, chats[0].LastMessage) {
+		assert.Equal(t, "SHARED", chats[0].LastMessage.ID)
+	}
+}
@@ -0,0 +1,282 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+const (
+	// reconnectBaseDelay is the backoff before the first reconnect, it doubles with every failed attempt
+	reconnectBaseDelay = 2 * time.Second
+	// reconnectMaxDelay caps the backoff between reconnect attempts
+	reconnectMaxDelay = 5 * time.Minute
+	// connectionHistorySize is the number of state transitions kept per session
+	connectionHistorySize = 50
+)
+
+// connectionSupervisor reconnects the client of one session after its connection dropped and
+// records the state transitions of that connection. It replaces the auto reconnect of whatsmeow,
+// which is disabled on every client.
+type connectionSupervisor struct {
+	mu      sync.Mutex
+	history []domainApp.ConnectionState
+	attempt int
+	timer   *time.Timer
+}
+
+var (
+	supervisorsMu sync.Mutex
+	supervisors   = make(map[string]*connectionSupervisor)
+)
+
+// supervisorFor returns the supervisor of a session, creating it on first use
+func supervisorFor(sessionID string) *connectionSupervisor {
+	supervisorsMu.Lock()
+	defer supervisorsMu.Unlock()
+
+	supervisor, ok := supervisors[sessionID]
+	if !ok {
+		supervisor = &connectionSupervisor{}
+		supervisors[sessionID] = supervisor
+	}
+	return supervisor
+}
+
+// stopConnectionSupervisor cancels pending reconnects of a removed session and forgets its history
+func stopConnectionSupervisor(sessionID string) {
+	supervisorsMu.Lock()
+	supervisor, ok := supervisors[sessionID]
+	delete(supervisors, sessionID)
+	supervisorsMu.Unlock()
+
+	if ok {
+		supervisor.cancel()
+	}
+}
+
+// handleConnectionState reacts to the connection events of a session
+func handleConnectionState(ctx context.Context, rawEvt any) {
+	supervisor := supervisorFor(domainSession.FromContext(ctx))
+
+	switch evt := rawEvt.(type) {
+	case *events.Connected:
+		supervisor.cancel()
+		supervisor.record(ctx, domainApp.ConnectionState{State: domainApp.ConnectionStateConnected})
+	case *events.KeepAliveRestored:
+		supervisor.record(ctx, domainApp.ConnectionState{State: domainApp.ConnectionStateConnected, Reason: "keepalive restored"})
+	case *events.KeepAliveTimeout:
+		if time.Since(evt.LastSuccess) < whatsmeow.KeepAliveMaxFailTime {
+			supervisor.record(ctx, domainApp.ConnectionState{State: domainApp.ConnectionStateUnstable, Reason: "keepalive pings fail"})
+			return
+		}
+		// whatsmeow only drops a dead connection itself when its own auto reconnect is enabled
+		if cli := ClientFromContext(ctx); cli != nil {
+			cli.Disconnect()
+		}
+		supervisor.reconnect(ctx, fmt.Sprintf("no keepalive response since %s", evt.LastSuccess.Format(time.RFC3339)))
+	case *events.Disconnected:
+		supervisor.reconnect(ctx, "connection lost")
+	case *events.ConnectFailure:
+		supervisor.reconnect(ctx, fmt.Sprintf("connect failure %d %s", int(evt.Reason), evt.Message))
+	case *events.TemporaryBan:
+		supervisor.retryAfterBan(ctx, evt)
+	case *events.ClientOutdated:
+		supervisor.halt(ctx, domainApp.ConnectionState{State: domainApp.ConnectionStateDisconnected, Reason: "client outdated, update the application"})
+	case *events.StreamReplaced:
+		supervisor.halt(ctx, domainApp.ConnectionState{State: domainApp.ConnectionStateReplaced, Reason: "another client connected with the same device"})
+	case *events.LoggedOut:
+		supervisor.halt(ctx, domainApp.ConnectionState{State: domainApp.ConnectionStateLoggedOut, Reason: evt.Reason.String()})
+	}
+}
+
+// ScheduleReconnect retries a connection of the session selected by ctx that could not be made,
+// such as the first connection after booting
+func ScheduleReconnect(ctx context.Context, err error) {
+	supervisorFor(domainSession.FromContext(ctx)).reconnect(ctx, err.Error())
+}
+
+// ConnectionHistory returns the state transitions of the connection of the session selected by
+// ctx, newest first
+func ConnectionHistory(ctx context.Context) []domainApp.ConnectionState {
+	supervisor := supervisorFor(domainSession.FromContext(ctx))
+
+	supervisor.mu.Lock()
+	defer supervisor.mu.Unlock()
+
+	history := make([]domainApp.ConnectionState, 0, len(supervisor.history))
+	for i := len(supervisor.history) - 1; i >= 0; i-- {
+		history = append(history, supervisor.history[i])
+	}
+	return history
+}
+
+// reconnect records a dropped connection and schedules the next attempt
+func (s *connectionSupervisor) reconnect(ctx context.Context, reason string) {
+	s.record(ctx, domainApp.ConnectionState{State: domainApp.ConnectionStateDisconnected, Reason: reason})
+	s.backoff(ctx, reason)
+}
+
+// backoff schedules a reconnect after the delay of the next attempt
+func (s *connectionSupervisor) backoff(ctx context.Context, reason string) {
+	s.mu.Lock()
+	s.attempt++
+	attempt := s.attempt
+	s.mu.Unlock()
+
+	s.schedule(ctx, domainApp.ConnectionState{
+		State:   domainApp.ConnectionStateReconnecting,
+		Reason:  reason,
+		Attempt: attempt,
+	}, backoffDelay(attempt))
+}
+
+// retryAfterBan waits until a temporary ban expires before reconnecting
+func (s *connectionSupervisor) retryAfterBan(ctx context.Context, evt *events.TemporaryBan) {
+	if evt.Expire <= 0 {
+		s.record(ctx, domainApp.ConnectionState{State: domainApp.ConnectionStateTemporaryBan, Reason: evt.String()})
+		s.backoff(ctx, evt.String())
+		return
+	}
+
+	s.schedule(ctx, domainApp.ConnectionState{
+		State:  domainApp.ConnectionStateTemporaryBan,
+		Reason: evt.String(),
+	}, evt.Expire+backoffDelay(1))
+}
+
+// schedule records a state with its retry time and reconnects once the delay passed
+func (s *connectionSupervisor) schedule(ctx context.Context, state domainApp.ConnectionState, delay time.Duration) {
+	if shuttingDown.Load() {
+		return
+	}
+
+	retryAt := time.Now().Add(delay)
+	state.RetryAt = &retryAt
+
+	s.mu.Lock()
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.timer = time.AfterFunc(delay, func() {
+		s.connect(ctx)
+	})
+	s.mu.Unlock()
+
+	logrus.Warnf("[CONNECTION] Session %s reconnects in %s: %s", domainSession.FromContext(ctx), delay.Round(time.Second), state.Reason)
+	s.record(ctx, state)
+}
+
+// connect makes a scheduled reconnect attempt. Success is recorded by the Connected event, a
+// failure schedules the next attempt.
+func (s *connectionSupervisor) connect(ctx context.Context) {
+	cli := ClientFromContext(ctx)
+	if shuttingDown.Load() || cli == nil || cli.Store.ID == nil || cli.IsConnected() {
+		return
+	}
+
+	if err := cli.Connect(); err != nil && !errors.Is(err, whatsmeow.ErrAlreadyConnected) {
+		s.backoff(ctx, err.Error())
+	}
+}
+
+// halt records a state the connection cannot recover from by reconnecting
+func (s *connectionSupervisor) halt(ctx context.Context, state domainApp.ConnectionState) {
+	s.cancel()
+	s.record(ctx, state)
+}
+
+// cancel stops a pending reconnect and resets the backoff
+func (s *connectionSupervisor) cancel() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.attempt = 0
+}
+
+// record appends a state transition and forwards it to the websocket and the webhooks. A state
+// equal to the current one is not a transition and is ignored.
+func (s *connectionSupervisor) record(ctx context.Context, state domainApp.ConnectionState) {
+	state.Timestamp = time.Now()
+
+	s.mu.Lock()
+	if n := len(s.history); n > 0 {
+		last := s.history[n-1]
+		if last.State == state.State && last.Reason == state.Reason && last.Attempt == state.Attempt {
+			s.mu.Unlock()
+			return
+		}
+	}
+	s.history = append(s.history, state)
+	if len(s.history) > connectionHistorySize {
+		s.history = s.history[len(s.history)-connectionHistorySize:]
+	}
+	s.mu.Unlock()
+
+	sessionID := domainSession.FromContext(ctx)
+	websocket.Publish(websocket.BroadcastMessage{
+		Code:    "CONNECTION_STATE",
+		Message: fmt.Sprintf("Connection of session %s is %s", sessionID, state.State),
+		Result:  state,
+	})
+
+	if webhookSubscribed(domainWebhook.EventConnection) {
+		go func() {
+			event := domainWebhook.Event{Type: domainWebhook.EventConnection}
+			if err := forwardPayloadToConfiguredWebhooks(ctx, createConnectionPayload(state), "connection event", event); err != nil {
+				logrus.Errorf("Failed to forward connection event to webhook: %v", err)
+			}
+		}()
+	}
+}
+
+// backoffDelay doubles the delay with every attempt up to reconnectMaxDelay and picks a random
+// point in its upper half, so sessions dropped together do not reconnect in lockstep
+func backoffDelay(attempt int) time.Duration {
+	delay := reconnectMaxDelay
+	if attempt < 1 {
+		attempt = 1
+	}
+	if attempt <= 20 {
+		delay = min(reconnectBaseDelay<<(attempt-1), reconnectMaxDelay)
+	}
+
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// createConnectionPayload creates a webhook payload for a connection state transition
+func createConnectionPayload(state domainApp.ConnectionState) map[string]any {
+	payload := map[string]any{
+		"state": state.State,
+	}
+	if state.Reason != "" {
+		payload["reason"] = state.Reason
+	}
+	if state.Attempt > 0 {
+		payload["attempt"] = state.Attempt
+	}
+	if state.RetryAt != nil {
+		payload["retry_at"] = state.RetryAt.Format(time.RFC3339)
+	}
+
+	return map[string]any{
+		"event":     "connection",
+		"payload":   payload,
+		"timestamp": state.Timestamp.Format(time.RFC3339),
+	}
+}
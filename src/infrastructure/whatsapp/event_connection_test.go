@@ -0,0 +1,85 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	for attempt, ceiling := range map[int]time.Duration{
+		1:   reconnectBaseDelay,
+		2:   2 * reconnectBaseDelay,
+		5:   16 * reconnectBaseDelay,
+		10:  reconnectMaxDelay,
+		100: reconnectMaxDelay,
+	} {
+		for range 20 {
+			delay := backoffDelay(attempt)
+			assert.GreaterOrEqual(t, delay, ceiling/2, "attempt %d", attempt)
+			assert.LessOrEqual(t, delay, ceiling, "attempt %d", attempt)
+		}
+	}
+}
+
+func TestHandleConnectionState(t *testing.T) {
+	ctx := domainSession.NewContext(context.Background(), "supervised")
+	defer stopConnectionSupervisor("supervised")
+	supervisor := supervisorFor("supervised")
+
+	handleConnectionState(ctx, &events.Connected{})
+	handleConnectionState(ctx, &events.Disconnected{})
+
+	history := ConnectionHistory(ctx)
+	assert.Len(t, history, 3)
+	assert.Equal(t, domainApp.ConnectionStateReconnecting, history[0].State)
+	assert.Equal(t, 1, history[0].Attempt)
+	assert.NotNil(t, history[0].RetryAt)
+	assert.Equal(t, domainApp.ConnectionStateDisconnected, history[1].State)
+	assert.Equal(t, domainApp.ConnectionStateConnected, history[2].State)
+	assert.NotNil(t, supervisor.timer)
+
+	// Repeated keepalive failures are one transition
+	handleConnectionState(ctx, &events.Connected{})
+	handleConnectionState(ctx, &events.KeepAliveTimeout{ErrorCount: 1, LastSuccess: time.Now()})
+	handleConnectionState(ctx, &events.KeepAliveTimeout{ErrorCount: 2, LastSuccess: time.Now()})
+	history = ConnectionHistory(ctx)
+	assert.Equal(t, domainApp.ConnectionStateUnstable, history[0].State)
+	assert.Equal(t, domainApp.ConnectionStateConnected, history[1].State)
+	assert.Nil(t, supervisor.timer, "connecting cancels the pending reconnect")
+
+	handleConnectionState(ctx, &events.TemporaryBan{Code: events.TempBanSentToTooManyPeople, Expire: time.Hour})
+	state := ConnectionHistory(ctx)[0]
+	assert.Equal(t, domainApp.ConnectionStateTemporaryBan, state.State)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *state.RetryAt, time.Minute)
+
+	handleConnectionState(ctx, &events.StreamReplaced{})
+	assert.Equal(t, domainApp.ConnectionStateReplaced, ConnectionHistory(ctx)[0].State)
+	assert.Nil(t, supervisor.timer, "a replaced stream is not reconnected")
+	assert.Zero(t, supervisor.attempt)
+}
+
+func TestCreateConnectionPayload(t *testing.T) {
+	retryAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	body := createConnectionPayload(domainApp.ConnectionState{
+		State:     domainApp.ConnectionStateReconnecting,
+		Reason:    "connection lost",
+		Attempt:   2,
+		RetryAt:   &retryAt,
+		Timestamp: retryAt.Add(-4 * time.Second),
+	})
+
+	assert.Equal(t, "connection", body["event"])
+	assert.Equal(t, "2025-01-02T03:04:01Z", body["timestamp"])
+	assert.Equal(t, map[string]any{
+		"state":    "reconnecting",
+		"reason":   "connection lost",
+		"attempt":  2,
+		"retry_at": "2025-01-02T03:04:05Z",
+	}, body["payload"])
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
@@ -15,7 +16,13 @@ func forwardDeleteToWebhook(ctx context.Context, evt *events.DeleteForMe, messag
 		return err
 	}
 
-	return forwardPayloadToConfiguredWebhooks(ctx, payload, "delete event")
+	event := domainWebhook.Event{Type: domainWebhook.EventDelete, Sender: identity.Canonical(ctx, evt.SenderJID).ToNonAD().String()}
+	if message != nil {
+		event.Chat = message.ChatJID
+		event.FromMe = message.IsFromMe
+	}
+
+	return forwardPayloadToConfiguredWebhooks(ctx, payload, "delete event", event)
 }
 
 // createDeletePayload creates a webhook payload for delete events
@@ -0,0 +1,45 @@
+package whatsapp
+
+import (
+	"context"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainGreeting "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/greeting"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// greeter sends the welcome and goodbye messages of groups, nil until registered
+var greeter domainGreeting.IMembershipGreeter
+
+// SetMembershipGreeter registers the usecase that greets members joining or leaving groups
+func SetMembershipGreeter(g domainGreeting.IMembershipGreeter) {
+	greeter = g
+}
+
+// handleGreetings passes the members that joined or left a group to the greeter
+func handleGreetings(ctx context.Context, evt *events.GroupInfo, groupEvents []*domainChatStorage.GroupEvent) {
+	if greeter == nil {
+		return
+	}
+
+	var joined, left []types.JID
+	for _, event := range groupEvents {
+		participant, err := types.ParseJID(event.Participant)
+		if err != nil || participant.IsEmpty() {
+			continue
+		}
+
+		switch event.EventType {
+		case domainChatStorage.GroupEventJoin, domainChatStorage.GroupEventAdd:
+			joined = append(joined, participant)
+		case domainChatStorage.GroupEventLeave, domainChatStorage.GroupEventRemove:
+			left = append(left, participant)
+		}
+	}
+	if len(joined) == 0 && len(left) == 0 {
+		return
+	}
+
+	go greeter.GreetMembers(ctx, evt.JID, joined, left)
+}
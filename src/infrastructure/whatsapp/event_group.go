@@ -3,10 +3,11 @@ package whatsapp
 import (
 	"context"
 	"fmt"
-	"strings"
+	"strconv"
 	"time"
 
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
@@ -51,8 +52,6 @@ func jidsToStrings(jids []types.JID) []string {
 
 // forwardGroupInfoToWebhook forwards group information events to the configured webhook URLs
 func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo) error {
-	logrus.Infof("Forwarding group info event to %d configured webhook(s)", len(config.WhatsappWebhook))
-
 	// Send separate webhook events for each action type
 	actions := []struct {
 		actionType string
@@ -67,32 +66,130 @@ func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo) error
 	for _, action := range actions {
 		if len(action.jids) > 0 {
 			payload := createGroupInfoPayload(evt, action.actionType, action.jids)
-
-			// Collect errors from all webhook URLs instead of failing fast
-			var errors []error
-			for _, url := range config.WhatsappWebhook {
-				if err := submitWebhook(ctx, payload, url); err != nil {
-					errors = append(errors, fmt.Errorf("webhook %s failed: %w", url, err))
-				}
+			if err := forwardPayloadToConfiguredWebhooks(ctx, payload, fmt.Sprintf("group %s event", action.actionType), domainWebhook.Event{
+				Type: domainWebhook.EventGroup,
+				Chat: evt.JID.String(),
+			}); err != nil {
+				return err
 			}
+		}
+	}
 
-			// If all webhooks failed, return combined error
-			if len(errors) == len(config.WhatsappWebhook) && len(errors) > 0 {
-				var errMessages []string
-				for _, err := range errors {
-					errMessages = append(errMessages, err.Error())
-				}
-				return fmt.Errorf("all webhook URLs failed: %s", strings.Join(errMessages, "; "))
-			}
+	return nil
+}
+
+// groupEventsFromInfo flattens a group info notification into event log entries.
+// Joins and leaves made by someone other than the participant are recorded as adds
+// and removes so the log answers who added or removed a member.
+func groupEventsFromInfo(ctx context.Context, evt *events.GroupInfo) []*domainChatStorage.GroupEvent {
+	actor := types.EmptyJID
+	if evt.Sender != nil {
+		actor = *evt.Sender
+		if actor.Server == types.HiddenUserServer && evt.SenderPN != nil && !evt.SenderPN.IsEmpty() {
+			actor = *evt.SenderPN
+		}
+		actor = identity.Canonical(ctx, actor).ToNonAD()
+	}
 
-			// Log partial failures
-			if len(errors) > 0 {
-				logrus.Warnf("Some webhook URLs failed for group %s event: %v", action.actionType, errors)
+	actorStr := ""
+	if !actor.IsEmpty() {
+		actorStr = actor.String()
+	}
+
+	var result []*domainChatStorage.GroupEvent
+	add := func(eventType, participant, value string) {
+		result = append(result, &domainChatStorage.GroupEvent{
+			GroupJID:    evt.JID.String(),
+			EventType:   eventType,
+			Participant: participant,
+			Actor:       actorStr,
+			Value:       value,
+			Timestamp:   evt.Timestamp,
+		})
+	}
+
+	// membership picks the self-initiated or the admin-initiated event type
+	membership := func(jids []types.JID, self, byAdmin, value string) {
+		for _, jid := range jids {
+			participant := identity.Canonical(ctx, jid).ToNonAD()
+			eventType := self
+			if !actor.IsEmpty() && actor.User != participant.User {
+				eventType = byAdmin
 			}
+			add(eventType, participant.String(), value)
+		}
+	}
+
+	membership(evt.Join, domainChatStorage.GroupEventJoin, domainChatStorage.GroupEventAdd, evt.JoinReason)
+	membership(evt.Leave, domainChatStorage.GroupEventLeave, domainChatStorage.GroupEventRemove, "")
+	for _, jid := range evt.Promote {
+		add(domainChatStorage.GroupEventPromote, identity.Canonical(ctx, jid).ToNonAD().String(), "")
+	}
+	for _, jid := range evt.Demote {
+		add(domainChatStorage.GroupEventDemote, identity.Canonical(ctx, jid).ToNonAD().String(), "")
+	}
+
+	if evt.Name != nil {
+		add(domainChatStorage.GroupEventName, "", evt.Name.Name)
+	}
+	if evt.Topic != nil {
+		add(domainChatStorage.GroupEventTopic, "", evt.Topic.Topic)
+	}
+	if evt.Locked != nil {
+		add(domainChatStorage.GroupEventLocked, "", strconv.FormatBool(evt.Locked.IsLocked))
+	}
+	if evt.Announce != nil {
+		add(domainChatStorage.GroupEventAnnounce, "", strconv.FormatBool(evt.Announce.IsAnnounce))
+	}
+	if evt.Ephemeral != nil {
+		timer := uint32(0)
+		if evt.Ephemeral.IsEphemeral {
+			timer = evt.Ephemeral.DisappearingTimer
+		}
+		add(domainChatStorage.GroupEventEphemeral, "", strconv.FormatUint(uint64(timer), 10))
+	}
+	if evt.MembershipApprovalMode != nil {
+		add(domainChatStorage.GroupEventMembershipApproval, "", strconv.FormatBool(evt.MembershipApprovalMode.IsJoinApprovalRequired))
+	}
+	if evt.NewInviteLink != nil {
+		add(domainChatStorage.GroupEventInviteLink, "", *evt.NewInviteLink)
+	}
+	if evt.Link != nil {
+		add(domainChatStorage.GroupEventLink, "", evt.Link.Group.JID.String())
+	}
+	if evt.Unlink != nil {
+		add(domainChatStorage.GroupEventUnlink, "", evt.Unlink.Group.JID.String())
+	}
+	if evt.Delete != nil {
+		add(domainChatStorage.GroupEventDelete, "", evt.Delete.DeleteReason)
+	}
 
-			logrus.Infof("Group %s event forwarded to webhook: %d users %s", action.actionType, len(action.jids), action.actionType)
+	return result
+}
+
+// trackImportedJoins marks participants invited by a bulk import as joined once they enter the group
+func trackImportedJoins(ctx context.Context, evt *events.GroupInfo, groupEvents []*domainChatStorage.GroupEvent, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	var joined []string
+	for _, event := range groupEvents {
+		if event.EventType == domainChatStorage.GroupEventJoin || event.EventType == domainChatStorage.GroupEventAdd {
+			joined = append(joined, event.Participant)
 		}
 	}
+	if len(joined) == 0 {
+		return
+	}
 
-	return nil
+	joinedAt := evt.Timestamp
+	if joinedAt.IsZero() {
+		joinedAt = time.Now()
+	}
+
+	updated, err := chatStorageRepo.MarkGroupImportJoined(ctx, evt.JID.String(), joined, joinedAt)
+	if err != nil {
+		logrus.Errorf("Failed to track imported participants joining %s: %v", evt.JID, err)
+		return
+	}
+	if updated > 0 {
+		logrus.Infof("Group %s: %d invited participants joined", evt.JID, updated)
+	}
 }
@@ -0,0 +1,71 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestGroupEventsFromInfo(t *testing.T) {
+	original := identity
+	identity = newTestIdentityResolver()
+	defer func() { identity = original }()
+
+	group := types.NewJID("120363025246125486", types.GroupServer)
+	admin := types.NewJID("123456789", types.HiddenUserServer) // resolves to 628123456789
+	member := types.NewJID("628111111111", types.DefaultUserServer)
+	timestamp := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+
+	evt := &events.GroupInfo{
+		JID:       group,
+		Sender:    &admin,
+		Timestamp: timestamp,
+		Join:      []types.JID{member},
+		Leave:     []types.JID{member, types.NewJID("628123456789", types.DefaultUserServer)},
+		Promote:   []types.JID{member},
+		Name:      &types.GroupName{Name: "Renamed"},
+		Locked:    &types.GroupLocked{IsLocked: true},
+		Ephemeral: &types.GroupEphemeral{IsEphemeral: true, DisappearingTimer: 86400},
+	}
+
+	got := groupEventsFromInfo(context.Background(), evt)
+
+	type row struct{ eventType, participant, value string }
+	var rows []row
+	for _, event := range got {
+		assert.Equal(t, group.String(), event.GroupJID)
+		assert.Equal(t, "628123456789@s.whatsapp.net", event.Actor)
+		assert.Equal(t, timestamp, event.Timestamp)
+		rows = append(rows, row{event.EventType, event.Participant, event.Value})
+	}
+
+	assert.Equal(t, []row{
+		{domainChatStorage.GroupEventAdd, "628111111111@s.whatsapp.net", ""},
+		{domainChatStorage.GroupEventRemove, "628111111111@s.whatsapp.net", ""},
+		{domainChatStorage.GroupEventLeave, "628123456789@s.whatsapp.net", ""},
+		{domainChatStorage.GroupEventPromote, "628111111111@s.whatsapp.net", ""},
+		{domainChatStorage.GroupEventName, "", "Renamed"},
+		{domainChatStorage.GroupEventLocked, "", "true"},
+		{domainChatStorage.GroupEventEphemeral, "", "86400"},
+	}, rows)
+}
+
+func TestGroupEventsFromInfoWithoutSender(t *testing.T) {
+	evt := &events.GroupInfo{
+		JID:        types.NewJID("120363025246125486", types.GroupServer),
+		JoinReason: "invite",
+		Join:       []types.JID{types.NewJID("628111111111", types.DefaultUserServer)},
+	}
+
+	got := groupEventsFromInfo(context.Background(), evt)
+
+	assert.Len(t, got, 1)
+	assert.Equal(t, domainChatStorage.GroupEventJoin, got[0].EventType)
+	assert.Equal(t, "invite", got[0].Value)
+	assert.Empty(t, got[0].Actor)
+}
@@ -3,13 +3,13 @@ package whatsapp
 import (
 	"context"
 	"fmt"
-	"regexp"
-	"strings"
 	"time"
 
 	"go.mau.fi/whatsmeow/types"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
@@ -17,77 +17,62 @@ import (
 )
 
 // forwardMessageToWebhook is a helper function to forward message event to webhook url
-func forwardMessageToWebhook(ctx context.Context, evt *events.Message) error {
-	payload, err := createMessagePayload(ctx, evt)
+func forwardMessageToWebhook(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
+	payload, err := createMessagePayload(ctx, evt, chatStorageRepo)
 	if err != nil {
 		return err
 	}
 
-	return forwardPayloadToConfiguredWebhooks(ctx, payload, "message event")
+	return forwardPayloadToConfiguredWebhooks(ctx, payload, "message event", domainWebhook.Event{
+		Type:   domainWebhook.EventMessage,
+		Chat:   identity.Canonical(ctx, evt.Info.Chat).String(),
+		Sender: identity.Canonical(ctx, evt.Info.Sender).ToNonAD().String(),
+		FromMe: evt.Info.IsFromMe,
+	})
 }
 
-func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]any, error) {
+func createMessagePayload(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) (map[string]any, error) {
 	message := utils.BuildEventMessage(evt)
 	waReaction := utils.BuildEventReaction(evt)
 	forwarded := utils.BuildForwarded(evt)
 
 	body := make(map[string]any)
 
-	body["sender_id"] = evt.Info.Sender.User
-	body["chat_id"] = evt.Info.Chat.User
+	body["sender_id"] = identity.Canonical(ctx, evt.Info.Sender).User
+	body["chat_id"] = identity.Canonical(ctx, evt.Info.Chat).User
 
 	if from := evt.Info.SourceString(); from != "" {
 		body["from"] = from
 
-		from_user, from_group := from, ""
-		if strings.Contains(from, " in ") {
-			from_user = strings.Split(from, " in ")[0]
-			from_group = strings.Split(from, " in ")[1]
-		}
-
-		if strings.HasSuffix(from_user, "@lid") {
-			body["from_lid"] = from_user
-			lid, err := types.ParseJID(from_user)
-			if err != nil {
-				logrus.Errorf("Error when parse jid: %v", err)
-			} else {
-				pn, err := cli.Store.LIDs.GetPNForLID(ctx, lid)
-				if err != nil {
-					logrus.Errorf("Error when get pn for lid %s: %v", lid.String(), err)
-				}
-				if !pn.IsEmpty() {
-					if from_group != "" {
-						body["from"] = fmt.Sprintf("%s in %s", pn.String(), from_group)
-					} else {
-						body["from"] = pn.String()
-					}
+		// Report LID senders by phone number when known, keeping both forms in the payload
+		if evt.Info.Sender.Server == types.HiddenUserServer {
+			body["from_lid"] = evt.Info.Sender.String()
+			if pn := identity.Canonical(ctx, evt.Info.Sender); pn.Server == types.DefaultUserServer {
+				if evt.Info.IsGroup {
+					body["from"] = fmt.Sprintf("%s in %s", pn.String(), evt.Info.Chat.String())
+				} else {
+					body["from"] = pn.String()
 				}
 			}
+		} else if lid := identity.Alternate(ctx, evt.Info.Sender); !lid.IsEmpty() {
+			body["from_lid"] = lid.String()
 		}
 	}
 	if message.ID != "" {
-		tags := regexp.MustCompile(`\B@\w+`).FindAllString(message.Text, -1)
-		tagsMap := make(map[string]bool)
-		for _, tag := range tags {
-			tagsMap[tag] = true
-		}
-		for tag := range tagsMap {
-			lid, err := types.ParseJID(tag[1:] + "@lid")
-			if err != nil {
-				logrus.Errorf("Error when parse jid: %v", err)
-			} else {
-				pn, err := cli.Store.LIDs.GetPNForLID(ctx, lid)
-				if err != nil {
-					logrus.Errorf("Error when get pn for lid %s: %v", lid.String(), err)
-				}
-				if !pn.IsEmpty() {
-					message.Text = strings.Replace(message.Text, tag, fmt.Sprintf("@%s", pn.User), -1)
-				}
+		message.Text = resolveMentions(ctx, identity, message.Text, utils.BuildMentionedJIDs(evt))
+		body["message"] = message
+	}
+	// The name our team gave the sender in the address book replaces the push name
+	pushname := evt.Info.PushName
+	if chatStorageRepo != nil {
+		if customName := chatStorageRepo.GetCustomName(ctx, identity.Canonical(ctx, evt.Info.Sender).String()); customName != "" {
+			if pushname != "" && pushname != customName {
+				body["original_pushname"] = pushname
 			}
+			pushname = customName
 		}
-		body["message"] = message
 	}
-	if pushname := evt.Info.PushName; pushname != "" {
+	if pushname != "" {
 		body["pushname"] = pushname
 	}
 	if waReaction.Message != "" {
@@ -130,7 +115,7 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if audioMedia := evt.Message.GetAudioMessage(); audioMedia != nil {
-		path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, audioMedia)
+		path, err := utils.ExtractMedia(ctx, ClientFromContext(ctx), config.PathMedia, audioMedia)
 		if err != nil {
 			logrus.Errorf("Failed to download audio from %s: %v", evt.Info.SourceString(), err)
 			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download audio: %v", err))
@@ -143,7 +128,7 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if documentMedia := evt.Message.GetDocumentMessage(); documentMedia != nil {
-		path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, documentMedia)
+		path, err := utils.ExtractMedia(ctx, ClientFromContext(ctx), config.PathMedia, documentMedia)
 		if err != nil {
 			logrus.Errorf("Failed to download document from %s: %v", evt.Info.SourceString(), err)
 			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download document: %v", err))
@@ -152,7 +137,7 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if imageMedia := evt.Message.GetImageMessage(); imageMedia != nil {
-		path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, imageMedia)
+		path, err := utils.ExtractMedia(ctx, ClientFromContext(ctx), config.PathMedia, imageMedia)
 		if err != nil {
 			logrus.Errorf("Failed to download image from %s: %v", evt.Info.SourceString(), err)
 			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download image: %v", err))
@@ -177,7 +162,7 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if stickerMedia := evt.Message.GetStickerMessage(); stickerMedia != nil {
-		path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, stickerMedia)
+		path, err := utils.ExtractMedia(ctx, ClientFromContext(ctx), config.PathMedia, stickerMedia)
 		if err != nil {
 			logrus.Errorf("Failed to download sticker from %s: %v", evt.Info.SourceString(), err)
 			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download sticker: %v", err))
@@ -186,7 +171,7 @@ func createMessagePayload(ctx context.Context, evt *events.Message) (map[string]
 	}
 
 	if videoMedia := evt.Message.GetVideoMessage(); videoMedia != nil {
-		path, err := utils.ExtractMedia(ctx, cli, config.PathMedia, videoMedia)
+		path, err := utils.ExtractMedia(ctx, ClientFromContext(ctx), config.PathMedia, videoMedia)
 		if err != nil {
 			logrus.Errorf("Failed to download video from %s: %v", evt.Info.SourceString(), err)
 			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download video: %v", err))
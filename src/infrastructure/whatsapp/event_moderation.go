@@ -0,0 +1,103 @@
+package whatsapp
+
+import (
+	"context"
+	"sync"
+
+	domainModeration "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/moderation"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/lifecycle"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+const (
+	// moderationWorkers is the number of messages moderated at the same time
+	moderationWorkers = 4
+	// moderationQueueSize is the number of messages waiting for a worker before new ones are skipped
+	moderationQueueSize = 256
+)
+
+// moderator evaluates incoming group messages against the moderation rules, nil until registered
+var moderator domainModeration.IMessageModerator
+
+// moderationQueue hands incoming messages to the moderation workers
+var moderationQueue = &moderationWorkerPool{}
+
+// SetMessageModerator registers the moderation engine consulted for every incoming message
+func SetMessageModerator(m domainModeration.IMessageModerator) {
+	moderator = m
+}
+
+// handleModeration hands the message to the moderation engine without blocking the event loop,
+// since enforcing a rule takes several round trips to WhatsApp
+func handleModeration(ctx context.Context, evt *events.Message) {
+	if moderator == nil || evt.Info.IsFromMe {
+		return
+	}
+
+	moderationQueue.enqueue(ctx, evt, moderator.ModerateMessage)
+}
+
+// StopModeration stops taking new messages and waits until the queued messages are moderated
+// or ctx is done
+func StopModeration(ctx context.Context) error {
+	return moderationQueue.stop(ctx)
+}
+
+type moderationJob struct {
+	ctx      context.Context
+	evt      *events.Message
+	moderate func(ctx context.Context, evt *events.Message)
+}
+
+// moderationWorkerPool runs moderation on a fixed number of workers, started with the first message
+type moderationWorkerPool struct {
+	inFlight lifecycle.InFlight
+
+	mu      sync.Mutex
+	jobs    chan moderationJob
+	stopped bool
+}
+
+// enqueue queues a message for moderation. Messages arriving while the queue is full or after
+// stop are skipped.
+func (p *moderationWorkerPool) enqueue(ctx context.Context, evt *events.Message, moderate func(ctx context.Context, evt *events.Message)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopped {
+		return
+	}
+	if p.jobs == nil {
+		p.jobs = make(chan moderationJob, moderationQueueSize)
+		for range moderationWorkers {
+			done := p.inFlight.Start()
+			go func() {
+				defer done()
+				for job := range p.jobs {
+					job.moderate(job.ctx, job.evt)
+				}
+			}()
+		}
+	}
+
+	select {
+	case p.jobs <- moderationJob{ctx: ctx, evt: evt, moderate: moderate}:
+	default:
+		logrus.Warnf("Skipped moderation of message %s in %s, too many messages are waiting", evt.Info.ID, evt.Info.Chat)
+	}
+}
+
+// stop closes the queue and waits until the workers moderated what was queued or ctx is done
+func (p *moderationWorkerPool) stop(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.stopped {
+		p.stopped = true
+		if p.jobs != nil {
+			close(p.jobs)
+		}
+	}
+	p.mu.Unlock()
+
+	return p.inFlight.Wait(ctx)
+}
@@ -0,0 +1,39 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestModerationWorkerPoolDrainsOnStop(t *testing.T) {
+	var moderated atomic.Int32
+	release := make(chan struct{})
+	moderate := func(context.Context, *events.Message) {
+		<-release
+		moderated.Add(1)
+	}
+
+	pool := &moderationWorkerPool{}
+	for i := range moderationWorkers + 2 {
+		pool.enqueue(context.Background(), &events.Message{Info: types.MessageInfo{ID: fmt.Sprintf("MSG%d", i)}}, moderate)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, pool.stop(ctx), context.DeadlineExceeded, "stop waits for the queued messages")
+
+	// Messages arriving after stop are skipped
+	pool.enqueue(context.Background(), &events.Message{}, moderate)
+
+	close(release)
+	require.NoError(t, pool.stop(context.Background()))
+	assert.Equal(t, int32(moderationWorkers+2), moderated.Load())
+}
@@ -0,0 +1,179 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// presenceHistoryRetention is how long online/offline changes are kept
+const presenceHistoryRetention = 30 * 24 * time.Hour
+
+// handlePresence records online/offline changes of subscribed contacts and forwards them to the
+// websocket and the webhooks
+func handlePresence(ctx context.Context, evt *events.Presence, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if evt.Unavailable {
+		if evt.LastSeen.IsZero() {
+			log.Infof("%s is now offline", evt.From)
+		} else {
+			log.Infof("%s is now offline (last seen: %s)", evt.From, evt.LastSeen)
+		}
+	} else {
+		log.Infof("%s is now online", evt.From)
+	}
+
+	// Subscriptions are made with phone numbers, but updates may arrive for the LID
+	jid := identity.Canonical(ctx, evt.From)
+
+	if chatStorageRepo != nil {
+		presence := &domainChatStorage.ContactPresence{JID: jid.String(), Online: !evt.Unavailable, UpdatedAt: time.Now()}
+		if !evt.LastSeen.IsZero() {
+			lastSeen := evt.LastSeen
+			presence.LastSeen = &lastSeen
+		}
+		if err := chatStorageRepo.StorePresence(presence); err != nil {
+			log.Warnf("Failed to store presence of %s: %v", jid, err)
+		}
+	}
+
+	payload := createPresencePayload(jid, evt)
+	websocket.Publish(websocket.BroadcastMessage{
+		Code:    "PRESENCE",
+		Message: fmt.Sprintf("%s is now %s", jid, presenceStatus(evt)),
+		Result:  payload["payload"],
+	})
+
+	if webhookSubscribed(domainWebhook.EventPresence) {
+		go func() {
+			event := domainWebhook.Event{Type: domainWebhook.EventPresence, Chat: jid.String()}
+			if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "presence event", event); err != nil {
+				logrus.Errorf("Failed to forward presence event to webhook: %v", err)
+			}
+		}()
+	}
+}
+
+// handleChatPresence forwards typing and recording indicators to the websocket and the webhooks
+func handleChatPresence(ctx context.Context, evt *events.ChatPresence) {
+	log.Debugf("%s is %s in %s", evt.Sender, chatPresenceActivity(evt), evt.Chat)
+
+	payload := createChatPresencePayload(ctx, evt)
+	websocket.Publish(websocket.BroadcastMessage{
+		Code:    "CHAT_PRESENCE",
+		Message: fmt.Sprintf("%s is %s", evt.Sender, chatPresenceActivity(evt)),
+		Result:  payload["payload"],
+	})
+
+	if webhookSubscribed(domainWebhook.EventChatPresence) {
+		go func() {
+			event := domainWebhook.Event{
+				Type:   domainWebhook.EventChatPresence,
+				Chat:   identity.Canonical(ctx, evt.Chat).String(),
+				Sender: identity.Canonical(ctx, evt.Sender).ToNonAD().String(),
+				FromMe: evt.IsFromMe,
+			}
+			if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "chat presence event", event); err != nil {
+				logrus.Errorf("Failed to forward chat presence event to webhook: %v", err)
+			}
+		}()
+	}
+}
+
+// resubscribePresence renews the stored presence subscriptions, which WhatsApp forgets on every
+// reconnect, and drops history older than the retention period
+func resubscribePresence(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	cli := ClientFromContext(ctx)
+	if chatStorageRepo == nil || cli == nil {
+		return
+	}
+
+	if _, err := chatStorageRepo.DeletePresenceHistoryBefore(time.Now().Add(-presenceHistoryRetention)); err != nil {
+		log.Warnf("Failed to prune presence history: %v", err)
+	}
+
+	subscriptions, err := chatStorageRepo.GetPresenceSubscriptions(ctx)
+	if err != nil {
+		log.Errorf("Failed to load presence subscriptions: %v", err)
+		return
+	}
+
+	subscribed := 0
+	for _, subscription := range subscriptions {
+		jid, err := types.ParseJID(subscription.JID)
+		if err != nil {
+			continue
+		}
+		if err := cli.SubscribePresence(ctx, jid); err != nil {
+			log.Warnf("Failed to resubscribe to presence of %s: %v", jid, err)
+			continue
+		}
+		subscribed++
+	}
+
+	if len(subscriptions) > 0 {
+		log.Infof("Resubscribed to presence of %d/%d contacts", subscribed, len(subscriptions))
+	}
+}
+
+// createPresencePayload creates a webhook payload for a contact going online or offline
+func createPresencePayload(jid types.JID, evt *events.Presence) map[string]any {
+	payload := map[string]any{
+		"from":   jid.String(),
+		"status": presenceStatus(evt),
+	}
+	if !evt.LastSeen.IsZero() {
+		payload["last_seen"] = evt.LastSeen.Format(time.RFC3339)
+	}
+
+	return map[string]any{
+		"event":     "presence",
+		"payload":   payload,
+		"timestamp": time.Now().Format(time.RFC3339),
+	}
+}
+
+// createChatPresencePayload creates a webhook payload for typing and recording indicators
+func createChatPresencePayload(ctx context.Context, evt *events.ChatPresence) map[string]any {
+	payload := map[string]any{
+		"chat_id":   identity.Canonical(ctx, evt.Chat).String(),
+		"sender_id": identity.Canonical(ctx, evt.Sender).String(),
+		"from":      evt.SourceString(),
+		"is_group":  evt.IsGroup,
+		"state":     string(evt.State),
+		"media":     string(evt.Media),
+		"activity":  chatPresenceActivity(evt),
+	}
+
+	return map[string]any{
+		"event":     "chat_presence",
+		"payload":   payload,
+		"timestamp": time.Now().Format(time.RFC3339),
+	}
+}
+
+// presenceStatus describes a presence update as online or offline
+func presenceStatus(evt *events.Presence) string {
+	if evt.Unavailable {
+		return "offline"
+	}
+	return "online"
+}
+
+// chatPresenceActivity describes a chat presence as typing, recording or paused
+func chatPresenceActivity(evt *events.ChatPresence) string {
+	switch {
+	case evt.State != types.ChatPresenceComposing:
+		return "paused"
+	case evt.Media == types.ChatPresenceMediaAudio:
+		return "recording"
+	default:
+		return "typing"
+	}
+}
@@ -0,0 +1,85 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestCreatePresencePayload(t *testing.T) {
+	customer := types.NewJID("628111111111", types.DefaultUserServer)
+
+	t.Run("online", func(t *testing.T) {
+		body := createPresencePayload(customer, &events.Presence{From: customer})
+
+		assert.Equal(t, "presence", body["event"])
+		payload := body["payload"].(map[string]any)
+		assert.Equal(t, "628111111111@s.whatsapp.net", payload["from"])
+		assert.Equal(t, "online", payload["status"])
+		assert.NotContains(t, payload, "last_seen")
+	})
+
+	t.Run("offline with last seen", func(t *testing.T) {
+		lastSeen := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		body := createPresencePayload(customer, &events.Presence{From: customer, Unavailable: true, LastSeen: lastSeen})
+
+		payload := body["payload"].(map[string]any)
+		assert.Equal(t, "offline", payload["status"])
+		assert.Equal(t, "2025-01-02T03:04:05Z", payload["last_seen"])
+	})
+}
+
+func TestCreateChatPresencePayload(t *testing.T) {
+	customer := types.JID{User: "628111111111", Server: types.DefaultUserServer, Device: 2}
+	group := types.NewJID("120363025246125486", types.GroupServer)
+
+	tests := []struct {
+		name     string
+		evt      *events.ChatPresence
+		activity string
+	}{
+		{
+			name: "typing in private chat",
+			evt: &events.ChatPresence{
+				MessageSource: types.MessageSource{Chat: customer.ToNonAD(), Sender: customer},
+				State:         types.ChatPresenceComposing,
+			},
+			activity: "typing",
+		},
+		{
+			name: "recording in group",
+			evt: &events.ChatPresence{
+				MessageSource: types.MessageSource{Chat: group, Sender: customer, IsGroup: true},
+				State:         types.ChatPresenceComposing,
+				Media:         types.ChatPresenceMediaAudio,
+			},
+			activity: "recording",
+		},
+		{
+			name: "paused",
+			evt: &events.ChatPresence{
+				MessageSource: types.MessageSource{Chat: customer.ToNonAD(), Sender: customer},
+				State:         types.ChatPresencePaused,
+			},
+			activity: "paused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := createChatPresencePayload(context.Background(), tt.evt)
+
+			assert.Equal(t, "chat_presence", body["event"])
+			payload := body["payload"].(map[string]any)
+			assert.Equal(t, tt.evt.Chat.String(), payload["chat_id"])
+			assert.Equal(t, "628111111111@s.whatsapp.net", payload["sender_id"])
+			assert.Equal(t, tt.evt.IsGroup, payload["is_group"])
+			assert.Equal(t, string(tt.evt.State), payload["state"])
+			assert.Equal(t, tt.activity, payload["activity"])
+		})
+	}
+}
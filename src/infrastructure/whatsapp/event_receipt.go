@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
@@ -69,5 +70,10 @@ func createReceiptPayload(evt *events.Receipt) map[string]any {
 // forwardReceiptToWebhook forwards message acknowledgement events to the configured webhook URLs
 func forwardReceiptToWebhook(ctx context.Context, evt *events.Receipt) error {
 	payload := createReceiptPayload(evt)
-	return forwardPayloadToConfiguredWebhooks(ctx, payload, "message ack event")
+	return forwardPayloadToConfiguredWebhooks(ctx, payload, "message ack event", domainWebhook.Event{
+		Type:   domainWebhook.EventReceipt,
+		Chat:   identity.Canonical(ctx, evt.Chat).String(),
+		Sender: identity.Canonical(ctx, evt.Sender).ToNonAD().String(),
+		FromMe: evt.IsFromMe,
+	})
 }
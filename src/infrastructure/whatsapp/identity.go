@@ -0,0 +1,260 @@
+package whatsapp
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainIdentity "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/identity"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/lifecycle"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// identity resolves LIDs for webhook payloads
+var identity = NewIdentityResolver()
+
+type identityResolver struct {
+	lidStore func(ctx context.Context) store.LIDStore
+}
+
+// NewIdentityResolver creates a resolver backed by the LID mappings whatsmeow keeps for the
+// client of the session selected by ctx. Mappings are learned from messages, group metadata
+// and history sync.
+func NewIdentityResolver() domainIdentity.IIdentityResolver {
+	return &identityResolver{lidStore: currentLIDStore}
+}
+
+// currentLIDStore returns the LID mappings of the session selected by ctx
+func currentLIDStore(ctx context.Context) store.LIDStore {
+	cli := ClientFromContext(ctx)
+	if cli == nil || cli.Store == nil {
+		return nil
+	}
+	return cli.Store.LIDs
+}
+
+func (r *identityResolver) Canonical(ctx context.Context, jid types.JID) types.JID {
+	if jid.Server != types.DefaultUserServer && jid.Server != types.HiddenUserServer {
+		return jid
+	}
+
+	jid = jid.ToNonAD()
+	if jid.Server == types.HiddenUserServer {
+		if pn := r.Alternate(ctx, jid); !pn.IsEmpty() {
+			return pn
+		}
+	}
+
+	return jid
+}
+
+func (r *identityResolver) CanonicalString(ctx context.Context, jid string) string {
+	if !strings.HasSuffix(jid, "@"+types.DefaultUserServer) && !strings.HasSuffix(jid, "@"+types.HiddenUserServer) {
+		return jid
+	}
+
+	parsed, err := types.ParseJID(jid)
+	if err != nil {
+		return jid
+	}
+
+	return r.Canonical(ctx, parsed).String()
+}
+
+func (r *identityResolver) Alternate(ctx context.Context, jid types.JID) types.JID {
+	lids := r.lidStore(ctx)
+	if lids == nil {
+		return types.EmptyJID
+	}
+
+	var (
+		alternate types.JID
+		err       error
+	)
+
+	jid = jid.ToNonAD()
+	switch jid.Server {
+	case types.HiddenUserServer:
+		alternate, err = lids.GetPNForLID(ctx, jid)
+	case types.DefaultUserServer:
+		alternate, err = lids.GetLIDForPN(ctx, jid)
+	default:
+		return types.EmptyJID
+	}
+
+	if err != nil {
+		logrus.WithError(err).WithField("jid", jid.String()).Debug("Failed to resolve alternate JID")
+		return types.EmptyJID
+	}
+	if alternate.IsEmpty() {
+		return types.EmptyJID
+	}
+
+	return alternate.ToNonAD()
+}
+
+// mergeLIDChats folds chats stored under a LID into their phone number chat once the mapping is known
+func mergeLIDChats(chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if chatStorageRepo == nil {
+		return
+	}
+
+	if _, err := chatStorageRepo.MergeLIDChats(); err != nil {
+		log.Warnf("Failed to merge LID chats: %v", err)
+	}
+}
+
+// lidChatMerges runs the LID chat merges one at a time in the background
+var lidChatMerges = &lidChatMerger{}
+
+type lidChatMerger struct {
+	inFlight lifecycle.InFlight
+
+	mu      sync.Mutex
+	repo    domainChatStorage.IChatStorageRepository // repository of the latest request
+	running bool
+	pending bool
+	stopped bool
+}
+
+// request schedules a merge. Requests made while a merge is running are folded into a single
+// follow-up merge, so a burst of new mappings, such as a history sync, costs one more scan of
+// the chats. Requests after stop are ignored.
+func (m *lidChatMerger) request(chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if chatStorageRepo == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopped {
+		return
+	}
+	m.repo = chatStorageRepo
+	if m.running {
+		m.pending = true
+		return
+	}
+
+	m.running = true
+	done := m.inFlight.Start()
+	go func() {
+		defer done()
+		m.run()
+	}()
+}
+
+// run merges until no request is pending anymore
+func (m *lidChatMerger) run() {
+	for {
+		m.mu.Lock()
+		chatStorageRepo := m.repo
+		m.pending = false
+		m.mu.Unlock()
+
+		mergeLIDChats(chatStorageRepo)
+
+		m.mu.Lock()
+		if !m.pending || m.stopped {
+			m.running = false
+			m.mu.Unlock()
+			return
+		}
+		m.mu.Unlock()
+	}
+}
+
+// stop ignores further requests and waits until the running merge finished or ctx is done
+func (m *lidChatMerger) stop(ctx context.Context) error {
+	m.mu.Lock()
+	m.stopped = true
+	m.mu.Unlock()
+
+	return m.inFlight.Wait(ctx)
+}
+
+// StopLIDChatMerges waits for the LID chat merge in progress, so it does not run against a closed
+// chat storage. Merges requested afterwards are skipped, the next start catches up on them.
+func StopLIDChatMerges(ctx context.Context) error {
+	return lidChatMerges.stop(ctx)
+}
+
+// mappingLIDStore reports the LID mappings whatsmeow learns that it did not know before
+type mappingLIDStore struct {
+	store.LIDStore
+	onNewMapping func()
+}
+
+// newMappingLIDStore wraps the LID mappings of a device so chats stored under a LID are merged
+// as soon as its phone number is learned, instead of rescanning the chats on every connect
+func newMappingLIDStore(lids store.LIDStore, chatStorageRepo domainChatStorage.IChatStorageRepository) store.LIDStore {
+	if _, ok := lids.(*mappingLIDStore); ok || lids == nil || chatStorageRepo == nil {
+		return lids
+	}
+	return &mappingLIDStore{LIDStore: lids, onNewMapping: func() { lidChatMerges.request(chatStorageRepo) }}
+}
+
+func (s *mappingLIDStore) PutLIDMapping(ctx context.Context, lid, pn types.JID) error {
+	isNew := s.isNew(ctx, lid, pn)
+	if err := s.LIDStore.PutLIDMapping(ctx, lid, pn); err != nil {
+		return err
+	}
+	if isNew {
+		s.onNewMapping()
+	}
+	return nil
+}
+
+func (s *mappingLIDStore) PutManyLIDMappings(ctx context.Context, mappings []store.LIDMapping) error {
+	isNew := false
+	for _, mapping := range mappings {
+		if s.isNew(ctx, mapping.LID, mapping.PN) {
+			isNew = true
+			break
+		}
+	}
+
+	if err := s.LIDStore.PutManyLIDMappings(ctx, mappings); err != nil {
+		return err
+	}
+	if isNew {
+		s.onNewMapping()
+	}
+	return nil
+}
+
+// isNew reports whether storing the mapping changes what the phone number resolves to
+func (s *mappingLIDStore) isNew(ctx context.Context, lid, pn types.JID) bool {
+	if lid.Server != types.HiddenUserServer || pn.Server != types.DefaultUserServer {
+		return false
+	}
+
+	known, err := s.LIDStore.GetLIDForPN(ctx, pn)
+	return err != nil || known.User != lid.User
+}
+
+// resolveMentions rewrites @<lid> mentions in text to the mentioned users' phone numbers
+func resolveMentions(ctx context.Context, resolver domainIdentity.IIdentityResolver, text string, mentionedJIDs []string) string {
+	for _, mentioned := range mentionedJIDs {
+		jid, err := types.ParseJID(mentioned)
+		if err != nil || jid.Server != types.HiddenUserServer {
+			continue
+		}
+
+		pn := resolver.Canonical(ctx, jid)
+		if pn.Server != types.DefaultUserServer {
+			continue
+		}
+
+		// A mention ends at a word boundary, so a longer ID sharing the prefix is left alone
+		mention := regexp.MustCompile("@" + regexp.QuoteMeta(jid.User) + `\b`)
+		text = mention.ReplaceAllLiteralString(text, "@"+pn.User)
+	}
+
+	return text
+}
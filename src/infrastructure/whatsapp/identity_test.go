@@ -0,0 +1,171 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types"
+)
+
+type fakeLIDStore struct {
+	store.LIDStore
+	pnByLID map[types.JID]types.JID
+}
+
+func (f *fakeLIDStore) GetPNForLID(_ context.Context, lid types.JID) (types.JID, error) {
+	return f.pnByLID[lid], nil
+}
+
+func (f *fakeLIDStore) GetLIDForPN(_ context.Context, pn types.JID) (types.JID, error) {
+	for lid, mapped := range f.pnByLID {
+		if mapped == pn {
+			return lid, nil
+		}
+	}
+	return types.EmptyJID, nil
+}
+
+func (f *fakeLIDStore) PutLIDMapping(_ context.Context, lid, pn types.JID) error {
+	f.pnByLID[lid] = pn
+	return nil
+}
+
+func (f *fakeLIDStore) PutManyLIDMappings(ctx context.Context, mappings []store.LIDMapping) error {
+	for _, mapping := range mappings {
+		_ = f.PutLIDMapping(ctx, mapping.LID, mapping.PN)
+	}
+	return nil
+}
+
+func newTestIdentityResolver() *identityResolver {
+	lids := &fakeLIDStore{pnByLID: map[types.JID]types.JID{
+		types.NewJID("123456789", types.HiddenUserServer): types.NewJID("628123456789", types.DefaultUserServer),
+	}}
+	return &identityResolver{lidStore: func(context.Context) store.LIDStore { return lids }}
+}
+
+func TestIdentityResolverCanonical(t *testing.T) {
+	ctx := context.Background()
+	resolver := newTestIdentityResolver()
+
+	tests := []struct {
+		name string
+		jid  string
+		want string
+	}{
+		{"known lid", "123456789@lid", "628123456789@s.whatsapp.net"},
+		{"lid with device", "123456789:12@lid", "628123456789@s.whatsapp.net"},
+		{"unknown lid", "987654321@lid", "987654321@lid"},
+		{"phone number with device", "628123456789:3@s.whatsapp.net", "628123456789@s.whatsapp.net"},
+		{"group", "120363025246125486@g.us", "120363025246125486@g.us"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, resolver.CanonicalString(ctx, tt.jid))
+		})
+	}
+
+	assert.Equal(t, "not a jid", resolver.CanonicalString(ctx, "not a jid"))
+}
+
+func TestIdentityResolverAlternate(t *testing.T) {
+	ctx := context.Background()
+	resolver := newTestIdentityResolver()
+
+	assert.Equal(t, "123456789@lid", resolver.Alternate(ctx, types.NewJID("628123456789", types.DefaultUserServer)).String())
+	assert.Equal(t, "628123456789@s.whatsapp.net", resolver.Alternate(ctx, types.NewJID("123456789", types.HiddenUserServer)).String())
+	assert.True(t, resolver.Alternate(ctx, types.NewJID("620000000000", types.DefaultUserServer)).IsEmpty())
+
+	offline := &identityResolver{lidStore: func(context.Context) store.LIDStore { return nil }}
+	assert.True(t, offline.Alternate(ctx, types.NewJID("123456789", types.HiddenUserServer)).IsEmpty())
+}
+
+func TestResolveMentions(t *testing.T) {
+	ctx := context.Background()
+	resolver := newTestIdentityResolver()
+
+	text := resolveMentions(ctx, resolver, "hi @123456789 and @987654321, cc @628111",
+		[]string{"123456789@lid", "987654321@lid", "628111@s.whatsapp.net"})
+
+	assert.Equal(t, "hi @628123456789 and @987654321, cc @628111", text)
+}
+
+func TestResolveMentionsMatchesWholeIDs(t *testing.T) {
+	ctx := context.Background()
+	resolver := newTestIdentityResolver()
+
+	text := resolveMentions(ctx, resolver, "@123456789, not @1234567890 or @123456789abc", []string{"123456789@lid"})
+
+	assert.Equal(t, "@628123456789, not @1234567890 or @123456789abc", text)
+}
+
+func TestMappingLIDStoreReportsNewMappings(t *testing.T) {
+	ctx := context.Background()
+	lid := types.NewJID("123456789", types.HiddenUserServer)
+	pn := types.NewJID("628123456789", types.DefaultUserServer)
+
+	newMappings := 0
+	lids := &mappingLIDStore{
+		LIDStore:     &fakeLIDStore{pnByLID: map[types.JID]types.JID{}},
+		onNewMapping: func() { newMappings++ },
+	}
+
+	assert.NoError(t, lids.PutLIDMapping(ctx, lid, pn))
+	assert.Equal(t, 1, newMappings)
+
+	// Mappings whatsmeow already knows, as on every message from the contact, are not reported
+	assert.NoError(t, lids.PutLIDMapping(ctx, lid, pn))
+	assert.NoError(t, lids.PutManyLIDMappings(ctx, []store.LIDMapping{{LID: lid, PN: pn}}))
+	assert.Equal(t, 1, newMappings)
+
+	assert.NoError(t, lids.PutManyLIDMappings(ctx, []store.LIDMapping{
+		{LID: lid, PN: pn},
+		{LID: types.NewJID("987654321", types.HiddenUserServer), PN: types.NewJID("628987654321", types.DefaultUserServer)},
+	}))
+	assert.Equal(t, 2, newMappings)
+}
+
+// blockingMergeRepo records its merges and holds each one until it is released
+type blockingMergeRepo struct {
+	domainChatStorage.IChatStorageRepository
+	merges  chan string
+	release chan struct{}
+	name    string
+}
+
+func (r *blockingMergeRepo) MergeLIDChats() (int, error) {
+	r.merges <- r.name
+	<-r.release
+	return 0, nil
+}
+
+func TestLIDChatMergerStop(t *testing.T) {
+	merges, release := make(chan string, 4), make(chan struct{})
+	first := &blockingMergeRepo{merges: merges, release: release, name: "first"}
+	second := &blockingMergeRepo{merges: merges, release: release, name: "second"}
+
+	merger := &lidChatMerger{}
+	merger.request(first)
+	assert.Equal(t, "first", <-merges)
+
+	// Requests during a merge fold into one follow-up merge with the latest repository
+	merger.request(first)
+	merger.request(second)
+	release <- struct{}{}
+	assert.Equal(t, "second", <-merges)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, merger.stop(ctx), context.DeadlineExceeded, "stop waits for the running merge")
+
+	merger.request(first)
+	release <- struct{}{}
+	require.NoError(t, merger.stop(context.Background()))
+	assert.Empty(t, merges, "requests after stop are ignored")
+}
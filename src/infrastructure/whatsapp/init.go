@@ -13,7 +13,10 @@ import (
 	"go.mau.fi/whatsmeow/proto/waHistorySync"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
@@ -38,7 +41,6 @@ type ExtractedMedia struct {
 
 // Global variables
 var (
-	cli           *whatsmeow.Client
 	db            *sqlstore.Container // Add global database reference for cleanup
 	keysDB        *sqlstore.Container
 	log           waLog.Logger
@@ -71,48 +73,44 @@ func initDatabase(ctx context.Context, dbLog waLog.Logger, DBURI string) (*sqlst
 	return nil, fmt.Errorf("unknown database type: %s. Currently only sqlite3(file:) and postgres are supported", DBURI)
 }
 
+// syncKeysDevice makes the devices of the keys database match the devices of every session
 func syncKeysDevice(ctx context.Context, db, keysDB *sqlstore.Container) {
 	if keysDB == nil {
 		return
 	}
 
-	dev, err := db.GetFirstDevice(ctx)
+	devs, err := db.GetAllDevices(ctx)
 	if err != nil {
 		log.Errorf("Failed to get all devices: %v", err)
-	} else {
-		found := false
-		if devs, err := keysDB.GetAllDevices(ctx); err != nil {
-			log.Errorf("Failed to get all devices: %v", err)
-		} else {
-			for _, d := range devs {
-				if d.ID == dev.ID {
-					found = true
-					break
-				} else {
-					keysDB.DeleteDevice(ctx, d)
-				}
-			}
-
-			if !found {
-				keysDB.PutDevice(ctx, dev)
-			}
-		}
+		return
 	}
-}
-
-// InitWaCLI initializes the WhatsApp client
-func InitWaCLI(ctx context.Context, storeContainer, keysStoreContainer *sqlstore.Container, chatStorageRepo domainChatStorage.IChatStorageRepository) *whatsmeow.Client {
-	device, err := storeContainer.GetFirstDevice(ctx)
+	keysDevs, err := keysDB.GetAllDevices(ctx)
 	if err != nil {
-		log.Errorf("Failed to get device: %v", err)
-		panic(err)
+		log.Errorf("Failed to get all devices: %v", err)
+		return
 	}
 
-	if device == nil {
-		log.Errorf("No device found")
-		panic("No device found")
+	known := make(map[string]bool, len(devs))
+	for _, dev := range devs {
+		known[dev.ID.String()] = true
+	}
+	synced := make(map[string]bool, len(keysDevs))
+	for _, d := range keysDevs {
+		if known[d.ID.String()] {
+			synced[d.ID.String()] = true
+		} else {
+			keysDB.DeleteDevice(ctx, d)
+		}
 	}
+	for _, dev := range devs {
+		if !synced[dev.ID.String()] {
+			keysDB.PutDevice(ctx, dev)
+		}
+	}
+}
 
+// InitWaCLI initializes the client of every session and returns the client of the default session
+func InitWaCLI(ctx context.Context, storeContainer, keysStoreContainer *sqlstore.Container, chatStorageRepo domainChatStorage.IChatStorageRepository) *whatsmeow.Client {
 	// Configure device properties
 	osName := fmt.Sprintf("%s %s", config.AppOs, config.AppVersion)
 	store.DeviceProps.PlatformType = &config.AppPlatform
@@ -122,43 +120,34 @@ func InitWaCLI(ctx context.Context, storeContainer, keysStoreContainer *sqlstore
 	db = storeContainer
 	keysDB = keysStoreContainer
 
-	// Configure a separated database for accelerating encryption caching
-	if keysDB != nil && device.ID != nil {
-		innerStore := sqlstore.NewSQLStore(keysStoreContainer, *device.ID)
-
-		syncKeysDevice(ctx, db, keysDB)
-		device.Identities = innerStore
-		device.Sessions = innerStore
-		device.PreKeys = innerStore
-		device.SenderKeys = innerStore
-		device.MsgSecrets = innerStore
-		device.PrivacyTokens = innerStore
+	cli, err := loadSessions(ctx, chatStorageRepo)
+	if err != nil {
+		log.Errorf("Failed to initialize sessions: %v", err)
+		panic(err)
 	}
 
-	// Create and configure the client
-	cli = whatsmeow.NewClient(device, waLog.Stdout("Client", config.WhatsappLogLevel, true))
-	cli.EnableAutoReconnect = true
-	cli.AutoTrustIdentity = true
-
-	cli.AddEventHandler(func(rawEvt interface{}) {
-		handler(ctx, rawEvt, chatStorageRepo)
-	})
-
 	return cli
 }
 
-// UpdateGlobalClient updates the global cli variable with a new client instance
+// UpdateGlobalClient replaces the client of the default session with a new client instance
 // This is needed when reinitializing the client after logout to ensure all
 // infrastructure code uses the new client instance
 func UpdateGlobalClient(newCli *whatsmeow.Client, newDB *sqlstore.Container) {
-	cli = newCli
+	sessionsMu.Lock()
+	if session, ok := sessions[domainSession.DefaultSessionID]; ok {
+		session.client = newCli
+	} else {
+		sessions[domainSession.DefaultSessionID] = &sessionClient{client: newCli, createdAt: time.Now()}
+	}
+	sessionsMu.Unlock()
+
 	db = newDB
 	log.Infof("Global WhatsApp client updated successfully")
 }
 
-// GetClient returns the current global client instance (alias for GetGlobalClient)
+// GetClient returns the client of the default session, use ClientFromContext to honour the selected session
 func GetClient() *whatsmeow.Client {
-	return cli
+	return ClientFromContext(context.Background())
 }
 
 // Get DB instance
@@ -166,8 +155,9 @@ func GetDB() *sqlstore.Container {
 	return db
 }
 
-// GetConnectionStatus returns the current connection status of the global client
-func GetConnectionStatus() (isConnected bool, isLoggedIn bool, deviceID string) {
+// GetConnectionStatus returns the current connection status of the client of the session selected by ctx
+func GetConnectionStatus(ctx context.Context) (isConnected bool, isLoggedIn bool, deviceID string) {
+	cli := ClientFromContext(ctx)
 	if cli == nil {
 		return false, false, ""
 	}
@@ -352,7 +342,6 @@ func ReinitializeWhatsAppComponents(ctx context.Context, chatStorageRepo domainC
 
 	// Update global references
 	db = newDB
-	cli = newCli
 
 	logrus.Info("[CLEANUP] Database and client reinitialized successfully")
 
@@ -364,7 +353,7 @@ func PerformCompleteCleanup(ctx context.Context, logPrefix string, chatStorageRe
 	logrus.Infof("[%s] Starting complete cleanup process...", logPrefix)
 
 	// Disconnect current client if it exists
-	if cli != nil {
+	if cli := ClientFromContext(ctx); cli != nil {
 		cli.Disconnect()
 		logrus.Infof("[%s] Client disconnected", logPrefix)
 	}
@@ -430,8 +419,8 @@ func handleRemoteLogout(ctx context.Context, chatStorageRepo domainChatStorage.I
 		}
 	}
 
-	// Perform complete cleanup with global client synchronization
-	_, _, err := PerformCleanupAndUpdateGlobals(ctx, "REMOTE_LOGOUT", chatStorageRepo)
+	// Clean up the session that was logged out
+	err := CleanupSession(ctx, "REMOTE_LOGOUT", chatStorageRepo)
 	if err != nil {
 		logrus.Errorf("[REMOTE_LOGOUT] Cleanup failed: %v", err)
 		return
@@ -449,24 +438,46 @@ func handler(ctx context.Context, rawEvt any, chatStorageRepo domainChatStorage.
 		handleAppStateSyncComplete(ctx, evt)
 	case *events.PairSuccess:
 		handlePairSuccess(ctx, evt)
+	case *events.PairError:
+		handlePairError(ctx, evt)
 	case *events.LoggedOut:
+		handleConnectionState(ctx, evt)
 		handleLoggedOut(ctx, chatStorageRepo)
-	case *events.Connected, *events.PushNameSetting:
+	case *events.Connected:
+		handleConnectionState(ctx, evt)
+		handleConnectionEvents(ctx)
+		go resubscribePresence(ctx, chatStorageRepo)
+	case *events.PushNameSetting:
 		handleConnectionEvents(ctx)
-	case *events.StreamReplaced:
-		handleStreamReplaced(ctx)
+	case *events.StreamReplaced, *events.Disconnected, *events.KeepAliveTimeout, *events.KeepAliveRestored,
+		*events.TemporaryBan, *events.ConnectFailure, *events.ClientOutdated:
+		handleConnectionState(ctx, evt)
 	case *events.Message:
 		handleMessage(ctx, evt, chatStorageRepo)
 	case *events.Receipt:
-		handleReceipt(ctx, evt)
+		handleReceipt(ctx, evt, chatStorageRepo)
 	case *events.Presence:
-		handlePresence(ctx, evt)
+		handlePresence(ctx, evt, chatStorageRepo)
+	case *events.ChatPresence:
+		handleChatPresence(ctx, evt)
 	case *events.HistorySync:
 		handleHistorySync(ctx, evt, chatStorageRepo)
 	case *events.AppState:
 		handleAppState(ctx, evt)
 	case *events.GroupInfo:
-		handleGroupInfo(ctx, evt)
+		handleGroupInfo(ctx, evt, chatStorageRepo)
+	case *events.Archive:
+		handleArchive(ctx, evt, chatStorageRepo)
+	case *events.Pin:
+		handlePin(ctx, evt, chatStorageRepo)
+	case *events.Mute:
+		handleMute(ctx, evt, chatStorageRepo)
+	case *events.MarkChatAsRead:
+		handleMarkChatAsRead(ctx, evt, chatStorageRepo)
+	case *events.Picture:
+		handlePicture(ctx, evt, chatStorageRepo)
+	case *events.Blocklist:
+		handleBlocklist(ctx, evt, chatStorageRepo)
 	}
 }
 
@@ -476,7 +487,7 @@ func handleDeleteForMe(ctx context.Context, evt *events.DeleteForMe, chatStorage
 	log.Infof("Deleted message %s for %s", evt.MessageID, evt.SenderJID.String())
 
 	// Find the message to get its chat JID
-	message, err := chatStorageRepo.GetMessageByID(evt.MessageID)
+	message, err := chatStorageRepo.GetMessageByID(ctx, evt.MessageID)
 	if err != nil {
 		log.Errorf("Failed to find message %s for deletion: %v", evt.MessageID, err)
 		return
@@ -488,14 +499,14 @@ func handleDeleteForMe(ctx context.Context, evt *events.DeleteForMe, chatStorage
 	}
 
 	// Delete the message from database
-	if err := chatStorageRepo.DeleteMessage(evt.MessageID, message.ChatJID); err != nil {
+	if err := chatStorageRepo.DeleteMessage(ctx, evt.MessageID, message.ChatJID); err != nil {
 		log.Errorf("Failed to delete message %s from database: %v", evt.MessageID, err)
 	} else {
 		log.Infof("Successfully deleted message %s from database", evt.MessageID)
 	}
 
 	// Send webhook notification for delete event
-	if len(config.WhatsappWebhook) > 0 {
+	if webhookSubscribed(domainWebhook.EventDelete) {
 		go func() {
 			if err := forwardDeleteToWebhook(ctx, evt, message); err != nil {
 				log.Errorf("Failed to forward delete event to webhook: %v", err)
@@ -504,7 +515,11 @@ func handleDeleteForMe(ctx context.Context, evt *events.DeleteForMe, chatStorage
 	}
 }
 
-func handleAppStateSyncComplete(_ context.Context, evt *events.AppStateSyncComplete) {
+func handleAppStateSyncComplete(ctx context.Context, evt *events.AppStateSyncComplete) {
+	cli := ClientFromContext(ctx)
+	if cli == nil {
+		return
+	}
 	if len(cli.Store.PushName) > 0 && evt.Name == appstate.WAPatchCriticalBlock {
 		if err := cli.SendPresence(context.Background(), types.PresenceAvailable); err != nil {
 			log.Warnf("Failed to send available presence: %v", err)
@@ -515,13 +530,21 @@ func handleAppStateSyncComplete(_ context.Context, evt *events.AppStateSyncCompl
 }
 
 func handlePairSuccess(ctx context.Context, evt *events.PairSuccess) {
-	websocket.Broadcast <- websocket.BroadcastMessage{
-		Code:    "LOGIN_SUCCESS",
-		Message: fmt.Sprintf("Successfully pair with %s", evt.ID.String()),
-	}
+	PublishLoginEvent(ctx, domainApp.LoginEvent{
+		Event:    domainApp.LoginEventSuccess,
+		DeviceID: evt.ID.String(),
+	})
+	storeSessionDevice(ctx, evt.ID)
 	syncKeysDevice(ctx, db, keysDB)
 }
 
+func handlePairError(ctx context.Context, evt *events.PairError) {
+	PublishLoginEvent(ctx, domainApp.LoginEvent{
+		Event: domainApp.LoginEventError,
+		Error: evt.Error.Error(),
+	})
+}
+
 func handleLoggedOut(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository) {
 	logrus.Warn("[REMOTE_LOGOUT] Received LoggedOut event - user logged out from phone")
 
@@ -529,15 +552,16 @@ func handleLoggedOut(ctx context.Context, chatStorageRepo domainChatStorage.ICha
 	handleRemoteLogout(ctx, chatStorageRepo)
 
 	// Broadcast final notification that cleanup is complete and ready for new login
-	websocket.Broadcast <- websocket.BroadcastMessage{
+	websocket.Publish(websocket.BroadcastMessage{
 		Code:    "LOGOUT_COMPLETE",
 		Message: "Remote logout cleanup completed - ready for new login",
 		Result:  nil,
-	}
+	})
 }
 
-func handleConnectionEvents(_ context.Context) {
-	if len(cli.Store.PushName) == 0 {
+func handleConnectionEvents(ctx context.Context) {
+	cli := ClientFromContext(ctx)
+	if cli == nil || len(cli.Store.PushName) == 0 {
 		return
 	}
 
@@ -550,10 +574,6 @@ func handleConnectionEvents(_ context.Context) {
 	}
 }
 
-func handleStreamReplaced(_ context.Context) {
-	os.Exit(0)
-}
-
 func handleMessage(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
 	// Log message metadata
 	metaParts := buildMessageMetaParts(evt)
@@ -569,17 +589,26 @@ func handleMessage(ctx context.Context, evt *events.Message, chatStorageRepo dom
 		log.Errorf("Failed to store incoming message %s: %v", evt.Info.ID, err)
 	}
 
+	// Enforce group moderation rules if any are configured
+	handleModeration(ctx, evt)
+
 	// Handle image message if present
 	handleImageMessage(ctx, evt)
 
 	// Auto-mark message as read if configured
-	handleAutoMarkRead(ctx, evt)
+	handleAutoMarkRead(ctx, evt, chatStorageRepo)
+
+	// Block-listed senders are stored but do not trigger automations when --ignore-blocked is set
+	if isBlockedSender(ctx, evt, chatStorageRepo) {
+		log.Infof("Ignoring message %s from blocked contact %s", evt.Info.ID, evt.Info.Sender)
+		return
+	}
 
 	// Handle auto-reply if configured
 	handleAutoReply(ctx, evt, chatStorageRepo)
 
 	// Forward to webhook if configured
-	handleWebhookForward(ctx, evt)
+	handleWebhookForward(ctx, evt, chatStorageRepo)
 }
 
 func buildMessageMetaParts(evt *events.Message) []string {
@@ -601,7 +630,7 @@ func buildMessageMetaParts(evt *events.Message) []string {
 
 func handleImageMessage(ctx context.Context, evt *events.Message) {
 	if img := evt.Message.GetImageMessage(); img != nil {
-		if path, err := utils.ExtractMedia(ctx, cli, config.PathStorages, img); err != nil {
+		if path, err := utils.ExtractMedia(ctx, ClientFromContext(ctx), config.PathStorages, img); err != nil {
 			log.Errorf("Failed to download image: %v", err)
 		} else {
 			log.Infof("Image downloaded to %s", path)
@@ -609,9 +638,10 @@ func handleImageMessage(ctx context.Context, evt *events.Message) {
 	}
 }
 
-func handleAutoMarkRead(_ context.Context, evt *events.Message) {
+func handleAutoMarkRead(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
 	// Only mark read if auto-mark read is enabled and message is incoming
-	if !config.WhatsappAutoMarkRead || evt.Info.IsFromMe {
+	cli := ClientFromContext(ctx)
+	if !config.WhatsappAutoMarkRead || evt.Info.IsFromMe || cli == nil {
 		return
 	}
 
@@ -625,6 +655,9 @@ func handleAutoMarkRead(_ context.Context, evt *events.Message) {
 		log.Warnf("Failed to mark message %s as read: %v", evt.Info.ID, err)
 	} else {
 		log.Debugf("Marked message %s as read", evt.Info.ID)
+		if err := chatStorageRepo.MarkChatAsRead(ctx, chat.String(), evt.Info.Timestamp); err != nil {
+			log.Warnf("Failed to update unread count for %s: %v", chat, err)
+		}
 	}
 }
 
@@ -694,6 +727,11 @@ func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo d
 		return
 	}
 
+	cli := ClientFromContext(ctx)
+	if cli == nil {
+		return
+	}
+
 	// Format recipient JID
 	recipientJID := utils.FormatJID(evt.Info.Sender.String())
 
@@ -734,7 +772,7 @@ func handleAutoReply(ctx context.Context, evt *events.Message, chatStorageRepo d
 	}
 }
 
-func handleWebhookForward(ctx context.Context, evt *events.Message) {
+func handleWebhookForward(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository) {
 	// Skip webhook for specific protocol messages that shouldn't trigger webhooks
 	if protocolMessage := evt.Message.GetProtocolMessage(); protocolMessage != nil {
 		protocolType := protocolMessage.GetType().String()
@@ -745,22 +783,29 @@ func handleWebhookForward(ctx context.Context, evt *events.Message) {
 		}
 	}
 
-	if len(config.WhatsappWebhook) > 0 &&
+	if webhookSubscribed(domainWebhook.EventMessage) &&
 		!strings.Contains(evt.Info.SourceString(), "broadcast") {
 		go func(evt *events.Message) {
-			if err := forwardMessageToWebhook(ctx, evt); err != nil {
+			if err := forwardMessageToWebhook(ctx, evt, chatStorageRepo); err != nil {
 				logrus.Error("Failed forward to webhook: ", err)
 			}
 		}(evt)
 	}
 }
 
-func handleReceipt(ctx context.Context, evt *events.Receipt) {
+func handleReceipt(ctx context.Context, evt *events.Receipt, chatStorageRepo domainChatStorage.IChatStorageRepository) {
 	sendReceipt := false
 	switch evt.Type {
 	case types.ReceiptTypeRead, types.ReceiptTypeReadSelf:
 		sendReceipt = true
 		log.Infof("%v was read by %s at %s: %+v", evt.MessageIDs, evt.SourceString(), evt.Timestamp, evt)
+
+		// Our own read receipts (sent from another device) clear the unread counter up to the read time
+		if evt.Type == types.ReceiptTypeReadSelf {
+			if err := chatStorageRepo.MarkChatAsRead(ctx, evt.Chat.String(), evt.Timestamp); err != nil {
+				log.Warnf("Failed to update unread count for %s: %v", evt.Chat, err)
+			}
+		}
 	case types.ReceiptTypeDelivered:
 		sendReceipt = true
 		log.Infof("%s was delivered to %s at %s: %+v", evt.MessageIDs[0], evt.SourceString(), evt.Timestamp, evt)
@@ -768,7 +813,7 @@ func handleReceipt(ctx context.Context, evt *events.Receipt) {
 
 	// Forward receipt (ack) event to webhook if configured
 	// Note: Receipt events are not rate limited as they are critical for message delivery status
-	if len(config.WhatsappWebhook) > 0 && sendReceipt {
+	if webhookSubscribed(domainWebhook.EventReceipt) && sendReceipt {
 		go func(e *events.Receipt) {
 			if err := forwardReceiptToWebhook(ctx, e); err != nil {
 				logrus.Errorf("Failed to forward ack event to webhook: %v", err)
@@ -777,19 +822,12 @@ func handleReceipt(ctx context.Context, evt *events.Receipt) {
 	}
 }
 
-func handlePresence(_ context.Context, evt *events.Presence) {
-	if evt.Unavailable {
-		if evt.LastSeen.IsZero() {
-			log.Infof("%s is now offline", evt.From)
-		} else {
-			log.Infof("%s is now offline (last seen: %s)", evt.From, evt.LastSeen)
-		}
-	} else {
-		log.Infof("%s is now online", evt.From)
+func handleHistorySync(ctx context.Context, evt *events.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	cli := ClientFromContext(ctx)
+	if cli == nil {
+		return
 	}
-}
 
-func handleHistorySync(ctx context.Context, evt *events.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) {
 	id := atomic.AddInt32(&historySyncID, 1)
 	fileName := fmt.Sprintf("%s/history-%d-%s-%d-%s.json",
 		config.PathStorages,
@@ -851,7 +889,13 @@ func processHistorySync(ctx context.Context, data *waHistorySync.HistorySync, ch
 }
 
 // processConversationMessages processes and stores conversation messages from history sync
-func processConversationMessages(_ context.Context, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
+func processConversationMessages(ctx context.Context, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
+	cli := ClientFromContext(ctx)
+	if cli == nil {
+		return nil
+	}
+	sessionID := domainSession.FromContext(ctx)
+
 	conversations := data.GetConversations()
 	log.Infof("Processing %d conversations from history sync", len(conversations))
 
@@ -871,7 +915,7 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 		displayName := conv.GetDisplayName()
 
 		// Get or create chat
-		chatName := chatStorageRepo.GetChatNameWithPushName(jid, chatJID, "", displayName)
+		chatName := chatStorageRepo.GetChatNameWithPushName(ctx, jid, chatJID, "", displayName)
 
 		// Extract ephemeral expiration from conversation
 		ephemeralExpiration := conv.GetEphemeralExpiration()
@@ -967,6 +1011,7 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 				FileSHA256:    fileSHA256,
 				FileEncSHA256: fileEncSHA256,
 				FileLength:    fileLength,
+				SessionID:     sessionID,
 			}
 
 			messageBatch = append(messageBatch, message)
@@ -979,6 +1024,7 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 				Name:                chatName,
 				LastMessageTime:     latestTimestamp,
 				EphemeralExpiration: ephemeralExpiration,
+				SessionID:           sessionID,
 			}
 
 			// Store or update the chat
@@ -987,6 +1033,8 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 				continue
 			}
 
+			storeConversationState(ctx, conv, chatJID, chatStorageRepo)
+
 			// Store messages in batch
 			if err := chatStorageRepo.StoreMessagesBatch(messageBatch); err != nil {
 				log.Warnf("Failed to store messages batch for chat %s: %v", chatJID, err)
@@ -999,8 +1047,32 @@ func processConversationMessages(_ context.Context, data *waHistorySync.HistoryS
 	return nil
 }
 
+// storeConversationState stores unread count and archived/pinned/muted flags from a history sync conversation
+func storeConversationState(ctx context.Context, conv *waHistorySync.Conversation, chatJID string, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	unreadCount := int(conv.GetUnreadCount())
+	if unreadCount == 0 && conv.GetMarkedAsUnread() {
+		unreadCount = 1
+	}
+
+	if err := chatStorageRepo.SetChatUnreadCount(ctx, chatJID, unreadCount); err != nil {
+		log.Warnf("Failed to store unread count for %s: %v", chatJID, err)
+	}
+	if err := chatStorageRepo.SetChatArchived(ctx, chatJID, conv.GetArchived()); err != nil {
+		log.Warnf("Failed to store archive state for %s: %v", chatJID, err)
+	}
+	if err := chatStorageRepo.SetChatPinned(ctx, chatJID, conv.GetPinned() > 0); err != nil {
+		log.Warnf("Failed to store pin state for %s: %v", chatJID, err)
+	}
+
+	muteEnd := int64(conv.GetMuteEndTime())
+	muted := muteEnd != 0 && (muteEnd < 0 || muteEndTime(muteEnd).After(time.Now()))
+	if err := chatStorageRepo.SetChatMuted(ctx, chatJID, muted, muteEndTime(muteEnd)); err != nil {
+		log.Warnf("Failed to store mute state for %s: %v", chatJID, err)
+	}
+}
+
 // processPushNames processes push names from history sync to update chat names
-func processPushNames(_ context.Context, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
+func processPushNames(ctx context.Context, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
 	pushnames := data.GetPushnames()
 	log.Infof("Processing %d push names from history sync", len(pushnames))
 
@@ -1013,7 +1085,7 @@ func processPushNames(_ context.Context, data *waHistorySync.HistorySync, chatSt
 		}
 
 		// Check if chat exists
-		existingChat, err := chatStorageRepo.GetChat(jidStr)
+		existingChat, err := chatStorageRepo.GetChat(ctx, jidStr)
 		if err != nil || existingChat == nil {
 			// Chat doesn't exist yet, skip
 			continue
@@ -1033,7 +1105,16 @@ func processPushNames(_ context.Context, data *waHistorySync.HistorySync, chatSt
 	return nil
 }
 
-func handleGroupInfo(ctx context.Context, evt *events.GroupInfo) {
+func handleGroupInfo(ctx context.Context, evt *events.GroupInfo, chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	// Persist every change, including settings that are not forwarded to webhooks
+	if groupEvents := groupEventsFromInfo(ctx, evt); len(groupEvents) > 0 {
+		if err := chatStorageRepo.StoreGroupEvents(ctx, groupEvents); err != nil {
+			log.Errorf("Failed to store group events for %s: %v", evt.JID, err)
+		}
+		trackImportedJoins(ctx, evt, groupEvents, chatStorageRepo)
+		handleGreetings(ctx, evt, groupEvents)
+	}
+
 	// Only process events that have actual changes
 	hasChanges := len(evt.Join) > 0 || len(evt.Leave) > 0 || len(evt.Promote) > 0 || len(evt.Demote) > 0 ||
 		evt.Name != nil || evt.Topic != nil || evt.Locked != nil || evt.Announce != nil
@@ -1057,7 +1138,7 @@ func handleGroupInfo(ctx context.Context, evt *events.GroupInfo) {
 	}
 
 	// Forward group info event to webhook if configured
-	if len(config.WhatsappWebhook) > 0 {
+	if webhookSubscribed(domainWebhook.EventGroup) {
 		go func(e *events.GroupInfo) {
 			if err := forwardGroupInfoToWebhook(ctx, e); err != nil {
 				logrus.Errorf("Failed to forward group info event to webhook: %v", err)
@@ -0,0 +1,102 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
+)
+
+// loginSubscriber receives the login events of one session
+type loginSubscriber struct {
+	sessionID string
+	events    chan domainApp.LoginEvent
+}
+
+var (
+	loginSubscribersMu sync.Mutex
+	loginSubscribers   = make(map[*loginSubscriber]struct{})
+)
+
+// loginEventCodes are the websocket codes of the login events
+var loginEventCodes = map[string]string{
+	domainApp.LoginEventCode:    "LOGIN_QR",
+	domainApp.LoginEventTimeout: "LOGIN_TIMEOUT",
+	domainApp.LoginEventSuccess: "LOGIN_SUCCESS",
+	domainApp.LoginEventError:   "LOGIN_ERROR",
+}
+
+// SubscribeLoginEvents streams the login events of the session selected by ctx until the
+// returned function is called. The channel is closed when the application shuts down.
+func SubscribeLoginEvents(ctx context.Context) (<-chan domainApp.LoginEvent, func()) {
+	subscriber := &loginSubscriber{
+		sessionID: domainSession.FromContext(ctx),
+		events:    make(chan domainApp.LoginEvent, 16),
+	}
+
+	loginSubscribersMu.Lock()
+	loginSubscribers[subscriber] = struct{}{}
+	loginSubscribersMu.Unlock()
+
+	var once sync.Once
+	return subscriber.events, func() {
+		once.Do(func() {
+			loginSubscribersMu.Lock()
+			delete(loginSubscribers, subscriber)
+			loginSubscribersMu.Unlock()
+		})
+	}
+}
+
+// CloseLoginEvents ends every login event stream by closing its channel
+func CloseLoginEvents() {
+	loginSubscribersMu.Lock()
+	defer loginSubscribersMu.Unlock()
+
+	for subscriber := range loginSubscribers {
+		close(subscriber.events)
+		delete(loginSubscribers, subscriber)
+	}
+}
+
+// PublishLoginEvent sends a login step of the session selected by ctx to the websocket and to
+// the subscribers of that session. Slow subscribers miss events instead of blocking the login.
+func PublishLoginEvent(ctx context.Context, event domainApp.LoginEvent) {
+	event.SessionID = domainSession.FromContext(ctx)
+	event.Timestamp = time.Now()
+
+	loginSubscribersMu.Lock()
+	for subscriber := range loginSubscribers {
+		if subscriber.sessionID != event.SessionID {
+			continue
+		}
+		select {
+		case subscriber.events <- event:
+		default:
+		}
+	}
+	loginSubscribersMu.Unlock()
+
+	websocket.Publish(websocket.BroadcastMessage{
+		Code:    loginEventCodes[event.Event],
+		Message: loginEventMessage(event),
+		Result:  event,
+	})
+}
+
+func loginEventMessage(event domainApp.LoginEvent) string {
+	switch event.Event {
+	case domainApp.LoginEventCode:
+		return fmt.Sprintf("Scan the QR code within %d seconds", event.Timeout)
+	case domainApp.LoginEventTimeout:
+		return "QR code login timed out"
+	case domainApp.LoginEventSuccess:
+		return fmt.Sprintf("Successfully pair with %s", event.DeviceID)
+	default:
+		return fmt.Sprintf("Login failed: %s", event.Error)
+	}
+}
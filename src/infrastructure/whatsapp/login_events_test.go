@@ -0,0 +1,40 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishLoginEvent(t *testing.T) {
+	defaultEvents, unsubscribeDefault := SubscribeLoginEvents(context.Background())
+	defer unsubscribeDefault()
+
+	salesCtx := domainSession.NewContext(context.Background(), "sales")
+	salesEvents, unsubscribeSales := SubscribeLoginEvents(salesCtx)
+
+	PublishLoginEvent(salesCtx, domainApp.LoginEvent{Event: domainApp.LoginEventCode, Code: "2@abc", Timeout: 60})
+
+	select {
+	case event := <-salesEvents:
+		assert.Equal(t, "sales", event.SessionID)
+		assert.Equal(t, "2@abc", event.Code)
+		assert.False(t, event.Timestamp.IsZero())
+	default:
+		t.Fatal("subscriber of the session did not receive the event")
+	}
+	assert.Empty(t, defaultEvents, "events of other sessions are not delivered")
+
+	unsubscribeSales()
+	unsubscribeSales()
+	PublishLoginEvent(salesCtx, domainApp.LoginEvent{Event: domainApp.LoginEventTimeout})
+	assert.Empty(t, salesEvents, "unsubscribed subscribers receive nothing")
+
+	PublishLoginEvent(context.Background(), domainApp.LoginEvent{Event: domainApp.LoginEventSuccess, DeviceID: "628111111111:3@s.whatsapp.net"})
+	event := <-defaultEvents
+	assert.Equal(t, domainSession.DefaultSessionID, event.SessionID)
+	assert.Equal(t, "Successfully pair with 628111111111:3@s.whatsapp.net", loginEventMessage(event))
+}
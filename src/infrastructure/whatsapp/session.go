@@ -0,0 +1,360 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/store/sqlstore"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	waLog "go.mau.fi/whatsmeow/util/log"
+)
+
+// sessionClient is one account served by this process, with its own device in the shared store
+type sessionClient struct {
+	client    *whatsmeow.Client
+	createdAt time.Time
+}
+
+// The session manager holds one client per session. All devices live in the same store
+// container, the sessions table of the chat storage records which device belongs to which session.
+var (
+	sessionsMu      sync.RWMutex
+	sessions        = make(map[string]*sessionClient)
+	sessionsCtx     = context.Background()
+	sessionsStorage domainChatStorage.IChatStorageRepository
+)
+
+// loadSessions creates a client for every stored session, always including the default session,
+// and returns the client of the default session
+func loadSessions(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository) (*whatsmeow.Client, error) {
+	records, err := chatStorageRepo.GetSessions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sessions: %w", err)
+	}
+
+	claimed := make(map[string]bool)
+	hasDefault := false
+	for _, record := range records {
+		if record.DeviceJID != "" {
+			claimed[record.DeviceJID] = true
+		}
+		hasDefault = hasDefault || record.ID == domainSession.DefaultSessionID
+	}
+	if !hasDefault {
+		records = append([]*domainChatStorage.Session{{ID: domainSession.DefaultSessionID, CreatedAt: time.Now()}}, records...)
+	}
+
+	loaded := make(map[string]*sessionClient, len(records))
+	for _, record := range records {
+		device, err := sessionDevice(ctx, record, claimed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load device of session %s: %w", record.ID, err)
+		}
+
+		record.DeviceJID = ""
+		if device.ID != nil {
+			record.DeviceJID = device.ID.String()
+			claimed[record.DeviceJID] = true
+		}
+		if err := chatStorageRepo.StoreSession(record); err != nil {
+			return nil, err
+		}
+
+		loaded[record.ID] = &sessionClient{
+			client:    newSessionClient(ctx, record.ID, device, chatStorageRepo),
+			createdAt: record.CreatedAt,
+		}
+	}
+
+	sessionsMu.Lock()
+	sessions = loaded
+	sessionsCtx = ctx
+	sessionsStorage = chatStorageRepo
+	sessionsMu.Unlock()
+
+	return loaded[domainSession.DefaultSessionID].client, nil
+}
+
+// sessionDevice returns the stored device of a session, or a new one when it is not paired.
+// The default session takes the first device no other session claims, which is the device
+// paired before the process served more than one account.
+func sessionDevice(ctx context.Context, record *domainChatStorage.Session, claimed map[string]bool) (*store.Device, error) {
+	if record.DeviceJID != "" {
+		if jid, err := types.ParseJID(record.DeviceJID); err == nil {
+			device, err := db.GetDevice(ctx, jid)
+			if err != nil {
+				return nil, err
+			}
+			if device != nil {
+				return device, nil
+			}
+		}
+		delete(claimed, record.DeviceJID)
+	}
+
+	if record.ID == domainSession.DefaultSessionID {
+		devices, err := db.GetAllDevices(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, device := range devices {
+			if !claimed[device.ID.String()] {
+				return device, nil
+			}
+		}
+	}
+
+	return db.NewDevice(), nil
+}
+
+// newSessionClient creates the client of a session. Its events are handled with a context
+// that selects the session, so handlers act on the account that received the event.
+func newSessionClient(ctx context.Context, sessionID string, device *store.Device, chatStorageRepo domainChatStorage.IChatStorageRepository) *whatsmeow.Client {
+	// Configure a separated database for accelerating encryption caching
+	if keysDB != nil && device.ID != nil {
+		innerStore := sqlstore.NewSQLStore(keysDB, *device.ID)
+
+		syncKeysDevice(ctx, db, keysDB)
+		device.Identities = innerStore
+		device.Sessions = innerStore
+		device.PreKeys = innerStore
+		device.SenderKeys = innerStore
+		device.MsgSecrets = innerStore
+		device.PrivacyTokens = innerStore
+	}
+
+	device.LIDs = newMappingLIDStore(device.LIDs, chatStorageRepo)
+
+	clientLog := waLog.Stdout("Client", config.WhatsappLogLevel, true)
+	if sessionID != domainSession.DefaultSessionID {
+		clientLog = clientLog.Sub(sessionID)
+	}
+
+	client := whatsmeow.NewClient(device, clientLog)
+	// Dropped connections are reconnected by the connection supervisor
+	client.EnableAutoReconnect = false
+	client.AutoTrustIdentity = true
+	setClientProxy(client)
+
+	sessionCtx := domainSession.NewContext(ctx, sessionID)
+	var mergedLIDChats sync.Once
+	client.AddEventHandler(func(rawEvt interface{}) {
+		// Chats stored under a LID before this process learned the mapping are merged on the
+		// first connect, later mappings trigger their own merge
+		if _, ok := rawEvt.(*events.Connected); ok {
+			mergedLIDChats.Do(func() { lidChatMerges.request(chatStorageRepo) })
+		}
+		handler(sessionCtx, rawEvt, chatStorageRepo)
+	})
+
+	return client
+}
+
+// setClientProxy routes the websocket and the media transfers of a client through their configured
+// proxies. Without a configured proxy whatsmeow keeps using the proxy from the environment.
+func setClientProxy(client *whatsmeow.Client) {
+	if utils.ProxyAddress(utils.ProxyWhatsapp) != "" {
+		client.SetProxy(utils.ProxyFunc(utils.ProxyWhatsapp), whatsmeow.SetProxyOptions{NoMedia: true})
+	}
+	if utils.ProxyAddress(utils.ProxyMedia) != "" {
+		client.SetProxy(utils.ProxyFunc(utils.ProxyMedia), whatsmeow.SetProxyOptions{NoWebsocket: true})
+	}
+}
+
+// ClientFromContext returns the client of the session selected by ctx, nil when the session does not exist
+func ClientFromContext(ctx context.Context) *whatsmeow.Client {
+	sessionsMu.RLock()
+	defer sessionsMu.RUnlock()
+
+	if session, ok := sessions[domainSession.FromContext(ctx)]; ok {
+		return session.client
+	}
+	return nil
+}
+
+// HasSession reports whether a session exists
+func HasSession(sessionID string) bool {
+	sessionsMu.RLock()
+	defer sessionsMu.RUnlock()
+
+	_, ok := sessions[sessionID]
+	return ok
+}
+
+// SessionIDs returns the IDs of all sessions, the default session first
+func SessionIDs() []string {
+	sessionsMu.RLock()
+	defer sessionsMu.RUnlock()
+
+	ids := make([]string, 0, len(sessions))
+	for id := range sessions {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if (ids[i] == domainSession.DefaultSessionID) != (ids[j] == domainSession.DefaultSessionID) {
+			return ids[i] == domainSession.DefaultSessionID
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// GetSession describes a session, ok is false when it does not exist
+func GetSession(sessionID string) (response domainSession.Session, ok bool) {
+	sessionsMu.RLock()
+	session, ok := sessions[sessionID]
+	sessionsMu.RUnlock()
+	if !ok {
+		return response, false
+	}
+
+	response.ID = sessionID
+	response.CreatedAt = session.createdAt
+	response.IsConnected = session.client.IsConnected()
+	response.IsLoggedIn = session.client.IsLoggedIn()
+	if session.client.Store != nil && session.client.Store.ID != nil {
+		response.DeviceID = session.client.Store.ID.String()
+		response.PushName = session.client.Store.PushName
+	}
+	return response, true
+}
+
+// AddSession creates an unpaired session. It is paired by logging in with the session selected.
+func AddSession(sessionID string) (domainSession.Session, error) {
+	sessionsMu.Lock()
+	if _, ok := sessions[sessionID]; ok {
+		sessionsMu.Unlock()
+		return domainSession.Session{}, pkgError.ValidationError(fmt.Sprintf("session %s already exists", sessionID))
+	}
+
+	record := &domainChatStorage.Session{ID: sessionID, CreatedAt: time.Now()}
+	if err := sessionsStorage.StoreSession(record); err != nil {
+		sessionsMu.Unlock()
+		return domainSession.Session{}, err
+	}
+
+	sessions[sessionID] = &sessionClient{
+		client:    newSessionClient(sessionsCtx, sessionID, db.NewDevice(), sessionsStorage),
+		createdAt: record.CreatedAt,
+	}
+	sessionsMu.Unlock()
+
+	logrus.Infof("[SESSION] Added session %s", sessionID)
+	response, _ := GetSession(sessionID)
+	return response, nil
+}
+
+// RemoveSession logs out the device of a session and removes the session with its chats and messages.
+// The default session cannot be removed, it is logged out instead.
+func RemoveSession(ctx context.Context, sessionID string) error {
+	if sessionID == domainSession.DefaultSessionID {
+		return pkgError.ValidationError("the default session cannot be removed, log it out instead")
+	}
+
+	sessionsMu.Lock()
+	session, ok := sessions[sessionID]
+	if !ok {
+		sessionsMu.Unlock()
+		return pkgError.ValidationError(fmt.Sprintf("session %s not found", sessionID))
+	}
+	delete(sessions, sessionID)
+	sessionsMu.Unlock()
+
+	stopConnectionSupervisor(sessionID)
+	unpairSessionDevice(ctx, sessionID, session.client)
+
+	if err := sessionsStorage.DeleteSession(sessionID); err != nil {
+		return err
+	}
+
+	logrus.Infof("[SESSION] Removed session %s", sessionID)
+	return nil
+}
+
+// CleanupSession clears the account of the session selected by ctx after it logged out. While the
+// default session is the only one, the whole WhatsApp database is cleaned up as before sessions
+// existed. Otherwise only the device and rows of the session are removed and it gets a new device
+// ready for the next login.
+func CleanupSession(ctx context.Context, logPrefix string, chatStorageRepo domainChatStorage.IChatStorageRepository) error {
+	sessionID := domainSession.FromContext(ctx)
+	if sessionID == domainSession.DefaultSessionID && len(SessionIDs()) == 1 {
+		_, _, err := PerformCleanupAndUpdateGlobals(ctx, logPrefix, chatStorageRepo)
+		return err
+	}
+
+	sessionsMu.RLock()
+	session, ok := sessions[sessionID]
+	sessionsMu.RUnlock()
+	if !ok {
+		return pkgError.ValidationError(fmt.Sprintf("session %s not found", sessionID))
+	}
+
+	logrus.Infof("[%s] Cleaning up session %s", logPrefix, sessionID)
+	unpairSessionDevice(ctx, sessionID, session.client)
+
+	if err := chatStorageRepo.DeleteSession(sessionID); err != nil {
+		logrus.Errorf("[%s] Failed to delete chatstorage data of session %s: %v", logPrefix, sessionID, err)
+	}
+	record := &domainChatStorage.Session{ID: sessionID, CreatedAt: session.createdAt}
+	if err := chatStorageRepo.StoreSession(record); err != nil {
+		return err
+	}
+
+	sessionsMu.Lock()
+	sessions[sessionID] = &sessionClient{
+		client:    newSessionClient(sessionsCtx, sessionID, db.NewDevice(), chatStorageRepo),
+		createdAt: session.createdAt,
+	}
+	sessionsMu.Unlock()
+
+	logrus.Infof("[%s] Session %s is ready for next login", logPrefix, sessionID)
+	return nil
+}
+
+// unpairSessionDevice disconnects a client and deletes its device from the store, logging out
+// from WhatsApp first when the device is still paired
+func unpairSessionDevice(ctx context.Context, sessionID string, client *whatsmeow.Client) {
+	if client.Store.ID != nil && client.IsLoggedIn() {
+		if err := client.Logout(ctx); err != nil {
+			logrus.Warnf("[SESSION] Failed to log out session %s: %v", sessionID, err)
+		}
+	}
+	client.Disconnect()
+
+	if client.Store.ID != nil {
+		if err := db.DeleteDevice(ctx, client.Store); err != nil {
+			logrus.Errorf("[SESSION] Failed to delete device of session %s: %v", sessionID, err)
+		}
+	}
+	syncKeysDevice(ctx, db, keysDB)
+}
+
+// storeSessionDevice records the device a session was paired with
+func storeSessionDevice(ctx context.Context, deviceID types.JID) {
+	sessionID := domainSession.FromContext(ctx)
+
+	sessionsMu.RLock()
+	session, ok := sessions[sessionID]
+	storage := sessionsStorage
+	sessionsMu.RUnlock()
+	if !ok || storage == nil {
+		return
+	}
+
+	record := &domainChatStorage.Session{ID: sessionID, DeviceJID: deviceID.String(), CreatedAt: session.createdAt}
+	if err := storage.StoreSession(record); err != nil {
+		logrus.Errorf("[SESSION] Failed to store device of session %s: %v", sessionID, err)
+	}
+}
@@ -0,0 +1,60 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/lifecycle"
+)
+
+var (
+	// shuttingDown stops the connection supervisors from reconnecting clients that were disconnected on purpose
+	shuttingDown atomic.Bool
+	// webhookDeliveries counts the webhook deliveries in flight, including their retries
+	webhookDeliveries lifecycle.InFlight
+)
+
+// DisconnectSessions cancels pending reconnects and disconnects the client of every session
+func DisconnectSessions(_ context.Context) error {
+	shuttingDown.Store(true)
+
+	supervisorsMu.Lock()
+	for _, supervisor := range supervisors {
+		supervisor.cancel()
+	}
+	supervisorsMu.Unlock()
+
+	sessionsMu.RLock()
+	clients := make([]*sessionClient, 0, len(sessions))
+	for _, session := range sessions {
+		clients = append(clients, session)
+	}
+	sessionsMu.RUnlock()
+
+	for _, session := range clients {
+		session.client.Disconnect()
+	}
+	return nil
+}
+
+// DrainWebhooks stops the webhook outbox and waits until the webhook deliveries in flight finished
+// or ctx is done. Queued deliveries are sent after the next start.
+func DrainWebhooks(ctx context.Context) error {
+	if err := stopWebhookOutbox(ctx); err != nil {
+		return err
+	}
+	return webhookDeliveries.Wait(ctx)
+}
+
+// CloseStores closes the databases of the WhatsApp devices and keys
+func CloseStores() error {
+	var errs []error
+	if db != nil {
+		errs = append(errs, db.Close())
+	}
+	if keysDB != nil && keysDB != db {
+		errs = append(errs, keysDB.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -10,48 +10,28 @@ import (
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 )
 
+// submitWebhook delivers a payload to one URL directly, retrying in process. It is used when the
+// webhook outbox is not running.
 func submitWebhook(ctx context.Context, payload map[string]any, url string) error {
-	client := &http.Client{Timeout: 10 * time.Second}
-
-	postBody, err := json.Marshal(payload)
+	postBody, err := json.Marshal(withSession(ctx, payload))
 	if err != nil {
 		return pkgError.WebhookError(fmt.Sprintf("Failed to marshal body: %v", err))
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
-	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("error when create http object %v", err))
-	}
-
-	secretKey := []byte(config.WhatsappWebhookSecret)
-	signature, err := utils.GetMessageDigestOrSignature(postBody, secretKey)
-	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("error when create signature %v", err))
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Hub-Signature-256", fmt.Sprintf("sha256=%s", signature))
-
 	var attempt int
 	var maxAttempts = 5
 	var sleepDuration = 1 * time.Second
 
 	for attempt = 0; attempt < maxAttempts; attempt++ {
-		// Create new request body for each attempt
-		req.Body = io.NopCloser(bytes.NewBuffer(postBody))
-		resp, err := client.Do(req)
-		if err == nil {
-			defer resp.Body.Close()
-			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-				logrus.Infof("Successfully submitted webhook on attempt %d", attempt+1)
-				return nil
-			}
-			err = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+		if _, err = postWebhook(ctx, postBody, url); err == nil {
+			logrus.Infof("Successfully submitted webhook on attempt %d", attempt+1)
+			return nil
 		}
 		logrus.Warnf("Attempt %d to submit webhook failed: %v", attempt+1, err)
 		if attempt < maxAttempts-1 {
@@ -62,3 +42,50 @@ func submitWebhook(ctx context.Context, payload map[string]any, url string) erro
 
 	return pkgError.WebhookError(fmt.Sprintf("error when submit webhook after %d attempts: %v", attempt, err))
 }
+
+// postWebhook makes one signed delivery attempt of a JSON body. The status code is 0 when no
+// response was received.
+func postWebhook(ctx context.Context, body []byte, url string) (int, error) {
+	client := &http.Client{Transport: utils.ProxyTransport(utils.ProxyWebhook), Timeout: 10 * time.Second}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, pkgError.WebhookError(fmt.Sprintf("error when create http object %v", err))
+	}
+
+	secretKey := []byte(config.WhatsappWebhookSecret)
+	signature, err := utils.GetMessageDigestOrSignature(body, secretKey)
+	if err != nil {
+		return 0, pkgError.WebhookError(fmt.Sprintf("error when create signature %v", err))
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Hub-Signature-256", fmt.Sprintf("sha256=%s", signature))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// withSession returns a copy of the payload tagged with the session that produced the event and
+// the device JID of that session
+func withSession(ctx context.Context, payload map[string]any) map[string]any {
+	tagged := make(map[string]any, len(payload)+2)
+	for key, value := range payload {
+		tagged[key] = value
+	}
+
+	tagged["session_id"] = domainSession.FromContext(ctx)
+	if cli := ClientFromContext(ctx); cli != nil && cli.Store != nil && cli.Store.ID != nil {
+		tagged["device_id"] = cli.Store.ID.String()
+	}
+	return tagged
+}
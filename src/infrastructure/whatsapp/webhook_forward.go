@@ -5,18 +5,22 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/sirupsen/logrus"
 )
 
 var submitWebhookFn = submitWebhook
 
-// forwardPayloadToConfiguredWebhooks attempts to deliver the provided payload to every configured webhook URL.
+// forwardPayloadToConfiguredWebhooks queues the provided payload in the webhook outbox for every webhook URL
+// the event is routed to, see webhookTargets. Without a running outbox it attempts to deliver the payload directly.
 // It only returns an error when all webhook deliveries fail. Partial failures are logged and suppressed so
 // successful targets still receive the event.
-func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]any, eventName string) error {
-	total := len(config.WhatsappWebhook)
+func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]any, eventName string, event domainWebhook.Event) error {
+	defer webhookDeliveries.Start()()
+
+	targets := webhookTargets(event)
+	total := len(targets)
 	logrus.Infof("Forwarding %s to %d configured webhook(s)", eventName, total)
 
 	if total == 0 {
@@ -24,11 +28,15 @@ func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]
 		return nil
 	}
 
+	if o := currentOutbox(); o != nil {
+		return o.enqueue(ctx, payload, eventName, targets)
+	}
+
 	var (
 		failed    []string
 		successes int
 	)
-	for _, url := range config.WhatsappWebhook {
+	for _, url := range targets {
 		if err := submitWebhookFn(ctx, payload, url); err != nil {
 			failed = append(failed, fmt.Sprintf("%s: %v", url, err))
 			logrus.Warnf("Failed forwarding %s to %s: %v", eventName, url, err)
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
 )
 
 func TestForwardPayloadToConfiguredWebhooks_NoWebhooksConfigured(t *testing.T) {
@@ -24,7 +25,7 @@ func TestForwardPayloadToConfiguredWebhooks_NoWebhooksConfigured(t *testing.T) {
 	}
 	defer func() { submitWebhookFn = originalSubmit }()
 
-	if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "test"); err != nil {
+	if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "test", domainWebhook.Event{Type: domainWebhook.EventMessage}); err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
 }
@@ -48,7 +49,7 @@ func TestForwardPayloadToConfiguredWebhooks_PartialFailure(t *testing.T) {
 	}
 	defer func() { submitWebhookFn = originalSubmit }()
 
-	if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "test"); err != nil {
+	if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "test", domainWebhook.Event{Type: domainWebhook.EventMessage}); err != nil {
 		t.Fatalf("expected partial failure to return nil, got %v", err)
 	}
 
@@ -71,7 +72,61 @@ func TestForwardPayloadToConfiguredWebhooks_AllFail(t *testing.T) {
 	}
 	defer func() { submitWebhookFn = originalSubmit }()
 
-	if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "test"); err == nil {
+	if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "test", domainWebhook.Event{Type: domainWebhook.EventMessage}); err == nil {
 		t.Fatalf("expected error when all webhooks fail")
 	}
 }
+
+// stubWebhookRouter routes message events to its URL
+type stubWebhookRouter struct{ url string }
+
+func (r stubWebhookRouter) WebhookTargets(event domainWebhook.Event) []string {
+	if event.Type == domainWebhook.EventMessage {
+		return []string{r.url, "https://legacy"}
+	}
+	return nil
+}
+
+func (r stubWebhookRouter) WebhookSubscribed(eventType string) bool {
+	return eventType == domainWebhook.EventMessage
+}
+
+func TestForwardPayloadToConfiguredWebhooks_RoutesToDefinitions(t *testing.T) {
+	ctx := context.Background()
+	payload := map[string]any{"foo": "bar"}
+
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = []string{"https://legacy"}
+	defer func() { config.WhatsappWebhook = originalWebhooks }()
+
+	SetWebhookRouter(stubWebhookRouter{url: "https://crm"})
+	defer SetWebhookRouter(nil)
+
+	originalSubmit := submitWebhookFn
+	var attempts []string
+	submitWebhookFn = func(_ context.Context, _ map[string]any, url string) error {
+		attempts = append(attempts, url)
+		return nil
+	}
+	defer func() { submitWebhookFn = originalSubmit }()
+
+	if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "test", domainWebhook.Event{Type: domainWebhook.EventMessage}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Join(attempts, ",") != "https://legacy,https://crm" {
+		t.Fatalf("expected the legacy URL and the definition once each, got %v", attempts)
+	}
+
+	attempts = nil
+	if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "test", domainWebhook.Event{Type: domainWebhook.EventReceipt}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Join(attempts, ",") != "https://legacy" {
+		t.Fatalf("expected only the legacy URL for unsubscribed events, got %v", attempts)
+	}
+
+	config.WhatsappWebhook = nil
+	if !webhookSubscribed(domainWebhook.EventMessage) || webhookSubscribed(domainWebhook.EventReceipt) {
+		t.Fatalf("expected only message events to be subscribed without legacy URLs")
+	}
+}
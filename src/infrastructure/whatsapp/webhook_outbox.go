@@ -0,0 +1,258 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// webhookOutboxBatchSize is the number of due deliveries loaded at once
+	webhookOutboxBatchSize = 50
+	// webhookOutboxWorkers is the number of deliveries attempted in parallel
+	webhookOutboxWorkers = 8
+	// webhookOutboxPollInterval is how often due retries are looked for without a wake up
+	webhookOutboxPollInterval = 5 * time.Second
+	// webhookOutboxCleanupInterval is how often expired deliveries are removed
+	webhookOutboxCleanupInterval = time.Hour
+	// webhookRetryBaseDelay is the delay before the second attempt, it doubles with every failed attempt
+	webhookRetryBaseDelay = 10 * time.Second
+	// webhookRetryMaxDelay caps the delay between attempts
+	webhookRetryMaxDelay = time.Hour
+)
+
+var postWebhookFn = postWebhook
+
+// webhookOutbox stores every webhook event in the chat storage before it is delivered, so failed
+// deliveries are retried with backoff across restarts until they succeed or are dead-lettered
+type webhookOutbox struct {
+	repo    domainChatStorage.IChatStorageRepository
+	wake    chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+var (
+	outboxMu sync.RWMutex
+	outbox   *webhookOutbox
+)
+
+// StartWebhookOutbox queues webhook events in the chat storage and starts delivering them.
+// Deliveries left pending by a previous run are sent right away.
+func StartWebhookOutbox(repo domainChatStorage.IChatStorageRepository) {
+	o := &webhookOutbox{
+		repo:    repo,
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	outboxMu.Lock()
+	outbox = o
+	outboxMu.Unlock()
+
+	go o.run()
+}
+
+// WakeWebhookOutbox makes the outbox look for due deliveries right away, e.g. after a replay
+func WakeWebhookOutbox() {
+	if o := currentOutbox(); o != nil {
+		o.notify()
+	}
+}
+
+// stopWebhookOutbox stops picking up deliveries and waits for the attempts in flight. Pending
+// deliveries stay queued for the next start.
+func stopWebhookOutbox(ctx context.Context) error {
+	outboxMu.Lock()
+	o := outbox
+	outbox = nil
+	outboxMu.Unlock()
+	if o == nil {
+		return nil
+	}
+
+	close(o.stop)
+	select {
+	case <-o.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func currentOutbox() *webhookOutbox {
+	outboxMu.RLock()
+	defer outboxMu.RUnlock()
+
+	return outbox
+}
+
+func (o *webhookOutbox) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// enqueue stores one delivery of the payload per webhook URL
+func (o *webhookOutbox) enqueue(ctx context.Context, payload map[string]any, eventName string, urls []string) error {
+	body, err := json.Marshal(withSession(ctx, payload))
+	if err != nil {
+		return pkgError.WebhookError(fmt.Sprintf("Failed to marshal body: %v", err))
+	}
+
+	event, _ := payload["event"].(string)
+	if event == "" {
+		event = strings.TrimSuffix(eventName, " event")
+	}
+
+	deliveries := make([]*domainChatStorage.WebhookDelivery, 0, len(urls))
+	for _, url := range urls {
+		deliveries = append(deliveries, &domainChatStorage.WebhookDelivery{
+			SessionID:   domainSession.FromContext(ctx),
+			Event:       event,
+			URL:         url,
+			Payload:     string(body),
+			MaxAttempts: max(config.WhatsappWebhookMaxAttempts, 1),
+		})
+	}
+	if err := o.repo.StoreWebhookDeliveries(deliveries); err != nil {
+		return pkgError.WebhookError(fmt.Sprintf("failed to queue %s: %v", eventName, err))
+	}
+
+	logrus.Infof("Queued %s for %d webhook(s)", eventName, len(deliveries))
+	o.notify()
+	return nil
+}
+
+func (o *webhookOutbox) run() {
+	defer close(o.stopped)
+
+	poll := time.NewTicker(webhookOutboxPollInterval)
+	defer poll.Stop()
+	cleanup := time.NewTicker(webhookOutboxCleanupInterval)
+	defer cleanup.Stop()
+
+	o.cleanup()
+	for {
+		o.deliverDue()
+
+		select {
+		case <-o.stop:
+			return
+		case <-o.wake:
+		case <-poll.C:
+		case <-cleanup.C:
+			o.cleanup()
+		}
+	}
+}
+
+// deliverDue attempts every due delivery, a batch at a time
+func (o *webhookOutbox) deliverDue() {
+	for {
+		select {
+		case <-o.stop:
+			return
+		default:
+		}
+
+		deliveries, err := o.repo.GetDueWebhookDeliveries(time.Now(), webhookOutboxBatchSize)
+		if err != nil {
+			logrus.Errorf("[WEBHOOK] Failed to load due deliveries: %v", err)
+			return
+		}
+
+		var wg sync.WaitGroup
+		workers := make(chan struct{}, webhookOutboxWorkers)
+		for _, delivery := range deliveries {
+			wg.Add(1)
+			workers <- struct{}{}
+			go func() {
+				defer func() {
+					<-workers
+					wg.Done()
+				}()
+				o.attempt(delivery)
+			}()
+		}
+		wg.Wait()
+
+		if len(deliveries) < webhookOutboxBatchSize {
+			return
+		}
+	}
+}
+
+// attempt makes one delivery attempt and records its outcome
+func (o *webhookOutbox) attempt(delivery *domainChatStorage.WebhookDelivery) {
+	defer webhookDeliveries.Start()()
+
+	started := time.Now()
+	statusCode, err := postWebhookFn(context.Background(), []byte(delivery.Payload), delivery.URL)
+	finished := time.Now()
+
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = ""
+	attempt := &domainChatStorage.WebhookDeliveryAttempt{
+		Attempt:     delivery.Attempts,
+		StatusCode:  statusCode,
+		DurationMs:  finished.Sub(started).Milliseconds(),
+		AttemptedAt: started,
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = domainChatStorage.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &finished
+		logrus.Infof("[WEBHOOK] Delivered %s #%d to %s on attempt %d", delivery.Event, delivery.ID, delivery.URL, delivery.Attempts)
+	case delivery.Attempts >= delivery.MaxAttempts:
+		attempt.Error, delivery.LastError = err.Error(), err.Error()
+		delivery.Status = domainChatStorage.WebhookDeliveryDead
+		logrus.Errorf("[WEBHOOK] Gave up on %s #%d to %s after %d attempts: %v", delivery.Event, delivery.ID, delivery.URL, delivery.Attempts, err)
+	default:
+		attempt.Error, delivery.LastError = err.Error(), err.Error()
+		delivery.NextAttemptAt = finished.Add(webhookRetryDelay(delivery.Attempts))
+		logrus.Warnf("[WEBHOOK] Attempt %d of %s #%d to %s failed, retrying at %s: %v",
+			delivery.Attempts, delivery.Event, delivery.ID, delivery.URL, delivery.NextAttemptAt.Format(time.RFC3339), err)
+	}
+
+	if err := o.repo.RecordWebhookDeliveryAttempt(delivery, attempt); err != nil {
+		logrus.Errorf("[WEBHOOK] Failed to record attempt of delivery #%d: %v", delivery.ID, err)
+	}
+}
+
+// cleanup removes delivered and dead deliveries past the retention
+func (o *webhookOutbox) cleanup() {
+	if config.WhatsappWebhookRetention <= 0 {
+		return
+	}
+
+	deleted, err := o.repo.DeleteWebhookDeliveriesBefore(time.Now().Add(-config.WhatsappWebhookRetention))
+	if err != nil {
+		logrus.Errorf("[WEBHOOK] Failed to remove expired deliveries: %v", err)
+		return
+	}
+	if deleted > 0 {
+		logrus.Infof("[WEBHOOK] Removed %d expired deliveries", deleted)
+	}
+}
+
+// webhookRetryDelay returns the delay after the given number of failed attempts
+func webhookRetryDelay(attempts int) time.Duration {
+	if attempts > 20 {
+		return webhookRetryMaxDelay
+	}
+	return min(webhookRetryBaseDelay<<(max(attempts, 1)-1), webhookRetryMaxDelay)
+}
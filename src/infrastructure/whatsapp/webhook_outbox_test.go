@@ -0,0 +1,144 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestOutbox creates an outbox on a fresh chat storage without starting its worker
+func newTestOutbox(t *testing.T, webhooks []string, maxAttempts int) *webhookOutbox {
+	t.Helper()
+
+	repo := newTestChatStorage(t)
+
+	oldWebhooks, oldMaxAttempts := config.WhatsappWebhook, config.WhatsappWebhookMaxAttempts
+	config.WhatsappWebhook, config.WhatsappWebhookMaxAttempts = webhooks, maxAttempts
+	t.Cleanup(func() {
+		config.WhatsappWebhook, config.WhatsappWebhookMaxAttempts = oldWebhooks, oldMaxAttempts
+	})
+
+	return &webhookOutbox{repo: repo, wake: make(chan struct{}, 1), stop: make(chan struct{})}
+}
+
+// stubPostWebhook answers every attempt with the status code and error returned by respond
+func stubPostWebhook(t *testing.T, respond func(url string) (int, error)) {
+	original := postWebhookFn
+	postWebhookFn = func(_ context.Context, _ []byte, url string) (int, error) {
+		return respond(url)
+	}
+	t.Cleanup(func() { postWebhookFn = original })
+}
+
+// makeDue moves the next attempt of every pending delivery into the past
+func makeDue(t *testing.T, o *webhookOutbox) {
+	t.Helper()
+
+	deliveries, err := o.repo.GetWebhookDeliveries(&domainChatStorage.WebhookDeliveryFilter{Status: domainChatStorage.WebhookDeliveryPending})
+	require.NoError(t, err)
+	for _, delivery := range deliveries {
+		_, err := o.repo.ReplayWebhookDelivery(delivery.ID, delivery.MaxAttempts-delivery.Attempts, time.Now().Add(-time.Second))
+		require.NoError(t, err)
+	}
+}
+
+func TestWebhookOutboxDeliversEveryWebhook(t *testing.T) {
+	o := newTestOutbox(t, []string{"https://one", "https://two"}, 3)
+	stubPostWebhook(t, func(string) (int, error) { return 200, nil })
+
+	require.NoError(t, o.enqueue(context.Background(), map[string]any{"event": "message"}, "message event", config.WhatsappWebhook))
+	o.deliverDue()
+
+	deliveries, err := o.repo.GetWebhookDeliveries(&domainChatStorage.WebhookDeliveryFilter{})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	for _, delivery := range deliveries {
+		assert.Equal(t, domainChatStorage.WebhookDeliveryDelivered, delivery.Status)
+		assert.Equal(t, "message", delivery.Event)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, 200, delivery.LastStatusCode)
+		assert.NotNil(t, delivery.DeliveredAt)
+	}
+}
+
+func TestWebhookOutboxRetriesUntilDead(t *testing.T) {
+	o := newTestOutbox(t, []string{"https://down"}, 2)
+	stubPostWebhook(t, func(string) (int, error) { return 503, errors.New("status 503") })
+
+	require.NoError(t, o.enqueue(context.Background(), map[string]any{"event": "message"}, "message event", config.WhatsappWebhook))
+	o.deliverDue()
+
+	deliveries, err := o.repo.GetWebhookDeliveries(&domainChatStorage.WebhookDeliveryFilter{})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	delivery := deliveries[0]
+	assert.Equal(t, domainChatStorage.WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.True(t, delivery.NextAttemptAt.After(time.Now()), "the retry is scheduled with a backoff")
+
+	o.deliverDue()
+	delivery, err = o.repo.GetWebhookDelivery(delivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivery.Attempts, "a retry is not attempted before it is due")
+
+	makeDue(t, o)
+	o.deliverDue()
+	delivery, err = o.repo.GetWebhookDelivery(delivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domainChatStorage.WebhookDeliveryDead, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, "status 503", delivery.LastError)
+
+	attempts, err := o.repo.GetWebhookDeliveryAttempts([]int64{delivery.ID})
+	require.NoError(t, err)
+	require.Len(t, attempts[delivery.ID], 2)
+	assert.Equal(t, 503, attempts[delivery.ID][1].StatusCode)
+}
+
+func TestWebhookOutboxReplayDeadDelivery(t *testing.T) {
+	o := newTestOutbox(t, []string{"https://flaky"}, 1)
+	up := false
+	stubPostWebhook(t, func(string) (int, error) {
+		if up {
+			return 204, nil
+		}
+		return 0, errors.New("connection refused")
+	})
+
+	require.NoError(t, o.enqueue(context.Background(), map[string]any{"event": "message"}, "message event", config.WhatsappWebhook))
+	o.deliverDue()
+
+	deliveries, err := o.repo.GetWebhookDeliveries(&domainChatStorage.WebhookDeliveryFilter{Status: domainChatStorage.WebhookDeliveryDead})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+
+	up = true
+	replayed, err := o.repo.ReplayWebhookDelivery(deliveries[0].ID, 1, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, domainChatStorage.WebhookDeliveryPending, replayed.Status)
+	assert.Equal(t, 2, replayed.MaxAttempts)
+
+	o.deliverDue()
+	delivery, err := o.repo.GetWebhookDelivery(replayed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domainChatStorage.WebhookDeliveryDelivered, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+
+	missing, err := o.repo.ReplayWebhookDelivery(delivery.ID+1, 1, time.Now())
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, webhookRetryBaseDelay, webhookRetryDelay(0))
+	assert.Equal(t, webhookRetryBaseDelay, webhookRetryDelay(1))
+	assert.Equal(t, 4*webhookRetryBaseDelay, webhookRetryDelay(3))
+	assert.Equal(t, webhookRetryMaxDelay, webhookRetryDelay(10))
+	assert.Equal(t, webhookRetryMaxDelay, webhookRetryDelay(64))
+}
@@ -0,0 +1,41 @@
+package whatsapp
+
+import (
+	"slices"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+)
+
+// webhookRouter picks the webhook definitions an event is delivered to, nil until registered
+var webhookRouter domainWebhook.IWebhookRouter
+
+// SetWebhookRouter registers the webhook definitions consulted for every webhook event
+func SetWebhookRouter(r domainWebhook.IWebhookRouter) {
+	webhookRouter = r
+}
+
+// webhookSubscribed reports whether any webhook receives events of the type. The URLs of --webhook
+// receive every event.
+func webhookSubscribed(eventType string) bool {
+	if len(config.WhatsappWebhook) > 0 {
+		return true
+	}
+	return webhookRouter != nil && webhookRouter.WebhookSubscribed(eventType)
+}
+
+// webhookTargets returns the URLs an event is delivered to: every URL of --webhook followed by the
+// URLs of the definitions the event matches, each URL once
+func webhookTargets(event domainWebhook.Event) []string {
+	targets := slices.Clone(config.WhatsappWebhook)
+	if webhookRouter == nil {
+		return targets
+	}
+
+	for _, url := range webhookRouter.WebhookTargets(event) {
+		if !slices.Contains(targets, url) {
+			targets = append(targets, url)
+		}
+	}
+	return targets
+}
@@ -0,0 +1,184 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// shutdownHook is one step of the shutdown
+type shutdownHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Manager runs a server until the process is asked to stop and then shuts the application down
+// in order. Readiness turns false before the first step, so load balancers stop sending traffic
+// while in-flight work is drained.
+type Manager struct {
+	timeout time.Duration
+	ready   atomic.Bool
+
+	mu    sync.Mutex
+	hooks []shutdownHook
+
+	once sync.Once
+	err  error
+}
+
+// New creates a manager whose shutdown steps share a drain timeout. A timeout of 0 waits without limit.
+func New(timeout time.Duration) *Manager {
+	return &Manager{timeout: timeout}
+}
+
+// OnShutdown adds a shutdown step. Steps run one after another in the order they were added.
+func (m *Manager) OnShutdown(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.hooks = append(m.hooks, shutdownHook{name: name, fn: fn})
+}
+
+// SetReady marks whether the application accepts traffic
+func (m *Manager) SetReady(ready bool) {
+	m.ready.Store(ready)
+}
+
+// Ready reports whether the application accepts traffic
+func (m *Manager) Ready() bool {
+	return m.ready.Load()
+}
+
+// Run starts serve and blocks until SIGINT or SIGTERM is received or serve returns, then shuts down.
+// A second signal during the shutdown exits immediately. The error of serve is returned before
+// the shutdown error.
+func (m *Manager) Run(serve func() error) error {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	served := make(chan error, 1)
+	go func() {
+		served <- serve()
+	}()
+
+	var serveErr error
+	select {
+	case sig := <-signals:
+		logrus.Infof("[LIFECYCLE] Received %s, shutting down", sig)
+		go func() {
+			<-signals
+			logrus.Warnln("[LIFECYCLE] Received second signal, exiting without finishing the shutdown")
+			os.Exit(1)
+		}()
+	case serveErr = <-served:
+	}
+
+	shutdownErr := m.Shutdown()
+	if serveErr != nil {
+		return serveErr
+	}
+	return shutdownErr
+}
+
+// Shutdown marks the application not ready and runs the shutdown steps. Every step runs, also after
+// the drain timeout expired, so resources such as databases are always closed; steps that wait
+// for work are expected to give up once the context is done. Later calls return the first result.
+func (m *Manager) Shutdown() error {
+	m.once.Do(func() {
+		m.SetReady(false)
+
+		ctx := context.Background()
+		if m.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, m.timeout)
+			defer cancel()
+		}
+
+		m.mu.Lock()
+		hooks := append([]shutdownHook(nil), m.hooks...)
+		m.mu.Unlock()
+
+		var errs []error
+		for _, hook := range hooks {
+			started := time.Now()
+			if err := hook.fn(ctx); err != nil {
+				logrus.Errorf("[LIFECYCLE] Failed to stop %s: %v", hook.name, err)
+				errs = append(errs, fmt.Errorf("%s: %w", hook.name, err))
+				continue
+			}
+			logrus.Infof("[LIFECYCLE] Stopped %s in %s", hook.name, time.Since(started).Round(time.Millisecond))
+		}
+
+		m.err = errors.Join(errs...)
+		logrus.Infoln("[LIFECYCLE] Shutdown complete")
+	})
+	return m.err
+}
+
+// InFlight counts running tasks so the shutdown can wait for them. Unlike sync.WaitGroup, tasks may
+// start while Wait is in progress.
+type InFlight struct {
+	mu    sync.Mutex
+	count int
+	idle  chan struct{}
+}
+
+// Start registers a running task, the returned function marks it finished
+func (f *InFlight) Start() (done func()) {
+	f.mu.Lock()
+	f.count++
+	f.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+
+			f.count--
+			if f.count == 0 && f.idle != nil {
+				close(f.idle)
+				f.idle = nil
+			}
+		})
+	}
+}
+
+// Count returns the number of running tasks
+func (f *InFlight) Count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.count
+}
+
+// Wait blocks until no task runs or ctx is done, returning the error of ctx with the number of
+// tasks left in the latter case
+func (f *InFlight) Wait(ctx context.Context) error {
+	f.mu.Lock()
+	if f.count == 0 {
+		f.mu.Unlock()
+		return nil
+	}
+	if f.idle == nil {
+		f.idle = make(chan struct{})
+	}
+	idle := f.idle
+	f.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d task(s) still running: %w", f.Count(), ctx.Err())
+	}
+}
@@ -0,0 +1,105 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdownRunsHooksInOrder(t *testing.T) {
+	manager := New(time.Second)
+	manager.SetReady(true)
+
+	var steps []string
+	manager.OnShutdown("first", func(context.Context) error {
+		assert.False(t, manager.Ready(), "readiness turns false before the first step")
+		steps = append(steps, "first")
+		return nil
+	})
+	manager.OnShutdown("second", func(context.Context) error {
+		steps = append(steps, "second")
+		return errors.New("boom")
+	})
+	manager.OnShutdown("third", func(context.Context) error {
+		steps = append(steps, "third")
+		return nil
+	})
+
+	err := manager.Shutdown()
+	assert.ErrorContains(t, err, "second: boom")
+	assert.Equal(t, []string{"first", "second", "third"}, steps, "a failed step does not skip the others")
+
+	assert.Equal(t, err, manager.Shutdown(), "the shutdown runs once")
+	assert.Len(t, steps, 3)
+}
+
+func TestShutdownRunsEveryHookAfterTimeout(t *testing.T) {
+	manager := New(20 * time.Millisecond)
+
+	closed := false
+	manager.OnShutdown("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	manager.OnShutdown("database", func(context.Context) error {
+		closed = true
+		return nil
+	})
+
+	assert.ErrorIs(t, manager.Shutdown(), context.DeadlineExceeded)
+	assert.True(t, closed)
+}
+
+func TestRunShutsDownWhenServeReturns(t *testing.T) {
+	manager := New(time.Second)
+
+	stopped := false
+	manager.OnShutdown("server", func(context.Context) error {
+		stopped = true
+		return nil
+	})
+
+	err := manager.Run(func() error {
+		return errors.New("address already in use")
+	})
+	assert.EqualError(t, err, "address already in use")
+	assert.True(t, stopped)
+}
+
+func TestInFlight(t *testing.T) {
+	var inFlight InFlight
+	assert.NoError(t, inFlight.Wait(context.Background()), "nothing to wait for")
+
+	done := inFlight.Start()
+	second := inFlight.Start()
+	assert.Equal(t, 2, inFlight.Count())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, inFlight.Wait(ctx), context.DeadlineExceeded)
+
+	waited := make(chan error, 1)
+	go func() {
+		waited <- inFlight.Wait(context.Background())
+	}()
+
+	done()
+	done()
+	select {
+	case <-waited:
+		t.Fatal("Wait returned while a task runs")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	second()
+	select {
+	case err := <-waited:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after the last task finished")
+	}
+	assert.Zero(t, inFlight.Count())
+}
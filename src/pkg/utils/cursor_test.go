@@ -0,0 +1,30 @@
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	timestamp := time.Date(2024, 5, 15, 10, 30, 0, 123456789, time.UTC)
+
+	// IDs may contain the separator, only the first one splits the cursor
+	for _, id := range []string{"3EB0B430B6F8F1D0E053", "120363025246125486@g.us", "a:b"} {
+		cursor := utils.EncodeCursor(timestamp, id)
+
+		gotTime, gotID, err := utils.DecodeCursor(cursor)
+		assert.NoError(t, err)
+		assert.True(t, timestamp.Equal(gotTime))
+		assert.Equal(t, id, gotID)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	for _, cursor := range []string{"", "not base64!", "bm8tc2VwYXJhdG9y", "YWJjOmlk", "MTIzOg"} {
+		_, _, err := utils.DecodeCursor(cursor)
+		assert.ErrorIs(t, err, utils.ErrInvalidCursor, cursor)
+	}
+}
@@ -84,7 +84,8 @@ type Metadata struct {
 func GetMetaDataFromURL(urlStr string) (meta Metadata, err error) {
 	// Create HTTP client with timeout
 	client := &http.Client{
-		Timeout: 15 * time.Second,
+		Transport: ProxyTransport(ProxyDownload),
+		Timeout:   15 * time.Second,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if len(via) >= 10 {
 				return fmt.Errorf("too many redirects")
@@ -234,7 +235,8 @@ func ContainsMention(message string) []string {
 
 func DownloadImageFromURL(url string) ([]byte, string, error) {
 	client := &http.Client{
-		Timeout: 30 * time.Second,
+		Transport: ProxyTransport(ProxyDownload),
+		Timeout:   30 * time.Second,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if len(via) >= 10 {
 				return fmt.Errorf("too many redirects")
@@ -290,7 +292,8 @@ func DownloadImageFromURL(url string) ([]byte, string, error) {
 // are allowed to ensure WhatsApp compatibility.
 func DownloadAudioFromURL(audioURL string) ([]byte, string, error) {
 	client := &http.Client{
-		Timeout: 30 * time.Second,
+		Transport: ProxyTransport(ProxyDownload),
+		Timeout:   30 * time.Second,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if len(via) >= 10 {
 				return fmt.Errorf("too many redirects")
@@ -374,7 +377,8 @@ func DownloadAudioFromURL(audioURL string) ([]byte, string, error) {
 // that the size does not exceed WhatsappSettingMaxDownloadSize to avoid memory exhaustion.
 func DownloadVideoFromURL(videoURL string) ([]byte, string, error) {
 	client := &http.Client{
-		Timeout: 30 * time.Second,
+		Transport: ProxyTransport(ProxyDownload),
+		Timeout:   30 * time.Second,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if len(via) >= 10 {
 				return fmt.Errorf("too many redirects")
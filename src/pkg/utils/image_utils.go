@@ -31,6 +31,26 @@ func ProcessGroupPhoto(file *multipart.FileHeader) (*bytes.Buffer, error) {
 	}
 	defer src.Close()
 
+	return processPhoto(src)
+}
+
+// ProcessGroupPhotoBytes applies the ProcessGroupPhoto pipeline to an image already in memory
+func ProcessGroupPhotoBytes(data []byte) (*bytes.Buffer, error) {
+	return processPhoto(bytes.NewReader(data))
+}
+
+// ProcessGroupPhotoFromURL downloads an image and applies the ProcessGroupPhoto pipeline to it.
+// The same pipeline serves profile photos, which have the same requirements.
+func ProcessGroupPhotoFromURL(url string) (*bytes.Buffer, error) {
+	data, _, err := DownloadImageFromURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download image: %w", err)
+	}
+
+	return ProcessGroupPhotoBytes(data)
+}
+
+func processPhoto(src io.Reader) (*bytes.Buffer, error) {
 	// Decode the image
 	img, format, err := image.Decode(src)
 	if err != nil {
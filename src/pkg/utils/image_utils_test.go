@@ -0,0 +1,74 @@
+package utils_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type ImageUtilsTestSuite struct {
+	suite.Suite
+}
+
+func encodePNG(width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+func (suite *ImageUtilsTestSuite) TestProcessGroupPhotoBytes() {
+	processed, err := utils.ProcessGroupPhotoBytes(encodePNG(1200, 800))
+	suite.Require().NoError(err)
+	suite.LessOrEqual(processed.Len(), utils.MaxGroupPhotoSize)
+
+	img, err := jpeg.Decode(processed)
+	suite.Require().NoError(err)
+	suite.Equal(utils.MaxGroupPhotoDimension, img.Bounds().Dx())
+	suite.Equal(utils.MaxGroupPhotoDimension, img.Bounds().Dy())
+}
+
+func (suite *ImageUtilsTestSuite) TestProcessGroupPhotoBytesInvalid() {
+	_, err := utils.ProcessGroupPhotoBytes([]byte("not an image"))
+	suite.ErrorContains(err, "failed to decode image")
+}
+
+func (suite *ImageUtilsTestSuite) TestProcessGroupPhotoFromURL() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/avatar.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(encodePNG(300, 200))
+	}))
+	defer server.Close()
+
+	processed, err := utils.ProcessGroupPhotoFromURL(server.URL + "/avatar.png")
+	suite.Require().NoError(err)
+	img, err := jpeg.Decode(processed)
+	suite.Require().NoError(err)
+	suite.Equal(200, img.Bounds().Dx())
+	suite.Equal(200, img.Bounds().Dy())
+
+	_, err = utils.ProcessGroupPhotoFromURL(server.URL + "/missing.png")
+	suite.ErrorContains(err, "failed to download image")
+}
+
+func TestImageUtilsTestSuite(t *testing.T) {
+	suite.Run(t, new(ImageUtilsTestSuite))
+}
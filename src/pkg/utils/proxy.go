@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+// ProxyPurpose is a kind of outgoing traffic that can be given its own proxy
+type ProxyPurpose string
+
+const (
+	ProxyWhatsapp ProxyPurpose = "whatsapp" // WhatsApp websocket
+	ProxyMedia    ProxyPurpose = "media"    // WhatsApp media uploads and downloads
+	ProxyDownload ProxyPurpose = "download" // Files and link previews fetched from URLs
+	ProxyWebhook  ProxyPurpose = "webhook"  // Webhook deliveries
+
+	// ProxyDirect as a proxy address connects without a proxy, e.g. to keep webhooks to an internal host off the proxy
+	ProxyDirect = "direct"
+)
+
+var (
+	proxyTransportsMu sync.Mutex
+	proxyTransports   = make(map[string]*http.Transport)
+)
+
+// ProxyAddress returns the proxy configured for a purpose, its override before the general proxy.
+// An empty address leaves the proxy from the HTTPS_PROXY and HTTP_PROXY environment variables in place.
+func ProxyAddress(purpose ProxyPurpose) string {
+	override := map[ProxyPurpose]string{
+		ProxyWhatsapp: config.ProxyWhatsappURL,
+		ProxyMedia:    config.ProxyMediaURL,
+		ProxyDownload: config.ProxyDownloadURL,
+		ProxyWebhook:  config.ProxyWebhookURL,
+	}[purpose]
+	if override != "" {
+		return override
+	}
+	return config.ProxyURL
+}
+
+// ParseProxy validates a proxy address. SOCKS5 (socks5://, socks5h://) and HTTP (http://, https://)
+// proxies are supported, credentials go in the user info. ProxyDirect returns nil.
+func ParseProxy(address string) (*url.URL, error) {
+	if address == ProxyDirect {
+		return nil, nil
+	}
+
+	proxyURL, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %q: %w", address, err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy %q: scheme must be http, https, socks5 or socks5h", address)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy %q: missing host", address)
+	}
+	return proxyURL, nil
+}
+
+// ValidateProxyConfig checks the proxy of every purpose, so a typo fails at boot instead of on the first request
+func ValidateProxyConfig() error {
+	for _, purpose := range []ProxyPurpose{ProxyWhatsapp, ProxyMedia, ProxyDownload, ProxyWebhook} {
+		if address := ProxyAddress(purpose); address != "" {
+			if _, err := ParseProxy(address); err != nil {
+				return fmt.Errorf("%s proxy: %w", purpose, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ProxyFunc returns the proxy selection of a purpose for an http.Transport. An invalid proxy fails
+// every request rather than connecting around it.
+func ProxyFunc(purpose ProxyPurpose) func(*http.Request) (*url.URL, error) {
+	address := ProxyAddress(purpose)
+	if address == "" {
+		return http.ProxyFromEnvironment
+	}
+
+	proxyURL, err := ParseProxy(address)
+	if err != nil {
+		return func(*http.Request) (*url.URL, error) { return nil, err }
+	}
+	if proxyURL == nil {
+		return nil
+	}
+	return http.ProxyURL(proxyURL)
+}
+
+// ProxyTransport returns the transport for a purpose, shared by every client using the same proxy
+// so connections are reused as with http.DefaultTransport
+func ProxyTransport(purpose ProxyPurpose) http.RoundTripper {
+	address := ProxyAddress(purpose)
+	if address == "" {
+		return http.DefaultTransport
+	}
+
+	proxyTransportsMu.Lock()
+	defer proxyTransportsMu.Unlock()
+
+	transport, ok := proxyTransports[address]
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = ProxyFunc(purpose)
+		proxyTransports[address] = transport
+	}
+	return transport
+}
@@ -590,7 +590,7 @@ func ExtractMedia(ctx context.Context, client *whatsmeow.Client, storageLocation
 const maxPhoneNumberLength = 15 // Maximum digits in a phone number
 
 func SanitizePhone(phone *string) {
-	if phone != nil && len(*phone) > 0 && !strings.Contains(*phone, "@") {
+	if phone != nil && len(*phone) > 0 && !strings.Contains(*phone, "@") && !strings.HasPrefix(*phone, ContactTagPrefix) {
 		if len(*phone) <= maxPhoneNumberLength {
 			*phone = fmt.Sprintf("%s%s", *phone, config.WhatsappTypeUser)
 		} else {
@@ -599,16 +599,55 @@ func SanitizePhone(phone *string) {
 	}
 }
 
+// ContactTagPrefix marks a recipient as an address book tag, so "tag:customers" sends to every
+// contact tagged customers
+const ContactTagPrefix = "tag:"
+
+// ContactTagSelector returns the tag a recipient selects, if it is a tag selector
+func ContactTagSelector(phone string) (string, bool) {
+	if !strings.HasPrefix(phone, ContactTagPrefix) {
+		return "", false
+	}
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(phone, ContactTagPrefix))), true
+}
+
+// NumberCheckCache remembers IsOnWhatsApp results so repeated checks of the same number skip the round trip
+type NumberCheckCache interface {
+	// CachedIsOnWhatsApp returns whether a phone is registered and whether a fresh cached answer exists
+	CachedIsOnWhatsApp(ctx context.Context, phone string) (registered bool, found bool)
+	StoreIsOnWhatsApp(ctx context.Context, results []types.IsOnWhatsAppResponse)
+}
+
+// numberCheckCache is consulted by IsOnWhatsapp, nil until registered
+var numberCheckCache NumberCheckCache
+
+// SetNumberCheckCache registers the cache consulted by IsOnWhatsapp
+func SetNumberCheckCache(cache NumberCheckCache) {
+	numberCheckCache = cache
+}
+
 // IsOnWhatsapp checks if a number is registered on WhatsApp
 func IsOnWhatsapp(client *whatsmeow.Client, jid string) bool {
 	// only check if the jid a user with @s.whatsapp.net
 	if strings.Contains(jid, "@s.whatsapp.net") {
-		data, err := client.IsOnWhatsApp(context.Background(), []string{jid})
+		ctx := context.Background()
+		phone := strings.SplitN(strings.SplitN(jid, "@", 2)[0], ":", 2)[0]
+		if numberCheckCache != nil {
+			if registered, found := numberCheckCache.CachedIsOnWhatsApp(ctx, phone); found {
+				return registered
+			}
+		}
+
+		data, err := client.IsOnWhatsApp(ctx, []string{jid})
 		if err != nil {
 			logrus.Error("Failed to check if user is on whatsapp: ", err)
 			return false
 		}
 
+		if numberCheckCache != nil {
+			numberCheckCache.StoreIsOnWhatsApp(ctx, data)
+		}
+
 		for _, v := range data {
 			if !v.IsIn {
 				return false
@@ -709,3 +748,17 @@ func BuildForwarded(evt *events.Message) bool {
 	}
 	return false
 }
+
+// BuildMentionedJIDs returns the JIDs mentioned in a text message
+func BuildMentionedJIDs(evt *events.Message) []string {
+	if extendedText := evt.Message.GetExtendedTextMessage(); extendedText != nil {
+		return extendedText.GetContextInfo().GetMentionedJID()
+	} else if protocolMessage := evt.Message.GetProtocolMessage(); protocolMessage != nil {
+		if editedMessage := protocolMessage.GetEditedMessage(); editedMessage != nil {
+			if extendedText := editedMessage.GetExtendedTextMessage(); extendedText != nil {
+				return extendedText.GetContextInfo().GetMentionedJID()
+			}
+		}
+	}
+	return nil
+}
@@ -50,3 +50,20 @@ func TestDetermineMediaExtension(t *testing.T) {
 		})
 	}
 }
+
+func TestSanitizePhoneKeepsTagSelector(t *testing.T) {
+	phone := "tag:Customers"
+	SanitizePhone(&phone)
+	if phone != "tag:Customers" {
+		t.Fatalf("SanitizePhone() = %q, want the tag selector unchanged", phone)
+	}
+
+	tag, ok := ContactTagSelector(phone)
+	if !ok || tag != "customers" {
+		t.Fatalf("ContactTagSelector() = %q, %v, want %q, true", tag, ok, "customers")
+	}
+
+	if _, ok := ContactTagSelector("6281234567890@s.whatsapp.net"); ok {
+		t.Fatal("ContactTagSelector() matched a phone number")
+	}
+}
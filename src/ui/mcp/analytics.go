@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	domainAnalytics "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/analytics"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type AnalyticsHandler struct {
+	analyticsService domainAnalytics.IAnalyticsUsecase
+}
+
+func InitMcpAnalytics(analyticsService domainAnalytics.IAnalyticsUsecase) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+func (h *AnalyticsHandler) AddAnalyticsTools(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(h.toolGetAnalytics(), h.handleGetAnalytics)
+}
+
+func (h *AnalyticsHandler) toolGetAnalytics() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_get_analytics",
+		mcp.WithDescription("Summarize conversation activity (e.g., \"how busy were we last week\"): message volume per day or hour, inbound vs outbound counts, top chats and senders, median first-response time, and media volume by type."),
+		mcp.WithTitleAnnotation("Get Conversation Analytics"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("period",
+			mcp.Description("Named time range relative to now. Cannot be combined with start_time/end_time."),
+			mcp.Enum(
+				domainAnalytics.PeriodToday,
+				domainAnalytics.PeriodYesterday,
+				domainAnalytics.PeriodLast7Days,
+				domainAnalytics.PeriodLastWeek,
+				domainAnalytics.PeriodThisWeek,
+				domainAnalytics.PeriodLast30Days,
+				domainAnalytics.PeriodThisMonth,
+				domainAnalytics.PeriodLastMonth,
+			),
+		),
+		mcp.WithString("start_time",
+			mcp.Description("Only include messages sent at or after this RFC3339 timestamp."),
+		),
+		mcp.WithString("end_time",
+			mcp.Description("Only include messages sent at or before this RFC3339 timestamp."),
+		),
+		mcp.WithString("chat_jid",
+			mcp.Description("Restrict analytics to a single chat JID (e.g., 628123456789@s.whatsapp.net or group@g.us)."),
+		),
+		mcp.WithString("granularity",
+			mcp.Description("Bucket size for message volume (default day)."),
+			mcp.Enum(domainAnalytics.GranularityDay, domainAnalytics.GranularityHour),
+			mcp.DefaultString(domainAnalytics.GranularityDay),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of top chats and senders to return (default 10, max 100)."),
+			mcp.DefaultNumber(10),
+		),
+	)
+}
+
+func (h *AnalyticsHandler) handleGetAnalytics(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var startTimePtr *string
+	startTime := strings.TrimSpace(request.GetString("start_time", ""))
+	if startTime != "" {
+		startTimePtr = &startTime
+	}
+
+	var endTimePtr *string
+	endTime := strings.TrimSpace(request.GetString("end_time", ""))
+	if endTime != "" {
+		endTimePtr = &endTime
+	}
+
+	req := domainAnalytics.GetAnalyticsRequest{
+		ChatJID:     strings.TrimSpace(request.GetString("chat_jid", "")),
+		Period:      strings.TrimSpace(request.GetString("period", "")),
+		StartTime:   startTimePtr,
+		EndTime:     endTimePtr,
+		Granularity: request.GetString("granularity", domainAnalytics.GranularityDay),
+		Limit:       request.GetInt("limit", 10),
+	}
+
+	resp, err := h.analyticsService.GetAnalytics(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf(
+		"%d messages (%d inbound, %d outbound), median first response %.0fs across %d replies",
+		resp.Totals.Total,
+		resp.Totals.Inbound,
+		resp.Totals.Outbound,
+		resp.ResponseTime.MedianSeconds,
+		resp.ResponseTime.Samples,
+	)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
@@ -32,7 +32,7 @@ func (h *AppHandler) AddAppTools(mcpServer *server.MCPServer) {
 func (h *AppHandler) toolConnectionStatus() mcp.Tool {
 	return mcp.NewTool(
 		"whatsapp_connection_status",
-		mcp.WithDescription("Check whether the WhatsApp client is connected and logged in."),
+		mcp.WithDescription("Check whether the WhatsApp client is connected and logged in, with the recent connection state changes and scheduled reconnects."),
 		mcp.WithTitleAnnotation("Connection Status"),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithDestructiveHintAnnotation(false),
@@ -40,8 +40,8 @@ func (h *AppHandler) toolConnectionStatus() mcp.Tool {
 	)
 }
 
-func (h *AppHandler) handleConnectionStatus(_ context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	isConnected, isLoggedIn, deviceID := whatsapp.GetConnectionStatus()
+func (h *AppHandler) handleConnectionStatus(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	isConnected, isLoggedIn, deviceID := whatsapp.GetConnectionStatus(ctx)
 
 	structured := map[string]any{
 		"is_connected": isConnected,
@@ -50,6 +50,10 @@ func (h *AppHandler) handleConnectionStatus(_ context.Context, _ mcp.CallToolReq
 	if deviceID != "" {
 		structured["device_id"] = deviceID
 	}
+	if history := whatsapp.ConnectionHistory(ctx); len(history) > 0 {
+		structured["state"] = history[0]
+		structured["history"] = history
+	}
 
 	fallback := fmt.Sprintf("connected=%t logged_in=%t", isConnected, isLoggedIn)
 	return mcp.NewToolResultStructured(structured, fallback), nil
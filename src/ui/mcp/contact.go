@@ -0,0 +1,166 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type ContactHandler struct {
+	contactService domainContact.IContactUsecase
+}
+
+func InitMcpContact(contactService domainContact.IContactUsecase) *ContactHandler {
+	return &ContactHandler{contactService: contactService}
+}
+
+func (h *ContactHandler) AddContactTools(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(h.toolSearchAddressBook(), h.handleSearchAddressBook)
+	mcpServer.AddTool(h.toolSaveContact(), h.handleSaveContact)
+	mcpServer.AddTool(h.toolDeleteContact(), h.handleDeleteContact)
+}
+
+func (h *ContactHandler) toolSearchAddressBook() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_search_address_book",
+		mcp.WithDescription("Search the local address book of custom names, tags, notes and custom fields kept for contacts and groups."),
+		mcp.WithTitleAnnotation("Search Address Book"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("search",
+			mcp.Description("Text matched against names, phone numbers, notes, custom fields and tags."),
+		),
+		mcp.WithString("tag",
+			mcp.Description("Only return contacts with this tag."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum contacts returned, 1-500 (default 50)."),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of contacts to skip."),
+		),
+	)
+}
+
+func (h *ContactHandler) handleSearchAddressBook(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resp, err := h.contactService.ListContacts(ctx, domainContact.ListContactsRequest{
+		Search: request.GetString("search", ""),
+		Tag:    request.GetString("tag", ""),
+		Limit:  request.GetInt("limit", 0),
+		Offset: request.GetInt("offset", 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Found %d of %d contacts", len(resp.Data), resp.Pagination.Total)), nil
+}
+
+func (h *ContactHandler) toolSaveContact() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_save_contact",
+		mcp.WithDescription("Add a contact or group to the address book, or change the given fields of an existing entry. The custom name replaces the WhatsApp push name in chats and webhooks, and tags can be used as send recipients with phone \"tag:<name>\"."),
+		mcp.WithTitleAnnotation("Save Contact"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("phone",
+			mcp.Description("Phone number or JID of the contact or group."),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("Custom display name."),
+		),
+		mcp.WithArray("tags",
+			mcp.Description("Tags, replacing the current ones. Letters, digits, dashes and underscores."),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("notes",
+			mcp.Description("Free-form notes, replacing the current ones."),
+		),
+		mcp.WithObject("custom_fields",
+			mcp.Description("Custom fields to set as string values; an empty value removes the field."),
+		),
+	)
+}
+
+func (h *ContactHandler) handleSaveContact(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	phone, err := request.RequireString("phone")
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := request.GetArguments()
+	fields := make(map[string]string)
+	if values, ok := arguments["custom_fields"].(map[string]any); ok {
+		for key, value := range values {
+			fields[key] = fmt.Sprint(value)
+		}
+	}
+
+	var resp domainContact.Contact
+	if _, err := h.contactService.GetContact(ctx, domainContact.GetContactRequest{Phone: phone}); err == nil {
+		update := domainContact.UpdateContactRequest{Phone: phone, CustomFields: fields}
+		if _, ok := arguments["name"]; ok {
+			name := request.GetString("name", "")
+			update.Name = &name
+		}
+		if _, ok := arguments["tags"]; ok {
+			tags := request.GetStringSlice("tags", nil)
+			update.Tags = &tags
+		}
+		if _, ok := arguments["notes"]; ok {
+			notes := request.GetString("notes", "")
+			update.Notes = &notes
+		}
+		resp, err = h.contactService.UpdateContact(ctx, update)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		resp, err = h.contactService.CreateContact(ctx, domainContact.ContactRequest{
+			Phone:        phone,
+			Name:         request.GetString("name", ""),
+			Tags:         request.GetStringSlice("tags", nil),
+			Notes:        request.GetString("notes", ""),
+			CustomFields: fields,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Saved contact %s", resp.JID)), nil
+}
+
+func (h *ContactHandler) toolDeleteContact() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_delete_contact",
+		mcp.WithDescription("Remove a contact or group from the address book. WhatsApp itself is not changed."),
+		mcp.WithTitleAnnotation("Delete Contact"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("phone",
+			mcp.Description("Phone number or JID of the contact or group."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *ContactHandler) handleDeleteContact(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	phone, err := request.RequireString("phone")
+	if err != nil {
+		return nil, err
+	}
+
+	if err = h.contactService.DeleteContact(ctx, domainContact.GetContactRequest{Phone: phone}); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Deleted contact %s", phone)), nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 )
 
 type GroupHandler struct {
@@ -30,10 +31,22 @@ func (h *GroupHandler) AddGroupTools(mcpServer *server.MCPServer) {
 	mcpServer.AddTool(h.toolGroupInfo(), h.handleGroupInfo)
 	mcpServer.AddTool(h.toolSetGroupName(), h.handleSetGroupName)
 	mcpServer.AddTool(h.toolSetGroupTopic(), h.handleSetGroupTopic)
+	mcpServer.AddTool(h.toolSetGroupPhoto(), h.handleSetGroupPhoto)
 	mcpServer.AddTool(h.toolSetGroupLocked(), h.handleSetGroupLocked)
 	mcpServer.AddTool(h.toolSetGroupAnnounce(), h.handleSetGroupAnnounce)
+	mcpServer.AddTool(h.toolSetGroupDescription(), h.handleSetGroupDescription)
+	mcpServer.AddTool(h.toolSetGroupMemberAddMode(), h.handleSetGroupMemberAddMode)
+	mcpServer.AddTool(h.toolSetGroupJoinApproval(), h.handleSetGroupJoinApproval)
+	mcpServer.AddTool(h.toolSetGroupDisappearing(), h.handleSetGroupDisappearing)
 	mcpServer.AddTool(h.toolListGroupJoinRequests(), h.handleListGroupJoinRequests)
 	mcpServer.AddTool(h.toolManageGroupJoinRequests(), h.handleManageGroupJoinRequests)
+	mcpServer.AddTool(h.toolGroupHistory(), h.handleGroupHistory)
+	mcpServer.AddTool(h.toolCloneGroup(), h.handleCloneGroup)
+	mcpServer.AddTool(h.toolCreateCommunity(), h.handleCreateCommunity)
+	mcpServer.AddTool(h.toolLinkGroup(), h.handleLinkGroup)
+	mcpServer.AddTool(h.toolUnlinkGroup(), h.handleUnlinkGroup)
+	mcpServer.AddTool(h.toolListSubGroups(), h.handleListSubGroups)
+	mcpServer.AddTool(h.toolSendAnnouncement(), h.handleSendAnnouncement)
 }
 
 func (h *GroupHandler) toolCreateGroup() mcp.Tool {
@@ -52,6 +65,9 @@ func (h *GroupHandler) toolCreateGroup() mcp.Tool {
 			mcp.Description("Phone numbers to add during creation (without @s.whatsapp.net suffix)."),
 			mcp.WithStringItems(),
 		),
+		mcp.WithString("community_id",
+			mcp.Description("Optional community JID to create the group inside."),
+		),
 	)
 }
 
@@ -71,9 +87,13 @@ func (h *GroupHandler) handleCreateGroup(ctx context.Context, request mcp.CallTo
 		}
 	}
 
+	communityID := strings.TrimSpace(request.GetString("community_id", ""))
+	utils.SanitizePhone(&communityID)
+
 	groupID, err := h.groupService.CreateGroup(ctx, domainGroup.CreateGroupRequest{
 		Title:        strings.TrimSpace(title),
 		Participants: participants,
+		CommunityID:  communityID,
 	})
 	if err != nil {
 		return nil, err
@@ -312,6 +332,9 @@ func (h *GroupHandler) handleGetInviteLink(ctx context.Context, request mcp.Call
 	}
 
 	fallback := fmt.Sprintf("Invite link for %s: %s", trimmed, resp.InviteLink)
+	if reset {
+		fallback = fmt.Sprintf("Invite link for %s was reset, new link: %s", trimmed, resp.InviteLink)
+	}
 	return mcp.NewToolResultStructured(resp, fallback), nil
 }
 
@@ -428,6 +451,45 @@ func (h *GroupHandler) handleSetGroupTopic(ctx context.Context, request mcp.Call
 	return mcp.NewToolResultText(fmt.Sprintf("Updated group %s topic", trimmed)), nil
 }
 
+func (h *GroupHandler) toolSetGroupPhoto() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_group_set_photo",
+		mcp.WithDescription("Set the group photo from an image URL, or remove it when no URL is given. The image is cropped to a square and compressed."),
+		mcp.WithTitleAnnotation("Set Group Photo"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("group_id",
+			mcp.Description("Group JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithString("photo_url",
+			mcp.Description("URL of a JPEG, PNG or WebP image. Leave empty to remove the photo."),
+		),
+	)
+}
+
+func (h *GroupHandler) handleSetGroupPhoto(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	groupID, err := request.RequireString("group_id")
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := strings.TrimSpace(groupID)
+	utils.SanitizePhone(&trimmed)
+	photoURL := strings.TrimSpace(request.GetString("photo_url", ""))
+
+	pictureID, err := h.groupService.SetGroupPhoto(ctx, domainGroup.SetGroupPhotoRequest{GroupID: trimmed, PhotoURL: photoURL})
+	if err != nil {
+		return nil, err
+	}
+
+	if photoURL == "" {
+		return mcp.NewToolResultText(fmt.Sprintf("Removed group %s photo", trimmed)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Updated group %s photo (picture id %s)", trimmed, pictureID)), nil
+}
+
 func (h *GroupHandler) toolSetGroupLocked() mcp.Tool {
 	return mcp.NewTool(
 		"whatsapp_group_set_locked",
@@ -538,6 +600,191 @@ func (h *GroupHandler) handleSetGroupAnnounce(ctx context.Context, request mcp.C
 	return mcp.NewToolResultText(fmt.Sprintf("Group %s is now in %s mode", trimmed, state)), nil
 }
 
+func (h *GroupHandler) toolSetGroupDescription() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_group_set_description",
+		mcp.WithDescription("Replace the group description. Pass previous_id (topic_id from group info) to refuse the update if the description changed since it was read."),
+		mcp.WithTitleAnnotation("Set Group Description"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("group_id",
+			mcp.Description("Group JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithString("description",
+			mcp.Description("New group description. Leave empty to remove it."),
+		),
+		mcp.WithString("previous_id",
+			mcp.Description("Description ID the update is based on."),
+		),
+	)
+}
+
+func (h *GroupHandler) handleSetGroupDescription(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	groupID, err := request.RequireString("group_id")
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := strings.TrimSpace(groupID)
+	utils.SanitizePhone(&trimmed)
+
+	resp, err := h.groupService.SetGroupDescription(ctx, domainGroup.SetGroupDescriptionRequest{
+		GroupID:     trimmed,
+		Description: strings.TrimSpace(request.GetString("description", "")),
+		PreviousID:  strings.TrimSpace(request.GetString("previous_id", "")),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Updated group %s description (id %s)", trimmed, resp.DescriptionID)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *GroupHandler) toolSetGroupMemberAddMode() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_group_set_member_add_mode",
+		mcp.WithDescription("Choose whether only admins or all members can add participants."),
+		mcp.WithTitleAnnotation("Set Member Add Mode"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("group_id",
+			mcp.Description("Group JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithString("mode",
+			mcp.Description("admin_add or all_member_add."),
+			mcp.Enum(string(types.GroupMemberAddModeAdmin), string(types.GroupMemberAddModeAllMember)),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *GroupHandler) handleSetGroupMemberAddMode(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	groupID, err := request.RequireString("group_id")
+	if err != nil {
+		return nil, err
+	}
+
+	mode, err := request.RequireString("mode")
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := strings.TrimSpace(groupID)
+	utils.SanitizePhone(&trimmed)
+
+	if err := h.groupService.SetGroupMemberAddMode(ctx, domainGroup.SetGroupMemberAddModeRequest{
+		GroupID: trimmed,
+		Mode:    types.GroupMemberAddMode(strings.TrimSpace(mode)),
+	}); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Group %s member add mode is now %s", trimmed, mode)), nil
+}
+
+func (h *GroupHandler) toolSetGroupJoinApproval() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_group_set_join_approval",
+		mcp.WithDescription("Toggle whether admins must approve new members joining via link."),
+		mcp.WithTitleAnnotation("Set Join Approval"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("group_id",
+			mcp.Description("Group JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("enabled",
+			mcp.Description("Set to true to require admin approval."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *GroupHandler) handleSetGroupJoinApproval(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	groupID, err := request.RequireString("group_id")
+	if err != nil {
+		return nil, err
+	}
+
+	args := request.GetArguments()
+	if args == nil {
+		return nil, fmt.Errorf("enabled flag is required")
+	}
+
+	val, ok := args["enabled"]
+	if !ok {
+		return nil, fmt.Errorf("enabled flag is required")
+	}
+
+	enabled, err := toBool(val)
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := strings.TrimSpace(groupID)
+	utils.SanitizePhone(&trimmed)
+
+	if err := h.groupService.SetGroupJoinApproval(ctx, domainGroup.SetGroupJoinApprovalRequest{GroupID: trimmed, Enabled: enabled}); err != nil {
+		return nil, err
+	}
+
+	state := "disabled"
+	if enabled {
+		state = "enabled"
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Join approval for group %s is now %s", trimmed, state)), nil
+}
+
+func (h *GroupHandler) toolSetGroupDisappearing() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_group_set_disappearing",
+		mcp.WithDescription("Set the default disappearing-messages timer of a group."),
+		mcp.WithTitleAnnotation("Set Disappearing Messages"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("group_id",
+			mcp.Description("Group JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithNumber("timer",
+			mcp.Description("Timer in seconds: 0 (off), 86400 (24 hours), 604800 (7 days) or 7776000 (90 days)."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *GroupHandler) handleSetGroupDisappearing(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	groupID, err := request.RequireString("group_id")
+	if err != nil {
+		return nil, err
+	}
+
+	timer, err := request.RequireInt("timer")
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := strings.TrimSpace(groupID)
+	utils.SanitizePhone(&trimmed)
+
+	if err := h.groupService.SetGroupDisappearing(ctx, domainGroup.SetGroupDisappearingRequest{GroupID: trimmed, Timer: timer}); err != nil {
+		return nil, err
+	}
+
+	if timer == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("Disappearing messages turned off for group %s", trimmed)), nil
+	}
+	return mcp.NewToolResultText(fmt.Sprintf("Disappearing messages for group %s set to %d seconds", trimmed, timer)), nil
+}
+
 func (h *GroupHandler) toolListGroupJoinRequests() mcp.Tool {
 	return mcp.NewTool(
 		"whatsapp_group_join_requests",
@@ -711,3 +958,321 @@ func toStringSlice(raw any) ([]string, error) {
 		return nil, fmt.Errorf("participants must be an array of strings")
 	}
 }
+
+func (h *GroupHandler) toolGroupHistory() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_group_history",
+		mcp.WithDescription("List recorded membership and setting changes of a group, newest first. Use it to find who added, removed, promoted or demoted a member and when."),
+		mcp.WithTitleAnnotation("Group History"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("group_id",
+			mcp.Description("Group JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithString("participant",
+			mcp.Description("Only changes affecting this member (phone number or JID)."),
+		),
+		mcp.WithString("actor",
+			mcp.Description("Only changes made by this member (phone number or JID)."),
+		),
+		mcp.WithString("event_type",
+			mcp.Description("Comma separated event types, e.g. remove,leave. Types: join, add, leave, remove, promote, demote, name, topic, locked, announce, ephemeral, membership_approval, invite_link, link, unlink, delete."),
+		),
+		mcp.WithString("start_time",
+			mcp.Description("Only changes at or after this RFC3339 time."),
+		),
+		mcp.WithString("end_time",
+			mcp.Description("Only changes at or before this RFC3339 time."),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of events to return (default 50, max 500)."),
+		),
+		mcp.WithNumber("offset",
+			mcp.Description("Number of events to skip."),
+		),
+	)
+}
+
+func (h *GroupHandler) handleGroupHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	groupID, err := request.RequireString("group_id")
+	if err != nil {
+		return nil, err
+	}
+
+	historyRequest := domainGroup.GetGroupHistoryRequest{
+		GroupID:     strings.TrimSpace(groupID),
+		Participant: strings.TrimSpace(request.GetString("participant", "")),
+		Actor:       strings.TrimSpace(request.GetString("actor", "")),
+		EventType:   request.GetString("event_type", ""),
+		Limit:       request.GetInt("limit", 0),
+		Offset:      request.GetInt("offset", 0),
+	}
+	if startTime := strings.TrimSpace(request.GetString("start_time", "")); startTime != "" {
+		historyRequest.StartTime = &startTime
+	}
+	if endTime := strings.TrimSpace(request.GetString("end_time", "")); endTime != "" {
+		historyRequest.EndTime = &endTime
+	}
+	utils.SanitizePhone(&historyRequest.GroupID)
+	utils.SanitizePhone(&historyRequest.Participant)
+	utils.SanitizePhone(&historyRequest.Actor)
+
+	resp, err := h.groupService.GetGroupHistory(ctx, historyRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Found %d of %d events for group %s", len(resp.Events), resp.Pagination.Total, resp.GroupID)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *GroupHandler) toolCloneGroup() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_group_clone",
+		mcp.WithDescription("Create a new group with the name, photo, topic, settings and members of an existing group. Admins of the source group are promoted in the new one."),
+		mcp.WithTitleAnnotation("Clone Group"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("group_id",
+			mcp.Description("Group JID or numeric ID of the group to copy."),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of the new group. {name} is replaced by the source group name and {date} by today's date, e.g. '{name} - {date}'. Defaults to the source name."),
+		),
+		mcp.WithBoolean("skip_participants",
+			mcp.Description("Create the group without copying its members."),
+		),
+		mcp.WithBoolean("skip_photo",
+			mcp.Description("Do not copy the group photo."),
+		),
+	)
+}
+
+func (h *GroupHandler) handleCloneGroup(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	groupID, err := request.RequireString("group_id")
+	if err != nil {
+		return nil, err
+	}
+
+	cloneRequest := domainGroup.CloneGroupRequest{
+		GroupID:          strings.TrimSpace(groupID),
+		Name:             strings.TrimSpace(request.GetString("name", "")),
+		SkipParticipants: request.GetBool("skip_participants", false),
+		SkipPhoto:        request.GetBool("skip_photo", false),
+	}
+	utils.SanitizePhone(&cloneRequest.GroupID)
+
+	resp, err := h.groupService.CloneGroup(ctx, cloneRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Created group %s (%s) from %s with %d participants", resp.Name, resp.GroupID, cloneRequest.GroupID, len(resp.Participants))
+	if len(resp.Warnings) > 0 {
+		fallback += fmt.Sprintf(". Warnings: %s", strings.Join(resp.Warnings, "; "))
+	}
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *GroupHandler) toolCreateCommunity() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_community_create",
+		mcp.WithDescription("Create a WhatsApp community. WhatsApp adds an announcement group to every new community."),
+		mcp.WithTitleAnnotation("Create Community"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("name",
+			mcp.Description("Community name."),
+			mcp.Required(),
+		),
+		mcp.WithString("description",
+			mcp.Description("Optional community description."),
+		),
+	)
+}
+
+func (h *GroupHandler) handleCreateCommunity(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name, err := request.RequireString("name")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.groupService.CreateCommunity(ctx, domainGroup.CreateCommunityRequest{
+		Name:        strings.TrimSpace(name),
+		Description: strings.TrimSpace(request.GetString("description", "")),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Created community %s", resp.CommunityID)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *GroupHandler) toolLinkGroup() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_community_link_group",
+		mcp.WithDescription("Link an existing group to a community."),
+		mcp.WithTitleAnnotation("Link Group To Community"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("community_id",
+			mcp.Description("Community JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithString("group_id",
+			mcp.Description("Group JID or numeric ID to link."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *GroupHandler) handleLinkGroup(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	linkRequest, err := communityLinkRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.groupService.LinkGroup(ctx, linkRequest); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Linked group %s to community %s", linkRequest.GroupID, linkRequest.CommunityID)), nil
+}
+
+func (h *GroupHandler) toolUnlinkGroup() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_community_unlink_group",
+		mcp.WithDescription("Unlink a group from its community. The group itself is kept."),
+		mcp.WithTitleAnnotation("Unlink Group From Community"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("community_id",
+			mcp.Description("Community JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithString("group_id",
+			mcp.Description("Group JID or numeric ID to unlink."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *GroupHandler) handleUnlinkGroup(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	linkRequest, err := communityLinkRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.groupService.UnlinkGroup(ctx, linkRequest); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Unlinked group %s from community %s", linkRequest.GroupID, linkRequest.CommunityID)), nil
+}
+
+func (h *GroupHandler) toolListSubGroups() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_community_list_subgroups",
+		mcp.WithDescription("List the groups linked to a community, including its announcement group."),
+		mcp.WithTitleAnnotation("List Community Subgroups"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("community_id",
+			mcp.Description("Community JID or numeric ID."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *GroupHandler) handleListSubGroups(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	communityID, err := request.RequireString("community_id")
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := strings.TrimSpace(communityID)
+	utils.SanitizePhone(&trimmed)
+
+	resp, err := h.groupService.GetSubGroups(ctx, domainGroup.GetSubGroupsRequest{CommunityID: trimmed})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Community %s has %d subgroups", resp.CommunityID, len(resp.SubGroups))
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func (h *GroupHandler) toolSendAnnouncement() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_community_send_announcement",
+		mcp.WithDescription("Send a text message to the announcement group of a community."),
+		mcp.WithTitleAnnotation("Send Community Announcement"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("community_id",
+			mcp.Description("Community JID or numeric ID."),
+			mcp.Required(),
+		),
+		mcp.WithString("message",
+			mcp.Description("Announcement text."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *GroupHandler) handleSendAnnouncement(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	communityID, err := request.RequireString("community_id")
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := request.RequireString("message")
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := strings.TrimSpace(communityID)
+	utils.SanitizePhone(&trimmed)
+
+	resp, err := h.groupService.SendCommunityAnnouncement(ctx, domainGroup.SendCommunityAnnouncementRequest{
+		CommunityID: trimmed,
+		Message:     message,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fallback := fmt.Sprintf("Announcement sent to community %s (message ID %s)", trimmed, resp.MessageID)
+	return mcp.NewToolResultStructured(resp, fallback), nil
+}
+
+func communityLinkRequest(request mcp.CallToolRequest) (domainGroup.LinkGroupRequest, error) {
+	communityID, err := request.RequireString("community_id")
+	if err != nil {
+		return domainGroup.LinkGroupRequest{}, err
+	}
+
+	groupID, err := request.RequireString("group_id")
+	if err != nil {
+		return domainGroup.LinkGroupRequest{}, err
+	}
+
+	linkRequest := domainGroup.LinkGroupRequest{
+		CommunityID: strings.TrimSpace(communityID),
+		GroupID:     strings.TrimSpace(groupID),
+	}
+	utils.SanitizePhone(&linkRequest.CommunityID)
+	utils.SanitizePhone(&linkRequest.GroupID)
+
+	return linkRequest, nil
+}
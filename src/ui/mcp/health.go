@@ -0,0 +1,32 @@
+package mcp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+)
+
+// HealthHandler answers the liveness probe of the MCP server
+func HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeProbe(w, http.StatusOK, "SUCCESS", "Alive")
+	})
+}
+
+// ReadinessHandler answers the readiness probe of the MCP server, failing while it starts or shuts down
+func ReadinessHandler(ready func() bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if !ready() {
+			writeProbe(w, http.StatusServiceUnavailable, "NOT_READY", "Not accepting traffic, starting or shutting down")
+			return
+		}
+		writeProbe(w, http.StatusOK, "SUCCESS", "Ready")
+	})
+}
+
+func writeProbe(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(utils.ResponseData{Status: status, Code: code, Message: message})
+}
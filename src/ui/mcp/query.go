@@ -75,6 +75,12 @@ func (h *QueryHandler) toolListChats() mcp.Tool {
 		mcp.WithString("search",
 			mcp.Description("Filter chats whose name contains this text."),
 		),
+		mcp.WithString("before",
+			mcp.Description("Cursor from pagination.next_cursor; returns chats older than it. Cannot be combined with offset."),
+		),
+		mcp.WithString("after",
+			mcp.Description("Cursor from pagination.prev_cursor; returns chats with newer activity than it. Cannot be combined with offset."),
+		),
 		mcp.WithBoolean("has_media",
 			mcp.Description("If true, return only chats that contain media messages."),
 			mcp.DefaultBool(false),
@@ -100,6 +106,8 @@ func (h *QueryHandler) handleListChats(ctx context.Context, request mcp.CallTool
 		Offset:   request.GetInt("offset", 0),
 		Search:   request.GetString("search", ""),
 		HasMedia: hasMedia,
+		Before:   strings.TrimSpace(request.GetString("before", "")),
+		After:    strings.TrimSpace(request.GetString("after", "")),
 	}
 
 	resp, err := h.chatService.ListChats(ctx, req)
@@ -152,6 +160,12 @@ func (h *QueryHandler) toolGetChatMessages() mcp.Tool {
 		mcp.WithString("search",
 			mcp.Description("Full-text search within the chat history (case-insensitive)."),
 		),
+		mcp.WithString("before",
+			mcp.Description("Cursor from pagination.next_cursor; returns messages older than it. Cannot be combined with offset or search."),
+		),
+		mcp.WithString("after",
+			mcp.Description("Cursor from pagination.prev_cursor; returns messages newer than it, useful for incremental sync. Cannot be combined with offset or search."),
+		),
 	)
 }
 
@@ -206,6 +220,8 @@ func (h *QueryHandler) handleGetChatMessages(ctx context.Context, request mcp.Ca
 		MediaOnly: mediaOnly,
 		IsFromMe:  isFromMePtr,
 		Search:    request.GetString("search", ""),
+		Before:    strings.TrimSpace(request.GetString("before", "")),
+		After:     strings.TrimSpace(request.GetString("after", "")),
 	}
 
 	resp, err := h.chatService.GetChatMessages(ctx, req)
@@ -218,6 +234,9 @@ func (h *QueryHandler) handleGetChatMessages(ctx context.Context, request mcp.Ca
 		len(resp.Data),
 		chatJID,
 	)
+	if resp.Pagination.NextCursor != "" {
+		fallback += fmt.Sprintf(" (older messages: before=%s)", resp.Pagination.NextCursor)
+	}
 	return mcp.NewToolResultStructured(resp, fallback), nil
 }
 
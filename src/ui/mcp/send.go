@@ -34,7 +34,7 @@ func (s *SendHandler) toolSendText() mcp.Tool {
 		mcp.WithDescription("Send a text message to a WhatsApp contact or group."),
 		mcp.WithString("phone",
 			mcp.Required(),
-			mcp.Description("Phone number or group ID to send message to"),
+			mcp.Description("Phone number or group ID to send message to, or tag:<name> to send to every address book contact with that tag"),
 		),
 		mcp.WithString("message",
 			mcp.Required(),
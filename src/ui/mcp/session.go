@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type SessionHandler struct {
+	sessionService domainSession.ISessionUsecase
+}
+
+func InitMcpSession(sessionService domainSession.ISessionUsecase) *SessionHandler {
+	return &SessionHandler{sessionService: sessionService}
+}
+
+// SessionMiddleware selects the WhatsApp session the tools act on, taken from the session_id path
+// value of the /sessions/{session_id}/... endpoints or the X-Session-ID header. Requests without
+// either use the default session.
+func SessionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.PathValue("session_id")
+		if sessionID == "" {
+			sessionID = r.Header.Get(domainSession.HeaderSessionID)
+		}
+
+		if sessionID != "" {
+			if !whatsapp.HasSession(sessionID) {
+				http.Error(w, fmt.Sprintf("session %s not found", sessionID), http.StatusNotFound)
+				return
+			}
+			r = r.WithContext(domainSession.NewContext(r.Context(), sessionID))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SessionBasePath keeps clients connected under /sessions/{session_id} on the same prefix
+// for their message endpoint
+func SessionBasePath(r *http.Request, _ string) string {
+	if sessionID := r.PathValue("session_id"); sessionID != "" {
+		return "/sessions/" + sessionID
+	}
+	return ""
+}
+
+func (h *SessionHandler) AddSessionTools(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(h.toolListSessions(), h.handleListSessions)
+	mcpServer.AddTool(h.toolAddSession(), h.handleAddSession)
+	mcpServer.AddTool(h.toolRemoveSession(), h.handleRemoveSession)
+}
+
+func (h *SessionHandler) toolListSessions() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_list_sessions",
+		mcp.WithDescription("List the WhatsApp accounts served by this server with their device and connection state."),
+		mcp.WithTitleAnnotation("List Sessions"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *SessionHandler) handleListSessions(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resp, err := h.sessionService.ListSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(map[string]any{"sessions": resp}, fmt.Sprintf("Found %d sessions", len(resp))), nil
+}
+
+func (h *SessionHandler) toolAddSession() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_add_session",
+		mcp.WithDescription("Add a session for another WhatsApp account. Connect to /sessions/<session_id>/sse, or send the X-Session-ID header, and log in to pair it."),
+		mcp.WithTitleAnnotation("Add Session"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("session_id",
+			mcp.Description("Session ID of letters, digits, dashes and underscores, e.g. sales."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *SessionHandler) handleAddSession(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sessionID, err := request.RequireString("session_id")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.sessionService.AddSession(ctx, domainSession.AddSessionRequest{SessionID: sessionID})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Added session %s", resp.ID)), nil
+}
+
+func (h *SessionHandler) toolRemoveSession() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_remove_session",
+		mcp.WithDescription("Log out a session and remove it together with its stored chats and messages. The default session cannot be removed."),
+		mcp.WithTitleAnnotation("Remove Session"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithString("session_id",
+			mcp.Description("Session ID to remove."),
+			mcp.Required(),
+		),
+	)
+}
+
+func (h *SessionHandler) handleRemoveSession(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sessionID, err := request.RequireString("session_id")
+	if err != nil {
+		return nil, err
+	}
+
+	if err = h.sessionService.RemoveSession(ctx, domainSession.SessionRequest{SessionID: sessionID}); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Removed session %s", sessionID)), nil
+}
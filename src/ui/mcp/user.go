@@ -0,0 +1,431 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type UserHandler struct {
+	userService domainUser.IUserUsecase
+}
+
+func InitMcpUser(userService domainUser.IUserUsecase) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+func (h *UserHandler) AddUserTools(mcpServer *server.MCPServer) {
+	mcpServer.AddTool(h.toolGetPrivacy(), h.handleGetPrivacy)
+	mcpServer.AddTool(h.toolSetPrivacy(), h.handleSetPrivacy)
+	mcpServer.AddTool(h.toolGetAbout(), h.handleGetAbout)
+	mcpServer.AddTool(h.toolSetAbout(), h.handleSetAbout)
+	mcpServer.AddTool(h.toolSetAvatar(), h.handleSetAvatar)
+	mcpServer.AddTool(h.toolRemoveAvatar(), h.handleRemoveAvatar)
+	mcpServer.AddTool(h.toolGetBlocklist(), h.handleGetBlocklist)
+	mcpServer.AddTool(h.toolBlockContact(), h.handleBlockContact)
+	mcpServer.AddTool(h.toolUnblockContact(), h.handleUnblockContact)
+	mcpServer.AddTool(h.toolSubscribePresence(), h.handleSubscribePresence)
+	mcpServer.AddTool(h.toolGetPresence(), h.handleGetPresence)
+	mcpServer.AddTool(h.toolCheckNumbers(), h.handleCheckNumbers)
+	mcpServer.AddTool(h.toolGetNumberCheck(), h.handleGetNumberCheck)
+}
+
+func (h *UserHandler) toolGetPrivacy() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_get_privacy",
+		mcp.WithDescription("Get the privacy settings of this WhatsApp account."),
+		mcp.WithTitleAnnotation("Get Privacy Settings"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *UserHandler) handleGetPrivacy(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resp, err := h.userService.MyPrivacySetting(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, privacySummary(resp)), nil
+}
+
+func (h *UserHandler) toolSetPrivacy() mcp.Tool {
+	audience := []string{"all", "contacts", "contact_blacklist", "none"}
+
+	return mcp.NewTool(
+		"whatsapp_set_privacy",
+		mcp.WithDescription("Change privacy settings of this WhatsApp account. Only the given settings are changed."),
+		mcp.WithTitleAnnotation("Set Privacy Settings"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("last_seen",
+			mcp.Description("Who can see when you were last online."),
+			mcp.Enum(audience...),
+		),
+		mcp.WithString("online",
+			mcp.Description("Who can see when you are online; match_last_seen follows last_seen."),
+			mcp.Enum("all", "match_last_seen"),
+		),
+		mcp.WithString("profile",
+			mcp.Description("Who can see your profile photo."),
+			mcp.Enum(audience...),
+		),
+		mcp.WithString("status",
+			mcp.Description("Who can see your about text."),
+			mcp.Enum(audience...),
+		),
+		mcp.WithString("group_add",
+			mcp.Description("Who can add you to groups."),
+			mcp.Enum(audience...),
+		),
+		mcp.WithString("read_receipts",
+			mcp.Description("Whether read receipts are sent."),
+			mcp.Enum("all", "none"),
+		),
+		mcp.WithString("call_add",
+			mcp.Description("Who can call you; known silences calls from unknown numbers."),
+			mcp.Enum("all", "known"),
+		),
+		mcp.WithNumber("disappearing_timer",
+			mcp.Description("Default disappearing-message timer for new chats in seconds: 0 (off), 86400, 604800 or 7776000."),
+		),
+	)
+}
+
+func (h *UserHandler) handleSetPrivacy(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	privacyRequest := domainUser.SetPrivacySettingRequest{
+		LastSeen:     request.GetString("last_seen", ""),
+		Online:       request.GetString("online", ""),
+		Profile:      request.GetString("profile", ""),
+		Status:       request.GetString("status", ""),
+		GroupAdd:     request.GetString("group_add", ""),
+		ReadReceipts: request.GetString("read_receipts", ""),
+		CallAdd:      request.GetString("call_add", ""),
+	}
+	if _, ok := request.GetArguments()["disappearing_timer"]; ok {
+		timer := request.GetInt("disappearing_timer", 0)
+		privacyRequest.DisappearingTimer = &timer
+	}
+
+	resp, err := h.userService.SetPrivacySetting(ctx, privacyRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, "Privacy updated. "+privacySummary(resp)), nil
+}
+
+func (h *UserHandler) toolGetAbout() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_get_about",
+		mcp.WithDescription("Get the about text shown on this account's profile."),
+		mcp.WithTitleAnnotation("Get About"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *UserHandler) handleGetAbout(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resp, err := h.userService.MyAbout(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("About: %s", resp.About)), nil
+}
+
+func (h *UserHandler) toolSetAbout() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_set_about",
+		mcp.WithDescription("Change the about text shown on this account's profile."),
+		mcp.WithTitleAnnotation("Set About"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("about",
+			mcp.Required(),
+			mcp.Description("New about text, up to 139 characters."),
+		),
+	)
+}
+
+func (h *UserHandler) handleSetAbout(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	about, err := request.RequireString("about")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.userService.ChangeAbout(ctx, domainUser.ChangeAboutRequest{About: about}); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText("About updated"), nil
+}
+
+func (h *UserHandler) toolSetAvatar() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_set_avatar",
+		mcp.WithDescription("Set this account's profile photo from an image URL. The image is cropped to a square and compressed."),
+		mcp.WithTitleAnnotation("Set Avatar"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("avatar_url",
+			mcp.Required(),
+			mcp.Description("URL of a JPEG, PNG or WebP image."),
+		),
+	)
+}
+
+func (h *UserHandler) handleSetAvatar(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	avatarURL, err := request.RequireString("avatar_url")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.userService.ChangeAvatar(ctx, domainUser.ChangeAvatarRequest{AvatarURL: avatarURL}); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText("Avatar updated"), nil
+}
+
+func (h *UserHandler) toolRemoveAvatar() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_remove_avatar",
+		mcp.WithDescription("Remove this account's profile photo."),
+		mcp.WithTitleAnnotation("Remove Avatar"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *UserHandler) handleRemoveAvatar(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := h.userService.RemoveAvatar(ctx); err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultText("Avatar removed"), nil
+}
+
+func (h *UserHandler) toolGetBlocklist() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_get_blocklist",
+		mcp.WithDescription("List the contacts blocked by this WhatsApp account."),
+		mcp.WithTitleAnnotation("Get Block List"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+	)
+}
+
+func (h *UserHandler) handleGetBlocklist(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resp, err := h.userService.MyBlocklist(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("%d blocked contacts", len(resp.Data))), nil
+}
+
+func (h *UserHandler) toolBlockContact() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_block_contact",
+		mcp.WithDescription("Block a contact so they can no longer message or call this account."),
+		mcp.WithTitleAnnotation("Block Contact"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("phone",
+			mcp.Required(),
+			mcp.Description("Phone number or JID of the contact to block."),
+		),
+	)
+}
+
+func (h *UserHandler) handleBlockContact(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	phone, err := request.RequireString("phone")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.userService.BlockContact(ctx, domainUser.BlockContactRequest{Phone: phone})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Blocked %s. %d blocked contacts", phone, len(resp.Data))), nil
+}
+
+func (h *UserHandler) toolUnblockContact() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_unblock_contact",
+		mcp.WithDescription("Unblock a previously blocked contact."),
+		mcp.WithTitleAnnotation("Unblock Contact"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("phone",
+			mcp.Required(),
+			mcp.Description("Phone number or JID of the contact to unblock."),
+		),
+	)
+}
+
+func (h *UserHandler) handleUnblockContact(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	phone, err := request.RequireString("phone")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.userService.UnblockContact(ctx, domainUser.BlockContactRequest{Phone: phone})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Unblocked %s. %d blocked contacts", phone, len(resp.Data))), nil
+}
+
+func (h *UserHandler) toolSubscribePresence() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_subscribe_presence",
+		mcp.WithDescription("Subscribe to the online/offline presence of contacts. Subscriptions are renewed after every reconnect."),
+		mcp.WithTitleAnnotation("Subscribe Presence"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithArray("phones",
+			mcp.Description("Phone numbers or JIDs of the contacts to follow."),
+			mcp.Required(),
+			mcp.WithStringItems(),
+		),
+	)
+}
+
+func (h *UserHandler) handleSubscribePresence(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	phones, err := request.RequireStringSlice("phones")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.userService.SubscribePresence(ctx, domainUser.PresenceSubscriptionRequest{Phones: phones})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Subscribed to presence of %d contacts", len(resp.Data))), nil
+}
+
+func (h *UserHandler) toolGetPresence() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_get_presence",
+		mcp.WithDescription("Get whether contacts are online and when they were last seen. With a phone the online/offline history of that contact is included."),
+		mcp.WithTitleAnnotation("Get Presence"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("phone",
+			mcp.Description("Phone number or JID of a single contact. Leave empty to list every followed contact."),
+		),
+		mcp.WithNumber("history_limit",
+			mcp.Description("Maximum presence changes returned for the contact, 1-500 (default 50)."),
+		),
+	)
+}
+
+func (h *UserHandler) handleGetPresence(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resp, err := h.userService.Presence(ctx, domainUser.PresenceRequest{
+		Phone:        request.GetString("phone", ""),
+		HistoryLimit: request.GetInt("history_limit", 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	online := 0
+	for _, item := range resp.Data {
+		if item.Status == "online" {
+			online++
+		}
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("%d of %d contacts online", online, len(resp.Data))), nil
+}
+
+func (h *UserHandler) toolCheckNumbers() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_check_numbers",
+		mcp.WithDescription("Start a background check of which phone numbers are registered on WhatsApp. Recently checked numbers are answered from the cache. Poll the result with whatsapp_get_number_check."),
+		mcp.WithTitleAnnotation("Check Numbers"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithArray("phones",
+			mcp.Description("Phone numbers with country code to check."),
+			mcp.Required(),
+			mcp.WithStringItems(),
+		),
+		mcp.WithNumber("delay_seconds",
+			mcp.Description("Pause between queries sent to WhatsApp, 1-60 seconds (default 3)."),
+		),
+	)
+}
+
+func (h *UserHandler) handleCheckNumbers(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	phones, err := request.RequireStringSlice("phones")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.userService.BulkCheck(ctx, domainUser.BulkCheckRequest{
+		Phones:       phones,
+		DelaySeconds: request.GetInt("delay_seconds", 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Check %s of %d numbers started", resp.JobID, resp.Total)), nil
+}
+
+func (h *UserHandler) toolGetNumberCheck() mcp.Tool {
+	return mcp.NewTool(
+		"whatsapp_get_number_check",
+		mcp.WithDescription("Get the progress and results of a number check started with whatsapp_check_numbers, including JID, LID and business details."),
+		mcp.WithTitleAnnotation("Get Number Check"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithString("job_id",
+			mcp.Required(),
+			mcp.Description("Job ID returned by whatsapp_check_numbers."),
+		),
+	)
+}
+
+func (h *UserHandler) handleGetNumberCheck(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	jobID, err := request.RequireString("job_id")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.userService.GetBulkCheck(ctx, domainUser.GetBulkCheckRequest{JobID: jobID})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewToolResultStructured(resp, fmt.Sprintf("Check %s is %s: %d of %d checked, %d registered, %d not registered, %d failed",
+		resp.JobID, resp.Status, resp.Checked, resp.Total, resp.Registered, resp.NotRegistered, resp.Failed)), nil
+}
+
+func privacySummary(settings domainUser.MyPrivacySettingResponse) string {
+	return fmt.Sprintf("last_seen=%s, online=%s, profile=%s, status=%s, group_add=%s, read_receipts=%s, call_add=%s",
+		settings.LastSeen, settings.Online, settings.Profile, settings.Status, settings.GroupAdd, settings.ReadReceipts, settings.CallAdd)
+}
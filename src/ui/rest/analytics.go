@@ -0,0 +1,47 @@
+package rest
+
+import (
+	domainAnalytics "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/analytics"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Analytics struct {
+	Service domainAnalytics.IAnalyticsUsecase
+}
+
+func InitRestAnalytics(app fiber.Router, service domainAnalytics.IAnalyticsUsecase) Analytics {
+	rest := Analytics{Service: service}
+
+	app.Get("/analytics", rest.GetAnalytics)
+
+	return rest
+}
+
+func (controller *Analytics) GetAnalytics(c *fiber.Ctx) error {
+	var request domainAnalytics.GetAnalyticsRequest
+
+	// Parse query parameters
+	request.ChatJID = c.Query("chat_jid", "")
+	request.Period = c.Query("period", "")
+	request.Granularity = c.Query("granularity", "")
+	request.Limit = c.QueryInt("limit", 10)
+
+	// Parse time filters
+	if startTime := c.Query("start_time"); startTime != "" {
+		request.StartTime = &startTime
+	}
+	if endTime := c.Query("end_time"); endTime != "" {
+		request.EndTime = &endTime
+	}
+
+	response, err := controller.Service.GetAnalytics(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get conversation analytics",
+		Results: response,
+	})
+}
@@ -1,7 +1,10 @@
 package rest
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
@@ -17,11 +20,13 @@ type App struct {
 func InitRestApp(app fiber.Router, service domainApp.IAppUsecase) App {
 	rest := App{Service: service}
 	app.Get("/app/login", rest.Login)
+	app.Get("/app/login/events", rest.LoginEvents)
 	app.Get("/app/login-with-code", rest.LoginWithCode)
 	app.Get("/app/logout", rest.Logout)
 	app.Get("/app/reconnect", rest.Reconnect)
 	app.Get("/app/devices", rest.Devices)
 	app.Get("/app/status", rest.ConnectionStatus)
+	app.Post("/app/backup", rest.Backup)
 
 	return App{Service: service}
 }
@@ -41,6 +46,62 @@ func (handler *App) Login(c *fiber.Ctx) error {
 	})
 }
 
+// LoginEvents streams every QR code, the timeout, success and failure of a login as server-sent
+// events until the login ends. With start=true it starts a QR login itself, so a client can pair
+// with this single request.
+func (handler *App) LoginEvents(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	events, unsubscribe := whatsapp.SubscribeLoginEvents(ctx)
+
+	if c.QueryBool("start") {
+		if _, err := handler.Service.Login(ctx); err != nil {
+			unsubscribe()
+			utils.PanicIfNeeded(err)
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		// Send the headers right away so clients know the stream is open
+		fmt.Fprint(w, ": connected\n\n")
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		keepAlive := time.NewTicker(15 * time.Second)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				payload, err := json.Marshal(event)
+				if err != nil {
+					return
+				}
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Event, payload)
+				if err = w.Flush(); err != nil || event.Event != domainApp.LoginEventCode {
+					return
+				}
+			case <-keepAlive.C:
+				// Comments keep proxies from closing the idle stream and detect gone clients
+				fmt.Fprint(w, ": keep-alive\n\n")
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
+
 func (handler *App) LoginWithCode(c *fiber.Ctx) error {
 	pairCode, err := handler.Service.LoginWithCode(c.UserContext(), c.Query("phone"))
 	utils.PanicIfNeeded(err)
@@ -92,16 +153,48 @@ func (handler *App) Devices(c *fiber.Ctx) error {
 }
 
 func (handler *App) ConnectionStatus(c *fiber.Ctx) error {
-	isConnected, isLoggedIn, deviceID := whatsapp.GetConnectionStatus()
+	isConnected, isLoggedIn, deviceID := whatsapp.GetConnectionStatus(c.UserContext())
+	history := whatsapp.ConnectionHistory(c.UserContext())
+
+	results := map[string]any{
+		"is_connected": isConnected,
+		"is_logged_in": isLoggedIn,
+		"device_id":    deviceID,
+		"history":      history,
+	}
+	if len(history) > 0 {
+		results["state"] = history[0]
+	}
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
 		Message: "Connection status retrieved",
-		Results: map[string]any{
-			"is_connected": isConnected,
-			"is_logged_in": isLoggedIn,
-			"device_id":    deviceID,
-		},
+		Results: results,
+	})
+}
+
+func (handler *App) Backup(c *fiber.Ctx) error {
+	var request domainApp.BackupRequest
+	if len(c.Body()) > 0 {
+		err := c.BodyParser(&request)
+		utils.PanicIfNeeded(err)
+	}
+	if c.Query("include_media") != "" {
+		request.IncludeMedia = c.QueryBool("include_media")
+	}
+
+	response, err := handler.Service.Backup(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	if c.QueryBool("download", false) {
+		return c.Download(response.Path, response.Filename)
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Backup created",
+		Results: response,
 	})
 }
@@ -29,6 +29,8 @@ func (controller *Chat) ListChats(c *fiber.Ctx) error {
 	request.Offset = c.QueryInt("offset", 0)
 	request.Search = c.Query("search", "")
 	request.HasMedia = c.QueryBool("has_media", false)
+	request.Before = c.Query("before", "")
+	request.After = c.Query("after", "")
 
 	response, err := controller.Service.ListChats(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -52,6 +54,8 @@ func (controller *Chat) GetChatMessages(c *fiber.Ctx) error {
 	request.Offset = c.QueryInt("offset", 0)
 	request.MediaOnly = c.QueryBool("media_only", false)
 	request.Search = c.Query("search", "")
+	request.Before = c.Query("before", "")
+	request.After = c.Query("after", "")
 
 	// Parse time filters
 	if startTime := c.Query("start_time"); startTime != "" {
@@ -0,0 +1,142 @@
+package rest
+
+import (
+	"fmt"
+
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Contact struct {
+	Service domainContact.IContactUsecase
+}
+
+func InitRestContact(app fiber.Router, service domainContact.IContactUsecase) Contact {
+	rest := Contact{Service: service}
+	app.Get("/contacts", rest.ListContacts)
+	app.Post("/contacts", rest.CreateContact)
+	app.Get("/contacts/export", rest.ExportContacts)
+	app.Post("/contacts/import", rest.ImportContacts)
+	app.Get("/contacts/:phone", rest.GetContact)
+	app.Post("/contacts/:phone", rest.UpdateContact)
+	app.Post("/contacts/:phone/delete", rest.DeleteContact)
+	return rest
+}
+
+func (controller *Contact) ListContacts(c *fiber.Ctx) error {
+	var request domainContact.ListContactsRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.ListContacts(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success get %d contacts", len(response.Data)),
+		Results: response,
+	})
+}
+
+func (controller *Contact) GetContact(c *fiber.Ctx) error {
+	request := domainContact.GetContactRequest{Phone: c.Params("phone")}
+	utils.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.GetContact(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get contact",
+		Results: response,
+	})
+}
+
+func (controller *Contact) CreateContact(c *fiber.Ctx) error {
+	var request domainContact.ContactRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.CreateContact(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success saved contact %s", response.JID),
+		Results: response,
+	})
+}
+
+func (controller *Contact) UpdateContact(c *fiber.Ctx) error {
+	var request domainContact.UpdateContactRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.Phone = c.Params("phone")
+	utils.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.UpdateContact(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success updated contact %s", response.JID),
+		Results: response,
+	})
+}
+
+func (controller *Contact) DeleteContact(c *fiber.Ctx) error {
+	request := domainContact.GetContactRequest{Phone: c.Params("phone")}
+	utils.SanitizePhone(&request.Phone)
+
+	err := controller.Service.DeleteContact(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success deleted contact %s", request.Phone),
+	})
+}
+
+func (controller *Contact) ImportContacts(c *fiber.Ctx) error {
+	var request domainContact.ImportContactsRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	if file, err := c.FormFile("file"); err == nil {
+		request.File = file
+	}
+
+	response, err := controller.Service.ImportContacts(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Imported %d contacts, updated %d, skipped %d", response.Created, response.Updated, response.Skipped),
+		Results: response,
+	})
+}
+
+// ExportContacts downloads the address book as a CSV or vCard file that ImportContacts accepts
+func (controller *Contact) ExportContacts(c *fiber.Ctx) error {
+	var request domainContact.ExportContactsRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.ExportContacts(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	c.Attachment(response.FileName)
+	c.Set(fiber.HeaderContentType, response.ContentType)
+
+	return c.Send(response.Content)
+}
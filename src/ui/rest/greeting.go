@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"fmt"
+
+	domainGreeting "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/greeting"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Greeting struct {
+	Service domainGreeting.IGreetingUsecase
+}
+
+func InitRestGreeting(app fiber.Router, service domainGreeting.IGreetingUsecase) Greeting {
+	rest := Greeting{Service: service}
+	app.Get("/group/greetings", rest.ListGreetings)
+	app.Get("/group/greeting", rest.GetGreeting)
+	app.Post("/group/greeting", rest.SetGreeting)
+	app.Post("/group/greeting/delete", rest.DeleteGreeting)
+	return rest
+}
+
+func (controller *Greeting) ListGreetings(c *fiber.Ctx) error {
+	response, err := controller.Service.ListGreetings(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get group greetings",
+		Results: response,
+	})
+}
+
+func (controller *Greeting) GetGreeting(c *fiber.Ctx) error {
+	var request domainGreeting.GetGreetingRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	response, err := controller.Service.GetGreeting(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get group greeting",
+		Results: response,
+	})
+}
+
+// SetGreeting accepts JSON, or a multipart form when a rules document is uploaded
+func (controller *Greeting) SetGreeting(c *fiber.Ctx) error {
+	var request domainGreeting.SetGreetingRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	if file, err := c.FormFile("rules_document"); err == nil {
+		request.RulesDocument = file
+	}
+
+	response, err := controller.Service.SetGreeting(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success set greetings of group %s", response.GroupID),
+		Results: response,
+	})
+}
+
+func (controller *Greeting) DeleteGreeting(c *fiber.Ctx) error {
+	var request domainGreeting.DeleteGreetingRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	err = controller.Service.DeleteGreeting(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success deleted greetings of group %s", request.GroupID),
+	})
+}
@@ -3,8 +3,10 @@ package rest
 import (
 	"bytes"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v2"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 )
 
 type Group struct {
@@ -27,6 +30,8 @@ func InitRestGroup(app fiber.Router, service domainGroup.IGroupUsecase) Group {
 	app.Post("/group/leave", rest.LeaveGroup)
 	app.Get("/group/participants", rest.ListParticipants)
 	app.Get("/group/participants/export", rest.ExportParticipants)
+	app.Post("/group/participants/import", rest.ImportParticipants)
+	app.Get("/group/participants/import", rest.GetImportReport)
 	app.Post("/group/participants", rest.AddParticipants)
 	app.Post("/group/participants/remove", rest.DeleteParticipants)
 	app.Post("/group/participants/promote", rest.PromoteParticipants)
@@ -39,7 +44,21 @@ func InitRestGroup(app fiber.Router, service domainGroup.IGroupUsecase) Group {
 	app.Post("/group/locked", rest.SetGroupLocked)
 	app.Post("/group/announce", rest.SetGroupAnnounce)
 	app.Post("/group/topic", rest.SetGroupTopic)
+	app.Post("/group/description", rest.SetGroupDescription)
+	app.Post("/group/member-add-mode", rest.SetGroupMemberAddMode)
+	app.Post("/group/join-approval", rest.SetGroupJoinApproval)
+	app.Post("/group/disappearing", rest.SetGroupDisappearing)
 	app.Get("/group/invite-link", rest.GetGroupInviteLink)
+	app.Get("/group/history", rest.GetGroupHistory)
+	app.Get("/group/history/membership", rest.GetGroupMembership)
+	app.Get("/group/snapshot", rest.ExportGroupSnapshot)
+	app.Post("/group/snapshot/import", rest.ImportGroupSnapshot)
+	app.Post("/group/clone", rest.CloneGroup)
+	app.Post("/community", rest.CreateCommunity)
+	app.Post("/community/link", rest.LinkGroup)
+	app.Post("/community/unlink", rest.UnlinkGroup)
+	app.Get("/community/subgroups", rest.GetSubGroups)
+	app.Post("/community/announcement", rest.SendCommunityAnnouncement)
 	return rest
 }
 
@@ -99,6 +118,8 @@ func (controller *Group) CreateGroup(c *fiber.Ctx) error {
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
 
+	utils.SanitizePhone(&request.CommunityID)
+
 	groupID, err := controller.Service.CreateGroup(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
@@ -309,7 +330,7 @@ func (controller *Group) SetGroupPhoto(c *fiber.Ctx) error {
 	utils.PanicIfNeeded(err)
 
 	message := "Success update group photo"
-	if request.Photo == nil {
+	if request.Photo == nil && request.PhotoURL == "" {
 		message = "Success remove group photo"
 	}
 
@@ -407,6 +428,95 @@ func (controller *Group) SetGroupTopic(c *fiber.Ctx) error {
 	})
 }
 
+func (controller *Group) SetGroupDescription(c *fiber.Ctx) error {
+	var request domainGroup.SetGroupDescriptionRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	response, err := controller.Service.SetGroupDescription(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	message := "Success update group description"
+	if request.Description == "" {
+		message = "Success remove group description"
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: message,
+		Results: response,
+	})
+}
+
+func (controller *Group) SetGroupMemberAddMode(c *fiber.Ctx) error {
+	var request domainGroup.SetGroupMemberAddModeRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	err = controller.Service.SetGroupMemberAddMode(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	message := "Success allow all members to add participants"
+	if request.Mode == types.GroupMemberAddModeAdmin {
+		message = "Success allow only admins to add participants"
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: message,
+	})
+}
+
+func (controller *Group) SetGroupJoinApproval(c *fiber.Ctx) error {
+	var request domainGroup.SetGroupJoinApprovalRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	err = controller.Service.SetGroupJoinApproval(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	message := "Success disable join approval"
+	if request.Enabled {
+		message = "Success enable join approval"
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: message,
+	})
+}
+
+func (controller *Group) SetGroupDisappearing(c *fiber.Ctx) error {
+	var request domainGroup.SetGroupDisappearingRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	err = controller.Service.SetGroupDisappearing(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	message := fmt.Sprintf("Success set disappearing messages to %d seconds", request.Timer)
+	if request.Timer == 0 {
+		message = "Success turn off disappearing messages"
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: message,
+	})
+}
+
 // GroupInfo handles the /group/info endpoint to fetch group information
 func (controller *Group) GroupInfo(c *fiber.Ctx) error {
 	var request domainGroup.GroupInfoRequest
@@ -436,10 +546,273 @@ func (controller *Group) GetGroupInviteLink(c *fiber.Ctx) error {
 	response, err := controller.Service.GetGroupInviteLink(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
+	message := "Success get group invite link"
+	if request.Reset {
+		message = "Success reset group invite link"
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: message,
+		Results: response,
+	})
+}
+
+func (controller *Group) CreateCommunity(c *fiber.Ctx) error {
+	var request domainGroup.CreateCommunityRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.CreateCommunity(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success created community with id %s", response.CommunityID),
+		Results: response,
+	})
+}
+
+func (controller *Group) LinkGroup(c *fiber.Ctx) error {
+	var request domainGroup.LinkGroupRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.CommunityID)
+	utils.SanitizePhone(&request.GroupID)
+
+	err = controller.Service.LinkGroup(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success link group to community",
+	})
+}
+
+func (controller *Group) UnlinkGroup(c *fiber.Ctx) error {
+	var request domainGroup.LinkGroupRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.CommunityID)
+	utils.SanitizePhone(&request.GroupID)
+
+	err = controller.Service.UnlinkGroup(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success unlink group from community",
+	})
+}
+
+func (controller *Group) GetSubGroups(c *fiber.Ctx) error {
+	var request domainGroup.GetSubGroupsRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.CommunityID)
+
+	response, err := controller.Service.GetSubGroups(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get community subgroups",
+		Results: response,
+	})
+}
+
+func (controller *Group) SendCommunityAnnouncement(c *fiber.Ctx) error {
+	var request domainGroup.SendCommunityAnnouncementRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.CommunityID)
+
+	response, err := controller.Service.SendCommunityAnnouncement(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
+func (controller *Group) GetGroupHistory(c *fiber.Ctx) error {
+	var request domainGroup.GetGroupHistoryRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+	utils.SanitizePhone(&request.Participant)
+	utils.SanitizePhone(&request.Actor)
+
+	response, err := controller.Service.GetGroupHistory(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get group history",
+		Results: response,
+	})
+}
+
+func (controller *Group) GetGroupMembership(c *fiber.Ctx) error {
+	var request domainGroup.GetGroupMembershipRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	response, err := controller.Service.GetGroupMembership(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get group membership",
+		Results: response,
+	})
+}
+
+func (controller *Group) ImportParticipants(c *fiber.Ctx) error {
+	var request domainGroup.ImportParticipantsRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	if file, err := c.FormFile("file"); err == nil {
+		request.File = file
+	}
+
+	response, err := controller.Service.ImportParticipants(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Import of %d participants started", response.Total),
+		Results: response,
+	})
+}
+
+// GetImportReport returns the report as JSON, or as a CSV download with ?format=csv
+func (controller *Group) GetImportReport(c *fiber.Ctx) error {
+	var request domainGroup.GetImportReportRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.GetImportReport(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	if c.Query("format") != "csv" {
+		return c.JSON(utils.ResponseData{
+			Status:  200,
+			Code:    "SUCCESS",
+			Message: "Success get import report",
+			Results: response,
+		})
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	utils.PanicIfNeeded(writer.Write([]string{"phone", "jid", "status", "message", "invited_at", "joined_at"}))
+
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+
+	for _, entry := range response.Entries {
+		record := []string{
+			entry.Phone,
+			entry.JID,
+			entry.Status,
+			entry.Message,
+			formatTime(entry.InvitedAt),
+			formatTime(entry.JoinedAt),
+		}
+
+		utils.PanicIfNeeded(writer.Write(record))
+	}
+
+	writer.Flush()
+	utils.PanicIfNeeded(writer.Error())
+
+	c.Type("text/csv; charset=utf-8")
+	c.Attachment(fmt.Sprintf("group-import-%s.csv", response.ImportID))
+
+	return c.Send(buffer.Bytes())
+}
+
+// ExportGroupSnapshot downloads the snapshot as a JSON file that ImportGroupSnapshot accepts
+func (controller *Group) ExportGroupSnapshot(c *fiber.Ctx) error {
+	var request domainGroup.ExportGroupSnapshotRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	snapshot, err := controller.Service.ExportGroupSnapshot(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	utils.PanicIfNeeded(err)
+
+	c.Type("json", "utf-8")
+	c.Attachment(fmt.Sprintf("group-%s-snapshot.json", strings.ReplaceAll(snapshot.SourceGroupID, "@", "_")))
+
+	return c.Send(content)
+}
+
+func (controller *Group) ImportGroupSnapshot(c *fiber.Ctx) error {
+	var request domainGroup.ImportGroupSnapshotRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	if file, err := c.FormFile("file"); err == nil {
+		request.File = file
+	}
+
+	response, err := controller.Service.ImportGroupSnapshot(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success created group %s from snapshot", response.GroupID),
+		Results: response,
+	})
+}
+
+func (controller *Group) CloneGroup(c *fiber.Ctx) error {
+	var request domainGroup.CloneGroupRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	response, err := controller.Service.CloneGroup(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Success get group invite link",
+		Message: fmt.Sprintf("Success cloned group %s into %s", request.GroupID, response.GroupID),
 		Results: response,
 	})
 }
@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Health struct {
+	Ready func() bool
+}
+
+// InitRestHealth registers the liveness and readiness probes. Register them before authentication
+// so orchestrators and load balancers can reach them without credentials.
+func InitRestHealth(app fiber.Router, ready func() bool) Health {
+	rest := Health{Ready: ready}
+	app.Get("/healthz", rest.Live)
+	app.Get("/readyz", rest.Readiness)
+	return rest
+}
+
+func (handler *Health) Live(c *fiber.Ctx) error {
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Alive",
+	})
+}
+
+func (handler *Health) Readiness(c *fiber.Ctx) error {
+	if !handler.Ready() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(utils.ResponseData{
+			Status:  fiber.StatusServiceUnavailable,
+			Code:    "NOT_READY",
+			Message: "Not accepting traffic, starting or shutting down",
+		})
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Ready",
+	})
+}
@@ -6,24 +6,25 @@ import (
 	"time"
 
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
-	"go.mau.fi/whatsmeow"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 )
 
+// SetAutoConnectAfterBooting connects the default session and every other session that is paired.
+// Once connected, dropped connections are reconnected by the connection supervisor.
 func SetAutoConnectAfterBooting(service domainApp.IAppUsecase) {
 	time.Sleep(2 * time.Second)
-	_ = service.Reconnect(context.Background())
-}
-
-func SetAutoReconnectChecking(cli *whatsmeow.Client) {
-	// Run every 5 minutes to check if the connection is still alive, if not, reconnect
-	go func() {
-		for {
-			time.Sleep(5 * time.Minute)
-			if !cli.IsConnected() {
-				_ = cli.Connect()
-			}
+	for _, sessionID := range whatsapp.SessionIDs() {
+		ctx := domainSession.NewContext(context.Background(), sessionID)
+		cli := whatsapp.ClientFromContext(ctx)
+		paired := cli != nil && cli.Store.ID != nil
+		if sessionID != domainSession.DefaultSessionID && !paired {
+			continue
+		}
+		if err := service.Reconnect(ctx); err != nil && paired {
+			whatsapp.ScheduleReconnect(ctx, err)
 		}
-	}()
+	}
 }
 
 func MultipartFormFileHeaderToBytes(fileHeader *multipart.FileHeader) []byte {
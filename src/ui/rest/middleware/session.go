@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Session selects the WhatsApp session of a request, either with the X-Session-ID header or by
+// prefixing any route with /sessions/<session_id>, e.g. /sessions/sales/send/message. Requests
+// without either use the default session.
+func Session(basePath string) fiber.Handler {
+	prefix := basePath + "/sessions/"
+
+	return func(c *fiber.Ctx) error {
+		sessionID := c.Get(domainSession.HeaderSessionID)
+
+		if rest, ok := strings.CutPrefix(c.Path(), prefix); ok {
+			// /sessions/<session_id>/delete is the session management route itself
+			if id, route, found := strings.Cut(rest, "/"); found && route != "" && route != "delete" {
+				// The path shares fiber's buffer which the rewrite overwrites
+				sessionID = strings.Clone(id)
+				c.Path(basePath + "/" + route)
+			}
+		}
+
+		if sessionID == "" {
+			return c.Next()
+		}
+		if !whatsapp.HasSession(sessionID) {
+			utils.PanicIfNeeded(pkgError.ValidationError(fmt.Sprintf("session %s not found", sessionID)))
+		}
+
+		c.SetUserContext(domainSession.NewContext(c.UserContext(), sessionID))
+		return c.Next()
+	}
+}
@@ -0,0 +1,112 @@
+package rest
+
+import (
+	"fmt"
+
+	domainModeration "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/moderation"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Moderation struct {
+	Service domainModeration.IModerationUsecase
+}
+
+func InitRestModeration(app fiber.Router, service domainModeration.IModerationUsecase) Moderation {
+	rest := Moderation{Service: service}
+	app.Get("/moderation/rules", rest.ListRules)
+	app.Post("/moderation/rules", rest.CreateRule)
+	app.Post("/moderation/rules/:rule_id", rest.UpdateRule)
+	app.Post("/moderation/rules/:rule_id/delete", rest.DeleteRule)
+	app.Get("/moderation/audit", rest.GetAudit)
+	return rest
+}
+
+func (controller *Moderation) ListRules(c *fiber.Ctx) error {
+	var request domainModeration.ListRulesRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	response, err := controller.Service.ListRules(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get moderation rules",
+		Results: response,
+	})
+}
+
+func (controller *Moderation) CreateRule(c *fiber.Ctx) error {
+	var request domainModeration.RuleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	response, err := controller.Service.CreateRule(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success created moderation rule %d", response.ID),
+		Results: response,
+	})
+}
+
+func (controller *Moderation) UpdateRule(c *fiber.Ctx) error {
+	var request domainModeration.RuleRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	ruleID, err := c.ParamsInt("rule_id")
+	utils.PanicIfNeeded(err)
+	request.RuleID = int64(ruleID)
+	utils.SanitizePhone(&request.GroupID)
+
+	response, err := controller.Service.UpdateRule(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success updated moderation rule %d", response.ID),
+		Results: response,
+	})
+}
+
+func (controller *Moderation) DeleteRule(c *fiber.Ctx) error {
+	ruleID, err := c.ParamsInt("rule_id")
+	utils.PanicIfNeeded(err)
+
+	err = controller.Service.DeleteRule(c.UserContext(), domainModeration.DeleteRuleRequest{RuleID: int64(ruleID)})
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success deleted moderation rule %d", ruleID),
+	})
+}
+
+func (controller *Moderation) GetAudit(c *fiber.Ctx) error {
+	var request domainModeration.GetAuditRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	response, err := controller.Service.GetAudit(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get moderation audit",
+		Results: response,
+	})
+}
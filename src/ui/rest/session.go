@@ -0,0 +1,79 @@
+package rest
+
+import (
+	"fmt"
+
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Session struct {
+	Service domainSession.ISessionUsecase
+}
+
+func InitRestSession(app fiber.Router, service domainSession.ISessionUsecase) Session {
+	rest := Session{Service: service}
+	app.Get("/sessions", rest.ListSessions)
+	app.Post("/sessions", rest.AddSession)
+	app.Get("/sessions/:session_id", rest.GetSession)
+	app.Post("/sessions/:session_id/delete", rest.RemoveSession)
+	return rest
+}
+
+func (controller *Session) ListSessions(c *fiber.Ctx) error {
+	response, err := controller.Service.ListSessions(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success get %d sessions", len(response)),
+		Results: response,
+	})
+}
+
+func (controller *Session) GetSession(c *fiber.Ctx) error {
+	request := domainSession.SessionRequest{SessionID: c.Params("session_id")}
+
+	response, err := controller.Service.GetSession(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get session",
+		Results: response,
+	})
+}
+
+// AddSession creates an unpaired session. Log in with the X-Session-ID header set to the new
+// session, or under /sessions/<session_id>/app/login, to pair it.
+func (controller *Session) AddSession(c *fiber.Ctx) error {
+	var request domainSession.AddSessionRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.AddSession(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success added session %s", response.ID),
+		Results: response,
+	})
+}
+
+func (controller *Session) RemoveSession(c *fiber.Ctx) error {
+	request := domainSession.SessionRequest{SessionID: c.Params("session_id")}
+
+	err := controller.Service.RemoveSession(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success removed session %s", request.SessionID),
+	})
+}
@@ -1,6 +1,12 @@
 package rest
 
 import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v2"
@@ -15,12 +21,24 @@ func InitRestUser(app fiber.Router, service domainUser.IUserUsecase) User {
 	app.Get("/user/info", rest.UserInfo)
 	app.Get("/user/avatar", rest.UserAvatar)
 	app.Post("/user/avatar", rest.UserChangeAvatar)
+	app.Post("/user/avatar/remove", rest.UserRemoveAvatar)
+	app.Get("/user/my/about", rest.UserMyAbout)
+	app.Post("/user/my/about", rest.UserChangeAbout)
 	app.Post("/user/pushname", rest.UserChangePushName)
 	app.Get("/user/my/privacy", rest.UserMyPrivacySetting)
+	app.Post("/user/my/privacy", rest.UserSetPrivacySetting)
+	app.Get("/user/blocklist", rest.UserBlocklist)
+	app.Post("/user/blocklist/block", rest.UserBlockContact)
+	app.Post("/user/blocklist/unblock", rest.UserUnblockContact)
+	app.Get("/user/presence", rest.UserPresence)
+	app.Post("/user/presence/subscribe", rest.UserSubscribePresence)
+	app.Post("/user/presence/unsubscribe", rest.UserUnsubscribePresence)
 	app.Get("/user/my/groups", rest.UserMyListGroups)
 	app.Get("/user/my/newsletters", rest.UserMyListNewsletter)
 	app.Get("/user/my/contacts", rest.UserMyListContacts)
 	app.Get("/user/check", rest.UserCheck)
+	app.Post("/user/check/bulk", rest.UserBulkCheck)
+	app.Get("/user/check/bulk", rest.UserGetBulkCheck)
 	app.Get("/user/business-profile", rest.UserBusinessProfile)
 
 	return rest
@@ -67,8 +85,10 @@ func (controller *User) UserChangeAvatar(c *fiber.Ctx) error {
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
 
-	request.Avatar, err = c.FormFile("avatar")
-	utils.PanicIfNeeded(err)
+	// The avatar is either uploaded or given as avatar_url
+	if file, err := c.FormFile("avatar"); err == nil {
+		request.Avatar = file
+	}
 
 	err = controller.Service.ChangeAvatar(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
@@ -80,6 +100,44 @@ func (controller *User) UserChangeAvatar(c *fiber.Ctx) error {
 	})
 }
 
+func (controller *User) UserRemoveAvatar(c *fiber.Ctx) error {
+	err := controller.Service.RemoveAvatar(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success remove avatar",
+	})
+}
+
+func (controller *User) UserMyAbout(c *fiber.Ctx) error {
+	response, err := controller.Service.MyAbout(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get about",
+		Results: response,
+	})
+}
+
+func (controller *User) UserChangeAbout(c *fiber.Ctx) error {
+	var request domainUser.ChangeAboutRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	err = controller.Service.ChangeAbout(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success change about",
+	})
+}
+
 func (controller *User) UserMyPrivacySetting(c *fiber.Ctx) error {
 	response, err := controller.Service.MyPrivacySetting(c.UserContext())
 	utils.PanicIfNeeded(err)
@@ -92,6 +150,126 @@ func (controller *User) UserMyPrivacySetting(c *fiber.Ctx) error {
 	})
 }
 
+func (controller *User) UserSetPrivacySetting(c *fiber.Ctx) error {
+	var request domainUser.SetPrivacySettingRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.SetPrivacySetting(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success update privacy",
+		Results: response,
+	})
+}
+
+func (controller *User) UserBlocklist(c *fiber.Ctx) error {
+	response, err := controller.Service.MyBlocklist(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get block list",
+		Results: response,
+	})
+}
+
+func (controller *User) UserBlockContact(c *fiber.Ctx) error {
+	var request domainUser.BlockContactRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.BlockContact(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success block contact",
+		Results: response,
+	})
+}
+
+func (controller *User) UserUnblockContact(c *fiber.Ctx) error {
+	var request domainUser.BlockContactRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.UnblockContact(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success unblock contact",
+		Results: response,
+	})
+}
+
+func (controller *User) UserPresence(c *fiber.Ctx) error {
+	var request domainUser.PresenceRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.Presence(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get presence",
+		Results: response,
+	})
+}
+
+func (controller *User) UserSubscribePresence(c *fiber.Ctx) error {
+	var request domainUser.PresenceSubscriptionRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	for i := range request.Phones {
+		utils.SanitizePhone(&request.Phones[i])
+	}
+
+	response, err := controller.Service.SubscribePresence(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success subscribe presence",
+		Results: response,
+	})
+}
+
+func (controller *User) UserUnsubscribePresence(c *fiber.Ctx) error {
+	var request domainUser.PresenceSubscriptionRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	for i := range request.Phones {
+		utils.SanitizePhone(&request.Phones[i])
+	}
+
+	err = controller.Service.UnsubscribePresence(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success unsubscribe presence",
+	})
+}
 func (controller *User) UserMyListGroups(c *fiber.Ctx) error {
 	response, err := controller.Service.MyListGroups(c.UserContext())
 	utils.PanicIfNeeded(err)
@@ -159,6 +337,82 @@ func (controller *User) UserCheck(c *fiber.Ctx) error {
 	})
 }
 
+func (controller *User) UserBulkCheck(c *fiber.Ctx) error {
+	var request domainUser.BulkCheckRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	if file, err := c.FormFile("file"); err == nil {
+		request.File = file
+	}
+
+	response, err := controller.Service.BulkCheck(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Check of %d numbers started", response.Total),
+		Results: response,
+	})
+}
+
+// UserGetBulkCheck returns the check results as JSON, or as a CSV download with ?format=csv
+func (controller *User) UserGetBulkCheck(c *fiber.Ctx) error {
+	var request domainUser.GetBulkCheckRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.GetBulkCheck(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	if c.Query("format") != "csv" {
+		return c.JSON(utils.ResponseData{
+			Status:  200,
+			Code:    "SUCCESS",
+			Message: "Success get bulk check",
+			Results: response,
+		})
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+
+	utils.PanicIfNeeded(writer.Write([]string{
+		"phone", "status", "is_on_whatsapp", "jid", "lid", "is_business", "business_name", "cached", "checked_at", "message",
+	}))
+
+	for _, result := range response.Results {
+		checkedAt := ""
+		if result.CheckedAt != nil {
+			checkedAt = result.CheckedAt.Format(time.RFC3339)
+		}
+
+		record := []string{
+			result.Phone,
+			result.Status,
+			strconv.FormatBool(result.IsOnWhatsApp),
+			result.JID,
+			result.LID,
+			strconv.FormatBool(result.IsBusiness),
+			result.BusinessName,
+			strconv.FormatBool(result.Cached),
+			checkedAt,
+			result.Message,
+		}
+
+		utils.PanicIfNeeded(writer.Write(record))
+	}
+
+	writer.Flush()
+	utils.PanicIfNeeded(writer.Error())
+
+	c.Type("text/csv; charset=utf-8")
+	c.Attachment(fmt.Sprintf("number-check-%s.csv", response.JobID))
+
+	return c.Send(buffer.Bytes())
+}
+
 func (controller *User) UserBusinessProfile(c *fiber.Ctx) error {
 	var request domainUser.BusinessProfileRequest
 	err := c.QueryParser(&request)
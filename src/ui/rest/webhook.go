@@ -0,0 +1,117 @@
+package rest
+
+import (
+	"fmt"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Webhook struct {
+	Service domainWebhook.IWebhookUsecase
+}
+
+func InitRestWebhook(app fiber.Router, service domainWebhook.IWebhookUsecase) Webhook {
+	rest := Webhook{Service: service}
+	app.Get("/webhooks", rest.ListDefinitions)
+	app.Post("/webhooks", rest.CreateDefinition)
+	app.Post("/webhooks/:definition_id", rest.UpdateDefinition)
+	app.Post("/webhooks/:definition_id/delete", rest.DeleteDefinition)
+	app.Get("/webhooks/deliveries", rest.ListDeliveries)
+	app.Post("/webhooks/deliveries/:delivery_id/replay", rest.ReplayDelivery)
+	return rest
+}
+
+func (controller *Webhook) ListDefinitions(c *fiber.Ctx) error {
+	response, err := controller.Service.ListDefinitions(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhook definitions",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) CreateDefinition(c *fiber.Ctx) error {
+	var request domainWebhook.DefinitionRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.CreateDefinition(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success created webhook definition %d", response.ID),
+		Results: response,
+	})
+}
+
+func (controller *Webhook) UpdateDefinition(c *fiber.Ctx) error {
+	var request domainWebhook.DefinitionRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	definitionID, err := c.ParamsInt("definition_id")
+	utils.PanicIfNeeded(err)
+	request.DefinitionID = int64(definitionID)
+
+	response, err := controller.Service.UpdateDefinition(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success updated webhook definition %d", response.ID),
+		Results: response,
+	})
+}
+
+func (controller *Webhook) DeleteDefinition(c *fiber.Ctx) error {
+	definitionID, err := c.ParamsInt("definition_id")
+	utils.PanicIfNeeded(err)
+
+	err = controller.Service.DeleteDefinition(c.UserContext(), domainWebhook.DeleteDefinitionRequest{DefinitionID: int64(definitionID)})
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success deleted webhook definition %d", definitionID),
+	})
+}
+
+func (controller *Webhook) ListDeliveries(c *fiber.Ctx) error {
+	var request domainWebhook.ListDeliveriesRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.ListDeliveries(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhook deliveries",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) ReplayDelivery(c *fiber.Ctx) error {
+	deliveryID, err := c.ParamsInt("delivery_id")
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.ReplayDelivery(c.UserContext(), domainWebhook.ReplayDeliveryRequest{DeliveryID: int64(deliveryID)})
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success queued webhook delivery %d for replay", deliveryID),
+		Results: response,
+	})
+}
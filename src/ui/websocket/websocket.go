@@ -3,6 +3,8 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"sync"
+	"sync/atomic"
 
 	"github.com/sirupsen/logrus"
 
@@ -24,8 +26,43 @@ var (
 	Register   = make(chan *websocket.Conn)
 	Broadcast  = make(chan BroadcastMessage)
 	Unregister = make(chan *websocket.Conn)
+
+	// hubRunning is set once RunHub consumes broadcasts, which only happens in REST mode
+	hubRunning atomic.Bool
+	// hubStop asks RunHub to close every connection and return, hubStopped is closed once it did
+	hubStop     = make(chan struct{})
+	hubStopped  = make(chan struct{})
+	hubStopOnce sync.Once
 )
 
+// Publish broadcasts a message to the connected clients. Unlike sending on Broadcast directly it
+// never blocks when no hub is running, so it is safe for frequent events such as presence updates.
+func Publish(message BroadcastMessage) {
+	if hubRunning.Load() {
+		select {
+		case Broadcast <- message:
+		case <-hubStopped:
+		}
+	}
+}
+
+// StopHub closes every websocket connection and stops the hub, waiting until it stopped or ctx is done
+func StopHub(ctx context.Context) error {
+	if !hubRunning.Load() {
+		return nil
+	}
+	hubStopOnce.Do(func() {
+		close(hubStop)
+	})
+
+	select {
+	case <-hubStopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func handleRegister(conn *websocket.Conn) {
 	Clients[conn] = client{}
 	logrus.Println("connection registered")
@@ -62,8 +99,17 @@ func closeConnection(conn *websocket.Conn) {
 }
 
 func RunHub() {
+	hubRunning.Store(true)
 	for {
 		select {
+		case <-hubStop:
+			for conn := range Clients {
+				closeConnection(conn)
+			}
+			hubRunning.Store(false)
+			close(hubStopped)
+			return
+
 		case conn := <-Register:
 			handleRegister(conn)
 
@@ -87,11 +133,18 @@ func RegisterRoutes(app fiber.Router, service domainApp.IAppUsecase) {
 
 	app.Get("/ws", websocket.New(func(conn *websocket.Conn) {
 		defer func() {
-			Unregister <- conn
+			select {
+			case Unregister <- conn:
+			case <-hubStopped:
+			}
 			_ = conn.Close()
 		}()
 
-		Register <- conn
+		select {
+		case Register <- conn:
+		case <-hubStopped:
+			return
+		}
 
 		for {
 			messageType, message, err := conn.ReadMessage()
@@ -111,11 +164,11 @@ func RegisterRoutes(app fiber.Router, service domainApp.IAppUsecase) {
 
 				if messageData.Code == "FETCH_DEVICES" {
 					devices, _ := service.FetchDevices(context.Background())
-					Broadcast <- BroadcastMessage{
+					Publish(BroadcastMessage{
 						Code:    "LIST_DEVICES",
 						Message: "Device found",
 						Result:  devices,
-					}
+					})
 				}
 			} else {
 				logrus.Println("unsupported message type:", messageType)
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	domainAnalytics "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/analytics"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+type serviceAnalytics struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+func NewAnalyticsService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainAnalytics.IAnalyticsUsecase {
+	return &serviceAnalytics{
+		chatStorageRepo: chatStorageRepo,
+	}
+}
+
+func (service serviceAnalytics) GetAnalytics(ctx context.Context, request domainAnalytics.GetAnalyticsRequest) (response domainAnalytics.GetAnalyticsResponse, err error) {
+	if err = validations.ValidateGetAnalytics(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := &domainChatStorage.AnalyticsFilter{
+		ChatJID:   request.ChatJID,
+		Limit:     request.Limit,
+		SessionID: domainSession.FromContext(ctx),
+	}
+
+	if request.Period != "" {
+		start, end := resolveAnalyticsPeriod(request.Period, time.Now())
+		filter.StartTime = &start
+		filter.EndTime = &end
+	}
+	if request.StartTime != nil && *request.StartTime != "" {
+		startTime, err := time.Parse(time.RFC3339, *request.StartTime)
+		if err != nil {
+			return response, pkgError.ValidationError("start_time: must be a valid date.")
+		}
+		filter.StartTime = &startTime
+	}
+	if request.EndTime != nil && *request.EndTime != "" {
+		endTime, err := time.Parse(time.RFC3339, *request.EndTime)
+		if err != nil {
+			return response, pkgError.ValidationError("end_time: must be a valid date.")
+		}
+		filter.EndTime = &endTime
+	}
+	if filter.StartTime != nil && filter.EndTime != nil && filter.StartTime.After(*filter.EndTime) {
+		return response, pkgError.ValidationError("start_time: must be before end_time.")
+	}
+
+	response.Range = domainAnalytics.AnalyticsRange{
+		ChatJID:     request.ChatJID,
+		Period:      request.Period,
+		Granularity: request.Granularity,
+	}
+	if filter.StartTime != nil {
+		response.Range.StartTime = filter.StartTime.Format(time.RFC3339)
+	}
+	if filter.EndTime != nil {
+		response.Range.EndTime = filter.EndTime.Format(time.RFC3339)
+	}
+
+	// Message volume per bucket, which also yields inbound/outbound totals
+	bucketFormat := "%Y-%m-%d"
+	if request.Granularity == domainAnalytics.GranularityHour {
+		bucketFormat = "%Y-%m-%dT%H:00"
+	}
+	volumes, err := service.chatStorageRepo.GetMessageVolume(filter, bucketFormat)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get message volume")
+		return response, err
+	}
+	response.Volume = make([]domainAnalytics.VolumeBucket, 0, len(volumes))
+	for _, volume := range volumes {
+		response.Volume = append(response.Volume, domainAnalytics.VolumeBucket{
+			Bucket:   volume.Bucket,
+			Total:    volume.Total,
+			Inbound:  volume.Inbound,
+			Outbound: volume.Outbound,
+		})
+		response.Totals.Total += volume.Total
+		response.Totals.Inbound += volume.Inbound
+		response.Totals.Outbound += volume.Outbound
+	}
+
+	// Top chats are only meaningful when not scoped to a single chat
+	response.TopChats = []domainAnalytics.ChatActivity{}
+	if request.ChatJID == "" {
+		chats, err := service.chatStorageRepo.GetTopChats(filter)
+		if err != nil {
+			logrus.WithError(err).Error("Failed to get top chats")
+			return response, err
+		}
+		for _, chat := range chats {
+			response.TopChats = append(response.TopChats, domainAnalytics.ChatActivity{
+				ChatJID:  chat.ChatJID,
+				Name:     chat.Name,
+				Total:    chat.Total,
+				Inbound:  chat.Inbound,
+				Outbound: chat.Outbound,
+			})
+		}
+	}
+
+	senders, err := service.chatStorageRepo.GetTopSenders(filter)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get top senders")
+		return response, err
+	}
+	response.TopSenders = make([]domainAnalytics.SenderActivity, 0, len(senders))
+	for _, sender := range senders {
+		response.TopSenders = append(response.TopSenders, domainAnalytics.SenderActivity{
+			Sender:       sender.Sender,
+			MessageCount: sender.MessageCount,
+		})
+	}
+
+	responseTimes, err := service.chatStorageRepo.GetFirstResponseTimes(filter)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get first response times")
+		return response, err
+	}
+	response.ResponseTime = domainAnalytics.ResponseTime{
+		Samples:        responseTimes.Samples,
+		MedianSeconds:  responseTimes.MedianSeconds,
+		AverageSeconds: responseTimes.AverageSeconds,
+	}
+
+	media, err := service.chatStorageRepo.GetMediaVolume(filter)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to get media volume")
+		return response, err
+	}
+	response.Media = make([]domainAnalytics.MediaVolume, 0, len(media))
+	for _, item := range media {
+		response.Media = append(response.Media, domainAnalytics.MediaVolume{
+			MediaType:  item.MediaType,
+			Count:      item.Count,
+			TotalBytes: item.TotalBytes,
+		})
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"chat_jid":    request.ChatJID,
+		"period":      request.Period,
+		"granularity": request.Granularity,
+		"total":       response.Totals.Total,
+	}).Info("Computed conversation analytics successfully")
+
+	return response, nil
+}
+
+// resolveAnalyticsPeriod converts a named period into an inclusive time range relative to now.
+// Weeks start on Monday.
+func resolveAnalyticsPeriod(period string, now time.Time) (start time.Time, end time.Time) {
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	daysSinceMonday := (int(now.Weekday()) + 6) % 7
+	startOfWeek := startOfDay.AddDate(0, 0, -daysSinceMonday)
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	switch period {
+	case domainAnalytics.PeriodToday:
+		return startOfDay, now
+	case domainAnalytics.PeriodYesterday:
+		return startOfDay.AddDate(0, 0, -1), startOfDay.Add(-time.Nanosecond)
+	case domainAnalytics.PeriodLast7Days:
+		return now.AddDate(0, 0, -7), now
+	case domainAnalytics.PeriodLastWeek:
+		return startOfWeek.AddDate(0, 0, -7), startOfWeek.Add(-time.Nanosecond)
+	case domainAnalytics.PeriodThisWeek:
+		return startOfWeek, now
+	case domainAnalytics.PeriodLast30Days:
+		return now.AddDate(0, 0, -30), now
+	case domainAnalytics.PeriodThisMonth:
+		return startOfMonth, now
+	case domainAnalytics.PeriodLastMonth:
+		return startOfMonth.AddDate(0, -1, 0), startOfMonth.Add(-time.Nanosecond)
+	default:
+		return startOfDay, now
+	}
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	domainAnalytics "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/analytics"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveAnalyticsPeriod(t *testing.T) {
+	// Wednesday
+	now := time.Date(2024, 5, 15, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		period    string
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{domainAnalytics.PeriodToday, time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC), now},
+		{domainAnalytics.PeriodYesterday, time.Date(2024, 5, 14, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)},
+		{domainAnalytics.PeriodLast7Days, time.Date(2024, 5, 8, 14, 30, 0, 0, time.UTC), now},
+		{domainAnalytics.PeriodLastWeek, time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)},
+		{domainAnalytics.PeriodThisWeek, time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC), now},
+		{domainAnalytics.PeriodThisMonth, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), now},
+		{domainAnalytics.PeriodLastMonth, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			start, end := resolveAnalyticsPeriod(tt.period, now)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
+
+func TestResolveAnalyticsPeriodOnSunday(t *testing.T) {
+	now := time.Date(2024, 5, 19, 9, 0, 0, 0, time.UTC)
+
+	start, _ := resolveAnalyticsPeriod(domainAnalytics.PeriodThisWeek, now)
+	assert.Equal(t, time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC), start)
+}
+
+func TestFirstResponseTimes(t *testing.T) {
+	start := time.Date(2024, 5, 15, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		messages []bool // is_from_me, one message per minute
+		want     domainAnalytics.ResponseTime
+	}{
+		{
+			name: "no samples",
+			want: domainAnalytics.ResponseTime{},
+		},
+		{
+			name:     "unanswered run",
+			messages: []bool{true, false, false},
+			want:     domainAnalytics.ResponseTime{},
+		},
+		{
+			name:     "odd number of samples",
+			messages: []bool{false, true, false, false, true, true, false, false, false, false, true},
+			want:     domainAnalytics.ResponseTime{Samples: 3, MedianSeconds: 120, AverageSeconds: 140},
+		},
+		{
+			name:     "even number of samples",
+			messages: []bool{false, true, false, false, true, false, false, false, true, false, false, false, false, false, true},
+			want:     domainAnalytics.ResponseTime{Samples: 4, MedianSeconds: 150, AverageSeconds: 165},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestChatStorage(t)
+			service := serviceAnalytics{chatStorageRepo: repo}
+
+			chatJID := "628123456789@s.whatsapp.net"
+			require.NoError(t, repo.StoreChat(&domainChatStorage.Chat{JID: chatJID, LastMessageTime: start}))
+			for i, isFromMe := range tt.messages {
+				require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+					ID:        fmt.Sprintf("MSG%d", i),
+					ChatJID:   chatJID,
+					Sender:    chatJID,
+					Content:   "hello",
+					Timestamp: start.Add(time.Duration(i) * time.Minute),
+					IsFromMe:  isFromMe,
+				}))
+			}
+
+			response, err := service.GetAnalytics(context.Background(), domainAnalytics.GetAnalyticsRequest{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.Samples, response.ResponseTime.Samples)
+			assert.InDelta(t, tt.want.MedianSeconds, response.ResponseTime.MedianSeconds, 0.01)
+			assert.InDelta(t, tt.want.AverageSeconds, response.ResponseTime.AverageSeconds, 0.01)
+		})
+	}
+}
+
+func TestGetAnalyticsRejectsInvalidTimes(t *testing.T) {
+	service := serviceAnalytics{chatStorageRepo: newTestChatStorage(t)}
+	invalid := "yesterday at noon"
+
+	_, err := service.GetAnalytics(context.Background(), domainAnalytics.GetAnalyticsRequest{StartTime: &invalid})
+	assert.Error(t, err)
+}
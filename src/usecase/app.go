@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/backup"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
@@ -19,10 +21,12 @@ import (
 	"github.com/skip2/go-qrcode"
 	"go.mau.fi/libsignal/logger"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/store/sqlstore"
 )
 
 type serviceApp struct {
 	chatStorageRepo domainChatStorage.IChatStorageRepository
+	deviceStore     *sqlstore.Container
 }
 
 func NewAppService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainApp.IAppUsecase {
@@ -31,8 +35,16 @@ func NewAppService(chatStorageRepo domainChatStorage.IChatStorageRepository) dom
 	}
 }
 
-func (service *serviceApp) Login(_ context.Context) (response domainApp.LoginResponse, err error) {
-	client := whatsapp.GetClient()
+// NewBackupService creates the app service of the backup and restore commands. They run without
+// WhatsApp clients, so the paired device is read from deviceStore instead.
+func NewBackupService(deviceStore *sqlstore.Container) domainApp.IAppUsecase {
+	return &serviceApp{
+		deviceStore: deviceStore,
+	}
+}
+
+func (service *serviceApp) Login(ctx context.Context) (response domainApp.LoginResponse, err error) {
+	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
 	}
@@ -59,7 +71,8 @@ func (service *serviceApp) Login(_ context.Context) (response domainApp.LoginRes
 	// Disconnect for reconnecting
 	client.Disconnect()
 
-	chImage := make(chan string)
+	chLogin := make(chan domainApp.LoginResponse, 1)
+	eventCtx := context.WithoutCancel(ctx)
 
 	logrus.Info("[DEBUG] Attempting to get QR channel...")
 	ch, err := client.GetQRChannel(context.Background())
@@ -79,29 +92,25 @@ func (service *serviceApp) Login(_ context.Context) (response domainApp.LoginRes
 		}
 	} else {
 		logrus.Info("[DEBUG] QR channel obtained successfully")
+		// Every code is streamed as a login event, the first one also answers this call
 		go func() {
+			defer close(chLogin)
 			for evt := range ch {
-				response.Code = evt.Code
-				response.Duration = evt.Timeout / time.Second / 2
-				if evt.Event == "code" {
-					qrPath := fmt.Sprintf("%s/scan-qr-%s.png", config.PathQrCode, fiberUtils.UUIDv4())
-					err = qrcode.WriteFile(evt.Code, qrcode.Medium, 512, qrPath)
-					if err != nil {
-						logrus.Error("Error when write qr code to file: ", err)
-					}
-					go func() {
-						time.Sleep(response.Duration * time.Second)
-						err := os.Remove(qrPath)
-						if err != nil {
-							// Only log if it's not a "file not found" error
-							if !os.IsNotExist(err) {
-								logrus.Error("error when remove qrImage file", err.Error())
-							}
-						}
-					}()
-					chImage <- qrPath
-				} else {
-					logrus.Error("error when get qrCode", evt.Event, evt.Error)
+				if evt.Event != whatsmeow.QRChannelEventCode {
+					handleQRChannelEvent(eventCtx, evt)
+					continue
+				}
+
+				loginResponse, image := writeQRCode(evt)
+				whatsapp.PublishLoginEvent(eventCtx, domainApp.LoginEvent{
+					Event:   domainApp.LoginEventCode,
+					Code:    evt.Code,
+					Image:   image,
+					Timeout: int(evt.Timeout / time.Second),
+				})
+				select {
+				case chLogin <- loginResponse:
+				default:
 				}
 			}
 		}()
@@ -112,25 +121,77 @@ func (service *serviceApp) Login(_ context.Context) (response domainApp.LoginRes
 		logger.Error("Error when connect to whatsapp", err)
 		return response, pkgError.ErrReconnect
 	}
-	response.ImagePath = <-chImage
+	response, ok := <-chLogin
+	if !ok {
+		return response, pkgError.ErrQrChannel
+	}
 
-	// [DEBUG] Verify connection state and sync global client
+	// [DEBUG] Verify connection state
 	logrus.Infof("[DEBUG] Login connection established - IsConnected: %v, IsLoggedIn: %v",
 		client.IsConnected(), client.IsLoggedIn())
 
-	// Ensure global client is synchronized with service client
-	whatsapp.UpdateGlobalClient(client, whatsapp.GetDB())
-
 	return response, nil
 }
 
+// writeQRCode saves a QR code as PNG for the login response, which removes it again once the
+// code expired, and returns it as a data URI for the login events
+func writeQRCode(evt whatsmeow.QRChannelItem) (response domainApp.LoginResponse, image string) {
+	response.Code = evt.Code
+	response.Duration = evt.Timeout / time.Second / 2
+
+	png, err := qrcode.Encode(evt.Code, qrcode.Medium, 512)
+	if err != nil {
+		logrus.Error("Error when encode qr code: ", err)
+		return response, ""
+	}
+
+	qrPath := fmt.Sprintf("%s/scan-qr-%s.png", config.PathQrCode, fiberUtils.UUIDv4())
+	if err = os.WriteFile(qrPath, png, 0644); err != nil {
+		logrus.Error("Error when write qr code to file: ", err)
+	}
+	go func() {
+		time.Sleep(response.Duration * time.Second)
+		err := os.Remove(qrPath)
+		if err != nil {
+			// Only log if it's not a "file not found" error
+			if !os.IsNotExist(err) {
+				logrus.Error("error when remove qrImage file", err.Error())
+			}
+		}
+	}()
+
+	response.ImagePath = qrPath
+	return response, "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+}
+
+// handleQRChannelEvent streams the end of a QR login. Success is left to the PairSuccess
+// handler, which also covers pairing with a code.
+func handleQRChannelEvent(ctx context.Context, evt whatsmeow.QRChannelItem) {
+	switch evt.Event {
+	case whatsmeow.QRChannelSuccess.Event:
+		return
+	case whatsmeow.QRChannelTimeout.Event:
+		whatsapp.PublishLoginEvent(ctx, domainApp.LoginEvent{Event: domainApp.LoginEventTimeout})
+	default:
+		logrus.Error("error when get qrCode", evt.Event, evt.Error)
+		message := evt.Event
+		if evt.Error != nil {
+			message = evt.Error.Error()
+		}
+		whatsapp.PublishLoginEvent(ctx, domainApp.LoginEvent{Event: domainApp.LoginEventError, Error: message})
+	}
+}
+
 func (service *serviceApp) LoginWithCode(ctx context.Context, phoneNumber string) (loginCode string, err error) {
 	if err = validations.ValidateLoginWithCode(ctx, phoneNumber); err != nil {
 		logrus.Errorf("Error when validate login with code: %s", err.Error())
 		return loginCode, err
 	}
 
-	client := whatsapp.GetClient()
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return loginCode, pkgError.ErrWaCLI
+	}
 	// detect is already logged in
 	if client.Store.ID != nil || client.IsLoggedIn() {
 		logrus.Warn("User is already logged in")
@@ -144,7 +205,7 @@ func (service *serviceApp) LoginWithCode(ctx context.Context, phoneNumber string
 	}
 
 	// refresh client reference after reconnect
-	client = whatsapp.GetClient()
+	client = whatsapp.ClientFromContext(ctx)
 	if client.IsLoggedIn() || client.Store.ID != nil {
 		logrus.Warn("User is already logged in after reconnect")
 		return loginCode, pkgError.ErrAlreadyLoggedIn
@@ -157,13 +218,10 @@ func (service *serviceApp) LoginWithCode(ctx context.Context, phoneNumber string
 		return loginCode, err
 	}
 
-	// [DEBUG] Verify pairing state and sync global client
+	// [DEBUG] Verify pairing state
 	logrus.Infof("[DEBUG] Phone pairing completed - IsConnected: %v, IsLoggedIn: %v",
 		client.IsConnected(), client.IsLoggedIn())
 
-	// Ensure global client is synchronized with service client
-	whatsapp.UpdateGlobalClient(client, whatsapp.GetDB())
-
 	logrus.Infof("Successfully paired phone with code: %s", loginCode)
 	return loginCode, nil
 }
@@ -181,9 +239,14 @@ func (service *serviceApp) Logout(ctx context.Context) (err error) {
 		}
 	}
 
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return pkgError.ErrWaCLI
+	}
+
 	// [DEBUG] Call WhatsApp client logout first to disconnect from server
 	logrus.Info("[DEBUG] Calling WhatsApp client logout...")
-	err = whatsapp.GetClient().Logout(ctx)
+	err = client.Logout(ctx)
 	if err != nil {
 		logrus.Errorf("[DEBUG] WhatsApp logout failed: %v", err)
 		// Continue with cleanup even if logout fails
@@ -199,24 +262,23 @@ func (service *serviceApp) Logout(ctx context.Context) (err error) {
 		logrus.Infof("[DEBUG] Devices after logout: %d found", len(devices))
 	}
 
-	// Perform complete cleanup with global client synchronization
-	newDB, newCli, err := whatsapp.PerformCleanupAndUpdateGlobals(ctx, "MANUAL_LOGOUT", service.chatStorageRepo)
-	if err != nil {
+	// Clean up the session, or everything when it is the only one
+	if err = whatsapp.CleanupSession(ctx, "MANUAL_LOGOUT", service.chatStorageRepo); err != nil {
 		logrus.Errorf("[DEBUG] Cleanup failed: %v", err)
 		return err
 	}
 
-	// Update service references
-	whatsapp.UpdateGlobalClient(newCli, newDB)
-
 	logrus.Info("[DEBUG] Logout process completed successfully")
 	return nil
 }
 
-func (service *serviceApp) Reconnect(_ context.Context) (err error) {
+func (service *serviceApp) Reconnect(ctx context.Context) (err error) {
 	logrus.Info("[DEBUG] Starting reconnect process...")
 
-	client := whatsapp.GetClient()
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return pkgError.ErrWaCLI
+	}
 	client.Disconnect()
 	err = client.Connect()
 
@@ -225,39 +287,38 @@ func (service *serviceApp) Reconnect(_ context.Context) (err error) {
 		return err
 	}
 
-	// [DEBUG] Verify reconnection state and sync global client
+	// [DEBUG] Verify reconnection state
 	logrus.Infof("[DEBUG] Reconnection completed - IsConnected: %v, IsLoggedIn: %v",
 		client.IsConnected(), client.IsLoggedIn())
 
-	// Ensure global client is synchronized with service client
-	whatsapp.UpdateGlobalClient(client, whatsapp.GetDB())
-
 	logrus.Info("[DEBUG] Reconnect process completed successfully")
 	return err
 }
 
 func (service *serviceApp) FirstDevice(ctx context.Context) (response domainApp.DevicesResponse, err error) {
-	if whatsapp.GetClient() == nil {
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
 		return response, pkgError.ErrWaCLI
 	}
 
-	devices, err := whatsapp.GetDB().GetFirstDevice(ctx)
-	if err != nil {
-		return response, err
+	// The device of the selected session
+	device := client.Store
+	if device.ID == nil {
+		return response, pkgError.ErrNotLoggedIn
 	}
 
-	response.Device = devices.ID.String()
-	if devices.PushName != "" {
-		response.Name = devices.PushName
+	response.Device = device.ID.String()
+	if device.PushName != "" {
+		response.Name = device.PushName
 	} else {
-		response.Name = devices.BusinessName
+		response.Name = device.BusinessName
 	}
 
 	return response, nil
 }
 
 func (service *serviceApp) FetchDevices(ctx context.Context) (response []domainApp.DevicesResponse, err error) {
-	if whatsapp.GetClient() == nil {
+	if whatsapp.ClientFromContext(ctx) == nil {
 		return response, pkgError.ErrWaCLI
 	}
 
@@ -280,3 +341,107 @@ func (service *serviceApp) FetchDevices(ctx context.Context) (response []domainA
 
 	return response, nil
 }
+
+func (service *serviceApp) Backup(ctx context.Context, request domainApp.BackupRequest) (response domainApp.BackupResponse, err error) {
+	options := backup.Options{
+		Sources:    backupSources(),
+		OutputDir:  config.PathBackups,
+		AppVersion: config.AppVersion,
+	}
+	if request.IncludeMedia {
+		options.MediaDir = config.PathMedia
+	}
+	deviceID, err := service.pairedDeviceID(ctx)
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to read the paired device: %v", err))
+	}
+	options.DeviceID = deviceID
+
+	archivePath, manifest, err := backup.Create(ctx, options)
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to create backup: %v", err))
+	}
+
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return response, err
+	}
+
+	response.Filename = info.Name()
+	response.Path = archivePath
+	response.Size = info.Size()
+	response.Manifest = manifest
+	return response, nil
+}
+
+func (service *serviceApp) Restore(ctx context.Context, request domainApp.RestoreRequest) (response domainApp.BackupManifest, err error) {
+	if err = validations.ValidateRestore(ctx, &request); err != nil {
+		return response, err
+	}
+
+	// Refuse to overwrite a paired session unless explicitly forced
+	deviceID, err := service.pairedDeviceID(ctx)
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to read the paired device: %v", err))
+	}
+	if deviceID != "" && !request.Force {
+		return response, pkgError.ValidationError(fmt.Sprintf("device %s is already paired on this host, use force to overwrite it", deviceID))
+	}
+
+	targets := map[string]string{
+		backup.KindWhatsappDB:  config.DBURI,
+		backup.KindChatStorage: config.ChatStorageURI,
+	}
+	if config.DBKeysURI != "" {
+		targets[backup.KindKeysDB] = config.DBKeysURI
+	}
+
+	response, err = backup.Restore(ctx, backup.RestoreOptions{
+		ArchivePath:  request.ArchivePath,
+		Targets:      targets,
+		MediaDir:     config.PathMedia,
+		IncludeMedia: request.IncludeMedia,
+	})
+	if errors.Is(err, backup.ErrInvalidArchive) {
+		return response, pkgError.ValidationError(fmt.Sprintf("failed to restore backup: %v", err))
+	}
+	if err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to restore backup: %v", err))
+	}
+
+	return response, nil
+}
+
+// pairedDeviceID returns the device paired for the session selected by ctx, or the first device of
+// the device store when the service runs without clients. It is empty when nothing is paired.
+func (service *serviceApp) pairedDeviceID(ctx context.Context) (string, error) {
+	if client := whatsapp.ClientFromContext(ctx); client != nil && client.Store.ID != nil {
+		return client.Store.ID.String(), nil
+	}
+	if service.deviceStore == nil {
+		return "", nil
+	}
+
+	devices, err := service.deviceStore.GetAllDevices(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, device := range devices {
+		if device.ID != nil {
+			return device.ID.String(), nil
+		}
+	}
+	return "", nil
+}
+
+// backupSources lists the configured SQLite databases to include in a backup
+func backupSources() []backup.Source {
+	sources := []backup.Source{
+		{Kind: backup.KindWhatsappDB, URI: config.DBURI},
+	}
+	if _, ok := backup.SQLitePath(config.DBKeysURI); ok {
+		sources = append(sources, backup.Source{Kind: backup.KindKeysDB, URI: config.DBKeysURI})
+	}
+	sources = append(sources, backup.Source{Kind: backup.KindChatStorage, URI: config.ChatStorageURI})
+	return sources
+}
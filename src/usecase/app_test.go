@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/proto/waAdv"
+	"go.mau.fi/whatsmeow/store/sqlstore"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestRestoreClassifiesErrors(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	deviceStore, err := sqlstore.New(ctx, "sqlite3", "file:"+filepath.Join(dir, "whatsapp.db")+"?_foreign_keys=on", nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { deviceStore.Close() })
+	service := NewBackupService(deviceStore)
+
+	corrupt := filepath.Join(dir, "corrupt.tar.gz")
+	require.NoError(t, os.WriteFile(corrupt, []byte("not a backup"), 0600))
+
+	_, err = service.Restore(ctx, domainApp.RestoreRequest{ArchivePath: corrupt})
+	assert.IsType(t, pkgError.ValidationError(""), err, "a corrupt archive is the caller's problem")
+
+	// A valid archive that cannot be written to this host is a server error
+	defer func(dbURI, chatStorageURI, pathBackups string) {
+		config.DBURI, config.ChatStorageURI, config.PathBackups = dbURI, chatStorageURI, pathBackups
+	}(config.DBURI, config.ChatStorageURI, config.PathBackups)
+	config.DBURI = "file:" + filepath.Join(dir, "whatsapp.db")
+	config.PathBackups = dir
+	config.ChatStorageURI = "file:" + filepath.Join(dir, "chatstorage.db")
+	response, err := service.Backup(ctx, domainApp.BackupRequest{})
+	require.NoError(t, err)
+
+	config.DBURI = "postgres://localhost/whatsapp"
+	_, err = service.Restore(ctx, domainApp.RestoreRequest{ArchivePath: response.Path})
+	assert.IsType(t, pkgError.InternalServerError(""), err)
+}
+
+func TestRestoreRefusesPairedDevice(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	deviceStore, err := sqlstore.New(ctx, "sqlite3", "file:"+filepath.Join(dir, "whatsapp.db")+"?_foreign_keys=on", nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { deviceStore.Close() })
+
+	device := deviceStore.NewDevice()
+	jid := types.NewJID("628123456789", types.DefaultUserServer)
+	device.ID = &jid
+	device.Account = &waAdv.ADVSignedDeviceIdentity{Details: []byte{}, AccountSignature: make([]byte, 64), AccountSignatureKey: make([]byte, 32), DeviceSignature: make([]byte, 64)}
+	require.NoError(t, deviceStore.PutDevice(ctx, device))
+
+	archive := filepath.Join(dir, "backup.tar.gz")
+	require.NoError(t, os.WriteFile(archive, []byte("not a backup"), 0600))
+
+	_, err = NewBackupService(deviceStore).Restore(ctx, domainApp.RestoreRequest{ArchivePath: archive})
+	assert.ErrorContains(t, err, "already paired on this host")
+}
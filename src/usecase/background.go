@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/lifecycle"
+	"github.com/sirupsen/logrus"
+)
+
+// jobInterruptedByShutdown is stored as the error of background jobs stopped by the shutdown
+const jobInterruptedByShutdown = "interrupted by shutdown before it finished"
+
+// jobInterruptedByRestart is stored as the error of background jobs found running at startup,
+// which happens when the process stopped without a graceful shutdown
+const jobInterruptedByRestart = "interrupted by a restart before it finished"
+
+// backgroundJobs runs the jobs that outlive the request or event starting them, such as bulk number
+// checks, participant imports and greeting batches
+var backgroundJobs = newBackgroundJobRunner()
+
+type backgroundJobRunner struct {
+	inFlight lifecycle.InFlight
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+func newBackgroundJobRunner() *backgroundJobRunner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &backgroundJobRunner{ctx: ctx, cancel: cancel}
+}
+
+// start runs job in the background. Its context keeps the values of ctx, such as the session,
+// but is only cancelled when the runner stops.
+func (r *backgroundJobRunner) start(ctx context.Context, job func(ctx context.Context)) {
+	done := r.inFlight.Start()
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stopCancel := context.AfterFunc(r.ctx, cancel)
+	if r.ctx.Err() != nil {
+		// AfterFunc calls cancel asynchronously once the runner stopped
+		cancel()
+	}
+
+	go func() {
+		defer done()
+		defer cancel()
+		defer stopCancel()
+
+		job(jobCtx)
+	}()
+}
+
+// stop cancels the running jobs and waits until they returned or ctx is done
+func (r *backgroundJobRunner) stop(ctx context.Context) error {
+	r.cancel()
+	return r.inFlight.Wait(ctx)
+}
+
+// StopBackgroundJobs cancels the running background jobs and waits until they stored their
+// progress. Jobs started afterwards are cancelled right away.
+func StopBackgroundJobs(ctx context.Context) error {
+	return backgroundJobs.stop(ctx)
+}
+
+// FailInterruptedJobs marks the background jobs a previous process left running as failed, since
+// nothing resumes them. It runs at startup before any job is started.
+func FailInterruptedJobs(chatStorageRepo domainChatStorage.IChatStorageRepository) {
+	if count, err := chatStorageRepo.FailRunningNumberCheckJobs(jobInterruptedByRestart); err != nil {
+		logrus.Errorf("Failed to mark interrupted bulk number checks as failed: %v", err)
+	} else if count > 0 {
+		logrus.Warnf("Marked %d bulk number checks interrupted by a restart as failed", count)
+	}
+
+	if count, err := chatStorageRepo.FailRunningGroupImports(jobInterruptedByRestart); err != nil {
+		logrus.Errorf("Failed to mark interrupted participant imports as failed: %v", err)
+	} else if count > 0 {
+		logrus.Warnf("Marked %d participant imports interrupted by a restart as failed", count)
+	}
+}
+
+// sleepContext pauses for d and reports false when ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatstorage"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestChatStorage creates a fresh chat storage
+func newTestChatStorage(t *testing.T) domainChatStorage.IChatStorageRepository {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "storage.db")+"?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	repo := chatstorage.NewStorageRepository(db, nil)
+	require.NoError(t, repo.InitializeSchema())
+	return repo
+}
+
+func TestBackgroundJobRunnerStopCancelsJobs(t *testing.T) {
+	runner := newBackgroundJobRunner()
+
+	finished := make(chan struct{})
+	runner.start(context.Background(), func(ctx context.Context) {
+		<-ctx.Done()
+		close(finished)
+	})
+
+	require.NoError(t, runner.stop(context.Background()))
+	select {
+	case <-finished:
+	default:
+		t.Fatal("stop returned before the job finished")
+	}
+
+	// Jobs started after the shutdown began are cancelled right away
+	runner.start(context.Background(), func(ctx context.Context) {
+		assert.Error(t, ctx.Err())
+	})
+	require.NoError(t, runner.stop(context.Background()))
+}
+
+func TestBackgroundJobRunnerStopTimesOut(t *testing.T) {
+	runner := newBackgroundJobRunner()
+
+	release := make(chan struct{})
+	defer close(release)
+	runner.start(context.Background(), func(context.Context) {
+		<-release
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, runner.stop(ctx), context.DeadlineExceeded)
+}
+
+func TestSleepContext(t *testing.T) {
+	assert.True(t, sleepContext(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, sleepContext(ctx, time.Hour))
+	assert.False(t, sleepContext(ctx, 0))
+}
+
+func TestBulkCheckInterruptedByShutdown(t *testing.T) {
+	repo := newTestChatStorage(t)
+	service := serviceUser{chatStorageRepo: repo, numberChecks: &numberChecker{chatStorageRepo: repo}}
+
+	phones := []string{"6281234567890"}
+	job := &domainChatStorage.NumberCheckJob{ID: "job-1", Status: domainChatStorage.NumberCheckJobRunning, Total: len(phones)}
+	require.NoError(t, repo.CreateNumberCheckJob(job, phones))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service.runBulkCheck(ctx, job.ID, phones, 0)
+
+	stored, err := repo.GetNumberCheckJob(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domainChatStorage.NumberCheckJobFailed, stored.Status)
+	assert.Equal(t, jobInterruptedByShutdown, stored.Error)
+}
+
+func TestFailInterruptedJobs(t *testing.T) {
+	repo := newTestChatStorage(t)
+
+	running := &domainChatStorage.NumberCheckJob{ID: "running", Status: domainChatStorage.NumberCheckJobRunning, Total: 1}
+	require.NoError(t, repo.CreateNumberCheckJob(running, []string{"6281234567890"}))
+	completed := &domainChatStorage.NumberCheckJob{ID: "completed", Status: domainChatStorage.NumberCheckJobRunning, Total: 1}
+	require.NoError(t, repo.CreateNumberCheckJob(completed, []string{"6281234567891"}))
+	require.NoError(t, repo.FinishNumberCheckJob(completed.ID, domainChatStorage.NumberCheckJobCompleted, ""))
+
+	groupImport := &domainChatStorage.GroupImport{ID: "import", GroupJID: "120363000000000001@g.us", Status: domainChatStorage.GroupImportRunning, Total: 1}
+	require.NoError(t, repo.CreateGroupImport(context.Background(), groupImport, []*domainChatStorage.GroupImportEntry{
+		{ImportID: "import", Phone: "6281234567890", Status: domainChatStorage.GroupImportEntryPending},
+	}))
+
+	FailInterruptedJobs(repo)
+
+	stored, err := repo.GetNumberCheckJob(running.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domainChatStorage.NumberCheckJobFailed, stored.Status)
+	assert.Equal(t, jobInterruptedByRestart, stored.Error)
+	assert.NotNil(t, stored.FinishedAt)
+
+	stored, err = repo.GetNumberCheckJob(completed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domainChatStorage.NumberCheckJobCompleted, stored.Status)
+
+	storedImport, err := repo.GetGroupImport(context.Background(), groupImport.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domainChatStorage.GroupImportFailed, storedImport.Status)
+	assert.Equal(t, jobInterruptedByRestart, storedImport.Error)
+}
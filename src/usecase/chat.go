@@ -3,10 +3,12 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
@@ -29,12 +31,15 @@ func (service serviceChat) ListChats(ctx context.Context, request domainChat.Lis
 		return response, err
 	}
 
-	// Create filter from request
+	// Create filter from request, fetching one extra row to detect further pages
 	filter := &domainChatStorage.ChatFilter{
-		Limit:      request.Limit,
+		Limit:      request.Limit + 1,
 		Offset:     request.Offset,
 		SearchName: request.Search,
 		HasMedia:   request.HasMedia,
+		Before:     toPageCursor(request.Before),
+		After:      toPageCursor(request.After),
+		SessionID:  domainSession.FromContext(ctx),
 	}
 
 	// Get chats from storage
@@ -43,6 +48,7 @@ func (service serviceChat) ListChats(ctx context.Context, request domainChat.Lis
 		logrus.WithError(err).Error("Failed to get chats from storage")
 		return response, err
 	}
+	chats, hasMore := trimPage(chats, request.Limit, request.After != "")
 
 	// Get total count for pagination
 	totalCount, err := service.chatStorageRepo.GetTotalChatCount()
@@ -55,15 +61,7 @@ func (service serviceChat) ListChats(ctx context.Context, request domainChat.Lis
 	// Convert entities to domain objects
 	chatInfos := make([]domainChat.ChatInfo, 0, len(chats))
 	for _, chat := range chats {
-		chatInfo := domainChat.ChatInfo{
-			JID:                 chat.JID,
-			Name:                chat.Name,
-			LastMessageTime:     chat.LastMessageTime.Format(time.RFC3339),
-			EphemeralExpiration: chat.EphemeralExpiration,
-			CreatedAt:           chat.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:           chat.UpdatedAt.Format(time.RFC3339),
-		}
-		chatInfos = append(chatInfos, chatInfo)
+		chatInfos = append(chatInfos, toChatInfo(chat, time.Now()))
 	}
 
 	// Create pagination response
@@ -72,6 +70,15 @@ func (service serviceChat) ListChats(ctx context.Context, request domainChat.Lis
 		Offset: request.Offset,
 		Total:  int(totalCount),
 	}
+	if len(chats) > 0 {
+		first, last := chats[0], chats[len(chats)-1]
+		pagination.PrevCursor = utils.EncodeCursor(first.LastMessageTime, first.JID)
+		if hasMore || request.After != "" {
+			pagination.NextCursor = utils.EncodeCursor(last.LastMessageTime, last.JID)
+		}
+	} else {
+		pagination.PrevCursor = request.After
+	}
 
 	response.Data = chatInfos
 	response.Pagination = pagination
@@ -91,7 +98,7 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	}
 
 	// Get chat info first
-	chat, err := service.chatStorageRepo.GetChat(request.ChatJID)
+	chat, err := service.chatStorageRepo.GetChat(ctx, request.ChatJID)
 	if err != nil {
 		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get chat info")
 		return response, err
@@ -107,6 +114,9 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 		Offset:    request.Offset,
 		MediaOnly: request.MediaOnly,
 		IsFromMe:  request.IsFromMe,
+		Before:    toPageCursor(request.Before),
+		After:     toPageCursor(request.After),
+		SessionID: domainSession.FromContext(ctx),
 	}
 
 	// Parse time filters if provided
@@ -127,25 +137,30 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	}
 
 	// Get messages from storage
-	var messages []*domainChatStorage.Message
+	var (
+		messages []*domainChatStorage.Message
+		hasMore  bool
+	)
 	if request.Search != "" {
 		// Use search functionality if search query is provided
-		messages, err = service.chatStorageRepo.SearchMessages(request.ChatJID, request.Search, request.Limit)
+		messages, err = service.chatStorageRepo.SearchMessages(ctx, request.ChatJID, request.Search, request.Limit)
 		if err != nil {
 			logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to search messages")
 			return response, err
 		}
 	} else {
-		// Use regular filter
+		// Use regular filter, fetching one extra row to detect further pages
+		filter.Limit++
 		messages, err = service.chatStorageRepo.GetMessages(filter)
 		if err != nil {
 			logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get messages")
 			return response, err
 		}
+		messages, hasMore = trimPage(messages, request.Limit, request.After != "")
 	}
 
 	// Get total message count for pagination
-	totalCount, err := service.chatStorageRepo.GetChatMessageCount(request.ChatJID)
+	totalCount, err := service.chatStorageRepo.GetChatMessageCount(ctx, request.ChatJID)
 	if err != nil {
 		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Error("Failed to get message count")
 		// Continue with partial data
@@ -173,21 +188,25 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	}
 
 	// Create chat info for response
-	chatInfo := domainChat.ChatInfo{
-		JID:                 chat.JID,
-		Name:                chat.Name,
-		LastMessageTime:     chat.LastMessageTime.Format(time.RFC3339),
-		EphemeralExpiration: chat.EphemeralExpiration,
-		CreatedAt:           chat.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:           chat.UpdatedAt.Format(time.RFC3339),
-	}
+	chatInfo := toChatInfo(chat, time.Now())
 
-	// Create pagination response
+	// Create pagination response, search results are not cursor-paginated
 	pagination := domainChat.PaginationResponse{
 		Limit:  request.Limit,
 		Offset: request.Offset,
 		Total:  int(totalCount),
 	}
+	if request.Search == "" {
+		if len(messages) > 0 {
+			first, last := messages[0], messages[len(messages)-1]
+			pagination.PrevCursor = utils.EncodeCursor(first.Timestamp, first.ID)
+			if hasMore || request.After != "" {
+				pagination.NextCursor = utils.EncodeCursor(last.Timestamp, last.ID)
+			}
+		} else {
+			pagination.PrevCursor = request.After
+		}
+	}
 
 	response.Data = messageInfos
 	response.Pagination = pagination
@@ -209,7 +228,7 @@ func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinCh
 	}
 
 	// Validate JID and ensure connection
-	targetJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.ChatJID)
+	targetJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.ChatJID)
 	if err != nil {
 		return response, err
 	}
@@ -218,7 +237,7 @@ func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinCh
 	patchInfo := appstate.BuildPin(targetJID, request.Pinned)
 
 	// Send app state update
-	if err = whatsapp.GetClient().SendAppState(ctx, patchInfo); err != nil {
+	if err = whatsapp.ClientFromContext(ctx).SendAppState(ctx, patchInfo); err != nil {
 		logrus.WithError(err).WithFields(logrus.Fields{
 			"chat_jid": request.ChatJID,
 			"pinned":   request.Pinned,
@@ -226,6 +245,11 @@ func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinCh
 		return response, err
 	}
 
+	// Keep the local chat list in sync, our own app state changes are not echoed back as events
+	if err = service.chatStorageRepo.SetChatPinned(ctx, targetJID.String(), request.Pinned); err != nil {
+		logrus.WithError(err).WithField("chat_jid", request.ChatJID).Warn("Failed to store pinned flag")
+	}
+
 	// Build response
 	response.Status = "success"
 	response.ChatJID = request.ChatJID
@@ -244,3 +268,86 @@ func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinCh
 
 	return response, nil
 }
+
+// lastMessagePreviewLength is the maximum number of characters kept in a last message preview
+const lastMessagePreviewLength = 100
+
+// toChatInfo converts a stored chat into its API representation
+func toChatInfo(chat *domainChatStorage.Chat, now time.Time) domainChat.ChatInfo {
+	chatInfo := domainChat.ChatInfo{
+		JID:                 chat.JID,
+		Name:                chat.Name,
+		LastMessageTime:     chat.LastMessageTime.Format(time.RFC3339),
+		EphemeralExpiration: chat.EphemeralExpiration,
+		CreatedAt:           chat.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           chat.UpdatedAt.Format(time.RFC3339),
+		UnreadCount:         chat.UnreadCount,
+		Archived:            chat.Archived,
+		Pinned:              chat.Pinned,
+		AvatarURL:           chat.AvatarURL,
+		LID:                 chat.LID,
+	}
+
+	// A mute with an end time in the past has expired
+	if chat.Muted && (chat.MutedUntil == nil || chat.MutedUntil.After(now)) {
+		chatInfo.Muted = true
+		if chat.MutedUntil != nil {
+			chatInfo.MutedUntil = chat.MutedUntil.Format(time.RFC3339)
+		}
+	}
+
+	if last := chat.LastMessage; last != nil {
+		messageType := "text"
+		if last.MediaType != "" {
+			messageType = last.MediaType
+		}
+
+		chatInfo.LastMessage = &domainChat.LastMessageInfo{
+			ID:        last.ID,
+			SenderJID: last.Sender,
+			IsFromMe:  last.IsFromMe,
+			Type:      messageType,
+			Preview:   truncatePreview(last.Content, lastMessagePreviewLength),
+			Timestamp: last.Timestamp.Format(time.RFC3339),
+		}
+	}
+
+	return chatInfo
+}
+
+// truncatePreview shortens text to at most limit characters on a single line
+func truncatePreview(text string, limit int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// toPageCursor converts an opaque cursor into its storage form, cursors are validated beforehand
+func toPageCursor(cursor string) *domainChatStorage.PageCursor {
+	if cursor == "" {
+		return nil
+	}
+
+	timestamp, id, err := utils.DecodeCursor(cursor)
+	if err != nil {
+		return nil
+	}
+
+	return &domainChatStorage.PageCursor{Timestamp: timestamp, ID: id}
+}
+
+// trimPage drops the extra row fetched beyond limit and reports whether it existed.
+// Pages are newest first, so for an after cursor the extra row is the newest one.
+func trimPage[T any](items []T, limit int, after bool) ([]T, bool) {
+	if len(items) <= limit {
+		return items, false
+	}
+
+	if after {
+		return items[len(items)-limit:], true
+	}
+	return items[:limit], true
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestTruncatePreview(t *testing.T) {
+	assert.Equal(t, "hello world", truncatePreview("  hello \n world ", 100))
+	assert.Equal(t, "abcd…", truncatePreview("abcdefghij", 5))
+	assert.Equal(t, "héll…", truncatePreview("héllo wörld", 5))
+}
+
+func TestToChatInfo(t *testing.T) {
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Hour)
+
+	chat := &domainChatStorage.Chat{
+		JID:             "628123@s.whatsapp.net",
+		Name:            "Alice",
+		LastMessageTime: now,
+		UnreadCount:     2,
+		Pinned:          true,
+		Muted:           true,
+		MutedUntil:      &expired,
+		LastMessage: &domainChatStorage.Message{
+			ID:        "MSG1",
+			Sender:    "628123",
+			MediaType: "image",
+			Timestamp: now,
+		},
+	}
+
+	info := toChatInfo(chat, now)
+	assert.Equal(t, 2, info.UnreadCount)
+	assert.True(t, info.Pinned)
+	assert.False(t, info.Muted, "mute should lapse once muted_until has passed")
+	assert.Empty(t, info.MutedUntil)
+	if assert.NotNil(t, info.LastMessage) {
+		assert.Equal(t, "MSG1", info.LastMessage.ID)
+		assert.Equal(t, "image", info.LastMessage.Type)
+	}
+}
+
+func TestTrimPage(t *testing.T) {
+	items := []int{5, 4, 3, 2}
+
+	page, hasMore := trimPage(items, 3, false)
+	assert.Equal(t, []int{5, 4, 3}, page)
+	assert.True(t, hasMore)
+
+	// With an after cursor the extra row is the newest one
+	page, hasMore = trimPage(items, 3, true)
+	assert.Equal(t, []int{4, 3, 2}, page)
+	assert.True(t, hasMore)
+
+	page, hasMore = trimPage(items, 4, false)
+	assert.Equal(t, items, page)
+	assert.False(t, hasMore)
+}
+
+// useLocalZone runs the test as on a host whose TZ is east of UTC
+func useLocalZone(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("WIB", 7*60*60)
+	t.Cleanup(func() { time.Local = local })
+}
+
+func TestMessagePaginationOutsideUTC(t *testing.T) {
+	useLocalZone(t)
+	repo := newTestChatStorage(t)
+
+	// The messages straddle midnight UTC, so their local and UTC dates differ
+	chatJID := "628123456789@s.whatsapp.net"
+	start := time.Date(2024, 5, 16, 5, 0, 0, 0, time.Local)
+	require.NoError(t, repo.StoreChat(&domainChatStorage.Chat{JID: chatJID, LastMessageTime: start}))
+	var want []string
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("MSG%d", i)
+		require.NoError(t, repo.StoreMessage(&domainChatStorage.Message{
+			ID:        id,
+			ChatJID:   chatJID,
+			Sender:    chatJID,
+			Content:   "hello",
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+		}))
+		want = append([]string{id}, want...)
+	}
+
+	var (
+		got    []string
+		before *domainChatStorage.PageCursor
+	)
+	for page := 0; page < 5; page++ {
+		messages, err := repo.GetMessages(&domainChatStorage.MessageFilter{ChatJID: chatJID, Limit: 2, Before: before})
+		require.NoError(t, err)
+		if len(messages) == 0 {
+			break
+		}
+		for _, message := range messages {
+			got = append(got, message.ID)
+		}
+
+		// The cursor travels through the API, which decodes it in UTC
+		last := messages[len(messages)-1]
+		timestamp, id, err := utils.DecodeCursor(utils.EncodeCursor(last.Timestamp, last.ID))
+		require.NoError(t, err)
+		before = &domainChatStorage.PageCursor{Timestamp: timestamp, ID: id}
+	}
+
+	assert.Equal(t, want, got)
+}
+
+func TestTimestampsMigratedToUTC(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "storage.db")+"?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	repo := chatstorage.NewStorageRepository(db, nil)
+	require.NoError(t, repo.InitializeSchema())
+
+	// A row written by an earlier version on a host at +07:00
+	_, err = db.Exec(`INSERT INTO chats (session_id, jid, name, last_message_time) VALUES ('default', 'chat@s.whatsapp.net', 'Chat', '2024-05-16 06:30:00.123456789+07:00')`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO messages (session_id, id, chat_jid, sender, content, timestamp) VALUES ('default', 'MSG1', 'chat@s.whatsapp.net', 'chat@s.whatsapp.net', 'hello', '2024-05-16 06:30:00+07:00')`)
+	require.NoError(t, err)
+
+	_, err = db.Exec("DELETE FROM schema_info WHERE version >= 19")
+	require.NoError(t, err)
+	require.NoError(t, repo.InitializeSchema())
+
+	var lastMessageTime, timestamp string
+	require.NoError(t, db.QueryRow("SELECT last_message_time || '' FROM chats").Scan(&lastMessageTime))
+	require.NoError(t, db.QueryRow("SELECT timestamp || '' FROM messages").Scan(&timestamp))
+	assert.Equal(t, "2024-05-15 23:30:00.123456789+00:00", lastMessageTime)
+	assert.Equal(t, "2024-05-15 23:30:00+00:00", timestamp)
+}
+
+// sessionIdentity resolves the LIDs each session has a mapping for
+type sessionIdentity map[string]map[string]string
+
+func (identity sessionIdentity) Canonical(ctx context.Context, jid types.JID) types.JID {
+	if pn, ok := identity[domainSession.FromContext(ctx)][jid.ToNonAD().String()]; ok {
+		return types.NewJID(pn, types.DefaultUserServer)
+	}
+	return jid.ToNonAD()
+}
+
+func (identity sessionIdentity) CanonicalString(ctx context.Context, jid string) string {
+	parsed, err := types.ParseJID(jid)
+	if err != nil {
+		return jid
+	}
+	return identity.Canonical(ctx, parsed).String()
+}
+
+func (identity sessionIdentity) Alternate(context.Context, types.JID) types.JID {
+	return types.EmptyJID
+}
+
+func TestMergeLIDChatsUsesTheSessionMappings(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "storage.db")+"?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	// Only the sales session learned the phone number behind the LID
+	repo := chatstorage.NewStorageRepository(db, sessionIdentity{"sales": {"123456789@lid": "628123456789"}})
+	require.NoError(t, repo.InitializeSchema())
+
+	group := "120363000000000001@g.us"
+	for _, sessionID := range []string{"sales", "support"} {
+		_, err = db.Exec("INSERT INTO chats (session_id, jid, name, last_message_time) VALUES (?, ?, 'Group', ?)", sessionID, group, time.Now())
+		require.NoError(t, err)
+		_, err = db.Exec("INSERT INTO messages (session_id, id, chat_jid, sender, content, timestamp) VALUES (?, 'MSG1', ?, '123456789@lid', 'hello', ?)", sessionID, group, time.Now())
+		require.NoError(t, err)
+		_, err = db.Exec("INSERT INTO group_events (session_id, group_jid, event_type, participant_jid, timestamp) VALUES (?, ?, 'join', '123456789@lid', ?)", sessionID, group, time.Now())
+		require.NoError(t, err)
+	}
+
+	_, err = repo.MergeLIDChats()
+	require.NoError(t, err)
+
+	senders := map[string]string{}
+	participants := map[string]string{}
+	for _, sessionID := range []string{"sales", "support"} {
+		var sender, participant string
+		require.NoError(t, db.QueryRow("SELECT sender FROM messages WHERE session_id = ?", sessionID).Scan(&sender))
+		require.NoError(t, db.QueryRow("SELECT participant_jid FROM group_events WHERE session_id = ?", sessionID).Scan(&participant))
+		senders[sessionID], participants[sessionID] = sender, participant
+	}
+
+	assert.Equal(t, map[string]string{"sales": "628123456789@s.whatsapp.net", "support": "123456789@lid"}, senders)
+	assert.Equal(t, map[string]string{"sales": "628123456789@s.whatsapp.net", "support": "123456789@lid"}, participants)
+}
@@ -0,0 +1,380 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
+	domainIdentity "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/identity"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	maxImportContacts    = 5000
+	maxContactImportSize = 10 << 20
+)
+
+type serviceContact struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+	identity        domainIdentity.IIdentityResolver
+}
+
+func NewContactService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainContact.IContactUsecase {
+	return &serviceContact{
+		chatStorageRepo: chatStorageRepo,
+		identity:        whatsapp.NewIdentityResolver(),
+	}
+}
+
+func (service *serviceContact) ListContacts(ctx context.Context, request domainContact.ListContactsRequest) (response domainContact.ListContactsResponse, err error) {
+	if err = validations.ValidateListContacts(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := &domainChatStorage.AddressBookFilter{
+		Search: request.Search,
+		Tag:    request.Tag,
+		Limit:  request.Limit,
+		Offset: request.Offset,
+	}
+
+	entries, err := service.chatStorageRepo.GetAddressBookContacts(ctx, filter)
+	if err != nil {
+		return response, err
+	}
+
+	total, err := service.chatStorageRepo.CountAddressBookContacts(ctx, filter)
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainContact.Contact, 0, len(entries))
+	for _, entry := range entries {
+		response.Data = append(response.Data, toContact(ctx, entry))
+	}
+	response.Pagination = domainContact.ContactPagination{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}
+
+	return response, nil
+}
+
+func (service *serviceContact) GetContact(ctx context.Context, request domainContact.GetContactRequest) (response domainContact.Contact, err error) {
+	if err = validations.ValidateGetContact(ctx, request); err != nil {
+		return response, err
+	}
+
+	entry, err := service.findContact(ctx, request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	return toContact(ctx, entry), nil
+}
+
+// CreateContact stores an address book entry, replacing the entry of the same JID if there is one
+func (service *serviceContact) CreateContact(ctx context.Context, request domainContact.ContactRequest) (response domainContact.Contact, err error) {
+	if err = validations.ValidateContact(ctx, request); err != nil {
+		return response, err
+	}
+
+	jid, err := service.contactJID(ctx, request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	entry := &domainChatStorage.AddressBookContact{
+		JID:          jid,
+		CustomName:   strings.TrimSpace(request.Name),
+		Notes:        request.Notes,
+		Tags:         normalizeContactTags(request.Tags),
+		CustomFields: mergeCustomFields(nil, request.CustomFields),
+	}
+	if err = service.chatStorageRepo.StoreAddressBookContact(ctx, entry); err != nil {
+		return response, err
+	}
+
+	return toContact(ctx, entry), nil
+}
+
+func (service *serviceContact) UpdateContact(ctx context.Context, request domainContact.UpdateContactRequest) (response domainContact.Contact, err error) {
+	if err = validations.ValidateUpdateContact(ctx, request); err != nil {
+		return response, err
+	}
+
+	entry, err := service.findContact(ctx, request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	if request.Name != nil {
+		entry.CustomName = strings.TrimSpace(*request.Name)
+	}
+	if request.Tags != nil {
+		entry.Tags = normalizeContactTags(*request.Tags)
+	}
+	if request.Notes != nil {
+		entry.Notes = *request.Notes
+	}
+	entry.CustomFields = mergeCustomFields(entry.CustomFields, request.CustomFields)
+
+	if err = service.chatStorageRepo.StoreAddressBookContact(ctx, entry); err != nil {
+		return response, err
+	}
+
+	return toContact(ctx, entry), nil
+}
+
+func (service *serviceContact) DeleteContact(ctx context.Context, request domainContact.GetContactRequest) (err error) {
+	if err = validations.ValidateGetContact(ctx, request); err != nil {
+		return err
+	}
+
+	entry, err := service.findContact(ctx, request.Phone)
+	if err != nil {
+		return err
+	}
+
+	return service.chatStorageRepo.DeleteAddressBookContact(ctx, entry.JID)
+}
+
+func (service *serviceContact) ImportContacts(ctx context.Context, request domainContact.ImportContactsRequest) (response domainContact.ImportContactsResponse, err error) {
+	if err = validations.ValidateImportContacts(ctx, request); err != nil {
+		return response, err
+	}
+
+	file, err := request.File.Open()
+	if err != nil {
+		return response, err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, maxContactImportSize+1))
+	if err != nil {
+		return response, err
+	}
+	if len(content) > maxContactImportSize {
+		return response, pkgError.ValidationError(fmt.Sprintf("file: must be smaller than %d MB.", maxContactImportSize>>20))
+	}
+
+	var records []domainContact.ContactRequest
+	if isVCardContent(request.File.Filename, content) {
+		records = parseContactVCards(content)
+	} else if records, err = parseContactCSV(strings.NewReader(string(content))); err != nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("file: %v.", err))
+	}
+	if len(records) == 0 {
+		return response, pkgError.ValidationError("file: must contain at least one contact.")
+	}
+	if len(records) > maxImportContacts {
+		return response, pkgError.ValidationError(fmt.Sprintf("file: must contain at most %d contacts.", maxImportContacts))
+	}
+
+	for i, record := range records {
+		record.Tags = append(record.Tags, request.Tags...)
+		created, err := service.importContact(ctx, record)
+		if err != nil {
+			response.Skipped++
+			response.Errors = append(response.Errors, fmt.Sprintf("record %d (%s): %v", i+1, record.Phone, err))
+			continue
+		}
+		if created {
+			response.Created++
+		} else {
+			response.Updated++
+		}
+	}
+
+	return response, nil
+}
+
+// importContact merges an imported record into the address book. Names and notes replace the
+// stored ones when the record has them, custom fields are merged and tags are added.
+func (service *serviceContact) importContact(ctx context.Context, record domainContact.ContactRequest) (created bool, err error) {
+	record.Tags = normalizeContactTags(record.Tags)
+	if err = validations.ValidateContact(ctx, record); err != nil {
+		return false, err
+	}
+
+	jid, err := service.contactJID(ctx, record.Phone)
+	if err != nil {
+		return false, err
+	}
+
+	entry, err := service.chatStorageRepo.GetAddressBookContact(ctx, jid)
+	if err != nil {
+		return false, err
+	}
+	if entry == nil {
+		created = true
+		entry = &domainChatStorage.AddressBookContact{JID: jid}
+	}
+
+	if name := strings.TrimSpace(record.Name); name != "" {
+		entry.CustomName = name
+	}
+	if record.Notes != "" {
+		entry.Notes = record.Notes
+	}
+	entry.Tags = normalizeContactTags(append(entry.Tags, record.Tags...))
+	entry.CustomFields = mergeCustomFields(entry.CustomFields, record.CustomFields)
+
+	return created, service.chatStorageRepo.StoreAddressBookContact(ctx, entry)
+}
+
+func (service *serviceContact) ExportContacts(ctx context.Context, request domainContact.ExportContactsRequest) (response domainContact.ExportContactsResponse, err error) {
+	if err = validations.ValidateExportContacts(ctx, &request); err != nil {
+		return response, err
+	}
+
+	entries, err := service.chatStorageRepo.GetAddressBookContacts(ctx, &domainChatStorage.AddressBookFilter{
+		Search: request.Search,
+		Tag:    request.Tag,
+	})
+	if err != nil {
+		return response, err
+	}
+
+	contacts := make([]domainContact.Contact, 0, len(entries))
+	for _, entry := range entries {
+		contacts = append(contacts, toContact(ctx, entry))
+	}
+
+	name := "contacts-" + time.Now().Format("20060102")
+	switch request.Format {
+	case domainContact.ExportFormatVCard:
+		response.FileName = name + ".vcf"
+		response.ContentType = "text/vcard; charset=utf-8"
+		response.Content = encodeContactVCards(contacts)
+	default:
+		response.FileName = name + ".csv"
+		response.ContentType = "text/csv; charset=utf-8"
+		if response.Content, err = encodeContactCSV(contacts); err != nil {
+			return response, err
+		}
+	}
+	response.Total = len(contacts)
+
+	return response, nil
+}
+
+// findContact loads the address book entry of a phone number or JID
+func (service *serviceContact) findContact(ctx context.Context, phone string) (*domainChatStorage.AddressBookContact, error) {
+	jid, err := service.contactJID(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := service.chatStorageRepo.GetAddressBookContact(ctx, jid)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, pkgError.ValidationError(fmt.Sprintf("contact %s not found", jid))
+	}
+	return entry, nil
+}
+
+// contactJID resolves a phone number or JID to the JID entries are stored under. Users are kept
+// under their phone number JID so an entry matches both their LID and phone number messages.
+func (service *serviceContact) contactJID(ctx context.Context, phone string) (string, error) {
+	jid, err := utils.ParseJID(strings.TrimSpace(phone))
+	if err != nil {
+		return "", pkgError.ValidationError(fmt.Sprintf("phone: %v.", err))
+	}
+
+	switch jid.Server {
+	case types.DefaultUserServer, types.HiddenUserServer, types.GroupServer:
+	default:
+		return "", pkgError.ValidationError(fmt.Sprintf("phone: must be a user or group, not %s.", jid))
+	}
+
+	return service.identity.Canonical(ctx, jid.ToNonAD()).String(), nil
+}
+
+func toContact(ctx context.Context, entry *domainChatStorage.AddressBookContact) domainContact.Contact {
+	contact := domainContact.Contact{
+		JID:          entry.JID,
+		Name:         entry.CustomName,
+		Tags:         entry.Tags,
+		Notes:        entry.Notes,
+		CustomFields: entry.CustomFields,
+		CreatedAt:    entry.CreatedAt,
+		UpdatedAt:    entry.UpdatedAt,
+	}
+	if contact.Tags == nil {
+		contact.Tags = []string{}
+	}
+	if contact.CustomFields == nil {
+		contact.CustomFields = map[string]string{}
+	}
+
+	jid, err := types.ParseJID(entry.JID)
+	if err != nil || jid.Server != types.DefaultUserServer {
+		return contact
+	}
+	contact.Phone = jid.User
+
+	if client := whatsapp.ClientFromContext(ctx); client != nil && client.Store != nil && client.Store.Contacts != nil {
+		if info, err := client.Store.Contacts.GetContact(ctx, jid); err == nil {
+			contact.PushName = info.PushName
+		}
+	}
+
+	return contact
+}
+
+// normalizeContactTags lower cases tags, turns spaces into dashes and drops duplicates
+func normalizeContactTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), "-"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// mergeCustomFields applies changes to the stored fields, removing fields set to an empty value
+func mergeCustomFields(fields, changes map[string]string) map[string]string {
+	merged := make(map[string]string, len(fields)+len(changes))
+	for key, value := range fields {
+		merged[key] = value
+	}
+	for key, value := range changes {
+		key = strings.TrimSpace(key)
+		if strings.TrimSpace(value) == "" {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// isVCardContent tells vCard files apart from CSV by extension or by their first line
+func isVCardContent(filename string, content []byte) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".vcf", ".vcard":
+		return true
+	}
+	start := strings.TrimSpace(strings.TrimPrefix(string(content[:min(len(content), 64)]), "\ufeff"))
+	return strings.HasPrefix(strings.ToUpper(start), "BEGIN:VCARD")
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeContactTags(t *testing.T) {
+	assert.Equal(t, []string{"new-customer", "vip"}, normalizeContactTags([]string{"VIP", " new  customer ", "vip", ""}))
+	assert.Equal(t, []string{}, normalizeContactTags(nil))
+}
+
+func TestMergeCustomFields(t *testing.T) {
+	stored := map[string]string{"company": "PT Maju", "city": "Jakarta"}
+
+	merged := mergeCustomFields(stored, map[string]string{"city": "", "role": "Buyer", " company ": "PT Jaya"})
+
+	assert.Equal(t, map[string]string{"company": "PT Jaya", "role": "Buyer"}, merged)
+	assert.Equal(t, "Jakarta", stored["city"], "the stored fields are left untouched")
+}
+
+func TestAddressBookIsKeptPerSession(t *testing.T) {
+	repo := newTestChatStorage(t)
+	sales := domainSession.NewContext(context.Background(), "sales")
+	support := domainSession.NewContext(context.Background(), "support")
+	customer := "6281234567890@s.whatsapp.net"
+
+	for _, sessionID := range []string{"sales", "support"} {
+		require.NoError(t, repo.StoreChat(&domainChatStorage.Chat{JID: customer, Name: "Budi", LastMessageTime: time.Now(), SessionID: sessionID}))
+	}
+	require.NoError(t, repo.StoreAddressBookContact(sales, &domainChatStorage.AddressBookContact{JID: customer, CustomName: "Budi (Lead)", Tags: []string{"lead"}}))
+
+	assert.Equal(t, "Budi (Lead)", repo.GetCustomName(sales, customer))
+	assert.Empty(t, repo.GetCustomName(support, customer), "a custom name of one session must not apply to the other")
+
+	jids, err := repo.GetAddressBookJIDsByTag(support, "lead")
+	require.NoError(t, err)
+	assert.Empty(t, jids)
+
+	chats, err := repo.GetChats(&domainChatStorage.ChatFilter{SessionID: "support"})
+	require.NoError(t, err)
+	if assert.Len(t, chats, 1) {
+		assert.Equal(t, "Budi", chats[0].Name)
+	}
+
+	// Deleting the entry of the other session leaves this one in place
+	require.NoError(t, repo.DeleteAddressBookContact(support, customer))
+	contact, err := repo.GetAddressBookContact(sales, customer)
+	require.NoError(t, err)
+	if assert.NotNil(t, contact) {
+		assert.Equal(t, []string{"lead"}, contact.Tags)
+	}
+}
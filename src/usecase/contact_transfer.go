@@ -0,0 +1,359 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
+)
+
+// contactCSVColumns are the columns every export starts with, custom fields follow
+var contactCSVColumns = []string{"phone", "jid", "name", "tags", "notes"}
+
+// parseContactCSV reads contacts from a CSV file. With a header row, the phone (or phone_number,
+// number, jid), name (or custom_name), tags and notes columns are recognised and every other
+// column becomes a custom field. Without one, the columns are phone, name, tags and notes.
+func parseContactCSV(reader io.Reader) ([]domainContact.ContactRequest, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	columns := []string{"phone", "name", "tags", "notes"}
+	var records []domainContact.ContactRequest
+
+	for line := 0; ; line++ {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		if line == 0 {
+			if header, ok := contactCSVHeader(record); ok {
+				columns = header
+				continue
+			}
+		}
+
+		contact := domainContact.ContactRequest{CustomFields: map[string]string{}}
+		for i, cell := range record {
+			if i >= len(columns) {
+				break
+			}
+			cell = strings.TrimSpace(cell)
+			switch columns[i] {
+			case "phone":
+				if phone := contactCSVPhone(cell); phone != "" {
+					contact.Phone = phone
+				}
+			case "jid":
+				// Only needed for groups, which have no phone number
+				if contact.Phone == "" {
+					contact.Phone = cell
+				}
+			case "name":
+				contact.Name = cell
+			case "tags":
+				if tags := strings.FieldsFunc(cell, func(r rune) bool { return r == ',' || r == ';' }); len(tags) > 0 {
+					contact.Tags = tags
+				}
+			case "notes":
+				contact.Notes = cell
+			case "":
+			default:
+				if cell != "" {
+					contact.CustomFields[columns[i]] = cell
+				}
+			}
+		}
+
+		if contact.Phone == "" && contact.Name == "" {
+			continue
+		}
+		records = append(records, contact)
+	}
+
+	return records, nil
+}
+
+// contactCSVHeader maps a header row to field names, or reports that the row holds a contact
+func contactCSVHeader(record []string) ([]string, bool) {
+	columns := make([]string, len(record))
+	known := false
+	for i, cell := range record {
+		name := strings.TrimSpace(strings.TrimPrefix(cell, "\ufeff"))
+		switch strings.ToLower(name) {
+		case "phone", "phone_number", "number":
+			columns[i], known = "phone", true
+		case "jid":
+			columns[i], known = "jid", true
+		case "name", "custom_name":
+			columns[i], known = "name", true
+		case "tags":
+			columns[i], known = "tags", true
+		case "notes":
+			columns[i], known = "notes", true
+		case "push_name":
+			// exported for reference, WhatsApp owns it
+		default:
+			columns[i] = name
+		}
+	}
+	return columns, known
+}
+
+// contactCSVPhone keeps the digits of a phone number, JIDs are kept as they are
+func contactCSVPhone(value string) string {
+	if strings.Contains(value, "@") {
+		return value
+	}
+	return normalizeImportPhone(value)
+}
+
+// encodeContactCSV writes contacts in the layout parseContactCSV reads
+func encodeContactCSV(contacts []domainContact.Contact) ([]byte, error) {
+	fieldSet := make(map[string]bool)
+	for _, contact := range contacts {
+		for key := range contact.CustomFields {
+			fieldSet[key] = true
+		}
+	}
+	fields := make([]string, 0, len(fieldSet))
+	for key := range fieldSet {
+		fields = append(fields, key)
+	}
+	sort.Strings(fields)
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(append(append([]string{}, contactCSVColumns...), fields...)); err != nil {
+		return nil, err
+	}
+
+	for _, contact := range contacts {
+		row := []string{contact.Phone, contact.JID, contact.Name, strings.Join(contact.Tags, ","), contact.Notes}
+		for _, key := range fields {
+			row = append(row, contact.CustomFields[key])
+		}
+		if err := writer.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// parseContactVCards reads the cards of a vCard file. The phone comes from the waid parameter
+// WhatsApp puts on TEL, or from the first number. CATEGORIES become tags, NOTE the notes and
+// X-GOWA-FIELD properties custom fields. Cards without a phone or JID are kept so the import
+// reports them.
+func parseContactVCards(content []byte) []domainContact.ContactRequest {
+	var (
+		records []domainContact.ContactRequest
+		card    *domainContact.ContactRequest
+		family  string
+	)
+
+	for _, line := range unfoldVCardLines(string(content)) {
+		name, params, value, ok := splitVCardLine(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCARD"):
+			card = &domainContact.ContactRequest{CustomFields: map[string]string{}}
+			family = ""
+		case card == nil:
+		case name == "END":
+			if card.Name == "" {
+				card.Name = family
+			}
+			records = append(records, *card)
+			card = nil
+		case name == "FN":
+			card.Name = unescapeVCard(value)
+		case name == "N":
+			// Used when there is no FN: family;given;additional;prefix;suffix
+			parts := strings.Split(value, ";")
+			if len(parts) > 1 {
+				family = strings.TrimSpace(unescapeVCard(parts[1]) + " " + unescapeVCard(parts[0]))
+			} else {
+				family = unescapeVCard(value)
+			}
+		case name == "TEL" && card.Phone == "":
+			if waid := params["WAID"]; waid != "" {
+				card.Phone = normalizeImportPhone(waid)
+			} else {
+				card.Phone = normalizeImportPhone(value)
+			}
+		case name == "X-GOWA-JID":
+			card.Phone = strings.TrimSpace(value)
+		case name == "CATEGORIES":
+			card.Tags = append(card.Tags, splitVCardList(value)...)
+		case name == "NOTE":
+			card.Notes = unescapeVCard(value)
+		case name == "X-GOWA-FIELD":
+			if key := params["KEY"]; key != "" {
+				card.CustomFields[key] = unescapeVCard(value)
+			}
+		}
+	}
+
+	return records
+}
+
+// encodeContactVCards writes contacts as vCard 3.0 cards that WhatsApp and phone address books
+// understand, with the address book extras in X-GOWA properties
+func encodeContactVCards(contacts []domainContact.Contact) []byte {
+	var buf bytes.Buffer
+	write := func(format string, args ...any) {
+		fmt.Fprintf(&buf, format+"\r\n", args...)
+	}
+
+	for _, contact := range contacts {
+		name := contact.Name
+		if name == "" {
+			name = contact.PushName
+		}
+		if name == "" {
+			name = contact.Phone
+		}
+
+		write("BEGIN:VCARD")
+		write("VERSION:3.0")
+		write("FN:%s", escapeVCard(name))
+		write("N:;%s;;;", escapeVCard(name))
+		if contact.Phone != "" {
+			write("TEL;type=CELL;waid=%s:+%s", contact.Phone, contact.Phone)
+		} else {
+			write("X-GOWA-JID:%s", contact.JID)
+		}
+		if len(contact.Tags) > 0 {
+			tags := make([]string, 0, len(contact.Tags))
+			for _, tag := range contact.Tags {
+				tags = append(tags, escapeVCard(tag))
+			}
+			write("CATEGORIES:%s", strings.Join(tags, ","))
+		}
+		if contact.Notes != "" {
+			write("NOTE:%s", escapeVCard(contact.Notes))
+		}
+
+		keys := make([]string, 0, len(contact.CustomFields))
+		for key := range contact.CustomFields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			write("X-GOWA-FIELD;KEY=\"%s\":%s", strings.ReplaceAll(key, "\"", "'"), escapeVCard(contact.CustomFields[key]))
+		}
+		write("END:VCARD")
+	}
+
+	return buf.Bytes()
+}
+
+// unfoldVCardLines joins continuation lines, which start with a space or tab
+func unfoldVCardLines(content string) []string {
+	content = strings.TrimPrefix(content, "\ufeff")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, "\r"))
+	}
+	return lines
+}
+
+// splitVCardLine splits a content line into its upper case property name without group prefix,
+// its upper case parameters and its value
+func splitVCardLine(line string) (name string, params map[string]string, value string, ok bool) {
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	name = strings.ToUpper(strings.TrimSpace(parts[0]))
+	if dot := strings.LastIndex(name, "."); dot >= 0 {
+		name = name[dot+1:]
+	}
+
+	params = make(map[string]string)
+	for _, param := range parts[1:] {
+		key, val, found := strings.Cut(param, "=")
+		if !found {
+			// vCard 2.1 style bare types such as TEL;CELL
+			params["TYPE"] = param
+			continue
+		}
+		params[strings.ToUpper(strings.TrimSpace(key))] = strings.Trim(strings.TrimSpace(val), "\"")
+	}
+
+	return name, params, line[colon+1:], true
+}
+
+// splitVCardList splits a comma separated value, keeping escaped commas
+func splitVCardList(value string) []string {
+	var (
+		items   []string
+		current strings.Builder
+	)
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value):
+			current.WriteByte(value[i])
+			current.WriteByte(value[i+1])
+			i++
+		case value[i] == ',':
+			items = append(items, unescapeVCard(current.String()))
+			current.Reset()
+		default:
+			current.WriteByte(value[i])
+		}
+	}
+	items = append(items, unescapeVCard(current.String()))
+
+	tags := items[:0]
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			tags = append(tags, item)
+		}
+	}
+	return tags
+}
+
+var (
+	vCardEscaper   = strings.NewReplacer("\\", "\\\\", "\n", "\\n", ",", "\\,", ";", "\\;")
+	vCardUnescaper = strings.NewReplacer("\\\\", "\\", "\\n", "\n", "\\N", "\n", "\\,", ",", "\\;", ";")
+)
+
+func escapeVCard(value string) string {
+	return vCardEscaper.Replace(strings.ReplaceAll(value, "\r\n", "\n"))
+}
+
+func unescapeVCard(value string) string {
+	return strings.TrimSpace(vCardUnescaper.Replace(value))
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseContactCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []domainContact.ContactRequest
+	}{
+		{
+			name:  "plain list without header",
+			input: "+62 812-3456-7890,Budi,vip;supplier,Morning calls\n",
+			want: []domainContact.ContactRequest{
+				{Phone: "6281234567890", Name: "Budi", Tags: []string{"vip", "supplier"}, Notes: "Morning calls", CustomFields: map[string]string{}},
+			},
+		},
+		{
+			name:  "header with custom fields",
+			input: "Phone,Custom_Name,Company,Tags\n6281234567890,Budi,PT Maju,\"vip,supplier\"\n,,,\n",
+			want: []domainContact.ContactRequest{
+				{Phone: "6281234567890", Name: "Budi", Tags: []string{"vip", "supplier"}, CustomFields: map[string]string{"Company": "PT Maju"}},
+			},
+		},
+		{
+			name:  "export layout keeps groups",
+			input: "phone,jid,name,tags,notes,push_name\n,120363025246125486@g.us,Team,,,\n",
+			want: []domainContact.ContactRequest{
+				{Phone: "120363025246125486@g.us", Name: "Team", CustomFields: map[string]string{}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseContactCSV(strings.NewReader(tt.input))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := parseContactCSV(strings.NewReader("\"unterminated\n"))
+	assert.Error(t, err)
+}
+
+func TestParseContactVCards(t *testing.T) {
+	input := "BEGIN:VCARD\r\n" +
+		"VERSION:3.0\r\n" +
+		"N:Santoso;Budi;;;\r\n" +
+		"item1.TEL;type=CELL;waid=6281234567890:+62 812-3456-7890\r\n" +
+		"TEL;type=WORK:+62 21 555 0100\r\n" +
+		"CATEGORIES:VIP,Supplier\\, Jakarta\r\n" +
+		"NOTE:Prefers calls\\nin the morning and is a very long note that\r\n" +
+		"  continues on the next line\r\n" +
+		"X-GOWA-FIELD;KEY=\"company:branch\":PT Maju\\; Jakarta\r\n" +
+		"END:VCARD\r\n" +
+		"BEGIN:VCARD\n" +
+		"VERSION:2.1\n" +
+		"FN:Siti\n" +
+		"TEL;CELL:081234567891\n" +
+		"END:VCARD\n"
+
+	got := parseContactVCards([]byte(input))
+
+	assert.Equal(t, []domainContact.ContactRequest{
+		{
+			Phone:        "6281234567890",
+			Name:         "Budi Santoso",
+			Tags:         []string{"VIP", "Supplier, Jakarta"},
+			Notes:        "Prefers calls\nin the morning and is a very long note that continues on the next line",
+			CustomFields: map[string]string{"company:branch": "PT Maju; Jakarta"},
+		},
+		{
+			Phone:        "081234567891",
+			Name:         "Siti",
+			CustomFields: map[string]string{},
+		},
+	}, got)
+}
+
+func TestContactTransferRoundTrip(t *testing.T) {
+	contacts := []domainContact.Contact{
+		{
+			JID:          "6281234567890@s.whatsapp.net",
+			Phone:        "6281234567890",
+			Name:         "Budi, Supplier",
+			Tags:         []string{"supplier", "vip"},
+			Notes:        "Line one\nLine two",
+			CustomFields: map[string]string{"company": "PT Maju"},
+		},
+		{
+			JID:          "120363025246125486@g.us",
+			Name:         "Team",
+			Tags:         []string{},
+			CustomFields: map[string]string{},
+		},
+	}
+
+	want := []domainContact.ContactRequest{
+		{Phone: "6281234567890", Name: "Budi, Supplier", Tags: []string{"supplier", "vip"}, Notes: "Line one\nLine two", CustomFields: map[string]string{"company": "PT Maju"}},
+		{Phone: "120363025246125486@g.us", Name: "Team", CustomFields: map[string]string{}},
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		content, err := encodeContactCSV(contacts)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(content), "phone,jid,name,tags,notes,company\n"))
+
+		got, err := parseContactCSV(strings.NewReader(string(content)))
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("vcard", func(t *testing.T) {
+		content := encodeContactVCards(contacts)
+		assert.Contains(t, string(content), "TEL;type=CELL;waid=6281234567890:+6281234567890\r\n")
+		assert.Contains(t, string(content), "FN:Budi\\, Supplier\r\n")
+
+		assert.Equal(t, want, parseContactVCards(content))
+	})
+}
+
+func TestIsVCardContent(t *testing.T) {
+	assert.True(t, isVCardContent("contacts.VCF", []byte("")))
+	assert.True(t, isVCardContent("upload", []byte("\ufeff\r\nbegin:vcard\r\n")))
+	assert.False(t, isVCardContent("contacts.csv", []byte("phone,name\n")))
+}
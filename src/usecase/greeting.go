@@ -0,0 +1,410 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainGreeting "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/greeting"
+	domainIdentity "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/identity"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// greetingDMInterval paces onboarding DMs so a burst of joins does not look like spam
+const greetingDMInterval = 3 * time.Second
+
+type serviceGreeting struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+	sendService     domainSend.ISendUsecase
+	identity        domainIdentity.IIdentityResolver
+
+	batches *greetingBatches
+}
+
+func NewGreetingService(chatStorageRepo domainChatStorage.IChatStorageRepository, sendService domainSend.ISendUsecase) domainGreeting.IGreetingUsecase {
+	return &serviceGreeting{
+		chatStorageRepo: chatStorageRepo,
+		sendService:     sendService,
+		identity:        whatsapp.NewIdentityResolver(),
+		batches:         newGreetingBatches(backgroundJobs),
+	}
+}
+
+func (service *serviceGreeting) ListGreetings(ctx context.Context) (response domainGreeting.ListGreetingsResponse, err error) {
+	greetings, err := service.chatStorageRepo.GetGroupGreetings(ctx)
+	if err != nil {
+		return response, err
+	}
+
+	response.Greetings = make([]domainGreeting.Greeting, 0, len(greetings))
+	for _, greeting := range greetings {
+		response.Greetings = append(response.Greetings, toGreeting(greeting))
+	}
+
+	return response, nil
+}
+
+func (service *serviceGreeting) GetGreeting(ctx context.Context, request domainGreeting.GetGreetingRequest) (response domainGreeting.Greeting, err error) {
+	groupJID, err := moderationGroupJID(request.GroupID)
+	if err != nil {
+		return response, err
+	}
+
+	greeting, err := service.chatStorageRepo.GetGroupGreeting(ctx, groupJID)
+	if err != nil {
+		return response, err
+	}
+	if greeting == nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("group %s has no greetings configured", groupJID))
+	}
+
+	return toGreeting(greeting), nil
+}
+
+func (service *serviceGreeting) SetGreeting(ctx context.Context, request domainGreeting.SetGreetingRequest) (response domainGreeting.Greeting, err error) {
+	if err = validations.ValidateSetGreeting(ctx, &request); err != nil {
+		return response, err
+	}
+
+	groupJID, err := moderationGroupJID(request.GroupID)
+	if err != nil {
+		return response, err
+	}
+
+	greeting, err := service.chatStorageRepo.GetGroupGreeting(ctx, groupJID)
+	if err != nil {
+		return response, err
+	}
+	if greeting == nil {
+		greeting = &domainChatStorage.GroupGreeting{GroupJID: groupJID}
+	}
+
+	greeting.WelcomeEnabled = request.WelcomeEnabled
+	greeting.WelcomeTemplate = request.WelcomeTemplate
+	greeting.GoodbyeEnabled = request.GoodbyeEnabled
+	greeting.GoodbyeTemplate = request.GoodbyeTemplate
+	greeting.DMEnabled = request.DMEnabled
+	greeting.DMTemplate = request.DMTemplate
+	greeting.BatchSeconds = *request.BatchSeconds
+
+	switch {
+	case request.RulesDocument != nil:
+		file, err := request.RulesDocument.Open()
+		if err != nil {
+			return response, err
+		}
+		defer file.Close()
+
+		if greeting.RulesDocument, err = io.ReadAll(file); err != nil {
+			return response, err
+		}
+		greeting.RulesDocumentName = request.RulesDocument.Filename
+	case request.RemoveRulesDocument:
+		greeting.RulesDocument, greeting.RulesDocumentName = nil, ""
+	}
+
+	if err = service.chatStorageRepo.StoreGroupGreeting(ctx, greeting); err != nil {
+		return response, err
+	}
+
+	return toGreeting(greeting), nil
+}
+
+func (service *serviceGreeting) DeleteGreeting(ctx context.Context, request domainGreeting.DeleteGreetingRequest) (err error) {
+	if err = validations.ValidateDeleteGreeting(ctx, request); err != nil {
+		return err
+	}
+
+	groupJID, err := moderationGroupJID(request.GroupID)
+	if err != nil {
+		return err
+	}
+
+	return service.chatStorageRepo.DeleteGroupGreeting(ctx, groupJID)
+}
+
+// GreetMembers queues members that joined or left a group. They are greeted together once the
+// batch window of the group passes, so a wave of joins produces a single welcome message.
+func (service *serviceGreeting) GreetMembers(ctx context.Context, groupJID types.JID, joined, left []types.JID) {
+	greeting, err := service.chatStorageRepo.GetGroupGreeting(ctx, groupJID.String())
+	if err != nil {
+		logrus.Warnf("Failed to load greetings of %s: %v", groupJID, err)
+		return
+	}
+	if greeting == nil {
+		return
+	}
+
+	joined = withoutSelf(ctx, joined)
+	left = withoutSelf(ctx, left)
+	if !greeting.WelcomeEnabled && !greeting.DMEnabled {
+		joined = nil
+	}
+	if !greeting.GoodbyeEnabled {
+		left = nil
+	}
+	if len(joined) == 0 && len(left) == 0 {
+		return
+	}
+
+	// The batch is sent from the session that saw the changes
+	window := time.Duration(greeting.BatchSeconds) * time.Second
+	service.batches.add(ctx, groupJID, joined, left, window, service.flushGreetings)
+}
+
+// flushGreetings sends the messages of a finished batch. The configuration is read again,
+// since it may have changed while the batch was collecting.
+func (service *serviceGreeting) flushGreetings(ctx context.Context, groupJID types.JID, batch greetingBatch) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("Greeting members of %s panicked: %v", groupJID, r)
+		}
+	}()
+
+	greeting, err := service.chatStorageRepo.GetGroupGreeting(ctx, groupJID.String())
+	if err != nil || greeting == nil {
+		return
+	}
+
+	groupName := service.groupName(ctx, groupJID)
+
+	if greeting.WelcomeEnabled && len(batch.joined) > 0 {
+		service.sendGreeting(ctx, groupJID.String(), greeting, greeting.WelcomeTemplate, service.mentions(ctx, batch.joined), groupName, true)
+	}
+	if greeting.GoodbyeEnabled && len(batch.left) > 0 {
+		service.sendGreeting(ctx, groupJID.String(), greeting, greeting.GoodbyeTemplate, service.mentions(ctx, batch.left), groupName, false)
+	}
+
+	if greeting.DMEnabled {
+		sent := 0
+		for _, member := range batch.joined {
+			phone := service.identity.Canonical(ctx, member)
+			if phone.Server != types.DefaultUserServer {
+				logrus.Warnf("Skipping onboarding DM to %s in %s, phone number is not known", member, groupJID)
+				continue
+			}
+			if sent > 0 && !sleepContext(ctx, greetingDMInterval) {
+				logrus.Warnf("Stopped onboarding DMs in %s, shutting down", groupJID)
+				return
+			}
+			sent++
+			service.sendGreeting(ctx, phone.String(), greeting, greeting.DMTemplate, service.mentions(ctx, []types.JID{member}), groupName, true)
+		}
+	}
+}
+
+// sendGreeting sends a rendered template, followed by the rules document when withRules is set
+func (service *serviceGreeting) sendGreeting(ctx context.Context, recipient string, greeting *domainChatStorage.GroupGreeting, template string, users []string, groupName string, withRules bool) {
+	text := renderGreeting(template, users, groupName)
+	request := domainSend.MessageRequest{BaseRequest: domainSend.BaseRequest{Phone: recipient}, Message: text}
+	if _, err := service.sendService.SendText(ctx, request); err != nil {
+		logrus.Warnf("Failed to send greeting to %s: %v", recipient, err)
+		return
+	}
+
+	if !withRules || greeting.RulesDocumentName == "" {
+		return
+	}
+
+	file, err := greetingDocument(greeting.RulesDocumentName, greeting.RulesDocument)
+	if err != nil {
+		logrus.Warnf("Failed to prepare rules document of %s: %v", greeting.GroupJID, err)
+		return
+	}
+	if _, err := service.sendService.SendFile(ctx, domainSend.FileRequest{BaseRequest: domainSend.BaseRequest{Phone: recipient}, File: file}); err != nil {
+		logrus.Warnf("Failed to send rules document to %s: %v", recipient, err)
+	}
+}
+
+// mentions renders members as @phone so SendText turns them into real mentions. Members whose
+// phone number is hidden behind a LID fall back to their push name.
+func (service *serviceGreeting) mentions(ctx context.Context, members []types.JID) []string {
+	client := whatsapp.ClientFromContext(ctx)
+
+	result := make([]string, 0, len(members))
+	for _, member := range members {
+		if phone := service.identity.Canonical(ctx, member); phone.Server == types.DefaultUserServer {
+			result = append(result, "@"+phone.User)
+			continue
+		}
+
+		name := "a new member"
+		if client != nil && client.Store != nil && client.Store.Contacts != nil {
+			if contact, err := client.Store.Contacts.GetContact(ctx, member); err == nil && contact.PushName != "" {
+				name = contact.PushName
+			}
+		}
+		result = append(result, name)
+	}
+
+	return result
+}
+
+func (service *serviceGreeting) groupName(ctx context.Context, groupJID types.JID) string {
+	if client := whatsapp.ClientFromContext(ctx); client != nil && client.IsLoggedIn() {
+		if info, err := client.GetGroupInfo(ctx, groupJID); err == nil && info.Name != "" {
+			return info.Name
+		}
+	}
+	if chat, err := service.chatStorageRepo.GetChat(ctx, groupJID.String()); err == nil && chat != nil && chat.Name != "" {
+		return chat.Name
+	}
+	return groupJID.User
+}
+
+// withoutSelf drops our own account, which shows up when we are added to a group
+func withoutSelf(ctx context.Context, members []types.JID) []types.JID {
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil || client.Store == nil || client.Store.ID == nil {
+		return members
+	}
+
+	self := map[string]bool{client.Store.ID.User: true, client.Store.GetLID().User: true}
+	result := members[:0:0]
+	for _, member := range members {
+		if !self[member.User] {
+			result = append(result, member)
+		}
+	}
+	return result
+}
+
+// renderGreeting fills in the {user}, {group} and {count} placeholders of a template
+func renderGreeting(template string, users []string, groupName string) string {
+	var names string
+	switch len(users) {
+	case 0:
+	case 1:
+		names = users[0]
+	default:
+		names = strings.Join(users[:len(users)-1], ", ") + " and " + users[len(users)-1]
+	}
+
+	return strings.NewReplacer(
+		"{user}", names,
+		"{group}", groupName,
+		"{count}", strconv.Itoa(len(users)),
+	).Replace(template)
+}
+
+// greetingDocument wraps a stored rules document in a file header, which is what the send usecase takes
+func greetingDocument(name string, data []byte) (*multipart.FileHeader, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = part.Write(data); err != nil {
+		return nil, err
+	}
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(int64(len(data)) + 1<<20)
+	if err != nil {
+		return nil, err
+	}
+	return form.File["file"][0], nil
+}
+
+// greetingBatch holds the members that joined and left a group during one batch window
+type greetingBatch struct {
+	joined []types.JID
+	left   []types.JID
+}
+
+// greetingBatchKey identifies a batch. Every session in a group collects its own batch, since
+// each session greets with its own configuration.
+type greetingBatchKey struct {
+	sessionID string
+	groupJID  types.JID
+}
+
+// greetingBatches collects membership changes per session and group until their batch window
+// passes. The windows run as background jobs, so the shutdown drops the pending batches before
+// the clients disconnect.
+type greetingBatches struct {
+	jobs *backgroundJobRunner
+
+	mu      sync.Mutex
+	pending map[greetingBatchKey]*greetingBatch
+}
+
+func newGreetingBatches(jobs *backgroundJobRunner) *greetingBatches {
+	return &greetingBatches{jobs: jobs, pending: make(map[greetingBatchKey]*greetingBatch)}
+}
+
+// add queues members of a group seen by the context session. The first change of a batch starts
+// the window; flush is called once it passes with everything collected in the meantime, with a
+// context that keeps the session of ctx.
+func (batches *greetingBatches) add(ctx context.Context, groupJID types.JID, joined, left []types.JID, window time.Duration, flush func(context.Context, types.JID, greetingBatch)) {
+	batches.mu.Lock()
+	defer batches.mu.Unlock()
+
+	key := greetingBatchKey{sessionID: domainSession.FromContext(ctx), groupJID: groupJID}
+	batch, ok := batches.pending[key]
+	if !ok {
+		batch = &greetingBatch{}
+		batches.pending[key] = batch
+		batches.jobs.start(ctx, func(ctx context.Context) {
+			waited := sleepContext(ctx, window)
+
+			batches.mu.Lock()
+			done := *batches.pending[key]
+			delete(batches.pending, key)
+			batches.mu.Unlock()
+
+			if !waited {
+				logrus.Warnf("Dropped greetings of %d members of %s, shutting down", len(done.joined)+len(done.left), groupJID)
+				return
+			}
+			flush(ctx, groupJID, done)
+		})
+	}
+
+	batch.joined = appendMembers(batch.joined, joined)
+	batch.left = appendMembers(batch.left, left)
+}
+
+// appendMembers adds members that are not in the list yet
+func appendMembers(list, members []types.JID) []types.JID {
+	for _, member := range members {
+		if !slices.Contains(list, member) {
+			list = append(list, member)
+		}
+	}
+	return list
+}
+
+func toGreeting(greeting *domainChatStorage.GroupGreeting) domainGreeting.Greeting {
+	return domainGreeting.Greeting{
+		GroupID:           greeting.GroupJID,
+		WelcomeEnabled:    greeting.WelcomeEnabled,
+		WelcomeTemplate:   greeting.WelcomeTemplate,
+		GoodbyeEnabled:    greeting.GoodbyeEnabled,
+		GoodbyeTemplate:   greeting.GoodbyeTemplate,
+		DMEnabled:         greeting.DMEnabled,
+		DMTemplate:        greeting.DMTemplate,
+		BatchSeconds:      greeting.BatchSeconds,
+		RulesDocumentName: greeting.RulesDocumentName,
+		CreatedAt:         greeting.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:         greeting.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestRenderGreeting(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		users    []string
+		want     string
+	}{
+		{name: "single member", template: "Welcome {user} to {group}!", users: []string{"@628111"}, want: "Welcome @628111 to Gophers!"},
+		{name: "two members", template: "Welcome {user}", users: []string{"@628111", "@628222"}, want: "Welcome @628111 and @628222"},
+		{name: "several members", template: "{count} new: {user}", users: []string{"@1", "@2", "@3"}, want: "3 new: @1, @2 and @3"},
+		{name: "placeholders repeated", template: "{group} says bye to {user}. {group} misses you", users: []string{"Ann"}, want: "Gophers says bye to Ann. Gophers misses you"},
+		{name: "no placeholders", template: "Hello", users: []string{"@1"}, want: "Hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, renderGreeting(tt.template, tt.users, "Gophers"))
+		})
+	}
+}
+
+func TestGreetingBatches(t *testing.T) {
+	group := types.NewJID("120363000000000001", types.GroupServer)
+	member := func(user string) types.JID { return types.NewJID(user, types.DefaultUserServer) }
+
+	flushed := make(chan greetingBatch, 2)
+	flush := func(_ context.Context, jid types.JID, batch greetingBatch) {
+		assert.Equal(t, group, jid)
+		flushed <- batch
+	}
+
+	batches := newGreetingBatches(newBackgroundJobRunner())
+	batches.add(context.Background(), group, []types.JID{member("1")}, nil, 50*time.Millisecond, flush)
+	batches.add(context.Background(), group, []types.JID{member("2"), member("1")}, []types.JID{member("3")}, 50*time.Millisecond, flush)
+
+	select {
+	case batch := <-flushed:
+		assert.Equal(t, []types.JID{member("1"), member("2")}, batch.joined)
+		assert.Equal(t, []types.JID{member("3")}, batch.left)
+	case <-time.After(time.Second):
+		t.Fatal("batch was not flushed")
+	}
+
+	// A change after the flush starts a new batch
+	batches.add(context.Background(), group, []types.JID{member("4")}, nil, 0, flush)
+	select {
+	case batch := <-flushed:
+		assert.Equal(t, []types.JID{member("4")}, batch.joined)
+		assert.Empty(t, batch.left)
+	case <-time.After(time.Second):
+		t.Fatal("second batch was not flushed")
+	}
+}
+
+func TestGreetingBatchesAreDroppedOnShutdown(t *testing.T) {
+	group := types.NewJID("120363000000000001", types.GroupServer)
+	flushed := make(chan greetingBatch, 1)
+	flush := func(_ context.Context, _ types.JID, batch greetingBatch) { flushed <- batch }
+
+	jobs := newBackgroundJobRunner()
+	batches := newGreetingBatches(jobs)
+	batches.add(context.Background(), group, []types.JID{types.NewJID("1", types.DefaultUserServer)}, nil, time.Hour, flush)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, jobs.stop(ctx), "the batch window must end with the shutdown")
+
+	assert.Empty(t, flushed)
+	assert.Empty(t, batches.pending)
+}
+
+func TestGreetingDocument(t *testing.T) {
+	file, err := greetingDocument("rules.pdf", []byte("%PDF-1.4 rules"))
+	require.NoError(t, err)
+	assert.Equal(t, "rules.pdf", file.Filename)
+	assert.Equal(t, int64(14), file.Size)
+
+	reader, err := file.Open()
+	require.NoError(t, err)
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "%PDF-1.4 rules", string(content))
+}
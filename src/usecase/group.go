@@ -3,11 +3,15 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
@@ -16,19 +20,25 @@ import (
 	"go.mau.fi/whatsmeow/types"
 )
 
-type serviceGroup struct{}
+type serviceGroup struct {
+	sendService     domainSend.ISendUsecase
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
 
-func NewGroupService() domainGroup.IGroupUsecase {
-	return &serviceGroup{}
+func NewGroupService(sendService domainSend.ISendUsecase, chatStorageRepo domainChatStorage.IChatStorageRepository) domainGroup.IGroupUsecase {
+	return &serviceGroup{
+		sendService:     sendService,
+		chatStorageRepo: chatStorageRepo,
+	}
 }
 
 func (service serviceGroup) JoinGroupWithLink(ctx context.Context, request domainGroup.JoinGroupWithLinkRequest) (groupID string, err error) {
 	if err = validations.ValidateJoinGroupWithLink(ctx, request); err != nil {
 		return groupID, err
 	}
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	jid, err := whatsapp.GetClient().JoinGroupWithLink(ctx, request.Link)
+	jid, err := whatsapp.ClientFromContext(ctx).JoinGroupWithLink(ctx, request.Link)
 	if err != nil {
 		return
 	}
@@ -40,33 +50,42 @@ func (service serviceGroup) LeaveGroup(ctx context.Context, request domainGroup.
 		return err
 	}
 
-	JID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	JID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return err
 	}
 
-	return whatsapp.GetClient().LeaveGroup(ctx, JID)
+	return whatsapp.ClientFromContext(ctx).LeaveGroup(ctx, JID)
 }
 
 func (service serviceGroup) CreateGroup(ctx context.Context, request domainGroup.CreateGroupRequest) (groupID string, err error) {
 	if err = validations.ValidateCreateGroup(ctx, request); err != nil {
 		return groupID, err
 	}
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	participantsJID, err := service.participantToJID(request.Participants)
+	participantsJID, err := service.participantToJID(ctx, request.Participants)
 	if err != nil {
 		return
 	}
 
+	linkedParent := types.GroupLinkedParent{}
+	if request.CommunityID != "" {
+		communityJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.CommunityID)
+		if err != nil {
+			return groupID, err
+		}
+		linkedParent.LinkedParentJID = communityJID
+	}
+
 	groupConfig := whatsmeow.ReqCreateGroup{
 		Name:              request.Title,
 		Participants:      participantsJID,
 		GroupParent:       types.GroupParent{},
-		GroupLinkedParent: types.GroupLinkedParent{},
+		GroupLinkedParent: linkedParent,
 	}
 
-	groupInfo, err := whatsapp.GetClient().CreateGroup(ctx, groupConfig)
+	groupInfo, err := whatsapp.ClientFromContext(ctx).CreateGroup(ctx, groupConfig)
 	if err != nil {
 		return
 	}
@@ -78,9 +97,9 @@ func (service serviceGroup) GetGroupInfoFromLink(ctx context.Context, request do
 	if err = validations.ValidateGetGroupInfoFromLink(ctx, request); err != nil {
 		return response, err
 	}
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	groupInfo, err := whatsapp.GetClient().GetGroupInfoFromLink(ctx, request.Link)
+	groupInfo, err := whatsapp.ClientFromContext(ctx).GetGroupInfoFromLink(ctx, request.Link)
 	if err != nil {
 		return response, err
 	}
@@ -104,19 +123,19 @@ func (service serviceGroup) ManageParticipant(ctx context.Context, request domai
 	if err = validations.ValidateParticipant(ctx, request); err != nil {
 		return result, err
 	}
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return result, err
 	}
 
-	participantsJID, err := service.participantToJID(request.Participants)
+	participantsJID, err := service.participantToJID(ctx, request.Participants)
 	if err != nil {
 		return result, err
 	}
 
-	participants, err := whatsapp.GetClient().UpdateGroupParticipants(ctx, groupJID, participantsJID, request.Action)
+	participants, err := whatsapp.ClientFromContext(ctx).UpdateGroupParticipants(ctx, groupJID, participantsJID, request.Action)
 	if err != nil {
 		return result, err
 	}
@@ -145,12 +164,12 @@ func (service serviceGroup) GetGroupParticipants(ctx context.Context, request do
 		return response, err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return response, err
 	}
 
-	groupInfo, err := whatsapp.GetClient().GetGroupInfo(ctx, groupJID)
+	groupInfo, err := whatsapp.ClientFromContext(ctx).GetGroupInfo(ctx, groupJID)
 	if err != nil {
 		return response, err
 	}
@@ -181,12 +200,12 @@ func (service serviceGroup) GetGroupRequestParticipants(ctx context.Context, req
 		return result, err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return result, err
 	}
 
-	participants, err := whatsapp.GetClient().GetGroupRequestParticipants(ctx, groupJID)
+	participants, err := whatsapp.ClientFromContext(ctx).GetGroupRequestParticipants(ctx, groupJID)
 	if err != nil {
 		return result, err
 	}
@@ -206,17 +225,17 @@ func (service serviceGroup) ManageGroupRequestParticipants(ctx context.Context,
 		return result, err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return result, err
 	}
 
-	participantsJID, err := service.participantToJID(request.Participants)
+	participantsJID, err := service.participantToJID(ctx, request.Participants)
 	if err != nil {
 		return result, err
 	}
 
-	participants, err := whatsapp.GetClient().UpdateGroupRequestParticipants(ctx, groupJID, participantsJID, request.Action)
+	participants, err := whatsapp.ClientFromContext(ctx).UpdateGroupRequestParticipants(ctx, groupJID, participantsJID, request.Action)
 	if err != nil {
 		return result, err
 	}
@@ -240,12 +259,12 @@ func (service serviceGroup) ManageGroupRequestParticipants(ctx context.Context,
 	return result, nil
 }
 
-func (service serviceGroup) participantToJID(participants []string) ([]types.JID, error) {
+func (service serviceGroup) participantToJID(ctx context.Context, participants []string) ([]types.JID, error) {
 	var participantsJID []types.JID
 	for _, participant := range participants {
 		formattedParticipant := participant + config.WhatsappTypeUser
 
-		if !utils.IsOnWhatsapp(whatsapp.GetClient(), formattedParticipant) {
+		if !utils.IsOnWhatsapp(whatsapp.ClientFromContext(ctx), formattedParticipant) {
 			return nil, pkgError.ErrUserNotRegistered
 		}
 
@@ -261,13 +280,23 @@ func (service serviceGroup) SetGroupPhoto(ctx context.Context, request domainGro
 		return pictureID, err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return pictureID, err
 	}
 
 	var photoBytes []byte
-	if request.Photo != nil {
+	if request.PhotoURL != "" {
+		logrus.Printf("Processing group photo from URL: %s", request.PhotoURL)
+
+		processedImageBuffer, err := utils.ProcessGroupPhotoFromURL(request.PhotoURL)
+		if err != nil {
+			logrus.Printf("Failed to process group photo: %v", err)
+			return pictureID, err
+		}
+
+		photoBytes = processedImageBuffer.Bytes()
+	} else if request.Photo != nil {
 		// Process the image for WhatsApp group photo requirements
 		logrus.Printf("Processing group photo: %s (size: %d bytes)", request.Photo.Filename, request.Photo.Size)
 
@@ -284,7 +313,7 @@ func (service serviceGroup) SetGroupPhoto(ctx context.Context, request domainGro
 		photoBytes = processedImageBuffer.Bytes()
 	}
 
-	pictureID, err = whatsapp.GetClient().SetGroupPhoto(ctx, groupJID, photoBytes)
+	pictureID, err = whatsapp.ClientFromContext(ctx).SetGroupPhoto(ctx, groupJID, photoBytes)
 	if err != nil {
 		logrus.Printf("Failed to set group photo: %v", err)
 		return pictureID, err
@@ -298,12 +327,12 @@ func (service serviceGroup) SetGroupName(ctx context.Context, request domainGrou
 		return err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return err
 	}
 
-	return whatsapp.GetClient().SetGroupName(ctx, groupJID, request.Name)
+	return whatsapp.ClientFromContext(ctx).SetGroupName(ctx, groupJID, request.Name)
 }
 
 func (service serviceGroup) SetGroupLocked(ctx context.Context, request domainGroup.SetGroupLockedRequest) (err error) {
@@ -311,12 +340,12 @@ func (service serviceGroup) SetGroupLocked(ctx context.Context, request domainGr
 		return err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return err
 	}
 
-	return whatsapp.GetClient().SetGroupLocked(ctx, groupJID, request.Locked)
+	return whatsapp.ClientFromContext(ctx).SetGroupLocked(ctx, groupJID, request.Locked)
 }
 
 func (service serviceGroup) SetGroupAnnounce(ctx context.Context, request domainGroup.SetGroupAnnounceRequest) (err error) {
@@ -324,12 +353,12 @@ func (service serviceGroup) SetGroupAnnounce(ctx context.Context, request domain
 		return err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return err
 	}
 
-	return whatsapp.GetClient().SetGroupAnnounce(ctx, groupJID, request.Announce)
+	return whatsapp.ClientFromContext(ctx).SetGroupAnnounce(ctx, groupJID, request.Announce)
 }
 
 func (service serviceGroup) SetGroupTopic(ctx context.Context, request domainGroup.SetGroupTopicRequest) (err error) {
@@ -337,13 +366,89 @@ func (service serviceGroup) SetGroupTopic(ctx context.Context, request domainGro
 		return err
 	}
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return err
 	}
 
 	// SetGroupTopic with auto-generated IDs (previousID and newID will be handled automatically)
-	return whatsapp.GetClient().SetGroupTopic(ctx, groupJID, "", "", request.Topic)
+	return whatsapp.ClientFromContext(ctx).SetGroupTopic(ctx, groupJID, "", "", request.Topic)
+}
+
+func (service serviceGroup) SetGroupMemberAddMode(ctx context.Context, request domainGroup.SetGroupMemberAddModeRequest) (err error) {
+	if err = validations.ValidateSetGroupMemberAddMode(ctx, request); err != nil {
+		return err
+	}
+
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
+	if err != nil {
+		return err
+	}
+
+	return whatsapp.ClientFromContext(ctx).SetGroupMemberAddMode(ctx, groupJID, request.Mode)
+}
+
+func (service serviceGroup) SetGroupJoinApproval(ctx context.Context, request domainGroup.SetGroupJoinApprovalRequest) (err error) {
+	if err = validations.ValidateSetGroupJoinApproval(ctx, request); err != nil {
+		return err
+	}
+
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
+	if err != nil {
+		return err
+	}
+
+	return whatsapp.ClientFromContext(ctx).SetGroupJoinApprovalMode(ctx, groupJID, request.Enabled)
+}
+
+func (service serviceGroup) SetGroupDisappearing(ctx context.Context, request domainGroup.SetGroupDisappearingRequest) (err error) {
+	if err = validations.ValidateSetGroupDisappearing(ctx, request); err != nil {
+		return err
+	}
+
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
+	if err != nil {
+		return err
+	}
+
+	return whatsapp.ClientFromContext(ctx).SetDisappearingTimer(ctx, groupJID, time.Duration(request.Timer)*time.Second, time.Now())
+}
+
+// SetGroupDescription replaces the group description. WhatsApp identifies every description
+// by an ID and expects the ID being replaced, so the current one is read from the group info.
+// When the caller passes the ID they last saw and it no longer matches, the update is refused
+// instead of silently overwriting someone else's change.
+func (service serviceGroup) SetGroupDescription(ctx context.Context, request domainGroup.SetGroupDescriptionRequest) (response domainGroup.SetGroupDescriptionResponse, err error) {
+	if err = validations.ValidateSetGroupDescription(ctx, request); err != nil {
+		return response, err
+	}
+
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
+	if err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	groupInfo, err := client.GetGroupInfo(ctx, groupJID)
+	if err != nil {
+		return response, err
+	}
+
+	previousID := groupInfo.TopicID
+	if request.PreviousID != "" && request.PreviousID != previousID {
+		return response, pkgError.ValidationError(fmt.Sprintf("previous_id: description was changed in the meantime, current id is %s", previousID))
+	}
+
+	newID := client.GenerateMessageID()
+	if err = client.SetGroupTopic(ctx, groupJID, previousID, newID, request.Description); err != nil {
+		return response, err
+	}
+
+	return domainGroup.SetGroupDescriptionResponse{
+		GroupID:       groupJID.String(),
+		DescriptionID: newID,
+		PreviousID:    previousID,
+	}, nil
 }
 
 // GroupInfo retrieves detailed information about a WhatsApp group
@@ -354,23 +459,26 @@ func (service serviceGroup) GroupInfo(ctx context.Context, request domainGroup.G
 	}
 
 	// Ensure we are logged in
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
 	// Validate and parse the provided group JID / ID
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return response, err
 	}
 
 	// Fetch group information from WhatsApp
-	groupInfo, err := whatsapp.GetClient().GetGroupInfo(ctx, groupJID)
+	groupInfo, err := whatsapp.ClientFromContext(ctx).GetGroupInfo(ctx, groupJID)
 	if err != nil {
 		return response, err
 	}
 
 	// Map the response
 	if groupInfo != nil {
-		response.Data = *groupInfo
+		response.Data = domainGroup.GroupInfo{
+			GroupInfo: *groupInfo,
+			Community: service.communityInfo(ctx, groupInfo),
+		}
 	}
 
 	return response, nil
@@ -380,14 +488,14 @@ func (service serviceGroup) GetGroupInviteLink(ctx context.Context, request doma
 	if err = validations.ValidateGetGroupInviteLink(ctx, request); err != nil {
 		return response, err
 	}
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	groupJID, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.GroupID)
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
 	if err != nil {
 		return response, err
 	}
 
-	inviteLink, err := whatsapp.GetClient().GetGroupInviteLink(ctx, groupJID, request.Reset)
+	inviteLink, err := whatsapp.ClientFromContext(ctx).GetGroupInviteLink(ctx, groupJID, request.Reset)
 	if err != nil {
 		return response, err
 	}
@@ -395,7 +503,351 @@ func (service serviceGroup) GetGroupInviteLink(ctx context.Context, request doma
 	response = domainGroup.GetGroupInviteLinkResponse{
 		InviteLink: inviteLink,
 		GroupID:    request.GroupID,
+		Reset:      request.Reset,
+	}
+
+	return response, nil
+}
+
+func (service serviceGroup) CreateCommunity(ctx context.Context, request domainGroup.CreateCommunityRequest) (response domainGroup.CreateCommunityResponse, err error) {
+	if err = validations.ValidateCreateCommunity(ctx, request); err != nil {
+		return response, err
+	}
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
+
+	communityInfo, err := whatsapp.ClientFromContext(ctx).CreateGroup(ctx, whatsmeow.ReqCreateGroup{
+		Name: request.Name,
+		GroupParent: types.GroupParent{
+			IsParent:                      true,
+			DefaultMembershipApprovalMode: "request_required",
+		},
+	})
+	if err != nil {
+		return response, err
+	}
+	response.CommunityID = communityInfo.JID.String()
+
+	if request.Description != "" {
+		if err = whatsapp.ClientFromContext(ctx).SetGroupTopic(ctx, communityInfo.JID, "", "", request.Description); err != nil {
+			logrus.Warnf("Community %s created but setting its description failed: %v", communityInfo.JID, err)
+		}
+	}
+
+	// WhatsApp creates the announcement group together with the community;
+	// it is not part of the create response so look it up separately.
+	if subGroups, err := whatsapp.ClientFromContext(ctx).GetSubGroups(ctx, communityInfo.JID); err == nil {
+		response.AnnouncementGroupID = announcementGroupID(subGroups)
+	} else {
+		logrus.Warnf("Failed to fetch announcement group of community %s: %v", communityInfo.JID, err)
+	}
+
+	return response, nil
+}
+
+func (service serviceGroup) LinkGroup(ctx context.Context, request domainGroup.LinkGroupRequest) (err error) {
+	if err = validations.ValidateLinkGroup(ctx, request); err != nil {
+		return err
+	}
+
+	communityJID, groupJID, err := service.parseCommunityAndGroup(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	return whatsapp.ClientFromContext(ctx).LinkGroup(ctx, communityJID, groupJID)
+}
+
+func (service serviceGroup) UnlinkGroup(ctx context.Context, request domainGroup.LinkGroupRequest) (err error) {
+	if err = validations.ValidateLinkGroup(ctx, request); err != nil {
+		return err
+	}
+
+	communityJID, groupJID, err := service.parseCommunityAndGroup(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	return whatsapp.ClientFromContext(ctx).UnlinkGroup(ctx, communityJID, groupJID)
+}
+
+func (service serviceGroup) GetSubGroups(ctx context.Context, request domainGroup.GetSubGroupsRequest) (response domainGroup.GetSubGroupsResponse, err error) {
+	if err = validations.ValidateGetSubGroups(ctx, request); err != nil {
+		return response, err
+	}
+
+	communityJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.CommunityID)
+	if err != nil {
+		return response, err
+	}
+
+	subGroups, err := whatsapp.ClientFromContext(ctx).GetSubGroups(ctx, communityJID)
+	if err != nil {
+		return response, err
+	}
+
+	response.CommunityID = communityJID.String()
+	response.AnnouncementGroupID = announcementGroupID(subGroups)
+	response.SubGroups = toSubGroups(subGroups)
+
+	return response, nil
+}
+
+func (service serviceGroup) SendCommunityAnnouncement(ctx context.Context, request domainGroup.SendCommunityAnnouncementRequest) (response domainSend.GenericResponse, err error) {
+	if err = validations.ValidateSendCommunityAnnouncement(ctx, request); err != nil {
+		return response, err
+	}
+
+	communityJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.CommunityID)
+	if err != nil {
+		return response, err
+	}
+
+	subGroups, err := whatsapp.ClientFromContext(ctx).GetSubGroups(ctx, communityJID)
+	if err != nil {
+		return response, err
+	}
+
+	announcementID := announcementGroupID(subGroups)
+	if announcementID == "" {
+		return response, pkgError.ValidationError(fmt.Sprintf("community %s has no announcement group", communityJID))
+	}
+
+	return service.sendService.SendText(ctx, domainSend.MessageRequest{
+		BaseRequest: domainSend.BaseRequest{Phone: announcementID},
+		Message:     request.Message,
+	})
+}
+
+func (service serviceGroup) parseCommunityAndGroup(ctx context.Context, request domainGroup.LinkGroupRequest) (communityJID, groupJID types.JID, err error) {
+	communityJID, err = utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.CommunityID)
+	if err != nil {
+		return communityJID, groupJID, err
+	}
+
+	groupJID, err = utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
+	return communityJID, groupJID, err
+}
+
+// communityInfo describes the community side of a group. Lookups of linked
+// groups are best effort so a failure never breaks the group info response.
+func (service serviceGroup) communityInfo(ctx context.Context, groupInfo *types.GroupInfo) *domainGroup.CommunityInfo {
+	if !groupInfo.IsParent && groupInfo.LinkedParentJID.IsEmpty() {
+		return nil
+	}
+
+	info := &domainGroup.CommunityInfo{
+		IsCommunity:         groupInfo.IsParent,
+		IsAnnouncementGroup: groupInfo.IsDefaultSubGroup,
+	}
+	if !groupInfo.LinkedParentJID.IsEmpty() {
+		info.ParentID = groupInfo.LinkedParentJID.String()
+	}
+
+	if groupInfo.IsParent {
+		subGroups, err := whatsapp.ClientFromContext(ctx).GetSubGroups(ctx, groupInfo.JID)
+		if err != nil {
+			logrus.Warnf("Failed to fetch subgroups of community %s: %v", groupInfo.JID, err)
+			return info
+		}
+		info.AnnouncementGroupID = announcementGroupID(subGroups)
+		info.LinkedGroups = toSubGroups(subGroups)
+	}
+
+	return info
+}
+
+func announcementGroupID(subGroups []*types.GroupLinkTarget) string {
+	for _, subGroup := range subGroups {
+		if subGroup != nil && subGroup.IsDefaultSubGroup {
+			return subGroup.JID.String()
+		}
+	}
+	return ""
+}
+
+func toSubGroups(subGroups []*types.GroupLinkTarget) []domainGroup.SubGroup {
+	result := make([]domainGroup.SubGroup, 0, len(subGroups))
+	for _, subGroup := range subGroups {
+		if subGroup == nil {
+			continue
+		}
+		result = append(result, domainGroup.SubGroup{
+			GroupID:             subGroup.JID.String(),
+			Name:                subGroup.Name,
+			IsAnnouncementGroup: subGroup.IsDefaultSubGroup,
+		})
+	}
+	return result
+}
+
+func (service serviceGroup) GetGroupHistory(ctx context.Context, request domainGroup.GetGroupHistoryRequest) (response domainGroup.GetGroupHistoryResponse, err error) {
+	if err = validations.ValidateGetGroupHistory(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := &domainChatStorage.GroupEventFilter{
+		GroupJID:    request.GroupID,
+		Participant: request.Participant,
+		Actor:       request.Actor,
+		Limit:       request.Limit,
+		Offset:      request.Offset,
+	}
+	for _, eventType := range strings.Split(request.EventType, ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			filter.EventTypes = append(filter.EventTypes, eventType)
+		}
+	}
+	if request.StartTime != nil && *request.StartTime != "" {
+		startTime, _ := time.Parse(time.RFC3339, *request.StartTime)
+		filter.StartTime = &startTime
+	}
+	if request.EndTime != nil && *request.EndTime != "" {
+		endTime, _ := time.Parse(time.RFC3339, *request.EndTime)
+		filter.EndTime = &endTime
+	}
+
+	groupEvents, err := service.chatStorageRepo.GetGroupEvents(ctx, filter)
+	if err != nil {
+		return response, err
+	}
+
+	total, err := service.chatStorageRepo.CountGroupEvents(ctx, filter)
+	if err != nil {
+		return response, err
+	}
+
+	response.GroupID = request.GroupID
+	response.Events = make([]domainGroup.GroupHistoryEvent, 0, len(groupEvents))
+	for _, event := range groupEvents {
+		response.Events = append(response.Events, domainGroup.GroupHistoryEvent{
+			ID:          event.ID,
+			EventType:   event.EventType,
+			Participant: event.Participant,
+			Actor:       event.Actor,
+			Value:       event.Value,
+			Timestamp:   event.Timestamp.Format(time.RFC3339),
+		})
+	}
+	response.Pagination = domainGroup.GroupHistoryPagination{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
 	}
 
 	return response, nil
 }
+
+func (service serviceGroup) GetGroupMembership(ctx context.Context, request domainGroup.GetGroupMembershipRequest) (response domainGroup.GetGroupMembershipResponse, err error) {
+	if err = validations.ValidateGetGroupMembership(ctx, request); err != nil {
+		return response, err
+	}
+
+	var startTime, endTime *time.Time
+	if request.StartTime != nil && *request.StartTime != "" {
+		parsed, _ := time.Parse(time.RFC3339, *request.StartTime)
+		startTime = &parsed
+	}
+	if request.EndTime != nil && *request.EndTime != "" {
+		parsed, _ := time.Parse(time.RFC3339, *request.EndTime)
+		endTime = &parsed
+	}
+
+	// Changes after the requested range are still needed to walk the current member
+	// count back in time, so the range end is applied when building the series.
+	days, err := service.chatStorageRepo.GetGroupMembershipDaily(ctx, request.GroupID, startTime, nil)
+	if err != nil {
+		return response, err
+	}
+
+	response.GroupID = request.GroupID
+	response.CurrentMembers = service.currentMemberCount(ctx, request.GroupID)
+	response.Series = membershipSeries(days, startTime, endTime, time.Now(), response.CurrentMembers)
+
+	return response, nil
+}
+
+// currentMemberCount fetches the live participant count of a group. It returns nil when
+// WhatsApp cannot be reached so the membership series can still be served from storage.
+func (service serviceGroup) currentMemberCount(ctx context.Context, groupID string) *int64 {
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil || !client.IsLoggedIn() {
+		return nil
+	}
+
+	groupJID, err := types.ParseJID(groupID)
+	if err != nil {
+		return nil
+	}
+
+	groupInfo, err := client.GetGroupInfo(ctx, groupJID)
+	if err != nil || groupInfo == nil {
+		logrus.Warnf("Failed to fetch participant count of group %s: %v", groupID, err)
+		return nil
+	}
+
+	count := int64(len(groupInfo.Participants))
+	return &count
+}
+
+// membershipSeries turns daily join and leave counts into a continuous series from the
+// start of the range (or the first recorded change) to its end (or now). When the current
+// member count is known, each day also reports the members at the end of that day.
+func membershipSeries(days []*domainChatStorage.GroupMembershipDay, startTime, endTime *time.Time, now time.Time, current *int64) []domainGroup.GroupMembershipPoint {
+	const layout = "2006-01-02"
+
+	byDay := make(map[string]*domainChatStorage.GroupMembershipDay, len(days))
+	for _, day := range days {
+		byDay[day.Day] = day
+	}
+
+	var from time.Time
+	switch {
+	case startTime != nil:
+		from = startTime.UTC()
+	case len(days) > 0:
+		from, _ = time.Parse(layout, days[0].Day)
+	default:
+		return []domainGroup.GroupMembershipPoint{}
+	}
+
+	to := now.UTC()
+	if endTime != nil && endTime.Before(to) {
+		to = endTime.UTC()
+	}
+
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	if to.Before(from) {
+		return []domainGroup.GroupMembershipPoint{}
+	}
+
+	// Net change recorded after the last day of the series
+	var netAfter int64
+	lastDay := to.Format(layout)
+	for _, day := range days {
+		if day.Day > lastDay {
+			netAfter += day.Joined - day.Left
+		}
+	}
+
+	series := make([]domainGroup.GroupMembershipPoint, 0, int(to.Sub(from).Hours()/24)+1)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		point := domainGroup.GroupMembershipPoint{Date: day.Format(layout)}
+		if recorded, ok := byDay[point.Date]; ok {
+			point.Joined = recorded.Joined
+			point.Left = recorded.Left
+			point.Net = recorded.Joined - recorded.Left
+		}
+		series = append(series, point)
+	}
+
+	if current != nil {
+		for i := len(series) - 1; i >= 0; i-- {
+			members := *current - netAfter
+			series[i].Members = &members
+			netAfter += series[i].Net
+		}
+	}
+
+	return series
+}
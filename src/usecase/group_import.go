@@ -0,0 +1,392 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// maxImportParticipants caps a single import so a paced run stays within hours, not days
+	maxImportParticipants = 1000
+	// importLookupBatch is the number of numbers checked per IsOnWhatsApp request
+	importLookupBatch = 50
+)
+
+// participantImport holds what a background import run needs after the request returns
+type participantImport struct {
+	id            string
+	groupJID      types.JID
+	groupName     string
+	delay         time.Duration
+	inviteCaption string
+	entries       []*domainChatStorage.GroupImportEntry
+}
+
+func (service serviceGroup) ImportParticipants(ctx context.Context, request domainGroup.ImportParticipantsRequest) (response domainGroup.ImportReport, err error) {
+	if err = validations.ValidateImportParticipants(ctx, &request); err != nil {
+		return response, err
+	}
+
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
+	if err != nil {
+		return response, err
+	}
+
+	file, err := request.File.Open()
+	if err != nil {
+		return response, err
+	}
+	defer file.Close()
+
+	phones, err := parseParticipantCSV(file)
+	if err != nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("file: %v.", err))
+	}
+	if len(phones) == 0 {
+		return response, pkgError.ValidationError("file: must contain at least one phone number.")
+	}
+	if len(phones) > maxImportParticipants {
+		return response, pkgError.ValidationError(fmt.Sprintf("file: must contain at most %d phone numbers.", maxImportParticipants))
+	}
+
+	groupInfo, err := whatsapp.ClientFromContext(ctx).GetGroupInfo(ctx, groupJID)
+	if err != nil {
+		return response, err
+	}
+
+	run := &participantImport{
+		id:            uuid.NewString(),
+		groupJID:      groupJID,
+		groupName:     groupInfo.Name,
+		delay:         time.Duration(request.DelaySeconds) * time.Second,
+		inviteCaption: request.InviteCaption,
+	}
+	for _, phone := range phones {
+		run.entries = append(run.entries, &domainChatStorage.GroupImportEntry{
+			ImportID: run.id,
+			Phone:    phone,
+			Status:   domainChatStorage.GroupImportEntryPending,
+		})
+	}
+
+	groupImport := &domainChatStorage.GroupImport{
+		ID:       run.id,
+		GroupJID: groupJID.String(),
+		Status:   domainChatStorage.GroupImportRunning,
+		Total:    len(phones),
+	}
+	if err = service.chatStorageRepo.CreateGroupImport(ctx, groupImport, run.entries); err != nil {
+		return response, err
+	}
+
+	backgroundJobs.start(ctx, func(ctx context.Context) {
+		service.runParticipantImport(ctx, run)
+	})
+
+	return toImportReport(groupImport, run.entries), nil
+}
+
+func (service serviceGroup) GetImportReport(ctx context.Context, request domainGroup.GetImportReportRequest) (response domainGroup.ImportReport, err error) {
+	if err = validations.ValidateGetImportReport(ctx, request); err != nil {
+		return response, err
+	}
+
+	groupImport, err := service.chatStorageRepo.GetGroupImport(ctx, request.ImportID)
+	if err != nil {
+		return response, err
+	}
+	if groupImport == nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("import %s not found", request.ImportID))
+	}
+
+	entries, err := service.chatStorageRepo.GetGroupImportEntries(request.ImportID)
+	if err != nil {
+		return response, err
+	}
+
+	return toImportReport(groupImport, entries), nil
+}
+
+// runParticipantImport checks every number, adds the registered ones one at a time and
+// invites by DM those whose privacy settings block direct adds
+func (service serviceGroup) runParticipantImport(ctx context.Context, run *participantImport) {
+	status, errMessage := domainChatStorage.GroupImportCompleted, ""
+
+	defer func() {
+		if r := recover(); r != nil {
+			status, errMessage = domainChatStorage.GroupImportFailed, fmt.Sprint(r)
+		}
+		if err := service.chatStorageRepo.FinishGroupImport(run.id, status, errMessage); err != nil {
+			logrus.Errorf("Failed to finish participant import %s: %v", run.id, err)
+		}
+		logrus.Infof("Participant import %s into %s finished with status %s", run.id, run.groupJID, status)
+	}()
+
+	registered, err := service.lookupImportEntries(ctx, run.entries)
+	if ctx.Err() != nil {
+		status, errMessage = domainChatStorage.GroupImportFailed, jobInterruptedByShutdown
+		return
+	}
+	if err != nil {
+		status, errMessage = domainChatStorage.GroupImportFailed, err.Error()
+		return
+	}
+
+	for i, entry := range registered {
+		if i > 0 && !sleepContext(ctx, run.delay) {
+			status, errMessage = domainChatStorage.GroupImportFailed, jobInterruptedByShutdown
+			return
+		}
+		service.addImportEntry(ctx, run, entry)
+	}
+}
+
+// lookupImportEntries resolves the numbers in batches and returns the entries registered on WhatsApp
+func (service serviceGroup) lookupImportEntries(ctx context.Context, entries []*domainChatStorage.GroupImportEntry) ([]*domainChatStorage.GroupImportEntry, error) {
+	var registered []*domainChatStorage.GroupImportEntry
+
+	for start := 0; start < len(entries); start += importLookupBatch {
+		batch := entries[start:min(start+importLookupBatch, len(entries))]
+
+		queries := make([]string, len(batch))
+		for i, entry := range batch {
+			queries[i] = "+" + entry.Phone
+		}
+
+		client := whatsapp.ClientFromContext(ctx)
+		if client == nil {
+			return registered, pkgError.ErrWaCLI
+		}
+
+		results, err := client.IsOnWhatsApp(ctx, queries)
+		if err != nil {
+			return registered, fmt.Errorf("failed to check numbers on WhatsApp: %w", err)
+		}
+
+		found := make(map[string]types.IsOnWhatsAppResponse, len(results))
+		for _, result := range results {
+			found[strings.TrimPrefix(result.Query, "+")] = result
+		}
+
+		for _, entry := range batch {
+			if result, ok := found[entry.Phone]; ok && result.IsIn {
+				entry.JID = result.JID.ToNonAD().String()
+				registered = append(registered, entry)
+				continue
+			}
+
+			entry.Status = domainChatStorage.GroupImportEntryNotOnWhatsApp
+			entry.Message = "number is not registered on WhatsApp"
+			service.saveImportEntry(entry)
+		}
+	}
+
+	return registered, nil
+}
+
+// addImportEntry adds a single participant, falling back to a DM invite when WhatsApp
+// refuses the add because of the participant's privacy settings
+func (service serviceGroup) addImportEntry(ctx context.Context, run *participantImport, entry *domainChatStorage.GroupImportEntry) {
+	defer service.saveImportEntry(entry)
+
+	jid, err := types.ParseJID(entry.JID)
+	if err != nil {
+		entry.Status, entry.Message = domainChatStorage.GroupImportEntryFailed, err.Error()
+		return
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		entry.Status, entry.Message = domainChatStorage.GroupImportEntryFailed, pkgError.ErrWaCLI.Error()
+		return
+	}
+
+	results, err := client.UpdateGroupParticipants(ctx, run.groupJID, []types.JID{jid}, whatsmeow.ParticipantChangeAdd)
+	if err != nil {
+		entry.Status, entry.Message = domainChatStorage.GroupImportEntryFailed, err.Error()
+		return
+	}
+	if len(results) == 0 {
+		entry.Status, entry.Message = domainChatStorage.GroupImportEntryFailed, "no result returned for participant"
+		return
+	}
+
+	result := results[0]
+	switch {
+	case result.Error == 0:
+		entry.Status, entry.Message = domainChatStorage.GroupImportEntryAdded, ""
+	case result.Error == 409:
+		entry.Status, entry.Message = domainChatStorage.GroupImportEntryAlreadyMember, ""
+	case result.Error == 403 && result.AddRequest != nil:
+		if err := sendGroupInvite(ctx, client, jid, run, result.AddRequest); err != nil {
+			entry.Status, entry.Message = domainChatStorage.GroupImportEntryFailed, fmt.Sprintf("could not be added and the invite failed: %v", err)
+			return
+		}
+		now := time.Now()
+		entry.Status, entry.Message, entry.InvitedAt = domainChatStorage.GroupImportEntryInvited, "privacy settings prevent direct adds, invite sent by DM", &now
+	case result.Error == 403:
+		entry.Status, entry.Message = domainChatStorage.GroupImportEntryFailed, "privacy settings prevent direct adds"
+	default:
+		entry.Status, entry.Message = domainChatStorage.GroupImportEntryFailed, fmt.Sprintf("add failed (code %d)", result.Error)
+	}
+}
+
+func (service serviceGroup) saveImportEntry(entry *domainChatStorage.GroupImportEntry) {
+	if err := service.chatStorageRepo.UpdateGroupImportEntry(entry); err != nil {
+		logrus.Errorf("Failed to update participant import entry %s: %v", entry.Phone, err)
+	}
+}
+
+// sendGroupInvite sends the invite WhatsApp issued for a refused add as a GroupInviteMessage
+func sendGroupInvite(ctx context.Context, client *whatsmeow.Client, recipient types.JID, run *participantImport, addRequest *types.GroupParticipantAddRequest) error {
+	caption := run.inviteCaption
+	if caption == "" {
+		caption = fmt.Sprintf("You're invited to join %s", run.groupName)
+	}
+
+	_, err := client.SendMessage(ctx, recipient, &waE2E.Message{
+		GroupInviteMessage: &waE2E.GroupInviteMessage{
+			GroupJID:         proto.String(run.groupJID.String()),
+			InviteCode:       proto.String(addRequest.Code),
+			InviteExpiration: proto.Int64(addRequest.Expiration.Unix()),
+			GroupName:        proto.String(run.groupName),
+			Caption:          proto.String(caption),
+		},
+	})
+	return err
+}
+
+// parseParticipantCSV reads phone numbers from the first column, or from a column named
+// phone, phone_number or participant_jid when the file has a header row (such as the
+// participant export). Numbers are reduced to digits and duplicates are dropped.
+func parseParticipantCSV(reader io.Reader) ([]string, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	column := 0
+	seen := make(map[string]bool)
+	var phones []string
+
+	for line := 0; ; line++ {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		if line == 0 {
+			if headerColumn, ok := participantCSVColumn(record); ok {
+				column = headerColumn
+				continue
+			}
+		}
+
+		if column >= len(record) {
+			continue
+		}
+
+		phone := normalizeImportPhone(record[column])
+		if phone == "" || seen[phone] {
+			continue
+		}
+		seen[phone] = true
+		phones = append(phones, phone)
+	}
+
+	return phones, nil
+}
+
+// participantCSVColumn reports the phone column when the record is a header row
+func participantCSVColumn(record []string) (int, bool) {
+	for i, cell := range record {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(cell, "\ufeff"))) {
+		case "phone", "phone_number", "participant_jid", "jid", "number":
+			return i, true
+		}
+	}
+
+	for _, cell := range record {
+		if normalizeImportPhone(cell) != "" {
+			return 0, false
+		}
+	}
+
+	// A first row without any number is a header we do not recognise
+	return 0, true
+}
+
+// normalizeImportPhone keeps the digits of a phone number or user JID
+func normalizeImportPhone(value string) string {
+	value = strings.TrimSpace(value)
+	if at := strings.Index(value, "@"); at >= 0 {
+		if !strings.HasSuffix(value, "@"+types.DefaultUserServer) {
+			return ""
+		}
+		value = value[:at]
+		if colon := strings.Index(value, ":"); colon >= 0 {
+			value = value[:colon]
+		}
+	}
+
+	phone := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, value)
+
+	if len(phone) < 7 || len(phone) > 15 {
+		return ""
+	}
+	return phone
+}
+
+func toImportReport(groupImport *domainChatStorage.GroupImport, entries []*domainChatStorage.GroupImportEntry) domainGroup.ImportReport {
+	report := domainGroup.ImportReport{
+		ImportID:   groupImport.ID,
+		GroupID:    groupImport.GroupJID,
+		Status:     groupImport.Status,
+		Error:      groupImport.Error,
+		Total:      groupImport.Total,
+		Summary:    make(map[string]int),
+		CreatedAt:  groupImport.CreatedAt,
+		FinishedAt: groupImport.FinishedAt,
+		Entries:    make([]domainGroup.ImportEntry, 0, len(entries)),
+	}
+
+	for _, entry := range entries {
+		report.Summary[entry.Status]++
+		report.Entries = append(report.Entries, domainGroup.ImportEntry{
+			Phone:     entry.Phone,
+			JID:       entry.JID,
+			Status:    entry.Status,
+			Message:   entry.Message,
+			InvitedAt: entry.InvitedAt,
+			JoinedAt:  entry.JoinedAt,
+		})
+	}
+
+	return report
+}
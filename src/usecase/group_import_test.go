@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestParseParticipantCSV(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "plain list without header",
+			input: "6281234567890\n+62 812-3456-7891\n6281234567890\n",
+			want:  []string{"6281234567890", "6281234567891"},
+		},
+		{
+			name:  "participant export",
+			input: "participant_jid,phone_number,lid,display_name,role\n6281234567890@s.whatsapp.net,6281234567890@s.whatsapp.net,1234@lid,,member\n",
+			want:  []string{"6281234567890"},
+		},
+		{
+			name:  "named phone column",
+			input: "name,phone\nAlice,6281234567890\nBob,\nCarol,12\n",
+			want:  []string{"6281234567890"},
+		},
+		{
+			name:  "unknown header is skipped",
+			input: "contacts\n6281234567890\n",
+			want:  []string{"6281234567890"},
+		},
+		{
+			name:  "group and lid jids are ignored",
+			input: "120363025246125486@g.us\n1234567890@lid\n6281234567890:3@s.whatsapp.net\n",
+			want:  []string{"6281234567890"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseParticipantCSV(strings.NewReader(tt.input))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := parseParticipantCSV(strings.NewReader("\"unterminated\n"))
+	assert.Error(t, err)
+}
+
+func TestToImportReport(t *testing.T) {
+	groupImport := &domainChatStorage.GroupImport{
+		ID:       "import-1",
+		GroupJID: "120363025246125486@g.us",
+		Status:   domainChatStorage.GroupImportRunning,
+		Total:    3,
+	}
+	entries := []*domainChatStorage.GroupImportEntry{
+		{Phone: "6281234567890", Status: domainChatStorage.GroupImportEntryAdded},
+		{Phone: "6281234567891", Status: domainChatStorage.GroupImportEntryInvited, Message: "invite sent"},
+		{Phone: "6281234567892", Status: domainChatStorage.GroupImportEntryAdded},
+	}
+
+	report := toImportReport(groupImport, entries)
+
+	assert.Equal(t, "import-1", report.ImportID)
+	assert.Equal(t, "120363025246125486@g.us", report.GroupID)
+	assert.Equal(t, map[string]int{"added": 2, "invited": 1}, report.Summary)
+	assert.Len(t, report.Entries, 3)
+	assert.Equal(t, "invite sent", report.Entries[1].Message)
+}
+
+func TestParticipantImportInterruptedByShutdown(t *testing.T) {
+	repo := newTestChatStorage(t)
+	service := serviceGroup{chatStorageRepo: repo}
+
+	run := &participantImport{
+		id:       "import-1",
+		groupJID: types.NewJID("120363000000000001", types.GroupServer),
+		entries: []*domainChatStorage.GroupImportEntry{
+			{ImportID: "import-1", Phone: "6281234567890", Status: domainChatStorage.GroupImportEntryPending},
+		},
+	}
+	groupImport := &domainChatStorage.GroupImport{ID: run.id, GroupJID: run.groupJID.String(), Status: domainChatStorage.GroupImportRunning, Total: 1}
+	require.NoError(t, repo.CreateGroupImport(context.Background(), groupImport, run.entries))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service.runParticipantImport(ctx, run)
+
+	stored, err := repo.GetGroupImport(ctx, run.id)
+	require.NoError(t, err)
+	assert.Equal(t, domainChatStorage.GroupImportFailed, stored.Status)
+	assert.Equal(t, jobInterruptedByShutdown, stored.Error)
+	assert.NotNil(t, stored.FinishedAt)
+}
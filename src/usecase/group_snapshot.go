@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// groupSnapshotVersion is written to exported snapshots so the format can evolve
+const groupSnapshotVersion = 1
+
+const (
+	snapshotRoleMember     = "member"
+	snapshotRoleAdmin      = "admin"
+	snapshotRoleSuperAdmin = "super_admin"
+)
+
+func (service serviceGroup) ExportGroupSnapshot(ctx context.Context, request domainGroup.ExportGroupSnapshotRequest) (response domainGroup.GroupSnapshot, err error) {
+	if err = validations.ValidateExportGroupSnapshot(ctx, request); err != nil {
+		return response, err
+	}
+
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
+	if err != nil {
+		return response, err
+	}
+
+	snapshot, err := service.snapshotGroup(ctx, groupJID, !request.SkipPhoto)
+	if err != nil {
+		return response, err
+	}
+
+	return *snapshot, nil
+}
+
+func (service serviceGroup) CloneGroup(ctx context.Context, request domainGroup.CloneGroupRequest) (response domainGroup.CloneGroupResponse, err error) {
+	if err = validations.ValidateCloneGroup(ctx, request); err != nil {
+		return response, err
+	}
+
+	groupJID, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.GroupID)
+	if err != nil {
+		return response, err
+	}
+
+	snapshot, err := service.snapshotGroup(ctx, groupJID, !request.SkipPhoto)
+	if err != nil {
+		return response, err
+	}
+
+	return service.restoreGroupSnapshot(ctx, snapshot, request.Name, request.SkipParticipants)
+}
+
+func (service serviceGroup) ImportGroupSnapshot(ctx context.Context, request domainGroup.ImportGroupSnapshotRequest) (response domainGroup.CloneGroupResponse, err error) {
+	if err = validations.ValidateImportGroupSnapshot(ctx, request); err != nil {
+		return response, err
+	}
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
+
+	snapshot := request.Snapshot
+	if request.File != nil {
+		file, err := request.File.Open()
+		if err != nil {
+			return response, err
+		}
+		defer file.Close()
+
+		snapshot = &domainGroup.GroupSnapshot{}
+		if err = json.NewDecoder(file).Decode(snapshot); err != nil {
+			return response, pkgError.ValidationError(fmt.Sprintf("file: not a valid group snapshot: %v", err))
+		}
+	}
+
+	if err = validations.ValidateGroupSnapshot(ctx, snapshot); err != nil {
+		return response, err
+	}
+	if request.SkipPhoto {
+		snapshot.Photo = ""
+	}
+
+	return service.restoreGroupSnapshot(ctx, snapshot, request.Name, request.SkipParticipants)
+}
+
+// snapshotGroup reads the configuration and members of a group. Our own account is left out,
+// since it is added to every group it creates.
+func (service serviceGroup) snapshotGroup(ctx context.Context, groupJID types.JID, withPhoto bool) (*domainGroup.GroupSnapshot, error) {
+	client := whatsapp.ClientFromContext(ctx)
+
+	info, err := client.GetGroupInfo(ctx, groupJID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &domainGroup.GroupSnapshot{
+		Version:       groupSnapshotVersion,
+		SourceGroupID: groupJID.String(),
+		Name:          info.Name,
+		Topic:         info.Topic,
+		Settings: domainGroup.GroupSnapshotSettings{
+			Locked:        info.IsLocked,
+			Announce:      info.IsAnnounce,
+			JoinApproval:  info.IsJoinApprovalRequired,
+			MemberAddMode: info.MemberAddMode,
+		},
+		Participants: make([]domainGroup.GroupSnapshotParticipant, 0, len(info.Participants)),
+		ExportedAt:   time.Now(),
+	}
+	if info.IsEphemeral {
+		snapshot.Settings.DisappearingTimer = int(info.DisappearingTimer)
+	}
+
+	identity := whatsapp.NewIdentityResolver()
+	self := map[string]bool{client.Store.ID.User: true, client.Store.GetLID().User: true}
+	for _, participant := range info.Participants {
+		if self[participant.JID.User] || self[participant.PhoneNumber.User] || self[participant.LID.User] {
+			continue
+		}
+
+		jid := participant.PhoneNumber
+		if jid.IsEmpty() {
+			jid = identity.Canonical(ctx, participant.JID)
+		}
+
+		role := snapshotRoleMember
+		switch {
+		case participant.IsSuperAdmin:
+			role = snapshotRoleSuperAdmin
+		case participant.IsAdmin:
+			role = snapshotRoleAdmin
+		}
+
+		snapshot.Participants = append(snapshot.Participants, domainGroup.GroupSnapshotParticipant{
+			JID:  jid.ToNonAD().String(),
+			Role: role,
+		})
+	}
+
+	if withPhoto {
+		photo, err := groupPhoto(ctx, client, groupJID)
+		if err != nil {
+			logrus.Warnf("Failed to read photo of group %s for snapshot: %v", groupJID, err)
+		}
+		if len(photo) > 0 {
+			snapshot.Photo = base64.StdEncoding.EncodeToString(photo)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// groupPhoto downloads the full-size photo of a group, returning nothing when it has none
+func groupPhoto(ctx context.Context, client *whatsmeow.Client, groupJID types.JID) ([]byte, error) {
+	picture, err := client.GetProfilePictureInfo(ctx, groupJID, &whatsmeow.GetProfilePictureParams{})
+	if errors.Is(err, whatsmeow.ErrProfilePictureNotSet) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if picture == nil || picture.URL == "" {
+		return nil, nil
+	}
+
+	photo, _, err := utils.DownloadImageFromURL(picture.URL)
+	return photo, err
+}
+
+// restoreGroupSnapshot creates a group from a snapshot. Settings that WhatsApp accepts at
+// creation are applied right away; the topic, photo, member add mode and admins follow, and
+// a failure in one of those steps is reported as a warning since the group already exists.
+func (service serviceGroup) restoreGroupSnapshot(ctx context.Context, snapshot *domainGroup.GroupSnapshot, namePattern string, skipParticipants bool) (response domainGroup.CloneGroupResponse, err error) {
+	client := whatsapp.ClientFromContext(ctx)
+
+	name := snapshotGroupName(namePattern, snapshot.Name, time.Now())
+	if name == "" {
+		return response, pkgError.ValidationError("name: cannot be blank.")
+	}
+
+	var (
+		participants []types.JID
+		admins       = make(map[string]bool)
+	)
+	if !skipParticipants {
+		for _, participant := range snapshot.Participants {
+			jid, err := utils.ParseJID(participant.JID)
+			if err != nil {
+				return response, pkgError.ValidationError(fmt.Sprintf("participants: invalid jid %s.", participant.JID))
+			}
+			participants = append(participants, jid)
+			if participant.Role == snapshotRoleAdmin || participant.Role == snapshotRoleSuperAdmin {
+				admins[jid.User] = true
+			}
+		}
+	}
+
+	request := whatsmeow.ReqCreateGroup{
+		Name:                        name,
+		Participants:                participants,
+		GroupLocked:                 types.GroupLocked{IsLocked: snapshot.Settings.Locked},
+		GroupAnnounce:               types.GroupAnnounce{IsAnnounce: snapshot.Settings.Announce},
+		GroupMembershipApprovalMode: types.GroupMembershipApprovalMode{IsJoinApprovalRequired: snapshot.Settings.JoinApproval},
+	}
+	if snapshot.Settings.DisappearingTimer > 0 {
+		request.GroupEphemeral = types.GroupEphemeral{IsEphemeral: true, DisappearingTimer: uint32(snapshot.Settings.DisappearingTimer)}
+	}
+
+	info, err := client.CreateGroup(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	response.GroupID = info.JID.String()
+	response.Name = name
+	response.SourceGroupID = snapshot.SourceGroupID
+	response.Participants = make([]domainGroup.ParticipantStatus, 0, len(info.Participants))
+	warn := func(format string, args ...any) {
+		message := fmt.Sprintf(format, args...)
+		logrus.Warnf("Group %s created from snapshot: %s", info.JID, message)
+		response.Warnings = append(response.Warnings, message)
+	}
+
+	run := &participantImport{groupJID: info.JID, groupName: name}
+	var promote []types.JID
+	for _, participant := range info.Participants {
+		if participant.JID.User == client.Store.ID.User || participant.JID.User == client.Store.GetLID().User {
+			continue
+		}
+
+		status := domainGroup.ParticipantStatus{Participant: participant.JID.String(), Status: "success", Message: "Participant added"}
+		switch {
+		case participant.Error == 0:
+			if admins[participant.JID.User] || admins[participant.PhoneNumber.User] {
+				promote = append(promote, participant.JID)
+			}
+		case participant.Error == 403 && participant.AddRequest != nil:
+			status.Status, status.Message = "invited", "privacy settings prevent direct adds, invite sent by DM"
+			if err := sendGroupInvite(ctx, client, participant.JID, run, participant.AddRequest); err != nil {
+				status.Status, status.Message = "error", fmt.Sprintf("could not be added and the invite failed: %v", err)
+			}
+		default:
+			status.Status, status.Message = "error", fmt.Sprintf("add failed (code %d)", participant.Error)
+		}
+		response.Participants = append(response.Participants, status)
+	}
+
+	if snapshot.Topic != "" {
+		if err := client.SetGroupTopic(ctx, info.JID, "", "", snapshot.Topic); err != nil {
+			warn("failed to set topic: %v", err)
+		}
+	}
+
+	if snapshot.Photo != "" {
+		photo, err := base64.StdEncoding.DecodeString(snapshot.Photo)
+		if err == nil {
+			_, err = client.SetGroupPhoto(ctx, info.JID, photo)
+		}
+		if err != nil {
+			warn("failed to set photo: %v", err)
+		}
+	}
+
+	if snapshot.Settings.MemberAddMode != "" && snapshot.Settings.MemberAddMode != info.MemberAddMode {
+		if err := client.SetGroupMemberAddMode(ctx, info.JID, snapshot.Settings.MemberAddMode); err != nil {
+			warn("failed to set member add mode: %v", err)
+		}
+	}
+
+	if len(promote) > 0 {
+		if _, err := client.UpdateGroupParticipants(ctx, info.JID, promote, whatsmeow.ParticipantChangePromote); err != nil {
+			warn("failed to promote %d admins: %v", len(promote), err)
+		}
+	}
+
+	return response, nil
+}
+
+// snapshotGroupName renders the name of a cloned group. The pattern may use {name}, the name
+// of the source group, and {date}; an empty pattern keeps the source name.
+func snapshotGroupName(pattern, sourceName string, now time.Time) string {
+	if pattern == "" {
+		pattern = "{name}"
+	}
+
+	return strings.TrimSpace(strings.NewReplacer(
+		"{name}", sourceName,
+		"{date}", now.Format("2006-01-02"),
+	).Replace(pattern))
+}
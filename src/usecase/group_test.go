@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestCommunitySubGroups(t *testing.T) {
+	announcement := types.NewJID("120363000000000002", types.GroupServer)
+	general := types.NewJID("120363000000000003", types.GroupServer)
+	subGroups := []*types.GroupLinkTarget{
+		{JID: general, GroupName: types.GroupName{Name: "General"}},
+		nil,
+		{
+			JID:               announcement,
+			GroupName:         types.GroupName{Name: "Announcements"},
+			GroupIsDefaultSub: types.GroupIsDefaultSub{IsDefaultSubGroup: true},
+		},
+	}
+
+	assert.Equal(t, announcement.String(), announcementGroupID(subGroups))
+	assert.Empty(t, announcementGroupID(subGroups[:1]))
+	assert.Equal(t, []domainGroup.SubGroup{
+		{GroupID: general.String(), Name: "General"},
+		{GroupID: announcement.String(), Name: "Announcements", IsAnnouncementGroup: true},
+	}, toSubGroups(subGroups))
+	assert.Empty(t, toSubGroups(nil))
+}
+
+func TestMembershipSeries(t *testing.T) {
+	now := time.Date(2024, 5, 20, 15, 0, 0, 0, time.UTC)
+	days := []*domainChatStorage.GroupMembershipDay{
+		{Day: "2024-05-16", Joined: 3, Left: 0},
+		{Day: "2024-05-18", Joined: 1, Left: 2},
+		{Day: "2024-05-20", Joined: 2, Left: 0},
+	}
+	current := int64(10)
+
+	members := func(points []domainGroup.GroupMembershipPoint) []int64 {
+		var result []int64
+		for _, point := range points {
+			result = append(result, *point.Members)
+		}
+		return result
+	}
+
+	series := membershipSeries(days, nil, nil, now, &current)
+	assert.Len(t, series, 5)
+	assert.Equal(t, "2024-05-16", series[0].Date)
+	assert.Equal(t, int64(3), series[0].Net)
+	assert.Equal(t, int64(0), series[1].Joined)
+	assert.Equal(t, int64(-1), series[2].Net)
+	assert.Equal(t, []int64{9, 9, 8, 8, 10}, members(series))
+
+	// Changes after the range end still shift the member count back in time
+	end := time.Date(2024, 5, 17, 23, 0, 0, 0, time.UTC)
+	series = membershipSeries(days, nil, &end, now, &current)
+	assert.Len(t, series, 2)
+	assert.Equal(t, []int64{9, 9}, members(series))
+
+	start := time.Date(2024, 5, 19, 8, 0, 0, 0, time.UTC)
+	series = membershipSeries(days, &start, nil, now, nil)
+	assert.Len(t, series, 2)
+	assert.Nil(t, series[0].Members)
+	assert.Equal(t, int64(2), series[1].Joined)
+
+	assert.Empty(t, membershipSeries(nil, nil, nil, now, &current))
+}
+
+func TestSnapshotGroupName(t *testing.T) {
+	now := time.Date(2026, 3, 9, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "Cohort", snapshotGroupName("", "Cohort", now))
+	assert.Equal(t, "Cohort - 2026-03-09", snapshotGroupName("{name} - {date}", "Cohort", now))
+	assert.Equal(t, "Spring intake", snapshotGroupName("Spring intake", "Cohort", now))
+	assert.Empty(t, snapshotGroupName(" {name} ", "", now))
+}
@@ -0,0 +1,557 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	domainIdentity "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/identity"
+	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+	domainModeration "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/moderation"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+const (
+	// moderationAdminCacheTTL bounds how long a promotion or demotion takes to be noticed
+	moderationAdminCacheTTL = 2 * time.Minute
+	moderationRateWindow    = time.Minute
+)
+
+// moderationActionOrder is the order actions run in, so the warning goes out before the
+// message disappears and the sender is removed last
+var moderationActionOrder = []string{
+	domainChatStorage.ModerationActionWarn,
+	domainChatStorage.ModerationActionDelete,
+	domainChatStorage.ModerationActionRemove,
+}
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+|\b[a-z0-9][a-z0-9-]*(?:\.[a-z0-9-]+)*\.[a-z]{2,}/\S*|\bchat\.whatsapp\.com\b`)
+
+type serviceModeration struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+	messageService  domainMessage.IMessageUsecase
+	groupService    domainGroup.IGroupUsecase
+	sendService     domainSend.ISendUsecase
+	identity        domainIdentity.IIdentityResolver
+
+	rates *moderationRates
+
+	mu     sync.Mutex
+	admins map[string]*moderationGroupAdmins // keyed by session and group, admin status differs per account
+}
+
+func NewModerationService(
+	chatStorageRepo domainChatStorage.IChatStorageRepository,
+	messageService domainMessage.IMessageUsecase,
+	groupService domainGroup.IGroupUsecase,
+	sendService domainSend.ISendUsecase,
+) domainModeration.IModerationUsecase {
+	return &serviceModeration{
+		chatStorageRepo: chatStorageRepo,
+		messageService:  messageService,
+		groupService:    groupService,
+		sendService:     sendService,
+		identity:        whatsapp.NewIdentityResolver(),
+		rates:           newModerationRates(moderationRateWindow),
+		admins:          make(map[string]*moderationGroupAdmins),
+	}
+}
+
+func (service *serviceModeration) ListRules(ctx context.Context, request domainModeration.ListRulesRequest) (response domainModeration.ListRulesResponse, err error) {
+	groupJID := ""
+	if request.GroupID != "" {
+		if groupJID, err = moderationGroupJID(request.GroupID); err != nil {
+			return response, err
+		}
+	}
+
+	rules, err := service.chatStorageRepo.GetModerationRules(ctx, groupJID)
+	if err != nil {
+		return response, err
+	}
+
+	response.Rules = make([]domainModeration.Rule, 0, len(rules))
+	for _, rule := range rules {
+		response.Rules = append(response.Rules, toModerationRule(rule))
+	}
+
+	return response, nil
+}
+
+func (service *serviceModeration) CreateRule(ctx context.Context, request domainModeration.RuleRequest) (response domainModeration.Rule, err error) {
+	if err = validations.ValidateModerationRule(ctx, &request); err != nil {
+		return response, err
+	}
+
+	rule := &domainChatStorage.ModerationRule{}
+	if err = applyModerationRule(rule, request); err != nil {
+		return response, err
+	}
+
+	if err = service.chatStorageRepo.StoreModerationRule(ctx, rule); err != nil {
+		return response, err
+	}
+
+	return toModerationRule(rule), nil
+}
+
+func (service *serviceModeration) UpdateRule(ctx context.Context, request domainModeration.RuleRequest) (response domainModeration.Rule, err error) {
+	if err = validations.ValidateUpdateModerationRule(ctx, &request); err != nil {
+		return response, err
+	}
+
+	rule, err := service.chatStorageRepo.GetModerationRule(ctx, request.RuleID)
+	if err != nil {
+		return response, err
+	}
+	if rule == nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("rule %d not found", request.RuleID))
+	}
+
+	if err = applyModerationRule(rule, request); err != nil {
+		return response, err
+	}
+
+	if err = service.chatStorageRepo.StoreModerationRule(ctx, rule); err != nil {
+		return response, err
+	}
+
+	return toModerationRule(rule), nil
+}
+
+func (service *serviceModeration) DeleteRule(ctx context.Context, request domainModeration.DeleteRuleRequest) (err error) {
+	if err = validations.ValidateDeleteModerationRule(ctx, request); err != nil {
+		return err
+	}
+
+	rule, err := service.chatStorageRepo.GetModerationRule(ctx, request.RuleID)
+	if err != nil {
+		return err
+	}
+	if rule == nil {
+		return pkgError.ValidationError(fmt.Sprintf("rule %d not found", request.RuleID))
+	}
+
+	return service.chatStorageRepo.DeleteModerationRule(ctx, request.RuleID)
+}
+
+func (service *serviceModeration) GetAudit(ctx context.Context, request domainModeration.GetAuditRequest) (response domainModeration.GetAuditResponse, err error) {
+	if err = validations.ValidateGetModerationAudit(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := &domainChatStorage.ModerationAuditFilter{
+		SenderJID: request.Sender,
+		RuleID:    request.RuleID,
+		Limit:     request.Limit,
+		Offset:    request.Offset,
+	}
+	if request.GroupID != "" {
+		if filter.GroupJID, err = moderationGroupJID(request.GroupID); err != nil {
+			return response, err
+		}
+	}
+	if filter.SenderJID != "" && !strings.Contains(filter.SenderJID, "@") {
+		filter.SenderJID = types.NewJID(filter.SenderJID, types.DefaultUserServer).String()
+	}
+
+	entries, err := service.chatStorageRepo.GetModerationAudit(ctx, filter)
+	if err != nil {
+		return response, err
+	}
+
+	total, err := service.chatStorageRepo.CountModerationAudit(ctx, filter)
+	if err != nil {
+		return response, err
+	}
+
+	response.Entries = make([]domainModeration.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, domainModeration.AuditEntry{
+			ID:        entry.ID,
+			GroupID:   entry.GroupJID,
+			RuleID:    entry.RuleID,
+			RuleType:  entry.RuleType,
+			Sender:    entry.SenderJID,
+			MessageID: entry.MessageID,
+			Action:    entry.Action,
+			Status:    entry.Status,
+			Error:     entry.Error,
+			CreatedAt: entry.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	response.Pagination = domainModeration.AuditPagination{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}
+
+	return response, nil
+}
+
+// ModerateMessage checks an incoming group message against the enabled rules of its group and
+// enforces the first rule it breaks. Groups where we are not an admin, and messages sent by
+// admins, are left alone.
+func (service *serviceModeration) ModerateMessage(ctx context.Context, evt *events.Message) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("Moderation of message %s panicked: %v", evt.Info.ID, r)
+		}
+	}()
+
+	if evt.Info.IsFromMe || evt.Info.Chat.Server != types.GroupServer || !isModeratedMessage(evt.Message) {
+		return
+	}
+
+	groupJID := evt.Info.Chat.String()
+	rules, err := service.chatStorageRepo.GetModerationRules(ctx, groupJID)
+	if err != nil {
+		logrus.Warnf("Failed to load moderation rules of %s: %v", groupJID, err)
+		return
+	}
+
+	rules = slices.DeleteFunc(rules, func(rule *domainChatStorage.ModerationRule) bool { return !rule.Enabled })
+	if len(rules) == 0 {
+		return
+	}
+
+	sender := evt.Info.Sender.ToNonAD()
+	recent := service.rates.hit(domainSession.FromContext(ctx)+"|"+groupJID+"|"+sender.String(), evt.Info.Timestamp)
+
+	admins, err := service.groupAdmins(ctx, evt.Info.Chat)
+	if err != nil {
+		logrus.Warnf("Failed to check admins of %s for moderation: %v", groupJID, err)
+		return
+	}
+	if !admins.self || admins.isAdmin(sender, evt.Info.SenderAlt) {
+		return
+	}
+
+	for _, rule := range rules {
+		if reason, violated := moderationViolation(rule, evt.Message, recent); violated {
+			service.enforceRule(ctx, rule, evt, reason)
+			return
+		}
+	}
+}
+
+// enforceRule runs the actions of a broken rule and records each of them in the audit trail
+func (service *serviceModeration) enforceRule(ctx context.Context, rule *domainChatStorage.ModerationRule, evt *events.Message, reason string) {
+	sender := evt.Info.Sender.ToNonAD()
+
+	previous, err := service.chatStorageRepo.CountModerationViolations(ctx, rule.ID, sender.String())
+	if err != nil {
+		logrus.Warnf("Failed to count moderation violations of %s: %v", sender, err)
+	}
+	violations := int(previous) + 1
+
+	actions := rule.Actions
+	if rule.RemoveAfter > 0 && violations >= rule.RemoveAfter {
+		actions = append(slices.Clone(actions), domainChatStorage.ModerationActionRemove)
+	}
+
+	logrus.Infof("Message %s from %s in %s broke moderation rule %d (%s): %s", evt.Info.ID, sender, evt.Info.Chat, rule.ID, rule.Type, reason)
+
+	for _, action := range moderationActionOrder {
+		if !slices.Contains(actions, action) {
+			continue
+		}
+
+		entry := &domainChatStorage.ModerationAudit{
+			GroupJID:  evt.Info.Chat.String(),
+			RuleID:    rule.ID,
+			RuleType:  rule.Type,
+			SenderJID: sender.String(),
+			MessageID: evt.Info.ID,
+			Action:    action,
+			Status:    domainChatStorage.ModerationAuditSuccess,
+		}
+
+		if err := service.runModerationAction(ctx, action, rule, evt, reason, violations); err != nil {
+			entry.Status, entry.Error = domainChatStorage.ModerationAuditFailed, err.Error()
+			logrus.Warnf("Moderation action %s on message %s failed: %v", action, evt.Info.ID, err)
+		}
+
+		if err := service.chatStorageRepo.StoreModerationAudit(ctx, entry); err != nil {
+			logrus.Errorf("Failed to store moderation audit for message %s: %v", evt.Info.ID, err)
+		}
+	}
+}
+
+// runModerationAction performs a single action through the regular message and group usecases.
+// Those panic when the client is disconnected, which is turned into an error here.
+func (service *serviceModeration) runModerationAction(ctx context.Context, action string, rule *domainChatStorage.ModerationRule, evt *events.Message, reason string, violations int) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	groupJID := evt.Info.Chat.String()
+	sender := evt.Info.Sender.ToNonAD()
+	phone := service.senderPhone(ctx, evt)
+
+	switch action {
+	case domainChatStorage.ModerationActionWarn:
+		mention := sender.User
+		if !phone.IsEmpty() {
+			mention = phone.User
+		}
+
+		text := fmt.Sprintf("@%s %s.", mention, reason)
+		if rule.RemoveAfter > 0 && violations < rule.RemoveAfter {
+			text += fmt.Sprintf(" Warning %d of %d.", violations, rule.RemoveAfter)
+		}
+
+		request := domainSend.MessageRequest{BaseRequest: domainSend.BaseRequest{Phone: groupJID}, Message: text}
+		if !slices.Contains(rule.Actions, domainChatStorage.ModerationActionDelete) {
+			request.ReplyMessageID = &evt.Info.ID
+		}
+		_, err = service.sendService.SendText(ctx, request)
+
+	case domainChatStorage.ModerationActionDelete:
+		_, err = service.messageService.RevokeMessage(ctx, domainMessage.RevokeRequest{
+			MessageID: evt.Info.ID,
+			Phone:     groupJID,
+			Sender:    sender.String(),
+		})
+
+	case domainChatStorage.ModerationActionRemove:
+		if phone.IsEmpty() {
+			return fmt.Errorf("phone number of %s is not known", sender)
+		}
+
+		var result []domainGroup.ParticipantStatus
+		result, err = service.groupService.ManageParticipant(ctx, domainGroup.ParticipantRequest{
+			GroupID:      groupJID,
+			Participants: []string{phone.User},
+			Action:       whatsmeow.ParticipantChangeRemove,
+		})
+		if err == nil && len(result) > 0 && result[0].Status != "success" {
+			err = fmt.Errorf("%s", result[0].Message)
+		}
+	}
+
+	return err
+}
+
+// senderPhone returns the phone number JID of the sender, resolving LIDs when the mapping is known
+func (service *serviceModeration) senderPhone(ctx context.Context, evt *events.Message) types.JID {
+	for _, jid := range []types.JID{evt.Info.Sender, evt.Info.SenderAlt} {
+		if jid.IsEmpty() {
+			continue
+		}
+		if canonical := service.identity.Canonical(ctx, jid); canonical.Server == types.DefaultUserServer {
+			return canonical
+		}
+	}
+	return types.EmptyJID
+}
+
+// moderationGroupAdmins is a cached view of who administers a group
+type moderationGroupAdmins struct {
+	self      bool            // whether we are an admin
+	users     map[string]bool // user parts of the admins, in both phone number and LID form
+	fetchedAt time.Time
+}
+
+func (admins *moderationGroupAdmins) isAdmin(jids ...types.JID) bool {
+	for _, jid := range jids {
+		if !jid.IsEmpty() && admins.users[jid.User] {
+			return true
+		}
+	}
+	return false
+}
+
+func (service *serviceModeration) groupAdmins(ctx context.Context, groupJID types.JID) (*moderationGroupAdmins, error) {
+	key := domainSession.FromContext(ctx) + "|" + groupJID.String()
+
+	service.mu.Lock()
+	cached, ok := service.admins[key]
+	service.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < moderationAdminCacheTTL {
+		return cached, nil
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil || client.Store == nil || client.Store.ID == nil {
+		return nil, pkgError.ErrWaCLI
+	}
+
+	info, err := client.GetGroupInfo(ctx, groupJID)
+	if err != nil {
+		return nil, err
+	}
+
+	admins := &moderationGroupAdmins{users: make(map[string]bool), fetchedAt: time.Now()}
+	for _, participant := range info.Participants {
+		if participant.IsAdmin || participant.IsSuperAdmin {
+			for _, jid := range []types.JID{participant.JID, participant.PhoneNumber, participant.LID} {
+				if !jid.IsEmpty() {
+					admins.users[jid.User] = true
+				}
+			}
+		}
+	}
+	admins.self = admins.isAdmin(client.Store.ID.ToNonAD(), client.Store.GetLID())
+
+	service.mu.Lock()
+	service.admins[key] = admins
+	service.mu.Unlock()
+
+	return admins, nil
+}
+
+// moderationRates counts messages per group member within a sliding window
+type moderationRates struct {
+	mu     sync.Mutex
+	window time.Duration
+	hits   map[string][]time.Time
+}
+
+func newModerationRates(window time.Duration) *moderationRates {
+	return &moderationRates{window: window, hits: make(map[string][]time.Time)}
+}
+
+// hit records a message and returns how many messages the key sent within the window, including it
+func (rates *moderationRates) hit(key string, at time.Time) int {
+	rates.mu.Lock()
+	defer rates.mu.Unlock()
+
+	cutoff := at.Add(-rates.window)
+	recent := slices.DeleteFunc(rates.hits[key], func(t time.Time) bool { return !t.After(cutoff) })
+	recent = append(recent, at)
+	rates.hits[key] = recent
+
+	// Forget members that went quiet so the map does not grow with every sender ever seen
+	if len(rates.hits) > 10000 {
+		for other, times := range rates.hits {
+			if len(times) == 0 || !times[len(times)-1].After(cutoff) {
+				delete(rates.hits, other)
+			}
+		}
+	}
+
+	return len(recent)
+}
+
+// isModeratedMessage skips protocol messages, reactions and other events that are not something a member wrote
+func isModeratedMessage(msg *waE2E.Message) bool {
+	return msg != nil && msg.GetProtocolMessage() == nil && msg.GetReactionMessage() == nil
+}
+
+// moderationViolation reports whether a message breaks a rule and why.
+// recent is the number of messages the sender posted in the group during the last minute.
+func moderationViolation(rule *domainChatStorage.ModerationRule, msg *waE2E.Message, recent int) (reason string, violated bool) {
+	text := utils.ExtractMessageTextFromProto(msg)
+
+	switch rule.Type {
+	case domainChatStorage.ModerationRuleLinks:
+		if linkPattern.MatchString(text) || msg.GetExtendedTextMessage().GetMatchedText() != "" {
+			return "links are not allowed in this group", true
+		}
+
+	case domainChatStorage.ModerationRuleBannedWords:
+		lower := strings.ToLower(text)
+		for _, word := range rule.Words {
+			pattern := `\b` + regexp.QuoteMeta(strings.ToLower(strings.TrimSpace(word))) + `\b`
+			if matched, _ := regexp.MatchString(pattern, lower); matched {
+				return "your message contains a banned word", true
+			}
+		}
+
+	case domainChatStorage.ModerationRuleRateLimit:
+		if rule.Limit > 0 && recent > rule.Limit {
+			return fmt.Sprintf("please send at most %d messages per minute", rule.Limit), true
+		}
+
+	case domainChatStorage.ModerationRuleForwardedMedia:
+		if info := mediaContextInfo(msg); info != nil && info.GetIsForwarded() {
+			return "forwarded media is not allowed in this group", true
+		}
+	}
+
+	return "", false
+}
+
+// mediaContextInfo returns the context info of a media message, or nil for other messages
+func mediaContextInfo(msg *waE2E.Message) *waE2E.ContextInfo {
+	switch {
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetContextInfo()
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetContextInfo()
+	case msg.GetAudioMessage() != nil:
+		return msg.GetAudioMessage().GetContextInfo()
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage().GetContextInfo()
+	case msg.GetStickerMessage() != nil:
+		return msg.GetStickerMessage().GetContextInfo()
+	}
+	return nil
+}
+
+// moderationGroupJID normalises a group ID to its full JID
+func moderationGroupJID(groupID string) (string, error) {
+	jid, err := utils.ParseJID(groupID)
+	if err != nil || jid.Server != types.GroupServer {
+		return "", pkgError.ValidationError("group_id: must be a group JID.")
+	}
+	return jid.String(), nil
+}
+
+func applyModerationRule(rule *domainChatStorage.ModerationRule, request domainModeration.RuleRequest) error {
+	groupJID, err := moderationGroupJID(request.GroupID)
+	if err != nil {
+		return err
+	}
+
+	rule.GroupJID = groupJID
+	rule.Type = request.Type
+	rule.Words = nil
+	rule.Limit = 0
+	switch request.Type {
+	case domainChatStorage.ModerationRuleBannedWords:
+		rule.Words = request.Words
+	case domainChatStorage.ModerationRuleRateLimit:
+		rule.Limit = request.Limit
+	}
+	rule.Actions = request.Actions
+	rule.RemoveAfter = request.RemoveAfter
+	rule.Enabled = *request.Enabled
+
+	return nil
+}
+
+func toModerationRule(rule *domainChatStorage.ModerationRule) domainModeration.Rule {
+	return domainModeration.Rule{
+		ID:          rule.ID,
+		GroupID:     rule.GroupJID,
+		Type:        rule.Type,
+		Words:       rule.Words,
+		Limit:       rule.Limit,
+		Actions:     rule.Actions,
+		RemoveAfter: rule.RemoveAfter,
+		Enabled:     rule.Enabled,
+		CreatedAt:   rule.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   rule.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestModerationViolation(t *testing.T) {
+	text := func(s string) *waE2E.Message { return &waE2E.Message{Conversation: proto.String(s)} }
+	forwardedImage := &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+		ContextInfo: &waE2E.ContextInfo{IsForwarded: proto.Bool(true)},
+	}}
+
+	links := &domainChatStorage.ModerationRule{Type: domainChatStorage.ModerationRuleLinks}
+	words := &domainChatStorage.ModerationRule{Type: domainChatStorage.ModerationRuleBannedWords, Words: []string{"Spam", "free money"}}
+	rate := &domainChatStorage.ModerationRule{Type: domainChatStorage.ModerationRuleRateLimit, Limit: 3}
+	forwarded := &domainChatStorage.ModerationRule{Type: domainChatStorage.ModerationRuleForwardedMedia}
+
+	tests := []struct {
+		name   string
+		rule   *domainChatStorage.ModerationRule
+		msg    *waE2E.Message
+		recent int
+		want   bool
+	}{
+		{name: "http link", rule: links, msg: text("see https://example.com now"), want: true},
+		{name: "www link", rule: links, msg: text("visit www.example.com"), want: true},
+		{name: "bare domain with path", rule: links, msg: text("go to example.com/offer"), want: true},
+		{name: "group invite", rule: links, msg: text("join chat.whatsapp.com"), want: true},
+		{name: "no link", rule: links, msg: text("see you at 10.30, e.g. tomorrow"), want: false},
+		{name: "link in caption", rule: links, msg: &waE2E.Message{ImageMessage: &waE2E.ImageMessage{Caption: proto.String("http://x.io")}}, want: true},
+		{name: "banned word any case", rule: words, msg: text("this is SPAM!"), want: true},
+		{name: "banned phrase", rule: words, msg: text("get free money here"), want: true},
+		{name: "banned word inside another word", rule: words, msg: text("spammer"), want: false},
+		{name: "within rate limit", rule: rate, msg: text("hi"), recent: 3, want: false},
+		{name: "over rate limit", rule: rate, msg: text("hi"), recent: 4, want: true},
+		{name: "forwarded image", rule: forwarded, msg: forwardedImage, want: true},
+		{name: "own image", rule: forwarded, msg: &waE2E.Message{ImageMessage: &waE2E.ImageMessage{}}, want: false},
+		{name: "forwarded text", rule: forwarded, msg: &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+			Text:        proto.String("hello"),
+			ContextInfo: &waE2E.ContextInfo{IsForwarded: proto.Bool(true)},
+		}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, violated := moderationViolation(tt.rule, tt.msg, tt.recent)
+			assert.Equal(t, tt.want, violated)
+			if tt.want {
+				assert.NotEmpty(t, reason)
+			}
+		})
+	}
+}
+
+func TestModerationRates(t *testing.T) {
+	rates := newModerationRates(time.Minute)
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 1, rates.hit("group|alice", start))
+	assert.Equal(t, 2, rates.hit("group|alice", start.Add(20*time.Second)))
+	assert.Equal(t, 1, rates.hit("group|bob", start.Add(30*time.Second)))
+	assert.Equal(t, 3, rates.hit("group|alice", start.Add(50*time.Second)))
+
+	// The first message falls out of the window
+	assert.Equal(t, 3, rates.hit("group|alice", start.Add(70*time.Second)))
+	assert.Equal(t, 1, rates.hit("group|alice", start.Add(5*time.Minute)))
+}
+
+func TestIsModeratedMessage(t *testing.T) {
+	assert.True(t, isModeratedMessage(&waE2E.Message{Conversation: proto.String("hi")}))
+	assert.False(t, isModeratedMessage(&waE2E.Message{ReactionMessage: &waE2E.ReactionMessage{}}))
+	assert.False(t, isModeratedMessage(&waE2E.Message{ProtocolMessage: &waE2E.ProtocolMessage{}}))
+	assert.False(t, isModeratedMessage(nil))
+}
+
+func TestModerationRulesAreKeptPerSession(t *testing.T) {
+	repo := newTestChatStorage(t)
+	sales := domainSession.NewContext(context.Background(), "sales")
+	support := domainSession.NewContext(context.Background(), "support")
+	group := "120363000000000001@g.us"
+
+	rule := &domainChatStorage.ModerationRule{GroupJID: group, Type: domainChatStorage.ModerationRuleLinks, Actions: []string{domainChatStorage.ModerationActionDelete}, Enabled: true}
+	require.NoError(t, repo.StoreModerationRule(sales, rule))
+
+	rules, err := repo.GetModerationRules(support, group)
+	require.NoError(t, err)
+	assert.Empty(t, rules, "a rule of one session must not be enforced by the other")
+
+	stored, err := repo.GetModerationRule(support, rule.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+
+	require.NoError(t, repo.DeleteModerationRule(support, rule.ID))
+	rules, err = repo.GetModerationRules(sales, group)
+	require.NoError(t, err)
+	assert.Len(t, rules, 1)
+
+	require.NoError(t, repo.StoreGroupGreeting(sales, &domainChatStorage.GroupGreeting{GroupJID: group, WelcomeEnabled: true}))
+	greeting, err := repo.GetGroupGreeting(support, group)
+	require.NoError(t, err)
+	assert.Nil(t, greeting, "a greeting of one session must not be sent by the other")
+}
@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+)
+
+// tagSendDelay spaces out sends to the contacts of a tag, like greetings and imports do
+var tagSendDelay = time.Second
+
+// tagSender lets every send accept an address book tag ("tag:customers") as recipient and sends
+// the message to each tagged contact in turn. Other recipients go straight to the send service.
+type tagSender struct {
+	domainSend.ISendUsecase
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+func (sender tagSender) SendText(ctx context.Context, request domainSend.MessageRequest) (domainSend.GenericResponse, error) {
+	return sender.fanOut(ctx, request.Phone, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return sender.ISendUsecase.SendText(ctx, request)
+	})
+}
+
+func (sender tagSender) SendImage(ctx context.Context, request domainSend.ImageRequest) (domainSend.GenericResponse, error) {
+	return sender.fanOut(ctx, request.Phone, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return sender.ISendUsecase.SendImage(ctx, request)
+	})
+}
+
+func (sender tagSender) SendFile(ctx context.Context, request domainSend.FileRequest) (domainSend.GenericResponse, error) {
+	return sender.fanOut(ctx, request.Phone, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return sender.ISendUsecase.SendFile(ctx, request)
+	})
+}
+
+func (sender tagSender) SendVideo(ctx context.Context, request domainSend.VideoRequest) (domainSend.GenericResponse, error) {
+	return sender.fanOut(ctx, request.Phone, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return sender.ISendUsecase.SendVideo(ctx, request)
+	})
+}
+
+func (sender tagSender) SendAudio(ctx context.Context, request domainSend.AudioRequest) (domainSend.GenericResponse, error) {
+	return sender.fanOut(ctx, request.Phone, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return sender.ISendUsecase.SendAudio(ctx, request)
+	})
+}
+
+func (sender tagSender) SendSticker(ctx context.Context, request domainSend.StickerRequest) (domainSend.GenericResponse, error) {
+	return sender.fanOut(ctx, request.Phone, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return sender.ISendUsecase.SendSticker(ctx, request)
+	})
+}
+
+func (sender tagSender) SendContact(ctx context.Context, request domainSend.ContactRequest) (domainSend.GenericResponse, error) {
+	return sender.fanOut(ctx, request.Phone, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return sender.ISendUsecase.SendContact(ctx, request)
+	})
+}
+
+func (sender tagSender) SendLink(ctx context.Context, request domainSend.LinkRequest) (domainSend.GenericResponse, error) {
+	return sender.fanOut(ctx, request.Phone, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return sender.ISendUsecase.SendLink(ctx, request)
+	})
+}
+
+func (sender tagSender) SendLocation(ctx context.Context, request domainSend.LocationRequest) (domainSend.GenericResponse, error) {
+	return sender.fanOut(ctx, request.Phone, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return sender.ISendUsecase.SendLocation(ctx, request)
+	})
+}
+
+func (sender tagSender) SendPoll(ctx context.Context, request domainSend.PollRequest) (domainSend.GenericResponse, error) {
+	return sender.fanOut(ctx, request.Phone, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return sender.ISendUsecase.SendPoll(ctx, request)
+	})
+}
+
+// fanOut sends to the recipient, or to every contact carrying the tag it selects
+func (sender tagSender) fanOut(ctx context.Context, recipient string, send func(phone string) (domainSend.GenericResponse, error)) (domainSend.GenericResponse, error) {
+	tag, ok := utils.ContactTagSelector(recipient)
+	if !ok {
+		return send(recipient)
+	}
+	if tag == "" {
+		return domainSend.GenericResponse{}, pkgError.ValidationError("phone: tag cannot be blank.")
+	}
+
+	jids, err := sender.chatStorageRepo.GetAddressBookJIDsByTag(ctx, tag)
+	if err != nil {
+		return domainSend.GenericResponse{}, err
+	}
+	if len(jids) == 0 {
+		return domainSend.GenericResponse{}, pkgError.ValidationError(fmt.Sprintf("phone: no contact is tagged %s.", tag))
+	}
+
+	return sendToRecipients(ctx, tag, jids, tagSendDelay, send)
+}
+
+// sendToRecipients sends to each JID in turn and reports every outcome. It only fails when no
+// message went out at all, or when ctx is done before every contact got its turn; the report is
+// returned in both cases, with the contacts left out marked as not sent.
+func sendToRecipients(ctx context.Context, tag string, jids []string, delay time.Duration, send func(phone string) (domainSend.GenericResponse, error)) (response domainSend.GenericResponse, err error) {
+	var (
+		sent    int
+		lastErr error
+	)
+
+	for i, jid := range jids {
+		if i > 0 && !sleepContext(ctx, delay) {
+			for _, skipped := range jids[i:] {
+				response.Recipients = append(response.Recipients, domainSend.RecipientResult{
+					Phone: skipped,
+					Error: fmt.Sprintf("not sent: %v", ctx.Err()),
+				})
+			}
+			response.Status = fmt.Sprintf("Message sent to %d of %d contacts tagged %s before the send was cancelled", sent, len(jids), tag)
+			return response, ctx.Err()
+		}
+
+		result := domainSend.RecipientResult{Phone: jid}
+		if res, err := send(jid); err != nil {
+			result.Error = err.Error()
+			lastErr = err
+		} else {
+			result.MessageID = res.MessageID
+			result.Status = res.Status
+			sent++
+		}
+		response.Recipients = append(response.Recipients, result)
+	}
+
+	if sent == 0 {
+		return response, lastErr
+	}
+
+	response.Status = fmt.Sprintf("Message sent to %d of %d contacts tagged %s", sent, len(jids), tag)
+	return response, nil
+}
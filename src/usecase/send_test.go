@@ -1,6 +1,14 @@
 package usecase
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/stretchr/testify/assert"
+)
 
 func TestResolveDocumentMIME(t *testing.T) {
 	tests := []struct {
@@ -39,3 +47,46 @@ func TestResolveDocumentMIME(t *testing.T) {
 		})
 	}
 }
+
+func TestSendToRecipients(t *testing.T) {
+	jids := []string{"6281111111111@s.whatsapp.net", "6282222222222@s.whatsapp.net"}
+
+	t.Run("reports every recipient", func(t *testing.T) {
+		response, err := sendToRecipients(context.Background(), "vip", jids, 0, func(phone string) (domainSend.GenericResponse, error) {
+			if phone == jids[1] {
+				return domainSend.GenericResponse{}, errors.New("not on whatsapp")
+			}
+			return domainSend.GenericResponse{MessageID: "MSG1", Status: "sent"}, nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Message sent to 1 of 2 contacts tagged vip", response.Status)
+		assert.Equal(t, []domainSend.RecipientResult{
+			{Phone: jids[0], MessageID: "MSG1", Status: "sent"},
+			{Phone: jids[1], Error: "not on whatsapp"},
+		}, response.Recipients)
+	})
+
+	t.Run("fails when nothing was sent", func(t *testing.T) {
+		_, err := sendToRecipients(context.Background(), "vip", jids, 0, func(string) (domainSend.GenericResponse, error) {
+			return domainSend.GenericResponse{}, errors.New("not connected")
+		})
+
+		assert.EqualError(t, err, "not connected")
+	})
+
+	t.Run("reports the recipients when cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		response, err := sendToRecipients(ctx, "vip", jids, time.Hour, func(string) (domainSend.GenericResponse, error) {
+			cancel()
+			return domainSend.GenericResponse{MessageID: "MSG1", Status: "sent"}, nil
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, "Message sent to 1 of 2 contacts tagged vip before the send was cancelled", response.Status)
+		assert.Equal(t, []domainSend.RecipientResult{
+			{Phone: jids[0], MessageID: "MSG1", Status: "sent"},
+			{Phone: jids[1], Error: "not sent: context canceled"},
+		}, response.Recipients)
+	})
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+)
+
+type serviceSession struct{}
+
+func NewSessionService() domainSession.ISessionUsecase {
+	return &serviceSession{}
+}
+
+func (service serviceSession) ListSessions(_ context.Context) (response []domainSession.Session, err error) {
+	response = []domainSession.Session{}
+	for _, sessionID := range whatsapp.SessionIDs() {
+		if session, ok := whatsapp.GetSession(sessionID); ok {
+			response = append(response, session)
+		}
+	}
+	return response, nil
+}
+
+func (service serviceSession) GetSession(ctx context.Context, request domainSession.SessionRequest) (response domainSession.Session, err error) {
+	if err = validations.ValidateSession(ctx, request); err != nil {
+		return response, err
+	}
+
+	response, ok := whatsapp.GetSession(request.SessionID)
+	if !ok {
+		return response, pkgError.ValidationError(fmt.Sprintf("session %s not found", request.SessionID))
+	}
+	return response, nil
+}
+
+// AddSession creates a session without a device. The device is paired by calling the login
+// endpoints with the new session selected.
+func (service serviceSession) AddSession(ctx context.Context, request domainSession.AddSessionRequest) (response domainSession.Session, err error) {
+	if err = validations.ValidateAddSession(ctx, request); err != nil {
+		return response, err
+	}
+
+	return whatsapp.AddSession(request.SessionID)
+}
+
+func (service serviceSession) RemoveSession(ctx context.Context, request domainSession.SessionRequest) (err error) {
+	if err = validations.ValidateSession(ctx, request); err != nil {
+		return err
+	}
+
+	return whatsapp.RemoveSession(ctx, request.SessionID)
+}
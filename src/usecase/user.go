@@ -5,26 +5,31 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"image"
 	"time"
 
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
-	"github.com/disintegration/imaging"
+	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/appstate"
 	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
 )
 
 type serviceUser struct {
-	// Remove the WaCli field - we'll use the global client instead
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+	numberChecks    *numberChecker
 }
 
-func NewUserService() domainUser.IUserUsecase {
-	return &serviceUser{}
+func NewUserService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainUser.IUserUsecase {
+	return &serviceUser{
+		chatStorageRepo: chatStorageRepo,
+		numberChecks:    &numberChecker{chatStorageRepo: chatStorageRepo},
+	}
 }
 
 func (service serviceUser) Info(ctx context.Context, request domainUser.InfoRequest) (response domainUser.InfoResponse, err error) {
@@ -33,13 +38,13 @@ func (service serviceUser) Info(ctx context.Context, request domainUser.InfoRequ
 		return response, err
 	}
 	var jids []types.JID
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.Phone)
 	if err != nil {
 		return response, err
 	}
 
 	jids = append(jids, dataWaRecipient)
-	resp, err := whatsapp.GetClient().GetUserInfo(ctx, jids)
+	resp, err := whatsapp.ClientFromContext(ctx).GetUserInfo(ctx, jids)
 	if err != nil {
 		return response, err
 	}
@@ -81,11 +86,11 @@ func (service serviceUser) Avatar(ctx context.Context, request domainUser.Avatar
 		if err != nil {
 			chanErr <- err
 		}
-		dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+		dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.Phone)
 		if err != nil {
 			chanErr <- err
 		}
-		pic, err := whatsapp.GetClient().GetProfilePictureInfo(ctx, dataWaRecipient, &whatsmeow.GetProfilePictureParams{
+		pic, err := whatsapp.ClientFromContext(ctx).GetProfilePictureInfo(ctx, dataWaRecipient, &whatsmeow.GetProfilePictureParams{
 			Preview:     request.IsPreview,
 			IsCommunity: request.IsCommunity,
 		})
@@ -98,6 +103,13 @@ func (service serviceUser) Avatar(ctx context.Context, request domainUser.Avatar
 			response.ID = pic.ID
 			response.Type = pic.Type
 
+			// Cache the full-size avatar so chat lists can show it without another lookup
+			if !request.IsPreview {
+				if err := service.chatStorageRepo.SetChatAvatarURL(ctx, dataWaRecipient.String(), pic.URL); err != nil {
+					logrus.WithError(err).WithField("jid", dataWaRecipient.String()).Warn("Failed to cache avatar URL")
+				}
+			}
+
 			chanResp <- response
 		}
 	}()
@@ -118,9 +130,9 @@ func (service serviceUser) Avatar(ctx context.Context, request domainUser.Avatar
 }
 
 func (service serviceUser) MyListGroups(ctx context.Context) (response domainUser.MyListGroupsResponse, err error) {
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	groups, err := whatsapp.GetClient().GetJoinedGroups(ctx)
+	groups, err := whatsapp.ClientFromContext(ctx).GetJoinedGroups(ctx)
 	if err != nil {
 		return
 	}
@@ -131,10 +143,10 @@ func (service serviceUser) MyListGroups(ctx context.Context) (response domainUse
 	return response, nil
 }
 
-func (service serviceUser) MyListNewsletter(_ context.Context) (response domainUser.MyListNewsletterResponse, err error) {
-	utils.MustLogin(whatsapp.GetClient())
+func (service serviceUser) MyListNewsletter(ctx context.Context) (response domainUser.MyListNewsletterResponse, err error) {
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	datas, err := whatsapp.GetClient().GetSubscribedNewsletters(context.Background())
+	datas, err := whatsapp.ClientFromContext(ctx).GetSubscribedNewsletters(ctx)
 	if err != nil {
 		return
 	}
@@ -146,24 +158,148 @@ func (service serviceUser) MyListNewsletter(_ context.Context) (response domainU
 }
 
 func (service serviceUser) MyPrivacySetting(ctx context.Context) (response domainUser.MyPrivacySettingResponse, err error) {
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	resp, err := whatsapp.GetClient().TryFetchPrivacySettings(ctx, true)
+	resp, err := whatsapp.ClientFromContext(ctx).TryFetchPrivacySettings(ctx, true)
 	if err != nil {
 		return
 	}
 
-	response.GroupAdd = string(resp.GroupAdd)
-	response.Status = string(resp.Status)
-	response.ReadReceipts = string(resp.ReadReceipts)
-	response.Profile = string(resp.Profile)
+	return toPrivacySettingResponse(*resp), nil
+}
+
+// SetPrivacySetting applies the given settings one by one, as WhatsApp only accepts a single
+// setting per request. When one fails, the settings before it have already been changed.
+func (service serviceUser) SetPrivacySetting(ctx context.Context, request domainUser.SetPrivacySettingRequest) (response domainUser.MyPrivacySettingResponse, err error) {
+	if err = validations.ValidateSetPrivacySetting(ctx, request); err != nil {
+		return response, err
+	}
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
+	client := whatsapp.ClientFromContext(ctx)
+
+	changes := []struct {
+		name    types.PrivacySettingType
+		setting string
+	}{
+		{types.PrivacySettingTypeLastSeen, request.LastSeen},
+		{types.PrivacySettingTypeOnline, request.Online},
+		{types.PrivacySettingTypeProfile, request.Profile},
+		{types.PrivacySettingTypeStatus, request.Status},
+		{types.PrivacySettingTypeGroupAdd, request.GroupAdd},
+		{types.PrivacySettingTypeReadReceipts, request.ReadReceipts},
+		{types.PrivacySettingTypeCallAdd, request.CallAdd},
+	}
+	for _, change := range changes {
+		if change.setting == "" {
+			continue
+		}
+		if _, err = client.SetPrivacySetting(ctx, change.name, types.PrivacySetting(change.setting)); err != nil {
+			return response, fmt.Errorf("failed to set %s privacy: %w", change.name, err)
+		}
+	}
+
+	if request.DisappearingTimer != nil {
+		if err = client.SetDefaultDisappearingTimer(ctx, time.Duration(*request.DisappearingTimer)*time.Second); err != nil {
+			return response, fmt.Errorf("failed to set default disappearing timer: %w", err)
+		}
+	}
+
+	settings, err := client.TryFetchPrivacySettings(ctx, false)
+	if err != nil {
+		return response, err
+	}
+
+	return toPrivacySettingResponse(*settings), nil
+}
+
+func toPrivacySettingResponse(settings types.PrivacySettings) domainUser.MyPrivacySettingResponse {
+	return domainUser.MyPrivacySettingResponse{
+		GroupAdd:     string(settings.GroupAdd),
+		LastSeen:     string(settings.LastSeen),
+		Status:       string(settings.Status),
+		Profile:      string(settings.Profile),
+		ReadReceipts: string(settings.ReadReceipts),
+		Online:       string(settings.Online),
+		CallAdd:      string(settings.CallAdd),
+	}
+}
+
+func (service serviceUser) MyBlocklist(ctx context.Context) (response domainUser.BlocklistResponse, err error) {
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
+
+	blocklist, err := whatsapp.ClientFromContext(ctx).GetBlocklist(ctx)
+	if err != nil {
+		return response, err
+	}
+
+	return service.blocklistResponse(ctx, blocklist)
+}
+
+func (service serviceUser) BlockContact(ctx context.Context, request domainUser.BlockContactRequest) (response domainUser.BlocklistResponse, err error) {
+	return service.updateBlocklist(ctx, request, events.BlocklistChangeActionBlock)
+}
+
+func (service serviceUser) UnblockContact(ctx context.Context, request domainUser.BlockContactRequest) (response domainUser.BlocklistResponse, err error) {
+	return service.updateBlocklist(ctx, request, events.BlocklistChangeActionUnblock)
+}
+
+func (service serviceUser) updateBlocklist(ctx context.Context, request domainUser.BlockContactRequest, action events.BlocklistChangeAction) (response domainUser.BlocklistResponse, err error) {
+	if err = validations.ValidateBlockContact(ctx, request); err != nil {
+		return response, err
+	}
+
+	jid, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	blocklist, err := whatsapp.ClientFromContext(ctx).UpdateBlocklist(ctx, jid, action)
+	if err != nil {
+		return response, fmt.Errorf("failed to %s %s: %w", action, jid, err)
+	}
+
+	return service.blocklistResponse(ctx, blocklist)
+}
+
+// blocklistResponse refreshes the stored block list from a list returned by WhatsApp and
+// describes it, adding contact names and the time each block was first seen
+func (service serviceUser) blocklistResponse(ctx context.Context, blocklist *types.Blocklist) (response domainUser.BlocklistResponse, err error) {
+	if err = whatsapp.StoreBlocklist(ctx, service.chatStorageRepo, blocklist); err != nil {
+		logrus.Warnf("Failed to store block list: %v", err)
+	}
+
+	blockedAt := make(map[string]time.Time)
+	if stored, err := service.chatStorageRepo.GetBlockedContacts(ctx); err == nil {
+		for _, contact := range stored {
+			blockedAt[contact.JID] = contact.BlockedAt
+		}
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	identity := whatsapp.NewIdentityResolver()
+	response.Data = make([]domainUser.BlocklistResponseData, 0, len(blocklist.JIDs))
+	for _, jid := range blocklist.JIDs {
+		jid = jid.ToNonAD()
+		item := domainUser.BlocklistResponseData{JID: jid.String()}
+		if contact, err := client.Store.Contacts.GetContact(ctx, identity.Canonical(ctx, jid)); err == nil {
+			item.Name = contact.FullName
+			if item.Name == "" {
+				item.Name = contact.PushName
+			}
+		}
+		if at, ok := blockedAt[item.JID]; ok {
+			item.BlockedAt = &at
+		}
+		response.Data = append(response.Data, item)
+	}
+
 	return response, nil
 }
 
 func (service serviceUser) MyListContacts(ctx context.Context) (response domainUser.MyListContactsResponse, err error) {
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	contacts, err := whatsapp.GetClient().Store.Contacts.GetAllContacts(ctx)
+	contacts, err := whatsapp.ClientFromContext(ctx).Store.Contacts.GetAllContacts(ctx)
 	if err != nil {
 		return
 	}
@@ -179,63 +315,61 @@ func (service serviceUser) MyListContacts(ctx context.Context) (response domainU
 }
 
 func (service serviceUser) ChangeAvatar(ctx context.Context, request domainUser.ChangeAvatarRequest) (err error) {
-	utils.MustLogin(whatsapp.GetClient())
-
-	file, err := request.Avatar.Open()
-	if err != nil {
+	if err = validations.ValidateChangeAvatar(ctx, request); err != nil {
 		return err
 	}
-	defer file.Close()
-
-	// Read original image
-	srcImage, err := imaging.Decode(file)
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
+
+	// Profile photos have the same requirements as group photos
+	var photo *bytes.Buffer
+	if request.AvatarURL != "" {
+		photo, err = utils.ProcessGroupPhotoFromURL(request.AvatarURL)
+	} else {
+		photo, err = utils.ProcessGroupPhoto(request.Avatar)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to decode image: %v", err)
+		return err
 	}
 
-	// Get original dimensions
-	bounds := srcImage.Bounds()
-	width := bounds.Dx()
-	height := bounds.Dy()
+	_, err = whatsapp.ClientFromContext(ctx).SetGroupPhoto(ctx, types.JID{}, photo.Bytes())
+	return err
+}
 
-	// Calculate new dimensions for 1:1 aspect ratio
-	size := width
-	if height < width {
-		size = height
-	}
-	if size > 640 {
-		size = 640
-	}
+func (service serviceUser) RemoveAvatar(ctx context.Context) (err error) {
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	// Create a square crop from the center
-	left := (width - size) / 2
-	top := (height - size) / 2
-	croppedImage := imaging.Crop(srcImage, image.Rect(left, top, left+size, top+size))
+	// Setting an empty photo on the empty JID clears our own profile photo
+	_, err = whatsapp.ClientFromContext(ctx).SetGroupPhoto(ctx, types.JID{}, nil)
+	return err
+}
 
-	// Resize if needed
-	if size > 640 {
-		croppedImage = imaging.Resize(croppedImage, 640, 640, imaging.Lanczos)
-	}
+func (service serviceUser) MyAbout(ctx context.Context) (response domainUser.MyAboutResponse, err error) {
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
+	client := whatsapp.ClientFromContext(ctx)
 
-	// Convert to bytes
-	var buf bytes.Buffer
-	err = imaging.Encode(&buf, croppedImage, imaging.JPEG, imaging.JPEGQuality(80))
+	self := client.Store.ID.ToNonAD()
+	info, err := client.GetUserInfo(ctx, []types.JID{self})
 	if err != nil {
-		return fmt.Errorf("failed to encode image: %v", err)
+		return response, err
 	}
 
-	_, err = whatsapp.GetClient().SetGroupPhoto(ctx, types.JID{}, buf.Bytes())
-	if err != nil {
+	response.About = info[self].Status
+	return response, nil
+}
+
+func (service serviceUser) ChangeAbout(ctx context.Context, request domainUser.ChangeAboutRequest) (err error) {
+	if err = validations.ValidateChangeAbout(ctx, request); err != nil {
 		return err
 	}
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	return nil
+	return whatsapp.ClientFromContext(ctx).SetStatusMessage(ctx, request.About)
 }
 
 func (service serviceUser) ChangePushName(ctx context.Context, request domainUser.ChangePushNameRequest) (err error) {
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
-	err = whatsapp.GetClient().SendAppState(ctx, appstate.BuildSettingPushName(request.PushName))
+	err = whatsapp.ClientFromContext(ctx).SendAppState(ctx, appstate.BuildSettingPushName(request.PushName))
 	if err != nil {
 		return err
 	}
@@ -243,11 +377,11 @@ func (service serviceUser) ChangePushName(ctx context.Context, request domainUse
 }
 
 func (service serviceUser) IsOnWhatsApp(ctx context.Context, request domainUser.CheckRequest) (response domainUser.CheckResponse, err error) {
-	utils.MustLogin(whatsapp.GetClient())
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
 
 	utils.SanitizePhone(&request.Phone)
 
-	response.IsOnWhatsApp = utils.IsOnWhatsapp(whatsapp.GetClient(), request.Phone)
+	response.IsOnWhatsApp = utils.IsOnWhatsapp(whatsapp.ClientFromContext(ctx), request.Phone)
 
 	return response, nil
 }
@@ -258,12 +392,12 @@ func (service serviceUser) BusinessProfile(ctx context.Context, request domainUs
 		return response, err
 	}
 
-	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.GetClient(), request.Phone)
+	dataWaRecipient, err := utils.ValidateJidWithLogin(whatsapp.ClientFromContext(ctx), request.Phone)
 	if err != nil {
 		return response, err
 	}
 
-	profile, err := whatsapp.GetClient().GetBusinessProfile(ctx, dataWaRecipient)
+	profile, err := whatsapp.ClientFromContext(ctx).GetBusinessProfile(ctx, dataWaRecipient)
 	if err != nil {
 		return response, err
 	}
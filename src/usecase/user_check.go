@@ -0,0 +1,398 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	// maxBulkCheckNumbers caps a single bulk check
+	maxBulkCheckNumbers = 50000
+	// bulkCheckBatch is the number of numbers sent in one IsOnWhatsApp query
+	bulkCheckBatch = 50
+	// maxBulkCheckFailures stops a bulk check after this many failed queries in a row, as
+	// WhatsApp is then most likely rate limiting us
+	maxBulkCheckFailures = 3
+)
+
+// numberChecker answers IsOnWhatsApp questions from the number check cache where possible.
+// It also backs utils.IsOnWhatsapp through utils.SetNumberCheckCache.
+type numberChecker struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+// NewNumberCheckCache creates the cache consulted by utils.IsOnWhatsapp
+func NewNumberCheckCache(chatStorageRepo domainChatStorage.IChatStorageRepository) utils.NumberCheckCache {
+	return &numberChecker{chatStorageRepo: chatStorageRepo}
+}
+
+func (c *numberChecker) CachedIsOnWhatsApp(_ context.Context, phone string) (registered bool, found bool) {
+	checks := c.cached([]string{phone})
+	if check, ok := checks[phone]; ok {
+		return check.IsOnWhatsApp, true
+	}
+	return false, false
+}
+
+func (c *numberChecker) StoreIsOnWhatsApp(ctx context.Context, results []types.IsOnWhatsAppResponse) {
+	if config.WhatsappNumberCheckTTL <= 0 || c.chatStorageRepo == nil {
+		return
+	}
+
+	checks := toNumberChecks(ctx, results)
+	if err := c.chatStorageRepo.StoreNumberChecks(checks); err != nil {
+		logrus.Warnf("Failed to cache number checks: %v", err)
+	}
+}
+
+// cached returns the fresh cached checks of the given phones
+func (c *numberChecker) cached(phones []string) map[string]*domainChatStorage.NumberCheck {
+	if config.WhatsappNumberCheckTTL <= 0 || c.chatStorageRepo == nil || len(phones) == 0 {
+		return nil
+	}
+
+	checks, err := c.chatStorageRepo.GetNumberChecks(phones, time.Now().Add(-config.WhatsappNumberCheckTTL))
+	if err != nil {
+		logrus.Warnf("Failed to read cached number checks: %v", err)
+		return nil
+	}
+	return checks
+}
+
+// lookup asks WhatsApp about the given phones in a single query and caches the answers.
+// LIDs missing from the local mapping are fetched with one extra user info query.
+func (c *numberChecker) lookup(ctx context.Context, client *whatsmeow.Client, phones []string) (map[string]*domainChatStorage.NumberCheck, error) {
+	queries := make([]string, len(phones))
+	for i, phone := range phones {
+		queries[i] = "+" + phone
+	}
+
+	results, err := client.IsOnWhatsApp(ctx, queries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check numbers on WhatsApp: %w", err)
+	}
+
+	checks := toNumberChecks(ctx, results)
+	resolveMissingLIDs(ctx, client, checks)
+
+	if config.WhatsappNumberCheckTTL > 0 && c.chatStorageRepo != nil {
+		if err := c.chatStorageRepo.StoreNumberChecks(checks); err != nil {
+			logrus.Warnf("Failed to cache number checks: %v", err)
+		}
+	}
+
+	found := make(map[string]*domainChatStorage.NumberCheck, len(checks))
+	for _, check := range checks {
+		found[check.Phone] = check
+	}
+	return found, nil
+}
+
+// toNumberChecks converts IsOnWhatsApp results, taking LIDs from the locally known mappings
+func toNumberChecks(ctx context.Context, results []types.IsOnWhatsAppResponse) []*domainChatStorage.NumberCheck {
+	identity := whatsapp.NewIdentityResolver()
+	now := time.Now()
+
+	checks := make([]*domainChatStorage.NumberCheck, 0, len(results))
+	for _, result := range results {
+		phone := normalizeImportPhone(result.Query)
+		if phone == "" {
+			phone = result.JID.User
+		}
+
+		check := &domainChatStorage.NumberCheck{Phone: phone, IsOnWhatsApp: result.IsIn, CheckedAt: now}
+		if result.IsIn {
+			jid := result.JID.ToNonAD()
+			check.JID = jid.String()
+			if lid := identity.Alternate(ctx, jid); !lid.IsEmpty() {
+				check.LID = lid.String()
+			}
+		}
+		if result.VerifiedName != nil {
+			check.IsBusiness = true
+			check.BusinessName = result.VerifiedName.Details.GetVerifiedName()
+		}
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+// resolveMissingLIDs fills in the LIDs of registered numbers we have no mapping for yet
+func resolveMissingLIDs(ctx context.Context, client *whatsmeow.Client, checks []*domainChatStorage.NumberCheck) {
+	missing := make(map[types.JID]*domainChatStorage.NumberCheck)
+	var jids []types.JID
+	for _, check := range checks {
+		if !check.IsOnWhatsApp || check.LID != "" {
+			continue
+		}
+		jid, err := types.ParseJID(check.JID)
+		if err != nil {
+			continue
+		}
+		missing[jid] = check
+		jids = append(jids, jid)
+	}
+	if len(jids) == 0 {
+		return
+	}
+
+	infos, err := client.GetUserInfo(ctx, jids)
+	if err != nil {
+		logrus.Debugf("Failed to fetch LIDs of %d numbers: %v", len(jids), err)
+		return
+	}
+	for jid, info := range infos {
+		if check, ok := missing[jid.ToNonAD()]; ok && !info.LID.IsEmpty() {
+			check.LID = info.LID.ToNonAD().String()
+		}
+	}
+}
+
+func (service serviceUser) BulkCheck(ctx context.Context, request domainUser.BulkCheckRequest) (response domainUser.BulkCheckReport, err error) {
+	if err = validations.ValidateBulkCheck(ctx, &request); err != nil {
+		return response, err
+	}
+	utils.MustLogin(whatsapp.ClientFromContext(ctx))
+
+	var phones []string
+	if request.File != nil {
+		file, err := request.File.Open()
+		if err != nil {
+			return response, err
+		}
+		defer file.Close()
+
+		if phones, err = parseParticipantCSV(file); err != nil {
+			return response, pkgError.ValidationError(fmt.Sprintf("file: %v.", err))
+		}
+	} else {
+		phones = parseBulkCheckPhones(request.Phones)
+	}
+	if len(phones) == 0 {
+		return response, pkgError.ValidationError("phones: must contain at least one valid phone number.")
+	}
+	if len(phones) > maxBulkCheckNumbers {
+		return response, pkgError.ValidationError(fmt.Sprintf("phones: must contain at most %d phone numbers.", maxBulkCheckNumbers))
+	}
+
+	job := &domainChatStorage.NumberCheckJob{
+		ID:     uuid.NewString(),
+		Status: domainChatStorage.NumberCheckJobRunning,
+		Total:  len(phones),
+	}
+	if err = service.chatStorageRepo.CreateNumberCheckJob(job, phones); err != nil {
+		return response, err
+	}
+
+	backgroundJobs.start(ctx, func(ctx context.Context) {
+		service.runBulkCheck(ctx, job.ID, phones, time.Duration(request.DelaySeconds)*time.Second)
+	})
+
+	entries := make([]*domainChatStorage.NumberCheckJobEntry, len(phones))
+	for i, phone := range phones {
+		entries[i] = &domainChatStorage.NumberCheckJobEntry{
+			NumberCheck: domainChatStorage.NumberCheck{Phone: phone},
+			JobID:       job.ID,
+			Position:    i,
+			Status:      domainChatStorage.NumberCheckEntryPending,
+		}
+	}
+
+	return toBulkCheckReport(job, entries), nil
+}
+
+func (service serviceUser) GetBulkCheck(ctx context.Context, request domainUser.GetBulkCheckRequest) (response domainUser.BulkCheckReport, err error) {
+	if err = validations.ValidateGetBulkCheck(ctx, request); err != nil {
+		return response, err
+	}
+
+	job, err := service.chatStorageRepo.GetNumberCheckJob(request.JobID)
+	if err != nil {
+		return response, err
+	}
+	if job == nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("bulk check %s not found", request.JobID))
+	}
+
+	entries, err := service.chatStorageRepo.GetNumberCheckJobEntries(request.JobID)
+	if err != nil {
+		return response, err
+	}
+
+	return toBulkCheckReport(job, entries), nil
+}
+
+// runBulkCheck works through the numbers in batches. Cached numbers are answered right away;
+// the rest is sent to WhatsApp with a pause between queries so large lists do not get the
+// account banned. The job fails as interrupted when ctx is cancelled by the shutdown.
+func (service serviceUser) runBulkCheck(ctx context.Context, jobID string, phones []string, delay time.Duration) {
+	status, errMessage := domainChatStorage.NumberCheckJobCompleted, ""
+
+	defer func() {
+		if r := recover(); r != nil {
+			status, errMessage = domainChatStorage.NumberCheckJobFailed, fmt.Sprint(r)
+		}
+		if err := service.chatStorageRepo.FinishNumberCheckJob(jobID, status, errMessage); err != nil {
+			logrus.Errorf("Failed to finish bulk number check %s: %v", jobID, err)
+		}
+		logrus.Infof("Bulk number check %s of %d numbers finished with status %s", jobID, len(phones), status)
+	}()
+
+	if config.WhatsappNumberCheckTTL > 0 {
+		if _, err := service.chatStorageRepo.DeleteNumberChecksBefore(time.Now().Add(-config.WhatsappNumberCheckTTL)); err != nil {
+			logrus.Warnf("Failed to purge expired number checks: %v", err)
+		}
+	}
+
+	queried, failures := false, 0
+	for start := 0; start < len(phones); start += bulkCheckBatch {
+		if ctx.Err() != nil {
+			status, errMessage = domainChatStorage.NumberCheckJobFailed, jobInterruptedByShutdown
+			return
+		}
+		batch := phones[start:min(start+bulkCheckBatch, len(phones))]
+
+		cached := service.numberChecks.cached(batch)
+		var uncached []string
+		for _, phone := range batch {
+			if _, ok := cached[phone]; !ok {
+				uncached = append(uncached, phone)
+			}
+		}
+
+		var (
+			found    map[string]*domainChatStorage.NumberCheck
+			queryErr error
+		)
+		if len(uncached) > 0 {
+			client := whatsapp.ClientFromContext(ctx)
+			if client == nil {
+				status, errMessage = domainChatStorage.NumberCheckJobFailed, pkgError.ErrWaCLI.Error()
+				return
+			}
+
+			if queried && !sleepContext(ctx, delay) {
+				status, errMessage = domainChatStorage.NumberCheckJobFailed, jobInterruptedByShutdown
+				return
+			}
+			queried = true
+
+			found, queryErr = service.numberChecks.lookup(ctx, client, uncached)
+			if queryErr != nil {
+				failures++
+			} else {
+				failures = 0
+			}
+		}
+
+		entries := make([]*domainChatStorage.NumberCheckJobEntry, 0, len(batch))
+		for i, phone := range batch {
+			entry := &domainChatStorage.NumberCheckJobEntry{
+				NumberCheck: domainChatStorage.NumberCheck{Phone: phone},
+				JobID:       jobID,
+				Position:    start + i,
+				Status:      domainChatStorage.NumberCheckEntryChecked,
+			}
+			switch {
+			case cached[phone] != nil:
+				entry.NumberCheck, entry.Cached = *cached[phone], true
+			case queryErr != nil:
+				entry.Status, entry.Message = domainChatStorage.NumberCheckEntryFailed, queryErr.Error()
+			case found[phone] != nil:
+				entry.NumberCheck = *found[phone]
+			default:
+				entry.Status, entry.Message = domainChatStorage.NumberCheckEntryFailed, "no result returned for number"
+			}
+			entries = append(entries, entry)
+		}
+
+		if err := service.chatStorageRepo.UpdateNumberCheckJobEntries(jobID, entries); err != nil {
+			logrus.Errorf("Failed to store bulk number check %s results: %v", jobID, err)
+		}
+
+		if failures >= maxBulkCheckFailures {
+			status, errMessage = domainChatStorage.NumberCheckJobFailed, fmt.Sprintf("stopped after %d failed queries in a row: %v", failures, queryErr)
+			return
+		}
+	}
+}
+
+// parseBulkCheckPhones normalizes the given numbers, which may also be comma or newline
+// separated lists, and drops invalid ones and duplicates
+func parseBulkCheckPhones(values []string) []string {
+	seen := make(map[string]bool)
+	var phones []string
+
+	for _, value := range values {
+		for _, field := range strings.FieldsFunc(value, func(r rune) bool {
+			return r == ',' || r == ';' || r == '\n' || r == '\r'
+		}) {
+			phone := normalizeImportPhone(field)
+			if phone == "" || seen[phone] {
+				continue
+			}
+			seen[phone] = true
+			phones = append(phones, phone)
+		}
+	}
+
+	return phones
+}
+
+func toBulkCheckReport(job *domainChatStorage.NumberCheckJob, entries []*domainChatStorage.NumberCheckJobEntry) domainUser.BulkCheckReport {
+	report := domainUser.BulkCheckReport{
+		JobID:      job.ID,
+		Status:     job.Status,
+		Error:      job.Error,
+		Total:      job.Total,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+		Results:    make([]domainUser.BulkCheckResult, 0, len(entries)),
+	}
+
+	for _, entry := range entries {
+		result := domainUser.BulkCheckResult{
+			Phone:        entry.Phone,
+			Status:       entry.Status,
+			Message:      entry.Message,
+			IsOnWhatsApp: entry.IsOnWhatsApp,
+			JID:          entry.JID,
+			LID:          entry.LID,
+			IsBusiness:   entry.IsBusiness,
+			BusinessName: entry.BusinessName,
+			Cached:       entry.Cached,
+		}
+		if !entry.CheckedAt.IsZero() {
+			checkedAt := entry.CheckedAt
+			result.CheckedAt = &checkedAt
+		}
+
+		switch {
+		case entry.Status == domainChatStorage.NumberCheckEntryFailed:
+			report.Failed++
+		case entry.Status == domainChatStorage.NumberCheckEntryChecked && entry.IsOnWhatsApp:
+			report.Registered++
+		case entry.Status == domainChatStorage.NumberCheckEntryChecked:
+			report.NotRegistered++
+		}
+		report.Results = append(report.Results, result)
+	}
+	report.Checked = report.Registered + report.NotRegistered + report.Failed
+
+	return report
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBulkCheckPhones(t *testing.T) {
+	got := parseBulkCheckPhones([]string{
+		"6281234567890",
+		"+62 812-3456-7891, 6281234567890",
+		"6281234567892;12\n6281234567893@s.whatsapp.net",
+		"1234567890@lid",
+	})
+
+	assert.Equal(t, []string{"6281234567890", "6281234567891", "6281234567892", "6281234567893"}, got)
+}
+
+func TestToBulkCheckReport(t *testing.T) {
+	checkedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	job := &domainChatStorage.NumberCheckJob{
+		ID:     "job-1",
+		Status: domainChatStorage.NumberCheckJobRunning,
+		Total:  4,
+	}
+	entries := []*domainChatStorage.NumberCheckJobEntry{
+		{
+			NumberCheck: domainChatStorage.NumberCheck{
+				Phone: "6281234567890", IsOnWhatsApp: true, JID: "6281234567890@s.whatsapp.net", LID: "1234@lid",
+				IsBusiness: true, BusinessName: "Acme", CheckedAt: checkedAt,
+			},
+			Status: domainChatStorage.NumberCheckEntryChecked,
+			Cached: true,
+		},
+		{
+			NumberCheck: domainChatStorage.NumberCheck{Phone: "6281234567891", CheckedAt: checkedAt},
+			Status:      domainChatStorage.NumberCheckEntryChecked,
+		},
+		{
+			NumberCheck: domainChatStorage.NumberCheck{Phone: "6281234567892"},
+			Status:      domainChatStorage.NumberCheckEntryFailed,
+			Message:     "rate limited",
+		},
+		{
+			NumberCheck: domainChatStorage.NumberCheck{Phone: "6281234567893"},
+			Status:      domainChatStorage.NumberCheckEntryPending,
+		},
+	}
+
+	report := toBulkCheckReport(job, entries)
+
+	assert.Equal(t, "job-1", report.JobID)
+	assert.Equal(t, 4, report.Total)
+	assert.Equal(t, 3, report.Checked)
+	assert.Equal(t, 1, report.Registered)
+	assert.Equal(t, 1, report.NotRegistered)
+	assert.Equal(t, 1, report.Failed)
+	assert.Len(t, report.Results, 4)
+
+	assert.Equal(t, "1234@lid", report.Results[0].LID)
+	assert.True(t, report.Results[0].IsBusiness)
+	assert.True(t, report.Results[0].Cached)
+	assert.Equal(t, &checkedAt, report.Results[0].CheckedAt)
+	assert.Equal(t, "rate limited", report.Results[2].Message)
+	assert.Nil(t, report.Results[3].CheckedAt)
+}
@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// SubscribePresence asks WhatsApp for presence updates of the given contacts. Subscriptions are
+// stored so they are renewed after every reconnect.
+func (service serviceUser) SubscribePresence(ctx context.Context, request domainUser.PresenceSubscriptionRequest) (response domainUser.PresenceResponse, err error) {
+	if err = validations.ValidatePresenceSubscription(ctx, request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	identity := whatsapp.NewIdentityResolver()
+
+	jids := make([]types.JID, 0, len(request.Phones))
+	keys := make([]string, 0, len(request.Phones))
+	for _, phone := range request.Phones {
+		jid, err := utils.ValidateJidWithLogin(client, phone)
+		if err != nil {
+			return response, err
+		}
+		if jid.Server != types.DefaultUserServer && jid.Server != types.HiddenUserServer {
+			return response, pkgError.ValidationError(fmt.Sprintf("phones: presence is only available for users, not %s.", jid))
+		}
+		jid = identity.Canonical(ctx, jid)
+		jids = append(jids, jid)
+		keys = append(keys, jid.String())
+	}
+
+	if err = service.chatStorageRepo.StorePresenceSubscriptions(ctx, keys, time.Now()); err != nil {
+		return response, err
+	}
+
+	for _, jid := range jids {
+		if err = client.SubscribePresence(ctx, jid); err != nil {
+			return response, fmt.Errorf("failed to subscribe to presence of %s: %w", jid, err)
+		}
+	}
+
+	return service.presenceResponse(ctx, keys, 0)
+}
+
+// UnsubscribePresence stops renewing the given subscriptions. WhatsApp has no way to cancel a
+// subscription, so updates may keep arriving until the next reconnect.
+func (service serviceUser) UnsubscribePresence(ctx context.Context, request domainUser.PresenceSubscriptionRequest) (err error) {
+	if err = validations.ValidatePresenceSubscription(ctx, request); err != nil {
+		return err
+	}
+
+	identity := whatsapp.NewIdentityResolver()
+	keys := make([]string, 0, len(request.Phones))
+	for _, phone := range request.Phones {
+		jid, err := utils.ParseJID(phone)
+		if err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("phones: %v.", err))
+		}
+		keys = append(keys, identity.Canonical(ctx, jid).String())
+	}
+
+	return service.chatStorageRepo.DeletePresenceSubscriptions(ctx, keys)
+}
+
+// Presence returns the recorded presence of a contact including its history, or of every
+// subscribed or seen contact when no phone is given
+func (service serviceUser) Presence(ctx context.Context, request domainUser.PresenceRequest) (response domainUser.PresenceResponse, err error) {
+	if err = validations.ValidatePresence(ctx, &request); err != nil {
+		return response, err
+	}
+
+	if request.Phone == "" {
+		return service.presenceResponse(ctx, nil, 0)
+	}
+
+	jid, err := utils.ParseJID(request.Phone)
+	if err != nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("phone: %v.", err))
+	}
+
+	return service.presenceResponse(ctx, []string{whatsapp.NewIdentityResolver().Canonical(ctx, jid).String()}, request.HistoryLimit)
+}
+
+// presenceResponse describes the given contacts, or every subscribed or seen contact when jids is
+// nil. History is only included when historyLimit is positive.
+func (service serviceUser) presenceResponse(ctx context.Context, jids []string, historyLimit int) (response domainUser.PresenceResponse, err error) {
+	subscriptions, err := service.chatStorageRepo.GetPresenceSubscriptions(ctx)
+	if err != nil {
+		return response, err
+	}
+	subscribedAt := make(map[string]time.Time, len(subscriptions))
+	for _, subscription := range subscriptions {
+		subscribedAt[subscription.JID] = subscription.SubscribedAt
+	}
+
+	presences, err := service.chatStorageRepo.GetContactPresences(jids)
+	if err != nil {
+		return response, err
+	}
+
+	if jids == nil {
+		for jid := range subscribedAt {
+			jids = append(jids, jid)
+		}
+		for jid := range presences {
+			if _, ok := subscribedAt[jid]; !ok {
+				jids = append(jids, jid)
+			}
+		}
+		sort.Strings(jids)
+	}
+
+	response.Data = make([]domainUser.PresenceResponseData, 0, len(jids))
+	for _, jid := range jids {
+		item := domainUser.PresenceResponseData{JID: jid, Status: "unknown"}
+		if at, ok := subscribedAt[jid]; ok {
+			item.Subscribed = true
+			item.SubscribedAt = &at
+		}
+		if presence, ok := presences[jid]; ok {
+			item.Status = presenceStatus(presence.Online)
+			item.LastSeen = presence.LastSeen
+			item.UpdatedAt = &presence.UpdatedAt
+		}
+
+		if historyLimit > 0 {
+			history, err := service.chatStorageRepo.GetPresenceHistory(jid, historyLimit)
+			if err != nil {
+				return response, err
+			}
+			item.History = toPresenceHistory(history)
+		}
+
+		response.Data = append(response.Data, item)
+	}
+
+	return response, nil
+}
+
+func toPresenceHistory(events []*domainChatStorage.PresenceEvent) []domainUser.PresenceHistoryItem {
+	history := make([]domainUser.PresenceHistoryItem, 0, len(events))
+	for _, event := range events {
+		history = append(history, domainUser.PresenceHistoryItem{
+			Status:   presenceStatus(event.Online),
+			LastSeen: event.LastSeen,
+			At:       event.CreatedAt,
+		})
+	}
+	return history
+}
+
+func presenceStatus(online bool) string {
+	if online {
+		return "online"
+	}
+	return "offline"
+}
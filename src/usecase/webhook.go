@@ -0,0 +1,407 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+)
+
+type serviceWebhook struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+
+	// configured are the definitions of the webhook config file
+	configured []*domainChatStorage.WebhookDefinition
+
+	// routes are the enabled definitions events are routed by, reloaded after every change
+	mu     sync.RWMutex
+	routes []*domainChatStorage.WebhookDefinition
+}
+
+// NewWebhookService creates the webhook service with the definitions of the config file, see ReadWebhookConfig
+func NewWebhookService(chatStorageRepo domainChatStorage.IChatStorageRepository, configured []*domainChatStorage.WebhookDefinition) domainWebhook.IWebhookUsecase {
+	service := &serviceWebhook{
+		chatStorageRepo: chatStorageRepo,
+		configured:      configured,
+	}
+	if err := service.reloadRoutes(); err != nil {
+		logrus.Errorf("[WEBHOOK] Failed to load webhook definitions: %v", err)
+	}
+	return service
+}
+
+// ReadWebhookConfig reads and validates the definitions of a webhook config file, none when path is empty
+func ReadWebhookConfig(path string) ([]*domainChatStorage.WebhookDefinition, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook config: %w", err)
+	}
+
+	var file domainWebhook.ConfigFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid webhook config %s: %w", path, err)
+	}
+
+	definitions := make([]*domainChatStorage.WebhookDefinition, 0, len(file.Webhooks))
+	for i, request := range file.Webhooks {
+		if err := validations.ValidateWebhookDefinition(context.Background(), &request); err != nil {
+			return nil, fmt.Errorf("invalid webhook %d in %s: %w", i+1, path, err)
+		}
+
+		definition := &domainChatStorage.WebhookDefinition{}
+		if err := applyWebhookDefinition(definition, request); err != nil {
+			return nil, fmt.Errorf("invalid webhook %d in %s: %w", i+1, path, err)
+		}
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, nil
+}
+
+// ListDefinitions returns the definitions of the config file followed by the ones managed with the API
+func (service *serviceWebhook) ListDefinitions(_ context.Context) (response domainWebhook.ListDefinitionsResponse, err error) {
+	stored, err := service.chatStorageRepo.GetWebhookDefinitions()
+	if err != nil {
+		return response, err
+	}
+
+	response.Definitions = make([]domainWebhook.Definition, 0, len(service.configured)+len(stored))
+	for _, definition := range service.configured {
+		response.Definitions = append(response.Definitions, toWebhookDefinition(definition, domainWebhook.SourceConfig))
+	}
+	for _, definition := range stored {
+		response.Definitions = append(response.Definitions, toWebhookDefinition(definition, domainWebhook.SourceAPI))
+	}
+
+	return response, nil
+}
+
+func (service *serviceWebhook) CreateDefinition(ctx context.Context, request domainWebhook.DefinitionRequest) (response domainWebhook.Definition, err error) {
+	if err = validations.ValidateWebhookDefinition(ctx, &request); err != nil {
+		return response, err
+	}
+
+	definition := &domainChatStorage.WebhookDefinition{}
+	if err = applyWebhookDefinition(definition, request); err != nil {
+		return response, err
+	}
+
+	if err = service.chatStorageRepo.StoreWebhookDefinition(definition); err != nil {
+		return response, err
+	}
+
+	return toWebhookDefinition(definition, domainWebhook.SourceAPI), service.reloadRoutes()
+}
+
+func (service *serviceWebhook) UpdateDefinition(ctx context.Context, request domainWebhook.DefinitionRequest) (response domainWebhook.Definition, err error) {
+	if err = validations.ValidateUpdateWebhookDefinition(ctx, &request); err != nil {
+		return response, err
+	}
+
+	definition, err := service.chatStorageRepo.GetWebhookDefinition(request.DefinitionID)
+	if err != nil {
+		return response, err
+	}
+	if definition == nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("webhook definition %d not found", request.DefinitionID))
+	}
+
+	if err = applyWebhookDefinition(definition, request); err != nil {
+		return response, err
+	}
+
+	if err = service.chatStorageRepo.StoreWebhookDefinition(definition); err != nil {
+		return response, err
+	}
+
+	return toWebhookDefinition(definition, domainWebhook.SourceAPI), service.reloadRoutes()
+}
+
+func (service *serviceWebhook) DeleteDefinition(ctx context.Context, request domainWebhook.DeleteDefinitionRequest) (err error) {
+	if err = validations.ValidateDeleteWebhookDefinition(ctx, request); err != nil {
+		return err
+	}
+
+	definition, err := service.chatStorageRepo.GetWebhookDefinition(request.DefinitionID)
+	if err != nil {
+		return err
+	}
+	if definition == nil {
+		return pkgError.ValidationError(fmt.Sprintf("webhook definition %d not found", request.DefinitionID))
+	}
+
+	if err = service.chatStorageRepo.DeleteWebhookDefinition(request.DefinitionID); err != nil {
+		return err
+	}
+
+	return service.reloadRoutes()
+}
+
+// WebhookTargets returns the URLs of the enabled definitions the event matches, each URL once
+func (service *serviceWebhook) WebhookTargets(event domainWebhook.Event) []string {
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
+	var targets []string
+	for _, definition := range service.routes {
+		if webhookDefinitionMatches(definition, event) && !slices.Contains(targets, definition.URL) {
+			targets = append(targets, definition.URL)
+		}
+	}
+	return targets
+}
+
+// WebhookSubscribed reports whether an enabled definition subscribes to the event type, so events
+// nobody receives are not built at all
+func (service *serviceWebhook) WebhookSubscribed(eventType string) bool {
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
+	for _, definition := range service.routes {
+		if len(definition.Events) == 0 || slices.Contains(definition.Events, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// reloadRoutes replaces the definitions events are routed by with the enabled definitions of the
+// config file and the chat storage
+func (service *serviceWebhook) reloadRoutes() error {
+	stored, err := service.chatStorageRepo.GetWebhookDefinitions()
+	if err != nil {
+		return err
+	}
+
+	var routes []*domainChatStorage.WebhookDefinition
+	for _, definition := range slices.Concat(service.configured, stored) {
+		if definition.Enabled {
+			routes = append(routes, definition)
+		}
+	}
+
+	service.mu.Lock()
+	service.routes = routes
+	service.mu.Unlock()
+	return nil
+}
+
+func (service *serviceWebhook) ListDeliveries(ctx context.Context, request domainWebhook.ListDeliveriesRequest) (response domainWebhook.ListDeliveriesResponse, err error) {
+	if err = validations.ValidateListWebhookDeliveries(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := &domainChatStorage.WebhookDeliveryFilter{
+		SessionID: domainSession.FromContext(ctx),
+		Status:    request.Status,
+		Event:     request.Event,
+		Limit:     request.Limit,
+		Offset:    request.Offset,
+	}
+
+	deliveries, err := service.chatStorageRepo.GetWebhookDeliveries(filter)
+	if err != nil {
+		return response, err
+	}
+
+	total, err := service.chatStorageRepo.CountWebhookDeliveries(filter)
+	if err != nil {
+		return response, err
+	}
+
+	ids := make([]int64, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ids = append(ids, delivery.ID)
+	}
+	attempts, err := service.chatStorageRepo.GetWebhookDeliveryAttempts(ids)
+	if err != nil {
+		return response, err
+	}
+
+	response.Deliveries = make([]domainWebhook.Delivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response.Deliveries = append(response.Deliveries, toWebhookDelivery(delivery, attempts[delivery.ID]))
+	}
+	response.Pagination = domainWebhook.DeliveriesPagination{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  total,
+	}
+
+	return response, nil
+}
+
+// ReplayDelivery queues a delivery again, whatever its state, with a fresh budget of attempts
+func (service *serviceWebhook) ReplayDelivery(ctx context.Context, request domainWebhook.ReplayDeliveryRequest) (response domainWebhook.Delivery, err error) {
+	if err = validations.ValidateReplayWebhookDelivery(ctx, &request); err != nil {
+		return response, err
+	}
+
+	existing, err := service.chatStorageRepo.GetWebhookDelivery(request.DeliveryID)
+	if err != nil {
+		return response, err
+	}
+	if existing == nil || existing.SessionID != domainSession.FromContext(ctx) {
+		return response, pkgError.ValidationError(fmt.Sprintf("webhook delivery %d not found", request.DeliveryID))
+	}
+
+	delivery, err := service.chatStorageRepo.ReplayWebhookDelivery(request.DeliveryID, max(config.WhatsappWebhookMaxAttempts, 1), time.Now())
+	if err != nil {
+		return response, err
+	}
+	if delivery == nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("webhook delivery %d not found", request.DeliveryID))
+	}
+	whatsapp.WakeWebhookOutbox()
+
+	attempts, err := service.chatStorageRepo.GetWebhookDeliveryAttempts([]int64{delivery.ID})
+	if err != nil {
+		return response, err
+	}
+
+	return toWebhookDelivery(delivery, attempts[delivery.ID]), nil
+}
+
+func toWebhookDelivery(delivery *domainChatStorage.WebhookDelivery, attempts []*domainChatStorage.WebhookDeliveryAttempt) domainWebhook.Delivery {
+	result := domainWebhook.Delivery{
+		ID:             delivery.ID,
+		Event:          delivery.Event,
+		URL:            delivery.URL,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		MaxAttempts:    delivery.MaxAttempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt.Format(time.RFC3339),
+		History:        make([]domainWebhook.DeliveryAttempt, 0, len(attempts)),
+	}
+	if delivery.Status == domainChatStorage.WebhookDeliveryPending {
+		result.NextAttemptAt = delivery.NextAttemptAt.Format(time.RFC3339)
+	}
+	if delivery.DeliveredAt != nil {
+		result.DeliveredAt = delivery.DeliveredAt.Format(time.RFC3339)
+	}
+	for _, attempt := range attempts {
+		result.History = append(result.History, domainWebhook.DeliveryAttempt{
+			Attempt:     attempt.Attempt,
+			StatusCode:  attempt.StatusCode,
+			Error:       attempt.Error,
+			DurationMs:  attempt.DurationMs,
+			AttemptedAt: attempt.AttemptedAt.Format(time.RFC3339),
+		})
+	}
+	return result
+}
+
+// webhookDefinitionMatches reports whether a definition subscribes to an event. Chat filters and
+// the chat type only let through events that belong to a chat.
+func webhookDefinitionMatches(definition *domainChatStorage.WebhookDefinition, event domainWebhook.Event) bool {
+	if len(definition.Events) > 0 && !slices.Contains(definition.Events, event.Type) {
+		return false
+	}
+	if event.FromMe && !definition.IncludeFromMe {
+		return false
+	}
+
+	isGroup := utils.IsGroupJID(event.Chat)
+	switch definition.ChatType {
+	case domainChatStorage.WebhookChatPrivate:
+		if event.Chat == "" || isGroup {
+			return false
+		}
+	case domainChatStorage.WebhookChatGroup:
+		if !isGroup {
+			return false
+		}
+	}
+
+	listed := func(chats []string) bool {
+		return slices.Contains(chats, event.Chat) || (event.Sender != "" && slices.Contains(chats, event.Sender))
+	}
+	if len(definition.IncludeChats) > 0 && (event.Chat == "" || !listed(definition.IncludeChats)) {
+		return false
+	}
+	if event.Chat != "" && listed(definition.ExcludeChats) {
+		return false
+	}
+
+	return true
+}
+
+// webhookChatJIDs turns phone numbers, group IDs and JIDs into the JIDs events carry
+func webhookChatJIDs(field string, chats []string) ([]string, error) {
+	jids := make([]string, 0, len(chats))
+	for _, chat := range chats {
+		utils.SanitizePhone(&chat)
+		jid, err := utils.ParseJID(chat)
+		if err != nil {
+			return nil, pkgError.ValidationError(fmt.Sprintf("%s: %v.", field, err))
+		}
+		jids = append(jids, jid.ToNonAD().String())
+	}
+	return jids, nil
+}
+
+func applyWebhookDefinition(definition *domainChatStorage.WebhookDefinition, request domainWebhook.DefinitionRequest) (err error) {
+	if definition.IncludeChats, err = webhookChatJIDs("include_chats", request.IncludeChats); err != nil {
+		return err
+	}
+	if definition.ExcludeChats, err = webhookChatJIDs("exclude_chats", request.ExcludeChats); err != nil {
+		return err
+	}
+
+	definition.Name = request.Name
+	definition.URL = request.URL
+	definition.Events = request.Events
+	definition.IncludeFromMe = *request.IncludeFromMe
+	definition.ChatType = request.ChatType
+	definition.Enabled = *request.Enabled
+
+	return nil
+}
+
+func toWebhookDefinition(definition *domainChatStorage.WebhookDefinition, source string) domainWebhook.Definition {
+	result := domainWebhook.Definition{
+		ID:            definition.ID,
+		Name:          definition.Name,
+		URL:           definition.URL,
+		Events:        definition.Events,
+		IncludeChats:  definition.IncludeChats,
+		ExcludeChats:  definition.ExcludeChats,
+		IncludeFromMe: definition.IncludeFromMe,
+		ChatType:      definition.ChatType,
+		Enabled:       definition.Enabled,
+		Source:        source,
+	}
+	if result.Events == nil {
+		result.Events = []string{}
+	}
+	if result.IncludeChats == nil {
+		result.IncludeChats = []string{}
+	}
+	if result.ExcludeChats == nil {
+		result.ExcludeChats = []string{}
+	}
+	if !definition.CreatedAt.IsZero() {
+		result.CreatedAt = definition.CreatedAt.Format(time.RFC3339)
+		result.UpdatedAt = definition.UpdatedAt.Format(time.RFC3339)
+	}
+	return result
+}
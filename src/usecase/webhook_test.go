@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDefinitionMatches(t *testing.T) {
+	const (
+		customer = "6289685028129@s.whatsapp.net"
+		group    = "120363025982934543@g.us"
+	)
+
+	analytics := &domainChatStorage.WebhookDefinition{IncludeFromMe: true, ChatType: domainChatStorage.WebhookChatAll}
+	crm := &domainChatStorage.WebhookDefinition{
+		Events:   []string{domainWebhook.EventMessage},
+		ChatType: domainChatStorage.WebhookChatPrivate,
+	}
+	groups := &domainChatStorage.WebhookDefinition{IncludeFromMe: true, ChatType: domainChatStorage.WebhookChatGroup}
+	included := &domainChatStorage.WebhookDefinition{IncludeFromMe: true, IncludeChats: []string{group}}
+	excluded := &domainChatStorage.WebhookDefinition{IncludeFromMe: true, ExcludeChats: []string{customer}}
+
+	inbound := domainWebhook.Event{Type: domainWebhook.EventMessage, Chat: customer, Sender: customer}
+	outbound := domainWebhook.Event{Type: domainWebhook.EventMessage, Chat: customer, FromMe: true}
+	inGroup := domainWebhook.Event{Type: domainWebhook.EventMessage, Chat: group, Sender: customer}
+	receipt := domainWebhook.Event{Type: domainWebhook.EventReceipt, Chat: customer, Sender: customer}
+	connection := domainWebhook.Event{Type: domainWebhook.EventConnection}
+
+	tests := []struct {
+		name       string
+		definition *domainChatStorage.WebhookDefinition
+		event      domainWebhook.Event
+		want       bool
+	}{
+		{name: "everything gets inbound", definition: analytics, event: inbound, want: true},
+		{name: "everything gets own messages", definition: analytics, event: outbound, want: true},
+		{name: "everything gets connection events", definition: analytics, event: connection, want: true},
+		{name: "crm gets private inbound", definition: crm, event: inbound, want: true},
+		{name: "crm skips own messages", definition: crm, event: outbound, want: false},
+		{name: "crm skips groups", definition: crm, event: inGroup, want: false},
+		{name: "crm skips receipts", definition: crm, event: receipt, want: false},
+		{name: "groups only gets groups", definition: groups, event: inGroup, want: true},
+		{name: "groups skips private", definition: groups, event: inbound, want: false},
+		{name: "groups skips events without chat", definition: groups, event: connection, want: false},
+		{name: "included chat", definition: included, event: inGroup, want: true},
+		{name: "chat not included", definition: included, event: inbound, want: false},
+		{name: "include skips events without chat", definition: included, event: connection, want: false},
+		{name: "excluded chat", definition: excluded, event: inbound, want: false},
+		{name: "excluded sender in a group", definition: excluded, event: inGroup, want: false},
+		{name: "exclude keeps events without chat", definition: excluded, event: connection, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, webhookDefinitionMatches(tt.definition, tt.event))
+		})
+	}
+}
+
+func TestWebhookTargets(t *testing.T) {
+	service := &serviceWebhook{routes: []*domainChatStorage.WebhookDefinition{
+		{URL: "https://analytics", IncludeFromMe: true},
+		{URL: "https://crm", Events: []string{domainWebhook.EventMessage}, ChatType: domainChatStorage.WebhookChatPrivate},
+		{URL: "https://analytics", Events: []string{domainWebhook.EventMessage}, IncludeFromMe: true},
+	}}
+
+	inbound := domainWebhook.Event{Type: domainWebhook.EventMessage, Chat: "6289685028129@s.whatsapp.net"}
+	assert.Equal(t, []string{"https://analytics", "https://crm"}, service.WebhookTargets(inbound))
+	assert.Equal(t, []string{"https://analytics"}, service.WebhookTargets(domainWebhook.Event{Type: domainWebhook.EventConnection}))
+	assert.True(t, service.WebhookSubscribed(domainWebhook.EventPresence))
+
+	service.routes = service.routes[1:2]
+	assert.False(t, service.WebhookSubscribed(domainWebhook.EventPresence))
+}
+
+func TestApplyWebhookDefinition(t *testing.T) {
+	includeFromMe, enabled := false, true
+	definition := &domainChatStorage.WebhookDefinition{}
+	err := applyWebhookDefinition(definition, domainWebhook.DefinitionRequest{
+		Name:          "crm",
+		URL:           "https://crm.local/hook",
+		IncludeChats:  []string{"+6289685028129", "120363025982934543", "6289685028129:12@s.whatsapp.net"},
+		IncludeFromMe: &includeFromMe,
+		ChatType:      domainChatStorage.WebhookChatPrivate,
+		Enabled:       &enabled,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"6289685028129@s.whatsapp.net",
+		"120363025982934543@g.us",
+		"6289685028129@s.whatsapp.net",
+	}, definition.IncludeChats)
+	assert.False(t, definition.IncludeFromMe)
+}
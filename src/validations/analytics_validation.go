@@ -0,0 +1,49 @@
+package validations
+
+import (
+	"context"
+	"time"
+
+	domainAnalytics "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/analytics"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateGetAnalytics(ctx context.Context, request *domainAnalytics.GetAnalyticsRequest) error {
+	// Set defaults if not provided
+	if request.Granularity == "" {
+		request.Granularity = domainAnalytics.GranularityDay
+	}
+	if request.Limit == 0 {
+		request.Limit = 10
+	}
+
+	hasExplicitRange := (request.StartTime != nil && *request.StartTime != "") ||
+		(request.EndTime != nil && *request.EndTime != "")
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Period,
+			validation.In(
+				domainAnalytics.PeriodToday,
+				domainAnalytics.PeriodYesterday,
+				domainAnalytics.PeriodLast7Days,
+				domainAnalytics.PeriodLastWeek,
+				domainAnalytics.PeriodThisWeek,
+				domainAnalytics.PeriodLast30Days,
+				domainAnalytics.PeriodThisMonth,
+				domainAnalytics.PeriodLastMonth,
+			),
+			validation.When(hasExplicitRange, validation.Empty.Error("cannot be combined with start_time or end_time")),
+		),
+		validation.Field(&request.StartTime, validation.Date(time.RFC3339)),
+		validation.Field(&request.EndTime, validation.Date(time.RFC3339)),
+		validation.Field(&request.Granularity, validation.In(domainAnalytics.GranularityDay, domainAnalytics.GranularityHour)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainAnalytics "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/analytics"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateGetAnalytics(t *testing.T) {
+	validTime := "2024-05-01T00:00:00Z"
+	invalidTime := "2024-05-01"
+
+	type args struct {
+		request domainAnalytics.GetAnalyticsRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with empty request (defaults applied)",
+			args: args{request: domainAnalytics.GetAnalyticsRequest{}},
+			err:  nil,
+		},
+		{
+			name: "should success with period and hour granularity",
+			args: args{request: domainAnalytics.GetAnalyticsRequest{
+				Period:      domainAnalytics.PeriodLastWeek,
+				Granularity: domainAnalytics.GranularityHour,
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with explicit range and chat",
+			args: args{request: domainAnalytics.GetAnalyticsRequest{
+				ChatJID:   "628123456789@s.whatsapp.net",
+				StartTime: &validTime,
+				EndTime:   &validTime,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with unknown period",
+			args: args{request: domainAnalytics.GetAnalyticsRequest{
+				Period: "last_year",
+			}},
+			err: pkgError.ValidationError("period: must be a valid value."),
+		},
+		{
+			name: "should error with period and explicit range",
+			args: args{request: domainAnalytics.GetAnalyticsRequest{
+				Period:    domainAnalytics.PeriodToday,
+				StartTime: &validTime,
+			}},
+			err: pkgError.ValidationError("period: cannot be combined with start_time or end_time."),
+		},
+		{
+			name: "should error with invalid start_time",
+			args: args{request: domainAnalytics.GetAnalyticsRequest{
+				StartTime: &invalidTime,
+			}},
+			err: pkgError.ValidationError("start_time: must be a valid date."),
+		},
+		{
+			name: "should error with invalid granularity",
+			args: args{request: domainAnalytics.GetAnalyticsRequest{
+				Granularity: "week",
+			}},
+			err: pkgError.ValidationError("granularity: must be a valid value."),
+		},
+		{
+			name: "should error with limit too high",
+			args: args{request: domainAnalytics.GetAnalyticsRequest{
+				Limit: 101,
+			}},
+			err: pkgError.ValidationError("limit: must be no greater than 100."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGetAnalytics(context.Background(), &tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -3,9 +3,12 @@ package validations
 import (
 	"context"
 	"fmt"
+	"os"
+	"regexp"
+
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
-	"regexp"
 )
 
 func ValidateLoginWithCode(ctx context.Context, phoneNumber string) error {
@@ -19,3 +22,17 @@ func ValidateLoginWithCode(ctx context.Context, phoneNumber string) error {
 	}
 	return nil
 }
+
+func ValidateRestore(ctx context.Context, request *domainApp.RestoreRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ArchivePath, validation.Required),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if _, err := os.Stat(request.ArchivePath); err != nil {
+		return pkgError.ValidationError(fmt.Sprintf("archive_path: %s", err.Error()))
+	}
+	return nil
+}
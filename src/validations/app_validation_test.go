@@ -2,7 +2,11 @@ package validations
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
+
+	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 )
 
 func TestValidateLoginWithCode(t *testing.T) {
@@ -59,3 +63,39 @@ func TestValidateLoginWithCode(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRestore(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "backup.tar.gz")
+	if err := os.WriteFile(archive, []byte("archive"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		request domainApp.RestoreRequest
+		wantErr bool
+	}{
+		{
+			name:    "Existing archive",
+			request: domainApp.RestoreRequest{ArchivePath: archive},
+			wantErr: false,
+		},
+		{
+			name:    "Empty archive path",
+			request: domainApp.RestoreRequest{},
+			wantErr: true,
+		},
+		{
+			name:    "Missing archive",
+			request: domainApp.RestoreRequest{ArchivePath: archive + ".missing"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateRestore(context.Background(), &tt.request); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRestore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
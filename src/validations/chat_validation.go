@@ -2,9 +2,11 @@ package validations
 
 import (
 	"context"
+	"errors"
 
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
@@ -17,13 +19,15 @@ func ValidateListChats(ctx context.Context, request *domainChat.ListChatsRequest
 	err := validation.ValidateStructWithContext(ctx, request,
 		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
 		validation.Field(&request.Offset, validation.Min(0)),
+		validation.Field(&request.Before, validation.By(validateCursor)),
+		validation.Field(&request.After, validation.By(validateCursor)),
 	)
 
 	if err != nil {
 		return pkgError.ValidationError(err.Error())
 	}
 
-	return nil
+	return validateCursorCombination(request.Before, request.After, request.Offset)
 }
 
 func ValidateGetChatMessages(ctx context.Context, request *domainChat.GetChatMessagesRequest) error {
@@ -36,13 +40,19 @@ func ValidateGetChatMessages(ctx context.Context, request *domainChat.GetChatMes
 		validation.Field(&request.ChatJID, validation.Required),
 		validation.Field(&request.Limit, validation.Min(1), validation.Max(100)),
 		validation.Field(&request.Offset, validation.Min(0)),
+		validation.Field(&request.Before, validation.By(validateCursor)),
+		validation.Field(&request.After, validation.By(validateCursor)),
 	)
 
 	if err != nil {
 		return pkgError.ValidationError(err.Error())
 	}
 
-	return nil
+	if (request.Before != "" || request.After != "") && request.Search != "" {
+		return pkgError.ValidationError("search cannot be combined with before or after cursors")
+	}
+
+	return validateCursorCombination(request.Before, request.After, request.Offset)
 }
 
 func ValidatePinChat(ctx context.Context, request *domainChat.PinChatRequest) error {
@@ -56,3 +66,30 @@ func ValidatePinChat(ctx context.Context, request *domainChat.PinChatRequest) er
 
 	return nil
 }
+
+// validateCursor checks that a pagination cursor, when provided, can be decoded
+func validateCursor(value any) error {
+	cursor, _ := value.(string)
+	if cursor == "" {
+		return nil
+	}
+
+	if _, _, err := utils.DecodeCursor(cursor); err != nil {
+		return errors.New("must be a cursor returned by a previous page")
+	}
+
+	return nil
+}
+
+// validateCursorCombination rejects mixing cursor directions or cursors with offsets
+func validateCursorCombination(before, after string, offset int) error {
+	if before != "" && after != "" {
+		return pkgError.ValidationError("before and after cannot be used together")
+	}
+
+	if (before != "" || after != "") && offset > 0 {
+		return pkgError.ValidationError("offset cannot be combined with before or after cursors")
+	}
+
+	return nil
+}
@@ -3,9 +3,11 @@ package validations
 import (
 	"context"
 	"testing"
+	"time"
 
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -13,6 +15,8 @@ func TestValidateListChats(t *testing.T) {
 	type args struct {
 		request domainChat.ListChatsRequest
 	}
+	cursor := utils.EncodeCursor(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), "3EB0B430B6F8F1D0E053")
+
 	tests := []struct {
 		name string
 		args args
@@ -58,6 +62,40 @@ func TestValidateListChats(t *testing.T) {
 			}},
 			err: pkgError.ValidationError("offset: must be no less than 0."),
 		},
+		{
+			name: "should success with before cursor",
+			args: args{request: domainChat.ListChatsRequest{
+				Limit:  25,
+				Before: cursor,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with malformed cursor",
+			args: args{request: domainChat.ListChatsRequest{
+				Limit: 25,
+				After: "not-a-cursor",
+			}},
+			err: pkgError.ValidationError("after: must be a cursor returned by a previous page."),
+		},
+		{
+			name: "should error with both cursors",
+			args: args{request: domainChat.ListChatsRequest{
+				Limit:  25,
+				Before: cursor,
+				After:  cursor,
+			}},
+			err: pkgError.ValidationError("before and after cannot be used together"),
+		},
+		{
+			name: "should error with cursor and offset",
+			args: args{request: domainChat.ListChatsRequest{
+				Limit:  25,
+				Offset: 10,
+				Before: cursor,
+			}},
+			err: pkgError.ValidationError("offset cannot be combined with before or after cursors"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -72,6 +110,8 @@ func TestValidateGetChatMessages(t *testing.T) {
 	type args struct {
 		request domainChat.GetChatMessagesRequest
 	}
+	cursor := utils.EncodeCursor(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), "3EB0B430B6F8F1D0E053")
+
 	tests := []struct {
 		name string
 		args args
@@ -131,6 +171,25 @@ func TestValidateGetChatMessages(t *testing.T) {
 			}},
 			err: pkgError.ValidationError("offset: must be no less than 0."),
 		},
+		{
+			name: "should success with after cursor",
+			args: args{request: domainChat.GetChatMessagesRequest{
+				ChatJID: "6289685028129@s.whatsapp.net",
+				Limit:   50,
+				After:   cursor,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with cursor and search",
+			args: args{request: domainChat.GetChatMessagesRequest{
+				ChatJID: "6289685028129@s.whatsapp.net",
+				Limit:   50,
+				Before:  cursor,
+				Search:  "hello",
+			}},
+			err: pkgError.ValidationError("search cannot be combined with before or after cursors"),
+		},
 	}
 
 	for _, tt := range tests {
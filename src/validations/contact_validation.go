@@ -0,0 +1,164 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	maxContactTags         = 20
+	maxContactCustomFields = 50
+)
+
+var contactTagPattern = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+
+var contactTagRules = []validation.Rule{
+	validation.Length(0, maxContactTags),
+	validation.Each(
+		validation.Required,
+		validation.RuneLength(1, 50),
+		validation.Match(contactTagPattern).Error("must contain only letters, digits, dashes and underscores"),
+	),
+}
+
+func ValidateContact(ctx context.Context, request domainContact.ContactRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.Name, validation.RuneLength(0, 100)),
+		validation.Field(&request.Tags, contactTagRules...),
+		validation.Field(&request.Notes, validation.RuneLength(0, 2000)),
+		validation.Field(&request.CustomFields, validation.By(validateContactCustomFields)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateUpdateContact(ctx context.Context, request domainContact.UpdateContactRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.Name, validation.NilOrNotEmpty.Error("cannot be blank, leave it out to keep the name"), validation.RuneLength(0, 100)),
+		validation.Field(&request.Tags, validation.By(func(any) error {
+			if request.Tags == nil {
+				return nil
+			}
+			return validation.Validate(*request.Tags, contactTagRules...)
+		})),
+		validation.Field(&request.Notes, validation.RuneLength(0, 2000)),
+		validation.Field(&request.CustomFields, validation.By(validateContactCustomFields)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateGetContact(ctx context.Context, request domainContact.GetContactRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateListContacts(ctx context.Context, request *domainContact.ListContactsRequest) error {
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Search, validation.RuneLength(0, 100)),
+		validation.Field(&request.Tag, validation.RuneLength(0, 50)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(500)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateImportContacts(ctx context.Context, request domainContact.ImportContactsRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.File, validation.NotNil),
+		validation.Field(&request.Tags, contactTagRules...),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if !isCSVFile(request.File.Filename, request.File.Header.Get("Content-Type")) && !isVCardFile(request.File.Filename, request.File.Header.Get("Content-Type")) {
+		return pkgError.ValidationError("file: must be a CSV or vCard file.")
+	}
+
+	return nil
+}
+
+func ValidateExportContacts(ctx context.Context, request *domainContact.ExportContactsRequest) error {
+	if request.Format == "" {
+		request.Format = domainContact.ExportFormatCSV
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Format, validation.In(domainContact.ExportFormatCSV, domainContact.ExportFormatVCard)),
+		validation.Field(&request.Tag, validation.RuneLength(0, 50)),
+		validation.Field(&request.Search, validation.RuneLength(0, 100)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func validateContactCustomFields(value any) error {
+	fields, _ := value.(map[string]string)
+	if len(fields) > maxContactCustomFields {
+		return fmt.Errorf("must contain at most %d fields", maxContactCustomFields)
+	}
+
+	for key, field := range fields {
+		if strings.TrimSpace(key) == "" || len([]rune(key)) > 50 {
+			return fmt.Errorf("field names must be between 1 and 50 characters")
+		}
+		if len([]rune(field)) > 500 {
+			return fmt.Errorf("%s: the length must be no more than 500", key)
+		}
+	}
+
+	return nil
+}
+
+// isVCardFile accepts files with a .vcf or .vcard extension or a vCard content type
+func isVCardFile(filename, contentType string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".vcf", ".vcard":
+		return true
+	}
+
+	switch strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0])) {
+	case "text/vcard", "text/x-vcard", "text/directory":
+		return true
+	}
+	return false
+}
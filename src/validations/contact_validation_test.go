@@ -0,0 +1,169 @@
+package validations
+
+import (
+	"context"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateContact(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainContact.ContactRequest
+		err     any
+	}{
+		{
+			name: "should success with all fields",
+			request: domainContact.ContactRequest{
+				Phone:        "6281234567890@s.whatsapp.net",
+				Name:         "Budi (Supplier)",
+				Tags:         []string{"suppliers", "jakarta_2025"},
+				Notes:        "Prefers calls in the morning",
+				CustomFields: map[string]string{"company": "PT Maju"},
+			},
+			err: nil,
+		},
+		{
+			name:    "should success with phone only",
+			request: domainContact.ContactRequest{Phone: "6281234567890@s.whatsapp.net"},
+			err:     nil,
+		},
+		{
+			name:    "should error with empty phone",
+			request: domainContact.ContactRequest{Name: "Budi"},
+			err:     pkgError.ValidationError("phone: cannot be blank."),
+		},
+		{
+			name:    "should error with long name",
+			request: domainContact.ContactRequest{Phone: "6281234567890@s.whatsapp.net", Name: strings.Repeat("a", 101)},
+			err:     pkgError.ValidationError("name: the length must be no more than 100."),
+		},
+		{
+			name:    "should error with tag containing spaces",
+			request: domainContact.ContactRequest{Phone: "6281234567890@s.whatsapp.net", Tags: []string{"vip", "new customer"}},
+			err:     pkgError.ValidationError("tags: (1: must contain only letters, digits, dashes and underscores.)."),
+		},
+		{
+			name:    "should error with empty tag",
+			request: domainContact.ContactRequest{Phone: "6281234567890@s.whatsapp.net", Tags: []string{""}},
+			err:     pkgError.ValidationError("tags: (0: cannot be blank.)."),
+		},
+		{
+			name:    "should error with long custom field",
+			request: domainContact.ContactRequest{Phone: "6281234567890@s.whatsapp.net", CustomFields: map[string]string{"address": strings.Repeat("a", 501)}},
+			err:     pkgError.ValidationError("custom_fields: address: the length must be no more than 500."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateContact(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateUpdateContact(t *testing.T) {
+	name := "Budi"
+	empty := ""
+	tags := []string{"vip"}
+	badTags := []string{"v i p"}
+
+	tests := []struct {
+		name    string
+		request domainContact.UpdateContactRequest
+		err     any
+	}{
+		{
+			name:    "should success with name and tags",
+			request: domainContact.UpdateContactRequest{Phone: "6281234567890@s.whatsapp.net", Name: &name, Tags: &tags},
+			err:     nil,
+		},
+		{
+			name:    "should success with nothing to change",
+			request: domainContact.UpdateContactRequest{Phone: "6281234567890@s.whatsapp.net"},
+			err:     nil,
+		},
+		{
+			name:    "should error with blank name",
+			request: domainContact.UpdateContactRequest{Phone: "6281234567890@s.whatsapp.net", Name: &empty},
+			err:     pkgError.ValidationError("name: cannot be blank, leave it out to keep the name."),
+		},
+		{
+			name:    "should error with invalid tag",
+			request: domainContact.UpdateContactRequest{Phone: "6281234567890@s.whatsapp.net", Tags: &badTags},
+			err:     pkgError.ValidationError("tags: (0: must contain only letters, digits, dashes and underscores.)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpdateContact(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateListContacts(t *testing.T) {
+	request := domainContact.ListContactsRequest{}
+	assert.NoError(t, ValidateListContacts(context.Background(), &request))
+	assert.Equal(t, 50, request.Limit)
+
+	request = domainContact.ListContactsRequest{Limit: 501}
+	assert.Equal(t, pkgError.ValidationError("limit: must be no greater than 500."), ValidateListContacts(context.Background(), &request))
+}
+
+func TestValidateImportContacts(t *testing.T) {
+	file := func(name, contentType string) *multipart.FileHeader {
+		return &multipart.FileHeader{Filename: name, Header: textproto.MIMEHeader{"Content-Type": {contentType}}}
+	}
+
+	tests := []struct {
+		name    string
+		request domainContact.ImportContactsRequest
+		err     any
+	}{
+		{
+			name:    "should success with csv file",
+			request: domainContact.ImportContactsRequest{File: file("contacts.csv", "text/csv")},
+			err:     nil,
+		},
+		{
+			name:    "should success with vcard file",
+			request: domainContact.ImportContactsRequest{File: file("contacts.vcf", "application/octet-stream"), Tags: []string{"imported"}},
+			err:     nil,
+		},
+		{
+			name:    "should error without file",
+			request: domainContact.ImportContactsRequest{},
+			err:     pkgError.ValidationError("file: is required."),
+		},
+		{
+			name:    "should error with other file",
+			request: domainContact.ImportContactsRequest{File: file("contacts.xlsx", "application/octet-stream")},
+			err:     pkgError.ValidationError("file: must be a CSV or vCard file."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateImportContacts(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateExportContacts(t *testing.T) {
+	request := domainContact.ExportContactsRequest{}
+	assert.NoError(t, ValidateExportContacts(context.Background(), &request))
+	assert.Equal(t, domainContact.ExportFormatCSV, request.Format)
+
+	request = domainContact.ExportContactsRequest{Format: "xlsx"}
+	assert.Equal(t, pkgError.ValidationError("format: must be a valid value."), ValidateExportContacts(context.Background(), &request))
+}
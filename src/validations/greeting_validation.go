@@ -0,0 +1,51 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainGreeting "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/greeting"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/dustin/go-humanize"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const defaultGreetingBatchSeconds = 30
+
+func ValidateSetGreeting(ctx context.Context, request *domainGreeting.SetGreetingRequest) error {
+	if request.BatchSeconds == nil {
+		batch := defaultGreetingBatchSeconds
+		request.BatchSeconds = &batch
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.GroupID, validation.Required),
+		validation.Field(&request.WelcomeTemplate, validation.When(request.WelcomeEnabled, validation.Required), validation.RuneLength(0, 4096)),
+		validation.Field(&request.GoodbyeTemplate, validation.When(request.GoodbyeEnabled, validation.Required), validation.RuneLength(0, 4096)),
+		validation.Field(&request.DMTemplate, validation.When(request.DMEnabled, validation.Required), validation.RuneLength(0, 4096)),
+		validation.Field(&request.BatchSeconds, validation.Min(0), validation.Max(3600)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if request.RulesDocument != nil && request.RulesDocument.Size > config.WhatsappSettingMaxFileSize {
+		return pkgError.ValidationError(fmt.Sprintf("rules_document: max file upload is %s.", humanize.Bytes(uint64(config.WhatsappSettingMaxFileSize))))
+	}
+
+	return nil
+}
+
+func ValidateDeleteGreeting(ctx context.Context, request domainGreeting.DeleteGreetingRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.GroupID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package validations
+
+import (
+	"context"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainGreeting "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/greeting"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/dustin/go-humanize"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSetGreeting(t *testing.T) {
+	batch := func(seconds int) *int { return &seconds }
+
+	tests := []struct {
+		name    string
+		request domainGreeting.SetGreetingRequest
+		err     any
+	}{
+		{
+			name:    "should success with welcome message",
+			request: domainGreeting.SetGreetingRequest{GroupID: "123456789@g.us", WelcomeEnabled: true, WelcomeTemplate: "Welcome {user} to {group}"},
+			err:     nil,
+		},
+		{
+			name:    "should success with everything disabled",
+			request: domainGreeting.SetGreetingRequest{GroupID: "123456789@g.us", BatchSeconds: batch(0)},
+			err:     nil,
+		},
+		{
+			name:    "should error with empty group id",
+			request: domainGreeting.SetGreetingRequest{WelcomeEnabled: true, WelcomeTemplate: "Welcome {user}"},
+			err:     pkgError.ValidationError("group_id: cannot be blank."),
+		},
+		{
+			name:    "should error with enabled welcome without template",
+			request: domainGreeting.SetGreetingRequest{GroupID: "123456789@g.us", WelcomeEnabled: true},
+			err:     pkgError.ValidationError("welcome_template: cannot be blank."),
+		},
+		{
+			name:    "should error with enabled goodbye without template",
+			request: domainGreeting.SetGreetingRequest{GroupID: "123456789@g.us", GoodbyeEnabled: true},
+			err:     pkgError.ValidationError("goodbye_template: cannot be blank."),
+		},
+		{
+			name:    "should error with enabled dm without template",
+			request: domainGreeting.SetGreetingRequest{GroupID: "123456789@g.us", DMEnabled: true},
+			err:     pkgError.ValidationError("dm_template: cannot be blank."),
+		},
+		{
+			name:    "should error with too long template",
+			request: domainGreeting.SetGreetingRequest{GroupID: "123456789@g.us", WelcomeTemplate: strings.Repeat("a", 4097)},
+			err:     pkgError.ValidationError("welcome_template: the length must be no more than 4096."),
+		},
+		{
+			name:    "should error with too long batch window",
+			request: domainGreeting.SetGreetingRequest{GroupID: "123456789@g.us", BatchSeconds: batch(3601)},
+			err:     pkgError.ValidationError("batch_seconds: must be no greater than 3600."),
+		},
+		{
+			name:    "should error with too large rules document",
+			request: domainGreeting.SetGreetingRequest{GroupID: "123456789@g.us", RulesDocument: &multipart.FileHeader{Filename: "rules.pdf", Size: 1 << 40}},
+			err:     pkgError.ValidationError("rules_document: max file upload is " + humanize.Bytes(uint64(config.WhatsappSettingMaxFileSize)) + "."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSetGreeting(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSetGreetingDefaultsBatch(t *testing.T) {
+	request := domainGreeting.SetGreetingRequest{GroupID: "123456789@g.us"}
+	assert.NoError(t, ValidateSetGreeting(context.Background(), &request))
+	assert.Equal(t, 30, *request.BatchSeconds)
+}
+
+func TestValidateDeleteGreeting(t *testing.T) {
+	assert.Equal(t, pkgError.ValidationError("group_id: cannot be blank."), ValidateDeleteGreeting(context.Background(), domainGreeting.DeleteGreetingRequest{}))
+	assert.NoError(t, ValidateDeleteGreeting(context.Background(), domainGreeting.DeleteGreetingRequest{GroupID: "123456789@g.us"}))
+}
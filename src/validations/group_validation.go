@@ -2,11 +2,19 @@ package validations
 
 import (
 	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 )
 
 func ValidateJoinGroupWithLink(ctx context.Context, request domainGroup.JoinGroupWithLinkRequest) error {
@@ -117,12 +125,17 @@ func ValidateSetGroupPhoto(ctx context.Context, request domainGroup.SetGroupPhot
 		validation.Field(&request.GroupID, validation.Required),
 		// Photo can be nil to remove the photo, so it's not required
 		// If photo is provided, we could add file type validation here if needed
+		validation.Field(&request.PhotoURL, is.URL),
 	)
 
 	if err != nil {
 		return pkgError.ValidationError(err.Error())
 	}
 
+	if request.Photo != nil && request.PhotoURL != "" {
+		return pkgError.ValidationError("provide either photo or photo_url, not both.")
+	}
+
 	// Optional: Add file type validation if photo is provided
 	if request.Photo != nil {
 		// Check if it's an image file based on content type or filename
@@ -205,6 +218,66 @@ func ValidateSetGroupTopic(ctx context.Context, request domainGroup.SetGroupTopi
 	return nil
 }
 
+func ValidateSetGroupMemberAddMode(ctx context.Context, request domainGroup.SetGroupMemberAddModeRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.GroupID, validation.Required),
+		validation.Field(&request.Mode, validation.Required, validation.In(types.GroupMemberAddModeAdmin, types.GroupMemberAddModeAllMember)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateSetGroupJoinApproval(ctx context.Context, request domainGroup.SetGroupJoinApprovalRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.GroupID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+// disappearingTimers are the only timers, in seconds, WhatsApp accepts for groups and as the default for new chats
+var disappearingTimers = []any{
+	0,
+	int(whatsmeow.DisappearingTimer24Hours.Seconds()),
+	int(whatsmeow.DisappearingTimer7Days.Seconds()),
+	int(whatsmeow.DisappearingTimer90Days.Seconds()),
+}
+
+func ValidateSetGroupDisappearing(ctx context.Context, request domainGroup.SetGroupDisappearingRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.GroupID, validation.Required),
+		validation.Field(&request.Timer, validation.In(disappearingTimers...).Error("must be one of 0, 86400, 604800 or 7776000")),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateSetGroupDescription(ctx context.Context, request domainGroup.SetGroupDescriptionRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.GroupID, validation.Required),
+		// Description can be empty to remove it
+		validation.Field(&request.Description, validation.RuneLength(0, 2048)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
 func ValidateGroupInfo(ctx context.Context, request domainGroup.GroupInfoRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.GroupID, validation.Required),
@@ -228,3 +301,243 @@ func ValidateGetGroupInviteLink(ctx context.Context, request domainGroup.GetGrou
 
 	return nil
 }
+
+func ValidateCreateCommunity(ctx context.Context, request domainGroup.CreateCommunityRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&request.Description, validation.Length(0, 2048)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateLinkGroup(ctx context.Context, request domainGroup.LinkGroupRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.CommunityID, validation.Required),
+		validation.Field(&request.GroupID, validation.Required, validation.NotIn(request.CommunityID).Error("must be different from community_id")),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateGetSubGroups(ctx context.Context, request domainGroup.GetSubGroupsRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.CommunityID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateSendCommunityAnnouncement(ctx context.Context, request domainGroup.SendCommunityAnnouncementRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.CommunityID, validation.Required),
+		validation.Field(&request.Message, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+var groupEventTypes = []string{
+	domainChatStorage.GroupEventJoin,
+	domainChatStorage.GroupEventAdd,
+	domainChatStorage.GroupEventLeave,
+	domainChatStorage.GroupEventRemove,
+	domainChatStorage.GroupEventPromote,
+	domainChatStorage.GroupEventDemote,
+	domainChatStorage.GroupEventName,
+	domainChatStorage.GroupEventTopic,
+	domainChatStorage.GroupEventLocked,
+	domainChatStorage.GroupEventAnnounce,
+	domainChatStorage.GroupEventEphemeral,
+	domainChatStorage.GroupEventMembershipApproval,
+	domainChatStorage.GroupEventInviteLink,
+	domainChatStorage.GroupEventLink,
+	domainChatStorage.GroupEventUnlink,
+	domainChatStorage.GroupEventDelete,
+}
+
+// validateGroupEventTypes checks a comma separated list of group event types
+func validateGroupEventTypes(value any) error {
+	list, _ := value.(string)
+	for _, eventType := range strings.Split(list, ",") {
+		eventType = strings.TrimSpace(eventType)
+		if eventType == "" {
+			continue
+		}
+		if !slices.Contains(groupEventTypes, eventType) {
+			return errors.New("must be a comma separated list of: " + strings.Join(groupEventTypes, ", "))
+		}
+	}
+	return nil
+}
+
+func ValidateGetGroupHistory(ctx context.Context, request *domainGroup.GetGroupHistoryRequest) error {
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.GroupID, validation.Required),
+		validation.Field(&request.EventType, validation.By(validateGroupEventTypes)),
+		validation.Field(&request.StartTime, validation.Date(time.RFC3339)),
+		validation.Field(&request.EndTime, validation.Date(time.RFC3339)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(500)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateGetGroupMembership(ctx context.Context, request domainGroup.GetGroupMembershipRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.GroupID, validation.Required),
+		validation.Field(&request.StartTime, validation.Date(time.RFC3339)),
+		validation.Field(&request.EndTime, validation.Date(time.RFC3339)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateImportParticipants(ctx context.Context, request *domainGroup.ImportParticipantsRequest) error {
+	if request.DelaySeconds == 0 {
+		request.DelaySeconds = 5
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.GroupID, validation.Required),
+		validation.Field(&request.File, validation.NotNil),
+		validation.Field(&request.DelaySeconds, validation.Min(1), validation.Max(300)),
+		validation.Field(&request.InviteCaption, validation.Length(0, 1024)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if !isCSVFile(request.File.Filename, request.File.Header.Get("Content-Type")) {
+		return pkgError.ValidationError("file: must be a CSV file.")
+	}
+
+	return nil
+}
+
+// isCSVFile accepts files with a .csv extension or a CSV/plain text content type
+func isCSVFile(filename, contentType string) bool {
+	if strings.EqualFold(filepath.Ext(filename), ".csv") {
+		return true
+	}
+
+	switch strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0])) {
+	case "text/csv", "application/csv", "text/plain", "application/vnd.ms-excel":
+		return true
+	}
+	return false
+}
+
+func ValidateGetImportReport(ctx context.Context, request domainGroup.GetImportReportRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.ImportID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateExportGroupSnapshot(ctx context.Context, request domainGroup.ExportGroupSnapshotRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.GroupID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateCloneGroup(ctx context.Context, request domainGroup.CloneGroupRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.GroupID, validation.Required),
+		validation.Field(&request.Name, validation.RuneLength(0, 100)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateImportGroupSnapshot(ctx context.Context, request domainGroup.ImportGroupSnapshotRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Snapshot, validation.When(request.File == nil, validation.Required.Error("cannot be blank without a snapshot file"))),
+		validation.Field(&request.Name, validation.RuneLength(0, 100)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+// ValidateGroupSnapshot checks a snapshot before a group is created from it
+func ValidateGroupSnapshot(ctx context.Context, snapshot *domainGroup.GroupSnapshot) error {
+	err := validation.ValidateStructWithContext(ctx, snapshot,
+		validation.Field(&snapshot.Version, validation.Min(0), validation.Max(1)),
+		validation.Field(&snapshot.Name, validation.Required, validation.RuneLength(0, 100)),
+		validation.Field(&snapshot.Topic, validation.RuneLength(0, 2048)),
+		validation.Field(&snapshot.Photo, is.Base64),
+		validation.Field(&snapshot.Settings, validation.By(validateSnapshotSettings)),
+		validation.Field(&snapshot.Participants, validation.Each(validation.By(validateSnapshotParticipant))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func validateSnapshotSettings(value any) error {
+	settings, _ := value.(domainGroup.GroupSnapshotSettings)
+	return validation.ValidateStruct(&settings,
+		validation.Field(&settings.MemberAddMode, validation.In(types.GroupMemberAddModeAdmin, types.GroupMemberAddModeAllMember)),
+		validation.Field(&settings.DisappearingTimer, validation.In(disappearingTimers...).Error("must be one of 0, 86400, 604800 or 7776000")),
+	)
+}
+
+func validateSnapshotParticipant(value any) error {
+	participant, _ := value.(domainGroup.GroupSnapshotParticipant)
+	return validation.ValidateStruct(&participant,
+		validation.Field(&participant.JID, validation.Required),
+		validation.Field(&participant.Role, validation.In("member", "admin", "super_admin")),
+	)
+}
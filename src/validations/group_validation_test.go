@@ -2,12 +2,16 @@ package validations
 
 import (
 	"context"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
 	"testing"
 
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/stretchr/testify/assert"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 )
 
 func TestValidateJoinGroupWithLink(t *testing.T) {
@@ -407,6 +411,31 @@ func TestValidateSetGroupPhoto(t *testing.T) {
 			}},
 			err: pkgError.ValidationError("group_id: cannot be blank."),
 		},
+		{
+			name: "should success with photo url",
+			args: args{request: domainGroup.SetGroupPhotoRequest{
+				GroupID:  "123456789@g.us",
+				PhotoURL: "https://example.com/photo.jpg",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with invalid photo url",
+			args: args{request: domainGroup.SetGroupPhotoRequest{
+				GroupID:  "123456789@g.us",
+				PhotoURL: "not a url",
+			}},
+			err: pkgError.ValidationError("photo_url: must be a valid URL."),
+		},
+		{
+			name: "should error with both photo and photo url",
+			args: args{request: domainGroup.SetGroupPhotoRequest{
+				GroupID:  "123456789@g.us",
+				Photo:    &multipart.FileHeader{Filename: "photo.jpg"},
+				PhotoURL: "https://example.com/photo.jpg",
+			}},
+			err: pkgError.ValidationError("provide either photo or photo_url, not both."),
+		},
 	}
 
 	for _, tt := range tests {
@@ -706,3 +735,509 @@ func TestValidateGroupInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCreateCommunity(t *testing.T) {
+	type args struct {
+		request domainGroup.CreateCommunityRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with name and description",
+			args: args{request: domainGroup.CreateCommunityRequest{
+				Name:        "Neighbourhood",
+				Description: "Everything happening around the block",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success without description",
+			args: args{request: domainGroup.CreateCommunityRequest{
+				Name: "Neighbourhood",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty name",
+			args: args{request: domainGroup.CreateCommunityRequest{
+				Name: "",
+			}},
+			err: pkgError.ValidationError("name: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateCommunity(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateLinkGroup(t *testing.T) {
+	type args struct {
+		request domainGroup.LinkGroupRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with community and group id",
+			args: args{request: domainGroup.LinkGroupRequest{
+				CommunityID: "120363000000000001@g.us",
+				GroupID:     "120363000000000002@g.us",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty community id",
+			args: args{request: domainGroup.LinkGroupRequest{
+				GroupID: "120363000000000002@g.us",
+			}},
+			err: pkgError.ValidationError("community_id: cannot be blank."),
+		},
+		{
+			name: "should error with empty group id",
+			args: args{request: domainGroup.LinkGroupRequest{
+				CommunityID: "120363000000000001@g.us",
+			}},
+			err: pkgError.ValidationError("group_id: cannot be blank."),
+		},
+		{
+			name: "should error when linking a community to itself",
+			args: args{request: domainGroup.LinkGroupRequest{
+				CommunityID: "120363000000000001@g.us",
+				GroupID:     "120363000000000001@g.us",
+			}},
+			err: pkgError.ValidationError("group_id: must be different from community_id."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLinkGroup(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateGetSubGroups(t *testing.T) {
+	type args struct {
+		request domainGroup.GetSubGroupsRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with community id",
+			args: args{request: domainGroup.GetSubGroupsRequest{
+				CommunityID: "120363000000000001@g.us",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty community id",
+			args: args{request: domainGroup.GetSubGroupsRequest{}},
+			err:  pkgError.ValidationError("community_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGetSubGroups(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSendCommunityAnnouncement(t *testing.T) {
+	type args struct {
+		request domainGroup.SendCommunityAnnouncementRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with community id and message",
+			args: args{request: domainGroup.SendCommunityAnnouncementRequest{
+				CommunityID: "120363000000000001@g.us",
+				Message:     "Meeting moved to Friday",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty message",
+			args: args{request: domainGroup.SendCommunityAnnouncementRequest{
+				CommunityID: "120363000000000001@g.us",
+			}},
+			err: pkgError.ValidationError("message: cannot be blank."),
+		},
+		{
+			name: "should error with empty community id",
+			args: args{request: domainGroup.SendCommunityAnnouncementRequest{
+				Message: "Meeting moved to Friday",
+			}},
+			err: pkgError.ValidationError("community_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendCommunityAnnouncement(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateGetGroupHistory(t *testing.T) {
+	validStart := "2024-05-01T00:00:00Z"
+	invalidStart := "yesterday"
+
+	tests := []struct {
+		name    string
+		request domainGroup.GetGroupHistoryRequest
+		err     any
+	}{
+		{
+			name:    "should success with group id only",
+			request: domainGroup.GetGroupHistoryRequest{GroupID: "120363025246125486@g.us"},
+			err:     nil,
+		},
+		{
+			name: "should success with filters",
+			request: domainGroup.GetGroupHistoryRequest{
+				GroupID:     "120363025246125486@g.us",
+				Participant: "628111111111@s.whatsapp.net",
+				EventType:   "remove, leave",
+				StartTime:   &validStart,
+				Limit:       100,
+			},
+			err: nil,
+		},
+		{
+			name:    "should error with empty group id",
+			request: domainGroup.GetGroupHistoryRequest{},
+			err:     pkgError.ValidationError("group_id: cannot be blank."),
+		},
+		{
+			name:    "should error with unknown event type",
+			request: domainGroup.GetGroupHistoryRequest{GroupID: "120363025246125486@g.us", EventType: "remove,kick"},
+			err:     pkgError.ValidationError("event_type: must be a comma separated list of: join, add, leave, remove, promote, demote, name, topic, locked, announce, ephemeral, membership_approval, invite_link, link, unlink, delete."),
+		},
+		{
+			name:    "should error with invalid start time",
+			request: domainGroup.GetGroupHistoryRequest{GroupID: "120363025246125486@g.us", StartTime: &invalidStart},
+			err:     pkgError.ValidationError("start_time: must be a valid date."),
+		},
+		{
+			name:    "should error with limit above maximum",
+			request: domainGroup.GetGroupHistoryRequest{GroupID: "120363025246125486@g.us", Limit: 501},
+			err:     pkgError.ValidationError("limit: must be no greater than 500."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGetGroupHistory(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateGetGroupHistoryDefaults(t *testing.T) {
+	request := domainGroup.GetGroupHistoryRequest{GroupID: "120363025246125486@g.us"}
+	assert.NoError(t, ValidateGetGroupHistory(context.Background(), &request))
+	assert.Equal(t, 50, request.Limit)
+}
+
+func TestValidateGetGroupMembership(t *testing.T) {
+	invalidEnd := "2024-13-01"
+
+	assert.NoError(t, ValidateGetGroupMembership(context.Background(), domainGroup.GetGroupMembershipRequest{
+		GroupID: "120363025246125486@g.us",
+	}))
+	assert.Equal(t, pkgError.ValidationError("group_id: cannot be blank."),
+		ValidateGetGroupMembership(context.Background(), domainGroup.GetGroupMembershipRequest{}))
+	assert.Equal(t, pkgError.ValidationError("end_time: must be a valid date."),
+		ValidateGetGroupMembership(context.Background(), domainGroup.GetGroupMembershipRequest{
+			GroupID: "120363025246125486@g.us",
+			EndTime: &invalidEnd,
+		}))
+}
+
+func TestValidateImportParticipants(t *testing.T) {
+	csvFile := func(name, contentType string) *multipart.FileHeader {
+		return &multipart.FileHeader{Filename: name, Header: textproto.MIMEHeader{"Content-Type": {contentType}}}
+	}
+
+	tests := []struct {
+		name    string
+		request domainGroup.ImportParticipantsRequest
+		err     any
+	}{
+		{
+			name:    "should success with csv file",
+			request: domainGroup.ImportParticipantsRequest{GroupID: "120363025246125486@g.us", File: csvFile("members.csv", "")},
+			err:     nil,
+		},
+		{
+			name:    "should success with csv content type",
+			request: domainGroup.ImportParticipantsRequest{GroupID: "120363025246125486@g.us", File: csvFile("members", "text/csv; charset=utf-8")},
+			err:     nil,
+		},
+		{
+			name:    "should error without file",
+			request: domainGroup.ImportParticipantsRequest{GroupID: "120363025246125486@g.us"},
+			err:     pkgError.ValidationError("file: is required."),
+		},
+		{
+			name:    "should error with non csv file",
+			request: domainGroup.ImportParticipantsRequest{GroupID: "120363025246125486@g.us", File: csvFile("members.png", "image/png")},
+			err:     pkgError.ValidationError("file: must be a CSV file."),
+		},
+		{
+			name:    "should error with delay above maximum",
+			request: domainGroup.ImportParticipantsRequest{GroupID: "120363025246125486@g.us", File: csvFile("members.csv", ""), DelaySeconds: 301},
+			err:     pkgError.ValidationError("delay_seconds: must be no greater than 300."),
+		},
+		{
+			name:    "should error with empty group id",
+			request: domainGroup.ImportParticipantsRequest{File: csvFile("members.csv", "")},
+			err:     pkgError.ValidationError("group_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateImportParticipants(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateImportParticipantsDefaultDelay(t *testing.T) {
+	request := domainGroup.ImportParticipantsRequest{
+		GroupID: "120363025246125486@g.us",
+		File:    &multipart.FileHeader{Filename: "members.csv"},
+	}
+	assert.NoError(t, ValidateImportParticipants(context.Background(), &request))
+	assert.Equal(t, 5, request.DelaySeconds)
+}
+
+func TestValidateGetImportReport(t *testing.T) {
+	assert.NoError(t, ValidateGetImportReport(context.Background(), domainGroup.GetImportReportRequest{ImportID: "abc"}))
+	assert.Equal(t, pkgError.ValidationError("import_id: cannot be blank."),
+		ValidateGetImportReport(context.Background(), domainGroup.GetImportReportRequest{}))
+}
+
+func TestValidateSetGroupMemberAddMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainGroup.SetGroupMemberAddModeRequest
+		err     any
+	}{
+		{
+			name:    "should success with admin add",
+			request: domainGroup.SetGroupMemberAddModeRequest{GroupID: "123456789@g.us", Mode: types.GroupMemberAddModeAdmin},
+			err:     nil,
+		},
+		{
+			name:    "should success with all member add",
+			request: domainGroup.SetGroupMemberAddModeRequest{GroupID: "123456789@g.us", Mode: types.GroupMemberAddModeAllMember},
+			err:     nil,
+		},
+		{
+			name:    "should error with empty mode",
+			request: domainGroup.SetGroupMemberAddModeRequest{GroupID: "123456789@g.us"},
+			err:     pkgError.ValidationError("mode: cannot be blank."),
+		},
+		{
+			name:    "should error with unknown mode",
+			request: domainGroup.SetGroupMemberAddModeRequest{GroupID: "123456789@g.us", Mode: "everyone"},
+			err:     pkgError.ValidationError("mode: must be a valid value."),
+		},
+		{
+			name:    "should error with empty group id",
+			request: domainGroup.SetGroupMemberAddModeRequest{Mode: types.GroupMemberAddModeAdmin},
+			err:     pkgError.ValidationError("group_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSetGroupMemberAddMode(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSetGroupJoinApproval(t *testing.T) {
+	assert.NoError(t, ValidateSetGroupJoinApproval(context.Background(), domainGroup.SetGroupJoinApprovalRequest{GroupID: "123456789@g.us", Enabled: true}))
+	assert.Equal(t, pkgError.ValidationError("group_id: cannot be blank."),
+		ValidateSetGroupJoinApproval(context.Background(), domainGroup.SetGroupJoinApprovalRequest{Enabled: true}))
+}
+
+func TestValidateSetGroupDisappearing(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainGroup.SetGroupDisappearingRequest
+		err     any
+	}{
+		{
+			name:    "should success turning the timer off",
+			request: domainGroup.SetGroupDisappearingRequest{GroupID: "123456789@g.us", Timer: 0},
+			err:     nil,
+		},
+		{
+			name:    "should success with 24 hours",
+			request: domainGroup.SetGroupDisappearingRequest{GroupID: "123456789@g.us", Timer: 86400},
+			err:     nil,
+		},
+		{
+			name:    "should success with 7 days",
+			request: domainGroup.SetGroupDisappearingRequest{GroupID: "123456789@g.us", Timer: 604800},
+			err:     nil,
+		},
+		{
+			name:    "should success with 90 days",
+			request: domainGroup.SetGroupDisappearingRequest{GroupID: "123456789@g.us", Timer: 7776000},
+			err:     nil,
+		},
+		{
+			name:    "should error with unsupported timer",
+			request: domainGroup.SetGroupDisappearingRequest{GroupID: "123456789@g.us", Timer: 3600},
+			err:     pkgError.ValidationError("timer: must be one of 0, 86400, 604800 or 7776000."),
+		},
+		{
+			name:    "should error with empty group id",
+			request: domainGroup.SetGroupDisappearingRequest{Timer: 86400},
+			err:     pkgError.ValidationError("group_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSetGroupDisappearing(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSetGroupDescription(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainGroup.SetGroupDescriptionRequest
+		err     any
+	}{
+		{
+			name:    "should success with description and previous id",
+			request: domainGroup.SetGroupDescriptionRequest{GroupID: "123456789@g.us", Description: "Rules: be nice", PreviousID: "3EB0ABC"},
+			err:     nil,
+		},
+		{
+			name:    "should success with empty description",
+			request: domainGroup.SetGroupDescriptionRequest{GroupID: "123456789@g.us"},
+			err:     nil,
+		},
+		{
+			name:    "should error with too long description",
+			request: domainGroup.SetGroupDescriptionRequest{GroupID: "123456789@g.us", Description: strings.Repeat("a", 2049)},
+			err:     pkgError.ValidationError("description: the length must be no more than 2048."),
+		},
+		{
+			name:    "should error with empty group id",
+			request: domainGroup.SetGroupDescriptionRequest{Description: "Rules"},
+			err:     pkgError.ValidationError("group_id: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSetGroupDescription(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateCloneGroup(t *testing.T) {
+	assert.NoError(t, ValidateCloneGroup(context.Background(), domainGroup.CloneGroupRequest{GroupID: "123456789@g.us", Name: "{name} {date}"}))
+	assert.Equal(t, pkgError.ValidationError("group_id: cannot be blank."), ValidateCloneGroup(context.Background(), domainGroup.CloneGroupRequest{}))
+	assert.Equal(t, pkgError.ValidationError("name: the length must be no more than 100."),
+		ValidateCloneGroup(context.Background(), domainGroup.CloneGroupRequest{GroupID: "123456789@g.us", Name: strings.Repeat("a", 101)}))
+}
+
+func TestValidateImportGroupSnapshot(t *testing.T) {
+	assert.NoError(t, ValidateImportGroupSnapshot(context.Background(), domainGroup.ImportGroupSnapshotRequest{Snapshot: &domainGroup.GroupSnapshot{Name: "Cohort"}}))
+	assert.NoError(t, ValidateImportGroupSnapshot(context.Background(), domainGroup.ImportGroupSnapshotRequest{File: &multipart.FileHeader{Filename: "snapshot.json"}}))
+	assert.Equal(t, pkgError.ValidationError("snapshot: cannot be blank without a snapshot file."),
+		ValidateImportGroupSnapshot(context.Background(), domainGroup.ImportGroupSnapshotRequest{}))
+}
+
+func TestValidateGroupSnapshot(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot domainGroup.GroupSnapshot
+		err      any
+	}{
+		{
+			name: "should success with full snapshot",
+			snapshot: domainGroup.GroupSnapshot{
+				Version:  1,
+				Name:     "Cohort",
+				Photo:    "aGVsbG8=",
+				Settings: domainGroup.GroupSnapshotSettings{Announce: true, MemberAddMode: types.GroupMemberAddModeAdmin, DisappearingTimer: 86400},
+				Participants: []domainGroup.GroupSnapshotParticipant{
+					{JID: "6281234567890@s.whatsapp.net", Role: "admin"},
+					{JID: "6289876543210", Role: "member"},
+				},
+			},
+			err: nil,
+		},
+		{
+			name:     "should error without name",
+			snapshot: domainGroup.GroupSnapshot{Version: 1},
+			err:      pkgError.ValidationError("name: cannot be blank."),
+		},
+		{
+			name:     "should error with newer version",
+			snapshot: domainGroup.GroupSnapshot{Version: 2, Name: "Cohort"},
+			err:      pkgError.ValidationError("version: must be no greater than 1."),
+		},
+		{
+			name:     "should error with invalid photo",
+			snapshot: domainGroup.GroupSnapshot{Name: "Cohort", Photo: "not base64!"},
+			err:      pkgError.ValidationError("photo: must be encoded in Base64."),
+		},
+		{
+			name:     "should error with invalid disappearing timer",
+			snapshot: domainGroup.GroupSnapshot{Name: "Cohort", Settings: domainGroup.GroupSnapshotSettings{DisappearingTimer: 60}},
+			err:      pkgError.ValidationError("settings: (disappearing_timer: must be one of 0, 86400, 604800 or 7776000.)."),
+		},
+		{
+			name:     "should error with unknown role",
+			snapshot: domainGroup.GroupSnapshot{Name: "Cohort", Participants: []domainGroup.GroupSnapshotParticipant{{JID: "6281234567890", Role: "owner"}}},
+			err:      pkgError.ValidationError("participants: (0: (role: must be a valid value.).)."),
+		},
+		{
+			name:     "should error with participant without jid",
+			snapshot: domainGroup.GroupSnapshot{Name: "Cohort", Participants: []domainGroup.GroupSnapshotParticipant{{Role: "member"}}},
+			err:      pkgError.ValidationError("participants: (0: (jid: cannot be blank.).)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGroupSnapshot(context.Background(), &tt.snapshot)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package validations
+
+import (
+	"context"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainModeration "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/moderation"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+func ValidateModerationRule(ctx context.Context, request *domainModeration.RuleRequest) error {
+	if request.Enabled == nil {
+		enabled := true
+		request.Enabled = &enabled
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.GroupID, validation.Required),
+		validation.Field(&request.Type, validation.Required, validation.In(
+			domainChatStorage.ModerationRuleLinks,
+			domainChatStorage.ModerationRuleBannedWords,
+			domainChatStorage.ModerationRuleRateLimit,
+			domainChatStorage.ModerationRuleForwardedMedia,
+		)),
+		validation.Field(&request.Words,
+			validation.When(request.Type == domainChatStorage.ModerationRuleBannedWords, validation.Required),
+			validation.Each(validation.Required, validation.Length(1, 100)),
+		),
+		validation.Field(&request.Limit,
+			validation.When(request.Type == domainChatStorage.ModerationRuleRateLimit, validation.Required),
+			validation.Min(0), validation.Max(600),
+		),
+		validation.Field(&request.Actions, validation.Required, validation.Each(validation.In(
+			domainChatStorage.ModerationActionWarn,
+			domainChatStorage.ModerationActionDelete,
+			domainChatStorage.ModerationActionRemove,
+		))),
+		validation.Field(&request.RemoveAfter, validation.Min(0), validation.Max(100)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateUpdateModerationRule(ctx context.Context, request *domainModeration.RuleRequest) error {
+	if err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.RuleID, validation.Required),
+	); err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return ValidateModerationRule(ctx, request)
+}
+
+func ValidateDeleteModerationRule(ctx context.Context, request domainModeration.DeleteRuleRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.RuleID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateGetModerationAudit(ctx context.Context, request *domainModeration.GetAuditRequest) error {
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.RuleID, validation.Min(int64(0))),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(500)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainModeration "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/moderation"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateModerationRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainModeration.RuleRequest
+		err     any
+	}{
+		{
+			name:    "should success with link rule",
+			request: domainModeration.RuleRequest{GroupID: "123456789@g.us", Type: "links", Actions: []string{"delete", "warn"}},
+			err:     nil,
+		},
+		{
+			name:    "should success with banned words rule",
+			request: domainModeration.RuleRequest{GroupID: "123456789@g.us", Type: "banned_words", Words: []string{"spam"}, Actions: []string{"delete"}, RemoveAfter: 3},
+			err:     nil,
+		},
+		{
+			name:    "should success with rate limit rule",
+			request: domainModeration.RuleRequest{GroupID: "123456789@g.us", Type: "rate_limit", Limit: 10, Actions: []string{"warn"}},
+			err:     nil,
+		},
+		{
+			name:    "should error with empty group id",
+			request: domainModeration.RuleRequest{Type: "links", Actions: []string{"delete"}},
+			err:     pkgError.ValidationError("group_id: cannot be blank."),
+		},
+		{
+			name:    "should error with unknown type",
+			request: domainModeration.RuleRequest{GroupID: "123456789@g.us", Type: "stickers", Actions: []string{"delete"}},
+			err:     pkgError.ValidationError("type: must be a valid value."),
+		},
+		{
+			name:    "should error with banned words rule without words",
+			request: domainModeration.RuleRequest{GroupID: "123456789@g.us", Type: "banned_words", Actions: []string{"delete"}},
+			err:     pkgError.ValidationError("words: cannot be blank."),
+		},
+		{
+			name:    "should error with rate limit rule without limit",
+			request: domainModeration.RuleRequest{GroupID: "123456789@g.us", Type: "rate_limit", Actions: []string{"warn"}},
+			err:     pkgError.ValidationError("limit: cannot be blank."),
+		},
+		{
+			name:    "should error without actions",
+			request: domainModeration.RuleRequest{GroupID: "123456789@g.us", Type: "forwarded_media"},
+			err:     pkgError.ValidationError("actions: cannot be blank."),
+		},
+		{
+			name:    "should error with unknown action",
+			request: domainModeration.RuleRequest{GroupID: "123456789@g.us", Type: "links", Actions: []string{"ban"}},
+			err:     pkgError.ValidationError("actions: (0: must be a valid value.)."),
+		},
+		{
+			name:    "should error with negative remove after",
+			request: domainModeration.RuleRequest{GroupID: "123456789@g.us", Type: "links", Actions: []string{"warn"}, RemoveAfter: -1},
+			err:     pkgError.ValidationError("remove_after: must be no less than 0."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateModerationRule(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateModerationRuleDefaultsEnabled(t *testing.T) {
+	request := domainModeration.RuleRequest{GroupID: "123456789@g.us", Type: "links", Actions: []string{"delete"}}
+	assert.NoError(t, ValidateModerationRule(context.Background(), &request))
+	assert.True(t, *request.Enabled)
+
+	disabled := false
+	request = domainModeration.RuleRequest{GroupID: "123456789@g.us", Type: "links", Actions: []string{"delete"}, Enabled: &disabled}
+	assert.NoError(t, ValidateModerationRule(context.Background(), &request))
+	assert.False(t, *request.Enabled)
+}
+
+func TestValidateUpdateModerationRule(t *testing.T) {
+	request := domainModeration.RuleRequest{GroupID: "123456789@g.us", Type: "links", Actions: []string{"delete"}}
+	assert.Equal(t, pkgError.ValidationError("rule_id: cannot be blank."), ValidateUpdateModerationRule(context.Background(), &request))
+
+	request.RuleID = 1
+	assert.NoError(t, ValidateUpdateModerationRule(context.Background(), &request))
+}
+
+func TestValidateGetModerationAudit(t *testing.T) {
+	request := domainModeration.GetAuditRequest{GroupID: "123456789@g.us"}
+	assert.NoError(t, ValidateGetModerationAudit(context.Background(), &request))
+	assert.Equal(t, 50, request.Limit)
+
+	request = domainModeration.GetAuditRequest{Limit: 501}
+	assert.Equal(t, pkgError.ValidationError("limit: must be no greater than 500."), ValidateGetModerationAudit(context.Background(), &request))
+}
@@ -0,0 +1,41 @@
+package validations
+
+import (
+	"context"
+	"regexp"
+
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// sessionIDPattern keeps session IDs safe to use in URL paths and headers
+var sessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func ValidateAddSession(ctx context.Context, request domainSession.AddSessionRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.SessionID,
+			validation.Required,
+			validation.Length(1, 64),
+			validation.Match(sessionIDPattern).Error("must contain only letters, digits, dashes and underscores"),
+		),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateSession(ctx context.Context, request domainSession.SessionRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.SessionID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
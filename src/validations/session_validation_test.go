@@ -0,0 +1,52 @@
+package validations
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	domainSession "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/session"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAddSession(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainSession.AddSessionRequest
+		err     any
+	}{
+		{
+			name:    "should success with letters, digits, dashes and underscores",
+			request: domainSession.AddSessionRequest{SessionID: "sales-team_02"},
+			err:     nil,
+		},
+		{
+			name:    "should error with empty session id",
+			request: domainSession.AddSessionRequest{},
+			err:     pkgError.ValidationError("session_id: cannot be blank."),
+		},
+		{
+			name:    "should error with slash in session id",
+			request: domainSession.AddSessionRequest{SessionID: "sales/02"},
+			err:     pkgError.ValidationError("session_id: must contain only letters, digits, dashes and underscores."),
+		},
+		{
+			name:    "should error with long session id",
+			request: domainSession.AddSessionRequest{SessionID: strings.Repeat("a", 65)},
+			err:     pkgError.ValidationError("session_id: the length must be between 1 and 64."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAddSession(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSession(t *testing.T) {
+	assert.NoError(t, ValidateSession(context.Background(), domainSession.SessionRequest{SessionID: "default"}))
+	assert.Equal(t, pkgError.ValidationError("session_id: cannot be blank."), ValidateSession(context.Background(), domainSession.SessionRequest{}))
+}
@@ -6,6 +6,8 @@ import (
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"go.mau.fi/whatsmeow/types"
 )
 
 func ValidateUserInfo(ctx context.Context, request domainUser.InfoRequest) error {
@@ -33,6 +35,86 @@ func ValidateUserAvatar(ctx context.Context, request domainUser.AvatarRequest) e
 	return nil
 }
 
+func ValidateChangeAvatar(ctx context.Context, request domainUser.ChangeAvatarRequest) error {
+	if request.Avatar == nil && request.AvatarURL == "" {
+		return pkgError.ValidationError("avatar or avatar_url is required.")
+	}
+	if request.Avatar != nil && request.AvatarURL != "" {
+		return pkgError.ValidationError("provide either avatar or avatar_url, not both.")
+	}
+
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.AvatarURL, is.URL),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if request.Avatar != nil {
+		contentType := request.Avatar.Header.Get("Content-Type")
+		if contentType != "" && !isImageContentType(contentType) {
+			return pkgError.ValidationError("uploaded file must be an image")
+		}
+	}
+
+	return nil
+}
+
+func ValidateBulkCheck(ctx context.Context, request *domainUser.BulkCheckRequest) error {
+	if request.DelaySeconds == 0 {
+		request.DelaySeconds = 3
+	}
+
+	if len(request.Phones) == 0 && request.File == nil {
+		return pkgError.ValidationError("phones or file is required.")
+	}
+	if len(request.Phones) > 0 && request.File != nil {
+		return pkgError.ValidationError("provide either phones or file, not both.")
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.DelaySeconds, validation.Min(1), validation.Max(60)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if request.File != nil && !isCSVFile(request.File.Filename, request.File.Header.Get("Content-Type")) {
+		return pkgError.ValidationError("file: must be a CSV file.")
+	}
+
+	return nil
+}
+
+func ValidateGetBulkCheck(ctx context.Context, request domainUser.GetBulkCheckRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.JobID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+// maxAboutLength is the longest about text WhatsApp accepts
+const maxAboutLength = 139
+
+func ValidateChangeAbout(ctx context.Context, request domainUser.ChangeAboutRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.About, validation.Required, validation.RuneLength(0, maxAboutLength)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
 func ValidateBusinessProfile(ctx context.Context, request domainUser.BusinessProfileRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
@@ -44,3 +126,81 @@ func ValidateBusinessProfile(ctx context.Context, request domainUser.BusinessPro
 
 	return nil
 }
+
+func ValidateBlockContact(ctx context.Context, request domainUser.BlockContactRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+// maxPresenceSubscriptions caps the contacts subscribed to in one request
+const maxPresenceSubscriptions = 100
+
+func ValidatePresenceSubscription(ctx context.Context, request domainUser.PresenceSubscriptionRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phones,
+			validation.Required,
+			validation.Length(1, maxPresenceSubscriptions),
+			validation.Each(validation.Required),
+		),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidatePresence(ctx context.Context, request *domainUser.PresenceRequest) error {
+	if request.HistoryLimit == 0 {
+		request.HistoryLimit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.HistoryLimit, validation.Min(1), validation.Max(500)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+// privacyAudience are the values accepted by settings that choose who can see or do something
+var privacyAudience = []any{
+	string(types.PrivacySettingAll),
+	string(types.PrivacySettingContacts),
+	string(types.PrivacySettingContactBlacklist),
+	string(types.PrivacySettingNone),
+}
+
+func ValidateSetPrivacySetting(ctx context.Context, request domainUser.SetPrivacySettingRequest) error {
+	if request == (domainUser.SetPrivacySettingRequest{}) {
+		return pkgError.ValidationError("at least one privacy setting is required.")
+	}
+
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.LastSeen, validation.In(privacyAudience...)),
+		validation.Field(&request.Online, validation.In(string(types.PrivacySettingAll), string(types.PrivacySettingMatchLastSeen))),
+		validation.Field(&request.Profile, validation.In(privacyAudience...)),
+		validation.Field(&request.Status, validation.In(privacyAudience...)),
+		validation.Field(&request.GroupAdd, validation.In(privacyAudience...)),
+		validation.Field(&request.ReadReceipts, validation.In(string(types.PrivacySettingAll), string(types.PrivacySettingNone))),
+		validation.Field(&request.CallAdd, validation.In(string(types.PrivacySettingAll), string(types.PrivacySettingKnown))),
+		validation.Field(&request.DisappearingTimer, validation.In(disappearingTimers...).Error("must be one of 0, 86400, 604800 or 7776000")),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -2,10 +2,14 @@ package validations
 
 import (
 	"context"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"testing"
+
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/stretchr/testify/assert"
-	"testing"
 )
 
 func TestValidateUserAvatar(t *testing.T) {
@@ -110,3 +114,330 @@ func TestValidateBusinessProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateChangeAvatar(t *testing.T) {
+	upload := &multipart.FileHeader{Filename: "avatar.png", Header: textproto.MIMEHeader{"Content-Type": {"image/png"}}}
+
+	tests := []struct {
+		name    string
+		request domainUser.ChangeAvatarRequest
+		err     any
+	}{
+		{
+			name:    "should success with upload",
+			request: domainUser.ChangeAvatarRequest{Avatar: upload},
+			err:     nil,
+		},
+		{
+			name:    "should success with url",
+			request: domainUser.ChangeAvatarRequest{AvatarURL: "https://example.com/avatar.jpg"},
+			err:     nil,
+		},
+		{
+			name:    "should error without avatar",
+			request: domainUser.ChangeAvatarRequest{},
+			err:     pkgError.ValidationError("avatar or avatar_url is required."),
+		},
+		{
+			name:    "should error with both avatar and url",
+			request: domainUser.ChangeAvatarRequest{Avatar: upload, AvatarURL: "https://example.com/avatar.jpg"},
+			err:     pkgError.ValidationError("provide either avatar or avatar_url, not both."),
+		},
+		{
+			name:    "should error with invalid url",
+			request: domainUser.ChangeAvatarRequest{AvatarURL: "not a url"},
+			err:     pkgError.ValidationError("avatar_url: must be a valid URL."),
+		},
+		{
+			name: "should error with non image upload",
+			request: domainUser.ChangeAvatarRequest{Avatar: &multipart.FileHeader{
+				Filename: "avatar.pdf",
+				Header:   textproto.MIMEHeader{"Content-Type": {"application/pdf"}},
+			}},
+			err: pkgError.ValidationError("uploaded file must be an image"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateChangeAvatar(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateChangeAbout(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainUser.ChangeAboutRequest
+		err     any
+	}{
+		{
+			name:    "should success with about",
+			request: domainUser.ChangeAboutRequest{About: "Available"},
+			err:     nil,
+		},
+		{
+			name:    "should error with empty about",
+			request: domainUser.ChangeAboutRequest{About: ""},
+			err:     pkgError.ValidationError("about: cannot be blank."),
+		},
+		{
+			name:    "should error with too long about",
+			request: domainUser.ChangeAboutRequest{About: strings.Repeat("a", 140)},
+			err:     pkgError.ValidationError("about: the length must be no more than 139."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateChangeAbout(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateBulkCheck(t *testing.T) {
+	csvFile := &multipart.FileHeader{Filename: "numbers.csv"}
+
+	tests := []struct {
+		name      string
+		request   domainUser.BulkCheckRequest
+		err       any
+		wantDelay int
+	}{
+		{
+			name:      "should success with phones and default delay",
+			request:   domainUser.BulkCheckRequest{Phones: []string{"6289685028129"}},
+			err:       nil,
+			wantDelay: 3,
+		},
+		{
+			name:      "should success with csv file",
+			request:   domainUser.BulkCheckRequest{File: csvFile, DelaySeconds: 10},
+			err:       nil,
+			wantDelay: 10,
+		},
+		{
+			name:      "should error without phones or file",
+			request:   domainUser.BulkCheckRequest{},
+			err:       pkgError.ValidationError("phones or file is required."),
+			wantDelay: 3,
+		},
+		{
+			name:      "should error with phones and file",
+			request:   domainUser.BulkCheckRequest{Phones: []string{"6289685028129"}, File: csvFile},
+			err:       pkgError.ValidationError("provide either phones or file, not both."),
+			wantDelay: 3,
+		},
+		{
+			name:      "should error with too long delay",
+			request:   domainUser.BulkCheckRequest{Phones: []string{"6289685028129"}, DelaySeconds: 61},
+			err:       pkgError.ValidationError("delay_seconds: must be no greater than 60."),
+			wantDelay: 61,
+		},
+		{
+			name:      "should error with non csv file",
+			request:   domainUser.BulkCheckRequest{File: &multipart.FileHeader{Filename: "numbers.pdf"}},
+			err:       pkgError.ValidationError("file: must be a CSV file."),
+			wantDelay: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBulkCheck(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantDelay, tt.request.DelaySeconds)
+		})
+	}
+}
+
+func TestValidateGetBulkCheck(t *testing.T) {
+	assert.Nil(t, ValidateGetBulkCheck(context.Background(), domainUser.GetBulkCheckRequest{JobID: "job"}))
+	assert.Equal(t, pkgError.ValidationError("job_id: cannot be blank."),
+		ValidateGetBulkCheck(context.Background(), domainUser.GetBulkCheckRequest{}))
+}
+
+func TestValidateBlockContact(t *testing.T) {
+	type args struct {
+		request domainUser.BlockContactRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with valid phone",
+			args: args{request: domainUser.BlockContactRequest{
+				Phone: "6289685028129@s.whatsapp.net",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty phone",
+			args: args{request: domainUser.BlockContactRequest{
+				Phone: "",
+			}},
+			err: pkgError.ValidationError("phone: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBlockContact(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidatePresenceSubscription(t *testing.T) {
+	tooMany := make([]string, 101)
+	for i := range tooMany {
+		tooMany[i] = "6289685028129"
+	}
+
+	tests := []struct {
+		name    string
+		request domainUser.PresenceSubscriptionRequest
+		err     any
+	}{
+		{
+			name:    "should success with phones and JIDs",
+			request: domainUser.PresenceSubscriptionRequest{Phones: []string{"6289685028129", "6289685028130@s.whatsapp.net"}},
+			err:     nil,
+		},
+		{
+			name:    "should error without phones",
+			request: domainUser.PresenceSubscriptionRequest{},
+			err:     pkgError.ValidationError("phones: cannot be blank."),
+		},
+		{
+			name:    "should error with empty phone",
+			request: domainUser.PresenceSubscriptionRequest{Phones: []string{"6289685028129", ""}},
+			err:     pkgError.ValidationError("phones: (1: cannot be blank.)."),
+		},
+		{
+			name:    "should error with too many phones",
+			request: domainUser.PresenceSubscriptionRequest{Phones: tooMany},
+			err:     pkgError.ValidationError("phones: the length must be between 1 and 100."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePresenceSubscription(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidatePresence(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   domainUser.PresenceRequest
+		wantLimit int
+		err       any
+	}{
+		{
+			name:      "should default history limit",
+			request:   domainUser.PresenceRequest{Phone: "6289685028129"},
+			wantLimit: 50,
+			err:       nil,
+		},
+		{
+			name:      "should keep history limit",
+			request:   domainUser.PresenceRequest{HistoryLimit: 10},
+			wantLimit: 10,
+			err:       nil,
+		},
+		{
+			name:      "should error with history limit too large",
+			request:   domainUser.PresenceRequest{HistoryLimit: 501},
+			wantLimit: 501,
+			err:       pkgError.ValidationError("history_limit: must be no greater than 500."),
+		},
+		{
+			name:      "should error with negative history limit",
+			request:   domainUser.PresenceRequest{HistoryLimit: -1},
+			wantLimit: -1,
+			err:       pkgError.ValidationError("history_limit: must be no less than 1."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePresence(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantLimit, tt.request.HistoryLimit)
+		})
+	}
+}
+
+func TestValidateSetPrivacySetting(t *testing.T) {
+	timer := func(seconds int) *int { return &seconds }
+
+	tests := []struct {
+		name    string
+		request domainUser.SetPrivacySettingRequest
+		err     any
+	}{
+		{
+			name: "should success with full profile",
+			request: domainUser.SetPrivacySettingRequest{
+				LastSeen:          "contacts",
+				Online:            "match_last_seen",
+				Profile:           "contact_blacklist",
+				Status:            "none",
+				GroupAdd:          "contacts",
+				ReadReceipts:      "none",
+				CallAdd:           "known",
+				DisappearingTimer: timer(604800),
+			},
+			err: nil,
+		},
+		{
+			name:    "should success turning off the default timer",
+			request: domainUser.SetPrivacySettingRequest{DisappearingTimer: timer(0)},
+			err:     nil,
+		},
+		{
+			name:    "should error without settings",
+			request: domainUser.SetPrivacySettingRequest{},
+			err:     pkgError.ValidationError("at least one privacy setting is required."),
+		},
+		{
+			name:    "should error with online set to contacts",
+			request: domainUser.SetPrivacySettingRequest{Online: "contacts"},
+			err:     pkgError.ValidationError("online: must be a valid value."),
+		},
+		{
+			name:    "should error with read receipts set to contacts",
+			request: domainUser.SetPrivacySettingRequest{ReadReceipts: "contacts"},
+			err:     pkgError.ValidationError("read_receipts: must be a valid value."),
+		},
+		{
+			name:    "should error with call add set to none",
+			request: domainUser.SetPrivacySettingRequest{CallAdd: "none"},
+			err:     pkgError.ValidationError("call_add: must be a valid value."),
+		},
+		{
+			name:    "should error with last seen set to known",
+			request: domainUser.SetPrivacySettingRequest{LastSeen: "known"},
+			err:     pkgError.ValidationError("last_seen: must be a valid value."),
+		},
+		{
+			name:    "should error with unsupported timer",
+			request: domainUser.SetPrivacySettingRequest{DisappearingTimer: timer(3600)},
+			err:     pkgError.ValidationError("disappearing_timer: must be one of 0, 86400, 604800 or 7776000."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSetPrivacySetting(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
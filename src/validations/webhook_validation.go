@@ -0,0 +1,108 @@
+package validations
+
+import (
+	"context"
+	"regexp"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+var webhookURLScheme = regexp.MustCompile(`^https?://`)
+
+func ValidateWebhookDefinition(ctx context.Context, request *domainWebhook.DefinitionRequest) error {
+	if request.IncludeFromMe == nil {
+		includeFromMe := true
+		request.IncludeFromMe = &includeFromMe
+	}
+	if request.Enabled == nil {
+		enabled := true
+		request.Enabled = &enabled
+	}
+	if request.ChatType == "" {
+		request.ChatType = domainChatStorage.WebhookChatAll
+	}
+
+	eventTypes := make([]any, 0, len(domainWebhook.EventTypes))
+	for _, eventType := range domainWebhook.EventTypes {
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&request.URL, validation.Required, is.URL, validation.Match(webhookURLScheme).Error("must start with http:// or https://")),
+		validation.Field(&request.Events, validation.Each(validation.Required, validation.In(eventTypes...))),
+		validation.Field(&request.IncludeChats, validation.Each(validation.Required)),
+		validation.Field(&request.ExcludeChats, validation.Each(validation.Required)),
+		validation.Field(&request.ChatType, validation.In(
+			domainChatStorage.WebhookChatAll,
+			domainChatStorage.WebhookChatPrivate,
+			domainChatStorage.WebhookChatGroup,
+		)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateUpdateWebhookDefinition(ctx context.Context, request *domainWebhook.DefinitionRequest) error {
+	if err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.DefinitionID, validation.Required),
+	); err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return ValidateWebhookDefinition(ctx, request)
+}
+
+func ValidateDeleteWebhookDefinition(ctx context.Context, request domainWebhook.DeleteDefinitionRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.DefinitionID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateListWebhookDeliveries(ctx context.Context, request *domainWebhook.ListDeliveriesRequest) error {
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Status, validation.In(
+			domainChatStorage.WebhookDeliveryPending,
+			domainChatStorage.WebhookDeliveryDelivered,
+			domainChatStorage.WebhookDeliveryDead,
+		)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(500)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateReplayWebhookDelivery(ctx context.Context, request *domainWebhook.ReplayDeliveryRequest) error {
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.DeliveryID, validation.Required, validation.Min(int64(1))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
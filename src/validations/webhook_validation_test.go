@@ -0,0 +1,47 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateWebhookDefinition(t *testing.T) {
+	request := domainWebhook.DefinitionRequest{Name: "crm", URL: "https://crm.local/hook", Events: []string{"message"}}
+	assert.NoError(t, ValidateWebhookDefinition(context.Background(), &request))
+	assert.True(t, *request.IncludeFromMe)
+	assert.True(t, *request.Enabled)
+	assert.Equal(t, "all", request.ChatType)
+
+	request = domainWebhook.DefinitionRequest{Name: "crm", URL: "crm.local/hook"}
+	assert.Equal(t, pkgError.ValidationError("url: must start with http:// or https://."), ValidateWebhookDefinition(context.Background(), &request))
+
+	request = domainWebhook.DefinitionRequest{Name: "crm", URL: "https://crm.local/hook", Events: []string{"message", "typing"}}
+	assert.Equal(t, pkgError.ValidationError("events: (1: must be a valid value.)."), ValidateWebhookDefinition(context.Background(), &request))
+
+	request = domainWebhook.DefinitionRequest{Name: "crm", URL: "https://crm.local/hook", ChatType: "channel"}
+	assert.Equal(t, pkgError.ValidationError("chat_type: must be a valid value."), ValidateWebhookDefinition(context.Background(), &request))
+
+	request = domainWebhook.DefinitionRequest{Name: "crm", URL: "https://crm.local/hook"}
+	assert.Equal(t, pkgError.ValidationError("definition_id: cannot be blank."), ValidateUpdateWebhookDefinition(context.Background(), &request))
+}
+
+func TestValidateListWebhookDeliveries(t *testing.T) {
+	request := domainWebhook.ListDeliveriesRequest{Status: "dead"}
+	assert.NoError(t, ValidateListWebhookDeliveries(context.Background(), &request))
+	assert.Equal(t, 50, request.Limit)
+
+	request = domainWebhook.ListDeliveriesRequest{Status: "failed"}
+	assert.Equal(t, pkgError.ValidationError("status: must be a valid value."), ValidateListWebhookDeliveries(context.Background(), &request))
+
+	request = domainWebhook.ListDeliveriesRequest{Limit: 501}
+	assert.Equal(t, pkgError.ValidationError("limit: must be no greater than 500."), ValidateListWebhookDeliveries(context.Background(), &request))
+}
+
+func TestValidateReplayWebhookDelivery(t *testing.T) {
+	assert.NoError(t, ValidateReplayWebhookDelivery(context.Background(), &domainWebhook.ReplayDeliveryRequest{DeliveryID: 3}))
+	assert.Equal(t, pkgError.ValidationError("delivery_id: cannot be blank."), ValidateReplayWebhookDelivery(context.Background(), &domainWebhook.ReplayDeliveryRequest{}))
+}
@@ -1,29 +1,118 @@
+const AUDIENCE = [
+    {value: 'all', text: 'Everyone'},
+    {value: 'contacts', text: 'My contacts'},
+    {value: 'contact_blacklist', text: 'My contacts except...'},
+    {value: 'none', text: 'Nobody'},
+];
+
 export default {
     name: 'AccountPrivacy',
     data() {
         return {
-            data_privacy: null
+            loading: false,
+            data_privacy: null,
+            form: {},
+            disappearing_timer: '',
+            settings: [
+                {key: 'last_seen', label: 'Who can see my Last Seen', options: AUDIENCE},
+                {key: 'online', label: 'Who can see when I am Online', options: [
+                    {value: 'all', text: 'Everyone'},
+                    {value: 'match_last_seen', text: 'Same as Last Seen'},
+                ]},
+                {key: 'profile', label: 'Who can see my Profile Photo', options: AUDIENCE},
+                {key: 'status', label: 'Who can see my About', options: AUDIENCE},
+                {key: 'group_add', label: 'Who can add me to Groups', options: AUDIENCE},
+                {key: 'read_receipts', label: 'Read Receipts', options: [
+                    {value: 'all', text: 'On'},
+                    {value: 'none', text: 'Off'},
+                ]},
+                {key: 'call_add', label: 'Who can call me', options: [
+                    {value: 'all', text: 'Everyone'},
+                    {value: 'known', text: 'Known contacts (silence unknown callers)'},
+                ]},
+            ],
+            timers: [
+                {value: '', text: 'Leave unchanged'},
+                {value: 0, text: 'Off'},
+                {value: 86400, text: '24 hours'},
+                {value: 604800, text: '7 days'},
+                {value: 7776000, text: '90 days'},
+            ],
         }
     },
     methods: {
         async openModal() {
             try {
-                await this.submitApi();
-                $('#modalUserPrivacy').modal('show');
+                await this.fetchApi();
+                $('#modalUserPrivacy').modal({
+                    onApprove: function () {
+                        return false;
+                    }
+                }).modal('show');
                 showSuccessInfo("Privacy fetched")
             } catch (err) {
                 showErrorInfo(err)
             }
         },
-        async submitApi() {
+        async fetchApi() {
             try {
                 let response = await window.http.get(`/user/my/privacy`)
-                this.data_privacy = response.data.results;
+                this.setPrivacy(response.data.results);
+            } catch (error) {
+                if (error.response) {
+                    throw new Error(error.response.data.message);
+                }
+                throw new Error(error.message);
+            }
+        },
+        setPrivacy(privacy) {
+            this.data_privacy = privacy;
+            this.form = {...privacy};
+            this.disappearing_timer = '';
+        },
+        changedSettings() {
+            let payload = {};
+            if (this.data_privacy == null) {
+                return payload;
+            }
+            this.settings.forEach(setting => {
+                if (this.form[setting.key] && this.form[setting.key] !== this.data_privacy[setting.key]) {
+                    payload[setting.key] = this.form[setting.key];
+                }
+            });
+            if (this.disappearing_timer !== '') {
+                payload.disappearing_timer = this.disappearing_timer;
+            }
+            return payload;
+        },
+        isValidForm() {
+            return Object.keys(this.changedSettings()).length > 0;
+        },
+        async handleSubmit() {
+            if (!this.isValidForm() || this.loading) {
+                return;
+            }
+
+            try {
+                let response = await this.submitApi()
+                showSuccessInfo(response)
+            } catch (err) {
+                showErrorInfo(err)
+            }
+        },
+        async submitApi() {
+            this.loading = true;
+            try {
+                let response = await window.http.post(`/user/my/privacy`, this.changedSettings())
+                this.setPrivacy(response.data.results);
+                return response.data.message;
             } catch (error) {
                 if (error.response) {
                     throw new Error(error.response.data.message);
                 }
                 throw new Error(error.message);
+            } finally {
+                this.loading = false;
             }
         },
     },
@@ -33,26 +122,41 @@ export default {
         <a class="ui olive right ribbon label">Account</a>
             <div class="header">My Privacy Setting</div>
             <div class="description">
-                Get your privacy settings
+                View and change your privacy settings
             </div>
         </div>
     </div>
-    
+
     <!--  Modal UserPrivacy  -->
     <div class="ui small modal" id="modalUserPrivacy">
         <i class="close icon"></i>
         <div class="header">
             My Privacy
         </div>
-        <div class="content">
-            <ol v-if="data_privacy != null">
-                <li>Who can add Group : <b>{{ data_privacy.group_add }}</b></li>
-                <li>Who can see my Last Seen : <b>{{ data_privacy.last_seen }}</b></li>
-                <li>Who can see my Status : <b>{{ data_privacy.status }}</b></li>
-                <li>Who can see my Profile : <b>{{ data_privacy.profile }}</b></li>
-                <li>Read Receipts : <b>{{ data_privacy.read_receipts }}</b></li>
-            </ol>
+        <div class="content" style="max-height: 70vh; overflow-y: auto;">
+            <form class="ui form" v-if="data_privacy != null">
+                <div class="field" v-for="setting in settings" :key="setting.key">
+                    <label>{{ setting.label }}</label>
+                    <select v-model="form[setting.key]">
+                        <option v-for="option in setting.options" :value="option.value">{{ option.text }}</option>
+                    </select>
+                </div>
+                <div class="field">
+                    <label>Default disappearing messages for new chats</label>
+                    <select v-model="disappearing_timer">
+                        <option v-for="timer in timers" :value="timer.value">{{ timer.text }}</option>
+                    </select>
+                </div>
+            </form>
+        </div>
+        <div class="actions">
+            <button class="ui approve positive right labeled icon button"
+                 :class="{'loading': this.loading, 'disabled': !isValidForm() || loading}"
+                 @click.prevent="handleSubmit">
+                Save Privacy
+                <i class="save icon"></i>
+            </button>
         </div>
     </div>
     `
-}
\ No newline at end of file
+}
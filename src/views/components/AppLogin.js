@@ -8,6 +8,7 @@ export default {
             login_link: '',
             login_duration_sec: 0,
             countdown_timer: null,
+            live_codes: false,
         }
     },
     methods: {
@@ -32,6 +33,7 @@ export default {
             try {
                 // Stop existing countdown before making new request
                 this.stopCountdown();
+                this.live_codes = false;
                 
                 let response = await window.http.get(`app/login`)
                 let results = response.data.results;
@@ -54,12 +56,36 @@ export default {
             this.countdown_timer = setInterval(() => {
                 if (this.login_duration_sec > 0) {
                     this.login_duration_sec--;
-                } else {
+                } else if (!this.live_codes) {
                     // Auto refresh when countdown reaches 0
                     this.autoRefresh();
                 }
             }, 1000);
         },
+        // Codes pushed over the websocket replace the shown code before it expires
+        handleLoginEvent(message) {
+            if (!$('#modalLogin').modal('is active')) return;
+
+            const event = message.result || {};
+            if (event.session_id && event.session_id !== 'default') return;
+
+            switch (message.code) {
+                case 'LOGIN_QR':
+                    this.live_codes = true;
+                    this.login_link = event.image;
+                    this.login_duration_sec = event.timeout;
+                    this.startCountdown();
+                    break;
+                case 'LOGIN_TIMEOUT':
+                    this.live_codes = false;
+                    this.autoRefresh();
+                    break;
+                case 'LOGIN_ERROR':
+                    this.stopCountdown();
+                    showErrorInfo(message.message);
+                    break;
+            }
+        },
         stopCountdown() {
             if (this.countdown_timer) {
                 clearInterval(this.countdown_timer);
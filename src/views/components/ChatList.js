@@ -9,6 +9,10 @@ export default {
             currentPage: 1,
             pageSize: 10,
             totalChats: 0,
+            // Cursor pagination keeps pages stable while chats get new messages
+            pageCursor: null,
+            nextCursor: '',
+            prevCursor: '',
             selectedChatJid: ''
         }
     },
@@ -27,6 +31,8 @@ export default {
     methods: {
         openModal() {
             $('#modalChatList').modal('show');
+            this.currentPage = 1;
+            this.pageCursor = null;
             this.loadChats();
         },
         closeModal() {
@@ -36,9 +42,12 @@ export default {
             this.loading = true;
             try {
                 const params = new URLSearchParams({
-                    offset: (this.currentPage - 1) * this.pageSize,
                     limit: this.pageSize
                 });
+
+                if (this.pageCursor) {
+                    Object.entries(this.pageCursor).forEach(([key, value]) => params.append(key, value));
+                }
                 
                 if (this.searchQuery.trim()) {
                     params.append('search', this.searchQuery);
@@ -51,6 +60,8 @@ export default {
                 const response = await window.http.get(`/chats?${params}`);
                 this.chats = response.data.results?.data || [];
                 this.totalChats = response.data.results?.pagination?.total || 0;
+                this.nextCursor = response.data.results?.pagination?.next_cursor || '';
+                this.prevCursor = response.data.results?.pagination?.prev_cursor || '';
             } catch (error) {
                 showErrorInfo(error.response?.data?.message || 'Failed to load chats');
             } finally {
@@ -59,17 +70,21 @@ export default {
         },
         async searchChats() {
             this.currentPage = 1;
+            this.pageCursor = null;
             await this.loadChats();
         },
         nextPage() {
             if (this.currentPage < this.totalPages) {
                 this.currentPage++;
+                this.pageCursor = this.nextCursor ? { before: this.nextCursor } : null;
                 this.loadChats();
             }
         },
         prevPage() {
             if (this.currentPage > 1) {
                 this.currentPage--;
+                // The first page is always the most recent chats
+                this.pageCursor = this.currentPage > 1 && this.prevCursor ? { after: this.prevCursor } : null;
                 this.loadChats();
             }
         },
@@ -179,6 +194,10 @@ export default {
                                 <div class="ui header">
                                     <div class="content">
                                         {{ chat.name || 'Unknown' }}
+                                        <div class="ui mini red circular label" v-if="chat.unread_count > 0">{{ chat.unread_count }}</div>
+                                        <i class="thumbtack icon" v-if="chat.pinned" title="Pinned"></i>
+                                        <i class="archive icon" v-if="chat.archived" title="Archived"></i>
+                                        <i class="volume off icon" v-if="chat.muted" title="Muted"></i>
                                     </div>
                                 </div>
                             </td>
@@ -191,7 +210,10 @@ export default {
                                 <code>{{ chat.jid }}</code>
                             </td>
                             <td>
-                                {{ formatTimestamp(chat.last_message_time) }}
+                                <div v-if="chat.last_message">
+                                    <span v-if="chat.last_message.is_from_me">You: </span>{{ chat.last_message.preview || '[' + chat.last_message.type + ']' }}
+                                </div>
+                                <small>{{ formatTimestamp(chat.last_message_time) }}</small>
                             </td>
                             <td class="collapsing">
                                 <button class="ui small primary button" 
@@ -13,6 +13,10 @@ export default {
       currentPage: 1,
       pageSize: 20,
       totalMessages: 0,
+      // Cursor pagination keeps pages stable while new messages arrive
+      pageCursor: null, // { before } or { after } for the current page
+      nextCursor: "",
+      prevCursor: "",
       // Media download tracking
       downloadedMedia: {}, // messageId -> { file_path, media_type, file_size, status }
       downloadingMedia: new Set(), // Set of messageIds currently downloading
@@ -41,6 +45,8 @@ export default {
       if (selectedJid) {
         this.jid = selectedJid;
         localStorage.removeItem("selectedChatJid"); // Clean up
+        this.currentPage = 1;
+        this.pageCursor = null;
 
         this.loadMessages();
       }
@@ -65,12 +71,15 @@ export default {
       this.loading = true;
       try {
         const params = new URLSearchParams({
-          offset: (this.currentPage - 1) * this.pageSize,
           limit: this.pageSize,
         });
 
         if (this.searchQuery.trim()) {
+          // Search results are not cursor-paginated
           params.append("search", this.searchQuery);
+          params.append("offset", (this.currentPage - 1) * this.pageSize);
+        } else if (this.pageCursor) {
+          Object.entries(this.pageCursor).forEach(([key, value]) => params.append(key, value));
         }
 
         if (this.startTime) {
@@ -94,6 +103,8 @@ export default {
         );
         this.messages = response.data.results?.data || [];
         this.totalMessages = response.data.results?.pagination?.total || 0;
+        this.nextCursor = response.data.results?.pagination?.next_cursor || "";
+        this.prevCursor = response.data.results?.pagination?.prev_cursor || "";
 
         if (this.messages.length === 0) {
           showErrorInfo("No messages found for the specified criteria");
@@ -111,17 +122,21 @@ export default {
     },
     searchMessages() {
       this.currentPage = 1;
+      this.pageCursor = null;
       this.loadMessages();
     },
     nextPage() {
       if (this.currentPage < this.totalPages) {
         this.currentPage++;
+        this.pageCursor = this.nextCursor ? { before: this.nextCursor } : null;
         this.loadMessages();
       }
     },
     prevPage() {
       if (this.currentPage > 1) {
         this.currentPage--;
+        // The first page is always the latest messages
+        this.pageCursor = this.currentPage > 1 && this.prevCursor ? { after: this.prevCursor } : null;
         this.loadMessages();
       }
     },
@@ -135,6 +150,9 @@ export default {
       this.onlyMedia = false;
       this.currentPage = 1;
       this.totalMessages = 0;
+      this.pageCursor = null;
+      this.nextCursor = "";
+      this.prevCursor = "";
       // Clear media download state
       this.downloadedMedia = {};
       this.downloadingMedia.clear();
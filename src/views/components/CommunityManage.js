@@ -0,0 +1,193 @@
+export default {
+    name: 'CommunityManage',
+    data() {
+        return {
+            loading: false,
+            name: '',
+            description: '',
+            community_id: '',
+            group_id: '',
+            announcement: '',
+            sub_groups: [],
+            announcement_group_id: '',
+        }
+    },
+    computed: {
+        communityID() {
+            return this.normalizeGroupID(this.community_id);
+        },
+        groupID() {
+            return this.normalizeGroupID(this.group_id);
+        },
+    },
+    methods: {
+        openModal() {
+            $('#modalCommunityManage').modal({
+                onApprove: function () {
+                    return false;
+                }
+            }).modal('show');
+        },
+        normalizeGroupID(value) {
+            const trimmed = String(value ?? '').trim();
+            if (!trimmed || trimmed.includes('@')) {
+                return trimmed;
+            }
+            return `${trimmed}@g.us`;
+        },
+        async request(fn) {
+            if (this.loading) {
+                return;
+            }
+            this.loading = true;
+            try {
+                const response = await fn();
+                showSuccessInfo(response.data.message);
+                return response.data.results;
+            } catch (error) {
+                showErrorInfo(error.response ? error.response.data.message : error.message);
+            } finally {
+                this.loading = false;
+            }
+        },
+        async handleCreate() {
+            if (!this.name.trim()) {
+                return;
+            }
+            const results = await this.request(() => window.http.post(`/community`, {
+                name: this.name.trim(),
+                description: this.description.trim(),
+            }));
+            if (results) {
+                this.community_id = results.community_id;
+                this.announcement_group_id = results.announcement_group_id || '';
+                this.name = '';
+                this.description = '';
+                await this.handleListSubGroups();
+            }
+        },
+        async handleLink(unlink = false) {
+            if (!this.communityID || !this.groupID) {
+                return;
+            }
+            await this.request(() => window.http.post(`/community/${unlink ? 'unlink' : 'link'}`, {
+                community_id: this.communityID,
+                group_id: this.groupID,
+            }));
+            this.group_id = '';
+            await this.handleListSubGroups();
+        },
+        async handleListSubGroups() {
+            if (!this.communityID) {
+                return;
+            }
+            const results = await this.request(() => window.http.get(`/community/subgroups`, {
+                params: {community_id: this.communityID},
+            }));
+            if (results) {
+                this.sub_groups = results.sub_groups || [];
+                this.announcement_group_id = results.announcement_group_id || '';
+            }
+        },
+        async handleAnnouncement() {
+            if (!this.communityID || !this.announcement.trim()) {
+                return;
+            }
+            const results = await this.request(() => window.http.post(`/community/announcement`, {
+                community_id: this.communityID,
+                message: this.announcement,
+            }));
+            if (results) {
+                this.announcement = '';
+            }
+        },
+    },
+    template: `
+    <div class="green card" @click="openModal" style="cursor: pointer">
+        <div class="content">
+            <a class="ui green right ribbon label">Community</a>
+            <div class="header">Manage Communities</div>
+            <div class="description">
+                Create communities, link groups and send announcements
+            </div>
+        </div>
+    </div>
+
+    <!--  Modal CommunityManage  -->
+    <div class="ui small modal" id="modalCommunityManage">
+        <i class="close icon"></i>
+        <div class="header">
+            Manage Community
+        </div>
+        <div class="scrolling content">
+            <form class="ui form">
+                <h4 class="ui dividing header">Create Community</h4>
+                <div class="field">
+                    <label>Name</label>
+                    <input v-model="name" type="text" placeholder="Community Name..." aria-label="Community Name">
+                </div>
+                <div class="field">
+                    <label>Description</label>
+                    <textarea v-model="description" rows="2" placeholder="Optional description..."
+                              aria-label="Community Description"></textarea>
+                </div>
+                <button class="ui positive button" :class="{'loading': loading, 'disabled': !name.trim() || loading}"
+                        @click.prevent="handleCreate" type="button">
+                    <i class="plus icon"></i> Create
+                </button>
+
+                <h4 class="ui dividing header">Existing Community</h4>
+                <div class="field">
+                    <label>Community ID</label>
+                    <div class="ui action input">
+                        <input v-model="community_id" type="text" placeholder="120363...@g.us" aria-label="Community ID">
+                        <button class="ui button" :class="{'disabled': !communityID || loading}"
+                                @click.prevent="handleListSubGroups" type="button">
+                            <i class="sync icon"></i> Subgroups
+                        </button>
+                    </div>
+                </div>
+                <div class="field">
+                    <label>Group ID</label>
+                    <div class="ui action input">
+                        <input v-model="group_id" type="text" placeholder="120363...@g.us" aria-label="Group ID">
+                        <button class="ui primary button" :class="{'disabled': !communityID || !groupID || loading}"
+                                @click.prevent="handleLink(false)" type="button">Link</button>
+                        <button class="ui red button" :class="{'disabled': !communityID || !groupID || loading}"
+                                @click.prevent="handleLink(true)" type="button">Unlink</button>
+                    </div>
+                </div>
+
+                <table class="ui very basic compact table" v-if="sub_groups.length">
+                    <thead>
+                    <tr>
+                        <th>Name</th>
+                        <th>Group ID</th>
+                    </tr>
+                    </thead>
+                    <tbody>
+                    <tr v-for="sub in sub_groups" :key="sub.group_id">
+                        <td>
+                            {{ sub.name }}
+                            <span class="ui mini blue label" v-if="sub.is_announcement_group">Announcements</span>
+                        </td>
+                        <td><code>{{ sub.group_id }}</code></td>
+                    </tr>
+                    </tbody>
+                </table>
+
+                <div class="field">
+                    <label>Announcement</label>
+                    <textarea v-model="announcement" rows="3" placeholder="Message for all community members..."
+                              aria-label="Announcement"></textarea>
+                </div>
+                <button class="ui primary button"
+                        :class="{'loading': loading, 'disabled': !communityID || !announcement.trim() || loading}"
+                        @click.prevent="handleAnnouncement" type="button">
+                    <i class="bullhorn icon"></i> Send Announcement
+                </button>
+            </form>
+        </div>
+    </div>
+    `
+}
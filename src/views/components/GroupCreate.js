@@ -5,6 +5,7 @@ export default {
             loading: false,
             title: '',
             participants: ['', ''],
+            community_id: '',
         }
     },
     methods: {
@@ -63,7 +64,8 @@ export default {
                     // sanitize participants list
                     participants: this.participants
                         .filter(p => !this.isEmpty(p))
-                        .map(p => `${p?.jid ?? p}`)
+                        .map(p => `${p?.jid ?? p}`),
+                    community_id: String(this.community_id ?? '').trim() || undefined,
                 })
                 this.handleReset();
                 return response.data.message;
@@ -79,6 +81,7 @@ export default {
         handleReset() {
             this.title = '';
             this.participants = ['', ''];
+            this.community_id = '';
         },
     },
     template: `
@@ -106,6 +109,13 @@ export default {
                            placeholder="Group Name..."
                            aria-label="Group Name">
                 </div>
+
+                <div class="field">
+                    <label>Community ID (optional)</label>
+                    <input v-model="community_id" type="text"
+                           placeholder="120363...@g.us"
+                           aria-label="Community ID">
+                </div>
                 
                 <div class="field">
                     <label>Participants</label>